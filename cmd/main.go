@@ -51,8 +51,15 @@ func main() {
 
 	// Initialize router
 	r := gin.New() // Use gin.New() instead of gin.Default() to have more control
+	// Gin trusts X-Forwarded-For from any client unless told otherwise, which
+	// would let clients pick the IP they are rate limited and audited by
+	if err := r.SetTrustedProxies(config.Server.TrustedProxies); err != nil {
+		log.Fatalf("Error setting trusted proxies: %v", err)
+	}
 	r.Use(gin.Recovery())
 	r.Use(middleware.LoggerMiddleware(appLogger))
+	r.Use(middleware.ClientInfoMiddleware())
+	r.Use(middleware.OrganizationMiddleware())
 
 	// Swagger documentation
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
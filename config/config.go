@@ -1,13 +1,16 @@
 package config
 
 import (
+	"strings"
+	"time"
+
 	"github.com/amirdashtii/go_auth/internal/core/errors"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	Environment string
-	DB struct {
+	DB          struct {
 		Host     string
 		Port     string
 		User     string
@@ -19,14 +22,101 @@ type Config struct {
 		Password string
 		DB       int
 	}
-	JWT struct {
-		Secret string
-	}
+	JWT    JWTConfig
 	Server struct {
 		Port string
+		// TrustedProxies are the addresses or CIDR ranges of the reverse
+		// proxies in front of the service. The client IP is only read from
+		// X-Forwarded-For when a request comes through one of them.
+		TrustedProxies []string
+	}
+	// OIDC configures go_auth as an OpenID Connect provider. Issuer is the
+	// public base URL of the service and is used as the iss claim of ID tokens.
+	OIDC struct {
+		Issuer string
+	}
+	WebAuthn  WebAuthnConfig
+	RateLimit RateLimitConfig
+	Password  PasswordConfig
+	SMS       SMSConfig
+	Email     EmailConfig
+	// OTP configures one-time codes. Secret keys the HMAC codes are stored
+	// under and is required.
+	OTP struct {
+		Secret string
 	}
 }
 
+// SMSConfig selects how text messages are delivered. Sender is console,
+// which prints them, file, which appends them to logs/sms.log, or webhook,
+// which posts them to WebhookURL with WebhookToken as a bearer token. The
+// console and file senders are only allowed in development.
+type SMSConfig struct {
+	Sender       string
+	WebhookURL   string
+	WebhookToken string
+}
+
+// EmailConfig selects how emails are delivered. Sender is console, which
+// prints them, file, which appends them to logs/email.log, or smtp, which
+// sends them from From through the SMTP server at SMTPHost and SMTPPort,
+// logging in with SMTPUsername and SMTPPassword when they are set. The
+// console and file senders are only allowed in development.
+type EmailConfig struct {
+	Sender       string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+}
+
+// PasswordConfig sets the cost of the argon2id hashes passwords are stored
+// with: Argon2Memory in KiB, Argon2Time passes over it and Argon2Parallelism
+// lanes. Stored hashes weaker than this are replaced at the next login.
+type PasswordConfig struct {
+	Argon2Memory      uint32
+	Argon2Time        uint32
+	Argon2Parallelism uint8
+}
+
+// RateLimitConfig limits how often clients can call each route group. Groups
+// are keyed by name: auth, oauth, users, profile and admin.
+type RateLimitConfig struct {
+	Enabled bool
+	Groups  map[string]RateLimitRule
+}
+
+// RateLimitRule allows Requests requests per Window. KeyBy picks what the
+// requests are counted against: ip, user (the authenticated user, or the IP
+// before login) or route (every client of a route together).
+type RateLimitRule struct {
+	Requests int
+	Window   time.Duration
+	KeyBy    string
+}
+
+// WebAuthnConfig describes go_auth as a WebAuthn relying party. RPID is the
+// domain passkeys are scoped to and Origins the web origins allowed to use
+// them, for example https://app.example.com with an RPID of example.com.
+type WebAuthnConfig struct {
+	RPID    string
+	RPName  string
+	Origins []string
+}
+
+// JWTConfig selects how tokens are signed. Secret is used for HS256 while
+// RS256, ES256 and EdDSA read a PEM encoded private key from PrivateKeyFile.
+type JWTConfig struct {
+	Algorithm      string
+	Secret         string
+	PrivateKeyFile string
+	KeyID          string
+	// KeyringDir enables signing key rotation. Generated keys and the
+	// keyring.json manifest are kept there.
+	KeyringDir string
+}
+
 func LoadConfig() (*Config, error) {
 	v := viper.New()
 
@@ -38,7 +128,30 @@ func LoadConfig() (*Config, error) {
 	v.SetDefault("db.user", "go_auth")
 	v.SetDefault("db.password", "go_auth")
 	v.SetDefault("db.name", "go_auth")
+	v.SetDefault("jwt.algorithm", "HS256")
 	v.SetDefault("jwt.secret", "h13dpx8nFiWwLbhHuOEBLWhA6kfYwoP9UNU5MQlgoZQ0")
+	v.SetDefault("oidc.issuer", "http://localhost:8080")
+	v.SetDefault("webauthn.rpID", "localhost")
+	v.SetDefault("webauthn.rpName", "go_auth")
+	v.SetDefault("webauthn.origins", []string{"http://localhost:8080"})
+	v.SetDefault("rateLimit.enabled", true)
+	v.SetDefault("rateLimit.groups", map[string]interface{}{
+		"auth":    map[string]interface{}{"requests": 20, "window": "1m", "keyBy": "ip"},
+		"oauth":   map[string]interface{}{"requests": 60, "window": "1m", "keyBy": "ip"},
+		"users":   map[string]interface{}{"requests": 120, "window": "1m", "keyBy": "user"},
+		"profile": map[string]interface{}{"requests": 120, "window": "1m", "keyBy": "user"},
+		"admin":   map[string]interface{}{"requests": 300, "window": "1m", "keyBy": "user"},
+		// Counts requests to authenticated routes before their credentials
+		// are checked, so failed attempts are limited too
+		"authenticate": map[string]interface{}{"requests": 300, "window": "1m", "keyBy": "ip"},
+	})
+	// The second recommended option of RFC 9106
+	v.SetDefault("password.argon2Memory", 64*1024)
+	v.SetDefault("password.argon2Time", 3)
+	v.SetDefault("password.argon2Parallelism", 4)
+	v.SetDefault("sms.sender", "console")
+	v.SetDefault("email.sender", "console")
+	v.SetDefault("email.smtpPort", 587)
 	v.SetDefault("redis.Addr", "localhost:6379")
 	v.SetDefault("redis.Password", "")
 	v.SetDefault("redis.DB", 0)
@@ -65,12 +178,73 @@ func LoadConfig() (*Config, error) {
 		v.AutomaticEnv()
 		v.Set("environment", v.GetString("ENVIRONMENT"))
 		v.Set("server.port", v.GetString("SERVER_PORT"))
+		if v.GetString("TRUSTED_PROXIES") != "" {
+			v.Set("server.trustedProxies", strings.Split(v.GetString("TRUSTED_PROXIES"), ","))
+		}
 		v.Set("db.host", v.GetString("DB_HOST"))
 		v.Set("db.port", v.GetString("DB_PORT"))
 		v.Set("db.user", v.GetString("DB_USER"))
 		v.Set("db.password", v.GetString("DB_PASSWORD"))
 		v.Set("db.name", v.GetString("DB_NAME"))
 		v.Set("jwt.secret", v.GetString("JWT_SECRET"))
+		v.Set("jwt.algorithm", v.GetString("JWT_ALGORITHM"))
+		v.Set("jwt.privateKeyFile", v.GetString("JWT_PRIVATE_KEY_FILE"))
+		v.Set("jwt.keyID", v.GetString("JWT_KEY_ID"))
+		v.Set("jwt.keyringDir", v.GetString("JWT_KEYRING_DIR"))
+		if v.IsSet("OIDC_ISSUER") {
+			v.Set("oidc.issuer", v.GetString("OIDC_ISSUER"))
+		}
+		if v.IsSet("WEBAUTHN_RP_ID") {
+			v.Set("webauthn.rpID", v.GetString("WEBAUTHN_RP_ID"))
+		}
+		if v.IsSet("WEBAUTHN_RP_NAME") {
+			v.Set("webauthn.rpName", v.GetString("WEBAUTHN_RP_NAME"))
+		}
+		if v.IsSet("WEBAUTHN_ORIGINS") {
+			v.Set("webauthn.origins", strings.Split(v.GetString("WEBAUTHN_ORIGINS"), ","))
+		}
+		if v.IsSet("RATE_LIMIT_ENABLED") {
+			v.Set("rateLimit.enabled", v.GetBool("RATE_LIMIT_ENABLED"))
+		}
+		if v.IsSet("PASSWORD_ARGON2_MEMORY") {
+			v.Set("password.argon2Memory", v.GetUint32("PASSWORD_ARGON2_MEMORY"))
+		}
+		if v.IsSet("PASSWORD_ARGON2_TIME") {
+			v.Set("password.argon2Time", v.GetUint32("PASSWORD_ARGON2_TIME"))
+		}
+		if v.IsSet("PASSWORD_ARGON2_PARALLELISM") {
+			v.Set("password.argon2Parallelism", v.GetUint32("PASSWORD_ARGON2_PARALLELISM"))
+		}
+		if v.IsSet("OTP_SECRET") {
+			v.Set("otp.secret", v.GetString("OTP_SECRET"))
+		}
+		if v.IsSet("SMS_SENDER") {
+			v.Set("sms.sender", v.GetString("SMS_SENDER"))
+		}
+		if v.IsSet("SMS_WEBHOOK_URL") {
+			v.Set("sms.webhookURL", v.GetString("SMS_WEBHOOK_URL"))
+		}
+		if v.IsSet("SMS_WEBHOOK_TOKEN") {
+			v.Set("sms.webhookToken", v.GetString("SMS_WEBHOOK_TOKEN"))
+		}
+		if v.IsSet("EMAIL_SENDER") {
+			v.Set("email.sender", v.GetString("EMAIL_SENDER"))
+		}
+		if v.IsSet("EMAIL_SMTP_HOST") {
+			v.Set("email.smtpHost", v.GetString("EMAIL_SMTP_HOST"))
+		}
+		if v.IsSet("EMAIL_SMTP_PORT") {
+			v.Set("email.smtpPort", v.GetInt("EMAIL_SMTP_PORT"))
+		}
+		if v.IsSet("EMAIL_SMTP_USERNAME") {
+			v.Set("email.smtpUsername", v.GetString("EMAIL_SMTP_USERNAME"))
+		}
+		if v.IsSet("EMAIL_SMTP_PASSWORD") {
+			v.Set("email.smtpPassword", v.GetString("EMAIL_SMTP_PASSWORD"))
+		}
+		if v.IsSet("EMAIL_FROM") {
+			v.Set("email.from", v.GetString("EMAIL_FROM"))
+		}
 	}
 
 	var config Config
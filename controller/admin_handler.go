@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
 
 	// "time" // This line should be removed or commented out
 
@@ -22,6 +24,9 @@ import (
 type AdminHTTPHandler struct {
 	svc    ports.AdminService
 	logger ports.Logger
+	// hasPermission reports whether the authenticated user was granted a
+	// permission, for handlers whose permission depends on the request
+	hasPermission func(c *gin.Context, permission string) (bool, error)
 }
 
 func NewAdminHTTPHandler() *AdminHTTPHandler {
@@ -43,33 +48,65 @@ func NewAdminHTTPHandler() *AdminHTTPHandler {
 
 	appLogger := logger.NewZerologLogger(loggerConfig)
 	return &AdminHTTPHandler{
-		svc:    svc,
-		logger: appLogger,
+		svc:           svc,
+		logger:        appLogger,
+		hasPermission: middleware.HasPermission,
 	}
 }
 
 func NewAdminRoutes(r *gin.Engine) {
 	h := NewAdminHTTPHandler()
 
+	adminRateLimit := middleware.RateLimit("admin")
+
 	usersGroup := r.Group("/users")
-	usersGroup.Use(middleware.AuthMiddleware())
-
-	usersGroup.GET("", h.GetUsersHandler)
-	usersGroup.GET("/:id", h.GetUserByIDHandler)
-	usersGroup.PUT("/:id", h.UpdateUserHandler)
-	usersGroup.PUT("/:id/role", h.ChangeUserRoleHandler)
-	usersGroup.PUT("/:id/status", h.ChangeUserStatusHandler)
-	usersGroup.DELETE("/:id", h.DeleteUserHandler)
+	usersGroup.Use(middleware.AuthenticateRateLimit(), middleware.AdminAuthMiddleware(), adminRateLimit)
+
+	usersGroup.GET("", middleware.RequirePermission(entities.PermissionUsersRead), h.GetUsersHandler)
+	usersGroup.GET("/:id", middleware.RequirePermission(entities.PermissionUsersRead), h.GetUserByIDHandler)
+	usersGroup.PUT("/:id", middleware.RequirePermission(entities.PermissionUsersWrite), h.UpdateUserHandler)
+	usersGroup.PUT("/:id/role", middleware.RequirePermission(entities.PermissionUsersRoleChange), h.ChangeUserRoleHandler)
+	usersGroup.PUT("/:id/status", middleware.RequirePermission(entities.PermissionUsersStatusChange), h.ChangeUserStatusHandler)
+	usersGroup.DELETE("/:id", middleware.RequirePermission(entities.PermissionUsersDelete), h.DeleteUserHandler)
+	usersGroup.DELETE("/:id/mfa", middleware.RequirePermission(entities.PermissionUsersMFAReset), h.ResetUserMFAHandler)
+	usersGroup.DELETE("/:id/lockout", middleware.RequirePermission(entities.PermissionUsersUnlock), h.UnlockUserHandler)
+
+	adminGroup := r.Group("/admin")
+	adminGroup.Use(middleware.AuthenticateRateLimit(), middleware.AdminAuthMiddleware(), adminRateLimit)
+
+	adminGroup.GET("/audit", middleware.RequirePermission(entities.PermissionAuditRead), h.ListAuditEventsHandler)
+	// The permission needed depends on the action, checked by the handler
+	adminGroup.POST("/users/bulk", h.BulkUpdateUsersHandler)
+	adminGroup.GET("/users/export", middleware.RequirePermission(entities.PermissionUsersExport), h.ExportUsersHandler)
+	adminGroup.POST("/users/import", middleware.RequirePermission(entities.PermissionUsersImport), h.ImportUsersHandler)
+
+	// These routes affect every organization, so only admins of the default
+	// organization can use them
+	defaultOrganization := middleware.RequireDefaultOrganization()
+	adminGroup.POST("/keys/rotate", defaultOrganization, middleware.RequirePermission(entities.PermissionKeysRotate), h.RotateSigningKeyHandler)
+	adminGroup.POST("/oauth/clients", defaultOrganization, middleware.RequirePermission(entities.PermissionOAuthClientsWrite), h.CreateOAuthClientHandler)
+	adminGroup.POST("/organizations", defaultOrganization, middleware.RequirePermission(entities.PermissionOrganizationsWrite), h.CreateOrganizationHandler)
+	adminGroup.GET("/organizations", defaultOrganization, middleware.RequirePermission(entities.PermissionOrganizationsRead), h.ListOrganizationsHandler)
 }
 
 // GetUsersHandler godoc
 // @Summary Get all users
-// @Description Get list of all users (admin only)
+// @Description List users matching the filters with the total count. Pass next_cursor as cursor to get the next page (requires the users:read permission).
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{}
+// @Param status query string false "Statuses, repeated or comma separated, default active"
+// @Param role query string false "Roles, repeated or comma separated, default user"
+// @Param search query string false "Part of the name, phone number or email"
+// @Param created_from query string false "Earliest creation time, RFC 3339"
+// @Param created_to query string false "Time before which users were created, RFC 3339"
+// @Param sort query string false "Sort field, default created_at"
+// @Param order query string false "asc or desc, default desc"
+// @Param limit query int false "Users per page, 1 to 200, default 50"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} dto.AdminUserListResponse
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
@@ -89,33 +126,25 @@ func (h *AdminHTTPHandler) GetUsersHandler(c *gin.Context) {
 		return
 	}
 
-	role, exists := c.Get("role")
-	if !exists {
-		h.logger.Error("User not authenticated",
-			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
-		)
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": errors.ErrUserNotAuthenticated,
-		})
-		return
-	}
+	var req dto.AdminGetUsersRequest
 
-	roleStr := role.(string)
-	if roleStr != entities.SuperAdminRole.String() && roleStr != entities.AdminRole.String() {
-		h.logger.Error("User not authorized",
-			ports.F("error", errors.ErrForbidden.Message.English),
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", err),
 		)
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": errors.ErrForbidden,
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidUserFilter,
 		})
 		return
 	}
 
-	req := dto.AdminGetUsersRequest{
-		Status: c.DefaultQuery("status", "active"),
-		Role:   c.DefaultQuery("role", "user"),
-		Sort:   c.DefaultQuery("sort", "created_at"),
-		Order:  c.DefaultQuery("order", "desc"),
+	req.Status = splitQueryValues(req.Status)
+	req.Role = splitQueryValues(req.Role)
+	if len(req.Status) == 0 {
+		req.Status = []string{"active"}
+	}
+	if len(req.Role) == 0 {
+		req.Role = []string{"user"}
 	}
 
 	if err := validators.ValidateGetUsersRequest(&req, h.logger); err != nil {
@@ -125,23 +154,26 @@ func (h *AdminHTTPHandler) GetUsersHandler(c *gin.Context) {
 		return
 	}
 
-	statuesType := entities.ParseStatusType(req.Status)
-	roleType := entities.ParseRoleType(req.Role)
-
-	resp, err := h.svc.GetUsers(ctx, &statuesType, &roleType, &req.Sort, &req.Order)
+	resp, err := h.svc.GetUsers(ctx, &req)
 	if err != nil {
+		if errors.IsValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err,
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": dto.AdminUserListResponse{Users: resp}})
+	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
 
 // GetUserByIDHandler godoc
 // @Summary Get user by ID
-// @Description Get user information by ID (admin only)
+// @Description Get user information by ID (requires the users:read permission)
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -157,28 +189,6 @@ func (h *AdminHTTPHandler) GetUserByIDHandler(c *gin.Context) {
 	ctx, cancel := context.WithCancel(c.Request.Context())
 	defer cancel()
 
-	role, exists := c.Get("role")
-	if !exists {
-		h.logger.Error("User not authenticated",
-			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
-		)
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": errors.ErrUserNotAuthenticated,
-		})
-		return
-	}
-
-	roleStr := role.(string)
-	if roleStr != entities.SuperAdminRole.String() && roleStr != entities.AdminRole.String() {
-		h.logger.Error("User not authorized",
-			ports.F("error", errors.ErrForbidden.Message.English),
-		)
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": errors.ErrForbidden,
-		})
-		return
-	}
-
 	id := c.Param("id")
 	userID, err := uuid.Parse(id)
 	if err != nil {
@@ -205,7 +215,7 @@ func (h *AdminHTTPHandler) GetUserByIDHandler(c *gin.Context) {
 
 // UpdateUserHandler godoc
 // @Summary Update user
-// @Description Update user information by ID (admin only)
+// @Description Update user information by ID (requires the users:write permission)
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -223,8 +233,8 @@ func (h *AdminHTTPHandler) UpdateUserHandler(c *gin.Context) {
 	ctx, cancel := context.WithCancel(c.Request.Context())
 	defer cancel()
 
-	role, exists := c.Get("role")
-	if !exists {
+	actor, ok := principal(c)
+	if !ok {
 		h.logger.Error("User not authenticated",
 			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
 		)
@@ -234,17 +244,6 @@ func (h *AdminHTTPHandler) UpdateUserHandler(c *gin.Context) {
 		return
 	}
 
-	roleStr := role.(string)
-	if roleStr != entities.SuperAdminRole.String() && roleStr != entities.AdminRole.String() {
-		h.logger.Error("User not authorized",
-			ports.F("error", errors.ErrForbidden),
-		)
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": errors.ErrForbidden,
-		})
-		return
-	}
-
 	id := c.Param("id")
 	userID, err := uuid.Parse(id)
 	if err != nil {
@@ -277,9 +276,9 @@ func (h *AdminHTTPHandler) UpdateUserHandler(c *gin.Context) {
 		return
 	}
 
-	err = h.svc.AdminUpdateUser(ctx, &userID, &updateReq)
+	err = h.svc.AdminUpdateUser(ctx, actor, &userID, &updateReq)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(adminErrorStatus(err), gin.H{
 			"error": err,
 		})
 		return
@@ -290,7 +289,7 @@ func (h *AdminHTTPHandler) UpdateUserHandler(c *gin.Context) {
 
 // ChangeUserRoleHandler godoc
 // @Summary Change user role
-// @Description Change user role by ID (admin only)
+// @Description Change user role by ID (requires the users:role:change permission)
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -319,8 +318,8 @@ func (h *AdminHTTPHandler) ChangeUserRoleHandler(c *gin.Context) {
 		return
 	}
 
-	role, exists := c.Get("role")
-	if !exists {
+	actor, ok := principal(c)
+	if !ok {
 		h.logger.Error("User not authenticated",
 			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
 		)
@@ -330,17 +329,6 @@ func (h *AdminHTTPHandler) ChangeUserRoleHandler(c *gin.Context) {
 		return
 	}
 
-	roleStr := role.(string)
-	if roleStr != entities.SuperAdminRole.String() && roleStr != entities.AdminRole.String() {
-		h.logger.Error("User not authorized",
-			ports.F("error", errors.ErrForbidden.Message.English),
-		)
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": errors.ErrForbidden,
-		})
-		return
-	}
-
 	id := c.Param("id")
 	userID, err := uuid.Parse(id)
 	if err != nil {
@@ -374,9 +362,9 @@ func (h *AdminHTTPHandler) ChangeUserRoleHandler(c *gin.Context) {
 	}
 
 	updateRole := entities.ParseRoleType(updateRoleReq.Role)
-	err = h.svc.ChangeUserRole(ctx, &userID, &updateRole)
+	err = h.svc.ChangeUserRole(ctx, actor, &userID, &updateRole)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(adminErrorStatus(err), gin.H{
 			"error": err,
 		})
 		return
@@ -387,7 +375,7 @@ func (h *AdminHTTPHandler) ChangeUserRoleHandler(c *gin.Context) {
 
 // ChangeUserStatusHandler godoc
 // @Summary Change user status
-// @Description Change user status by ID (admin only)
+// @Description Change user status by ID (requires the users:status:change permission)
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -416,8 +404,8 @@ func (h *AdminHTTPHandler) ChangeUserStatusHandler(c *gin.Context) {
 		return
 	}
 
-	role, exists := c.Get("role")
-	if !exists {
+	actor, ok := principal(c)
+	if !ok {
 		h.logger.Error("User not authenticated",
 			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
 		)
@@ -427,17 +415,6 @@ func (h *AdminHTTPHandler) ChangeUserStatusHandler(c *gin.Context) {
 		return
 	}
 
-	roleStr := role.(string)
-	if roleStr != entities.SuperAdminRole.String() && roleStr != entities.AdminRole.String() {
-		h.logger.Error("User not authorized",
-			ports.F("error", errors.ErrForbidden.Message.English),
-		)
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": errors.ErrForbidden,
-		})
-		return
-	}
-
 	id := c.Param("id")
 	userID, err := uuid.Parse(id)
 	if err != nil {
@@ -471,9 +448,9 @@ func (h *AdminHTTPHandler) ChangeUserStatusHandler(c *gin.Context) {
 	}
 
 	updateStatus := entities.ParseStatusType(updateStatusReq.Status)
-	err = h.svc.ChangeUserStatus(ctx, &userID, &updateStatus)
+	err = h.svc.ChangeUserStatus(ctx, actor, &userID, &updateStatus)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(adminErrorStatus(err), gin.H{
 			"error": err,
 		})
 		return
@@ -484,13 +461,14 @@ func (h *AdminHTTPHandler) ChangeUserStatusHandler(c *gin.Context) {
 
 // DeleteUserHandler godoc
 // @Summary Delete user
-// @Description Delete user by ID (admin only)
+// @Description Delete user by ID (requires the users:delete permission)
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "User ID"
 // @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -511,8 +489,8 @@ func (h *AdminHTTPHandler) DeleteUserHandler(c *gin.Context) {
 		return
 	}
 
-	role, exists := c.Get("role")
-	if !exists {
+	actor, ok := principal(c)
+	if !ok {
 		h.logger.Error("User not authenticated",
 			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
 		)
@@ -522,13 +500,67 @@ func (h *AdminHTTPHandler) DeleteUserHandler(c *gin.Context) {
 		return
 	}
 
-	roleStr := role.(string)
-	if roleStr != entities.SuperAdminRole.String() && roleStr != entities.AdminRole.String() {
-		h.logger.Error("User not authorized",
-			ports.F("error", errors.ErrForbidden.Message.English),
+	id := c.Param("id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		h.logger.Error("Invalid user ID",
+			ports.F("error", errors.ErrInvalidUserID.Message.English),
+			ports.F("user_id", userID),
 		)
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": errors.ErrForbidden,
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidUserID,
+		})
+		return
+	}
+
+	err = h.svc.AdminDeleteUser(ctx, actor, &userID)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+}
+
+// ResetUserMFAHandler godoc
+// @Summary Reset user two-factor authentication
+// @Description Turn off two-factor authentication for a user who lost their authenticator and recovery codes (requires the users:mfa:reset permission)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/mfa [delete]
+func (h *AdminHTTPHandler) ResetUserMFAHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling reset user MFA request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	actor, ok := principal(c)
+	if !ok {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
 		})
 		return
 	}
@@ -538,7 +570,71 @@ func (h *AdminHTTPHandler) DeleteUserHandler(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Invalid user ID",
 			ports.F("error", errors.ErrInvalidUserID.Message.English),
-			ports.F("user_id", userID),
+			ports.F("user_id", id),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidUserID,
+		})
+		return
+	}
+
+	if err := h.svc.ResetUserMFA(ctx, actor, &userID); err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication reset successfully"})
+}
+
+// UnlockUserHandler godoc
+// @Summary Clear user login lockout
+// @Description Clear the failed login attempts of a user so they can log in again right away (requires the users:unlock permission)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/lockout [delete]
+func (h *AdminHTTPHandler) UnlockUserHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling unlock user request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	actor, ok := principal(c)
+	if !ok {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	id := c.Param("id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		h.logger.Error("Invalid user ID",
+			ports.F("error", errors.ErrInvalidUserID.Message.English),
+			ports.F("user_id", id),
 		)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": errors.ErrInvalidUserID,
@@ -546,13 +642,641 @@ func (h *AdminHTTPHandler) DeleteUserHandler(c *gin.Context) {
 		return
 	}
 
-	err = h.svc.AdminDeleteUser(ctx, &userID)
+	if err := h.svc.UnlockUser(ctx, actor, &userID); err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unlocked successfully"})
+}
+
+// bulkActionPermissions are the permissions needed for each bulk action,
+// the same as for the action on a single user
+var bulkActionPermissions = map[string]string{
+	entities.BulkActionChangeStatus:   entities.PermissionUsersStatusChange,
+	entities.BulkActionChangeRole:     entities.PermissionUsersRoleChange,
+	entities.BulkActionDelete:         entities.PermissionUsersDelete,
+	entities.BulkActionRevokeSessions: entities.PermissionUsersRevokeSession,
+}
+
+// BulkUpdateUsersHandler godoc
+// @Summary Apply an action to many users
+// @Description Change the status or role of, delete or revoke the sessions of up to 100 users, reporting the outcome for each. With dry_run nothing is changed. Requires the permission of the action: users:status:change, users:role:change, users:delete or users:sessions:revoke.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.AdminBulkUsersRequest true "Users and action"
+// @Success 200 {object} dto.AdminBulkUsersResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users/bulk [post]
+func (h *AdminHTTPHandler) BulkUpdateUsersHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling bulk update users request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	actor, ok := principal(c)
+	if !ok {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	var req dto.AdminBulkUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", err),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	if err := validators.ValidateBulkUsersRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	allowed, err := h.hasPermission(c, bulkActionPermissions[req.Action])
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err,
 		})
 		return
 	}
+	if !allowed {
+		h.logger.Error("User not authorized",
+			ports.F("error", errors.ErrForbidden.Message.English),
+			ports.F("user_id", actor.UserID),
+			ports.F("permission", bulkActionPermissions[req.Action]),
+		)
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": errors.ErrForbidden,
+		})
+		return
+	}
+
+	resp, err := h.svc.BulkUpdateUsers(ctx, actor, &req)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{
+			"error": err,
+		})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// ExportUsersHandler godoc
+// @Summary Export users
+// @Description Download every user matching the filters of the user list as CSV or NDJSON, streamed in the order of the list (requires the users:export permission)
+// @Tags admin
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param format query string true "csv or ndjson"
+// @Param status query string false "Statuses, repeated or comma separated, default active"
+// @Param role query string false "Roles, repeated or comma separated, default user"
+// @Param search query string false "Part of the name, phone number or email"
+// @Param created_from query string false "Earliest creation time, RFC 3339"
+// @Param created_to query string false "Time before which users were created, RFC 3339"
+// @Param sort query string false "Sort field, default created_at"
+// @Param order query string false "asc or desc, default desc"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users/export [get]
+func (h *AdminHTTPHandler) ExportUsersHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling export users request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	actor, ok := principal(c)
+	if !ok {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	var req dto.AdminExportUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", err),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidTransferFormat,
+		})
+		return
+	}
+
+	req.Status = splitQueryValues(req.Status)
+	req.Role = splitQueryValues(req.Role)
+	if len(req.Status) == 0 {
+		req.Status = []string{"active"}
+	}
+	if len(req.Role) == 0 {
+		req.Role = []string{"user"}
+	}
+
+	if err := validators.ValidateExportUsersRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// The response starts with the first user, so errors found before it
+	// can still be reported as JSON
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		c.Header("Content-Type", exportContentTypes[req.Format])
+		c.Header("Content-Disposition", `attachment; filename="users.`+req.Format+`"`)
+		c.Status(http.StatusOK)
+	}
+	writer := newUserExportWriter(req.Format, c.Writer)
+
+	err := h.svc.ExportUsers(ctx, actor, &req, func(user *dto.AdminUserResponse) error {
+		start()
+		return writer.Write(user)
+	})
+	if err != nil {
+		if started {
+			h.logger.Error("Export users interrupted",
+				ports.F("error", err),
+				ports.F("user_id", actor.UserID),
+			)
+			return
+		}
+		c.JSON(adminErrorStatus(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	start()
+	if err := writer.Flush(); err != nil {
+		h.logger.Error("Error writing export",
+			ports.F("error", err),
+			ports.F("user_id", actor.UserID),
+		)
+	}
+}
+
+// ImportUsersHandler godoc
+// @Summary Import users
+// @Description Create users from a CSV file with a header row or an NDJSON file, of up to 5000 rows. Each row needs a phone_number and either a password or a password_hash, a bcrypt hash or a PHC string of argon2id, scrypt, PBKDF2 or salted SHA; first_name, last_name, email, role and status are optional. Rows that fail are reported by number without stopping the others (requires the users:import permission).
+// @Tags admin
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Security BearerAuth
+// @Param format query string true "csv or ndjson"
+// @Success 200 {object} dto.AdminImportUsersResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users/import [post]
+func (h *AdminHTTPHandler) ImportUsersHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling import users request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	actor, ok := principal(c)
+	if !ok {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	var req dto.AdminImportUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", err),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidTransferFormat,
+		})
+		return
+	}
+
+	if err := validators.ValidateImportUsersRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	rows, rowErrors, err := readImportRows(req.Format, body)
+	if err != nil {
+		h.logger.Error("Invalid import file",
+			ports.F("error", err),
+			ports.F("format", req.Format),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	valid := make([]dto.AdminImportUserRow, 0, len(rows))
+	for _, row := range rows {
+		if err := validators.ValidateImportUserRow(&row, h.logger); err != nil {
+			rowErrors = append(rowErrors, dto.AdminImportRowError{Row: row.Row, Error: err})
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	resp, err := h.svc.ImportUsers(ctx, actor, valid)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	resp.Errors = append(resp.Errors, rowErrors...)
+	sort.SliceStable(resp.Errors, func(i, j int) bool {
+		return resp.Errors[i].Row < resp.Errors[j].Row
+	})
+	resp.Failed = len(resp.Errors)
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// RotateSigningKeyHandler godoc
+// @Summary Rotate token signing key
+// @Description Generate a new token signing key; tokens signed with the previous key stay valid until they expire (requires the keys:rotate permission, for admins of the default organization)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/keys/rotate [post]
+func (h *AdminHTTPHandler) RotateSigningKeyHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling rotate signing key request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	kid, err := h.svc.RotateSigningKey(ctx)
+	if err != nil {
+		if err == errors.ErrKeyRotationDisabled {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	h.logger.Info("Signing key rotated",
+		ports.F("kid", kid),
+		ports.F("user_id", c.GetString("user_id")),
+	)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Signing key rotated successfully",
+		"kid":     kid,
+	})
+}
+
+// CreateOAuthClientHandler godoc
+// @Summary Register an OAuth client
+// @Description Register an application that signs its users in through go_auth. The client secret of a confidential client is only returned once (requires the oauth_clients:write permission, for admins of the default organization).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.AdminCreateOAuthClientRequest true "OAuth client"
+// @Success 201 {object} dto.AdminOAuthClientResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/oauth/clients [post]
+func (h *AdminHTTPHandler) CreateOAuthClientHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling create OAuth client request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	var req dto.AdminCreateOAuthClientRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+			ports.F("request", req),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	if err := validators.ValidateCreateOAuthClientRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	client, err := h.svc.CreateOAuthClient(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, client)
+}
+
+// ListAuditEventsHandler godoc
+// @Summary List audit events
+// @Description List logins, logouts, token refreshes, profile and password changes, and admin actions on users, newest first. Pass next_cursor as cursor to get the next page (requires the audit:read permission).
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Event type, e.g. auth.login.failed"
+// @Param actor_id query string false "ID of the user who acted"
+// @Param target_id query string false "ID of the user acted on"
+// @Param from query string false "Earliest time, RFC 3339"
+// @Param to query string false "Time before which events are listed, RFC 3339"
+// @Param limit query int false "Events per page, 1 to 200, default 50"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} dto.AdminAuditEventListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/audit [get]
+func (h *AdminHTTPHandler) ListAuditEventsHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling list audit events request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	var req dto.AdminAuditEventsRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", err),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidAuditFilter,
+		})
+		return
+	}
+
+	if err := validators.ValidateListAuditEventsRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	resp, err := h.svc.ListAuditEvents(ctx, &req)
+	if err != nil {
+		if errors.IsValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// CreateOrganizationHandler godoc
+// @Summary Create an organization
+// @Description Create an organization with its own directory of users, and its first user, a super admin who manages them. Clients name the organization with its slug in the X-Organization header (requires the organizations:write permission, for admins of the default organization).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.AdminCreateOrganizationRequest true "Organization"
+// @Success 201 {object} dto.AdminOrganizationResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/organizations [post]
+func (h *AdminHTTPHandler) CreateOrganizationHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling create organization request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	var req dto.AdminCreateOrganizationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	if err := validators.ValidateCreateOrganizationRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	organization, err := h.svc.CreateOrganization(ctx, &req)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, organization)
+}
+
+// ListOrganizationsHandler godoc
+// @Summary List organizations
+// @Description List every organization, oldest first (requires the organizations:read permission, for admins of the default organization).
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/organizations [get]
+func (h *AdminHTTPHandler) ListOrganizationsHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling list organizations request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	organizations, err := h.svc.ListOrganizations(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": organizations})
+}
+
+// principal returns the authenticated user making the request
+func principal(c *gin.Context) (*entities.Principal, bool) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		return nil, false
+	}
+	return &entities.Principal{
+		UserID: userID,
+		Role:   entities.ParseRoleType(c.GetString("role")),
+	}, true
+}
+
+// adminErrorStatus returns the status code for an error from the admin
+// service
+func adminErrorStatus(err error) int {
+	switch {
+	case errors.IsValidationError(err):
+		return http.StatusBadRequest
+	case errors.IsAuthorizationError(err):
+		return http.StatusForbidden
+	case errors.IsNotFoundError(err):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// splitQueryValues splits the comma separated values of a repeated query
+// parameter
+func splitQueryValues(values []string) []string {
+	var split []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				split = append(split, part)
+			}
+		}
+	}
+	return split
 }
@@ -0,0 +1,620 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAdminService struct {
+	mock.Mock
+}
+
+func (m *MockAdminService) GetUsers(ctx context.Context, req *dto.AdminGetUsersRequest) (*dto.AdminUserListResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AdminUserListResponse), args.Error(1)
+}
+
+func (m *MockAdminService) AdminGetUserByID(ctx context.Context, userID *uuid.UUID) (*dto.AdminUserResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AdminUserResponse), args.Error(1)
+}
+
+func (m *MockAdminService) AdminUpdateUser(ctx context.Context, actor *entities.Principal, userID *uuid.UUID, updateReq *dto.AdminUserUpdateRequest) error {
+	args := m.Called(actor, userID, updateReq)
+	return args.Error(0)
+}
+
+func (m *MockAdminService) ChangeUserRole(ctx context.Context, actor *entities.Principal, userID *uuid.UUID, updateRole *entities.RoleType) error {
+	args := m.Called(actor, userID, updateRole)
+	return args.Error(0)
+}
+
+func (m *MockAdminService) ChangeUserStatus(ctx context.Context, actor *entities.Principal, userID *uuid.UUID, updateStatus *entities.StatusType) error {
+	args := m.Called(actor, userID, updateStatus)
+	return args.Error(0)
+}
+
+func (m *MockAdminService) AdminDeleteUser(ctx context.Context, actor *entities.Principal, userID *uuid.UUID) error {
+	args := m.Called(actor, userID)
+	return args.Error(0)
+}
+
+func (m *MockAdminService) ResetUserMFA(ctx context.Context, actor *entities.Principal, userID *uuid.UUID) error {
+	args := m.Called(actor, userID)
+	return args.Error(0)
+}
+
+func (m *MockAdminService) UnlockUser(ctx context.Context, actor *entities.Principal, userID *uuid.UUID) error {
+	args := m.Called(actor, userID)
+	return args.Error(0)
+}
+
+func (m *MockAdminService) BulkUpdateUsers(ctx context.Context, actor *entities.Principal, req *dto.AdminBulkUsersRequest) (*dto.AdminBulkUsersResponse, error) {
+	args := m.Called(actor, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AdminBulkUsersResponse), args.Error(1)
+}
+
+func (m *MockAdminService) ExportUsers(ctx context.Context, actor *entities.Principal, req *dto.AdminExportUsersRequest, write func(user *dto.AdminUserResponse) error) error {
+	args := m.Called(actor, req, write)
+	return args.Error(0)
+}
+
+func (m *MockAdminService) ImportUsers(ctx context.Context, actor *entities.Principal, rows []dto.AdminImportUserRow) (*dto.AdminImportUsersResponse, error) {
+	args := m.Called(actor, rows)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AdminImportUsersResponse), args.Error(1)
+}
+
+func (m *MockAdminService) RotateSigningKey(ctx context.Context) (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAdminService) CreateOAuthClient(ctx context.Context, req *dto.AdminCreateOAuthClientRequest) (*dto.AdminOAuthClientResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AdminOAuthClientResponse), args.Error(1)
+}
+
+func (m *MockAdminService) ListAuditEvents(ctx context.Context, req *dto.AdminAuditEventsRequest) (*dto.AdminAuditEventListResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AdminAuditEventListResponse), args.Error(1)
+}
+
+func (m *MockAdminService) CreateOrganization(ctx context.Context, req *dto.AdminCreateOrganizationRequest) (*dto.AdminOrganizationResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AdminOrganizationResponse), args.Error(1)
+}
+
+func (m *MockAdminService) ListOrganizations(ctx context.Context) ([]dto.AdminOrganizationResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.AdminOrganizationResponse), args.Error(1)
+}
+
+// newTestAdminRouter serves the admin endpoints of a handler using svc, as
+// a user with role who was granted permissions
+func newTestAdminRouter(svc *MockAdminService, role entities.RoleType, permissions ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	handler := &AdminHTTPHandler{
+		svc:    svc,
+		logger: newTestLogger(),
+		hasPermission: func(c *gin.Context, permission string) (bool, error) {
+			return slices.Contains(permissions, permission), nil
+		},
+	}
+	authenticated := func(c *gin.Context) {
+		c.Set("user_id", testAdminID.String())
+		c.Set("role", role.String())
+	}
+
+	r := gin.New()
+	r.Use(authenticated)
+	r.POST("/admin/oauth/clients", handler.CreateOAuthClientHandler)
+	r.POST("/admin/users/bulk", handler.BulkUpdateUsersHandler)
+	r.GET("/admin/users/export", handler.ExportUsersHandler)
+	r.POST("/admin/users/import", handler.ImportUsersHandler)
+	return r
+}
+
+var testAdminID = uuid.MustParse("0b9d3c55-1f2a-4d6e-8c7b-9a0e1f2d3c4b")
+
+// newJSONRequest returns a request with body encoded as JSON
+func newJSONRequest(method, path string, body interface{}) *http.Request {
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+// TestCreateOAuthClientHandler tests that a valid client is registered and its secret returned once
+func TestCreateOAuthClientHandler(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		requestBody    map[string]interface{}
+		mockSetup      func(*MockAdminService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name: "confidential service client",
+			requestBody: map[string]interface{}{
+				"name":         "Billing",
+				"scopes":       []string{"openid"},
+				"grant_types":  []string{"client_credentials"},
+				"confidential": true,
+			},
+			mockSetup: func(m *MockAdminService) {
+				m.On("CreateOAuthClient", mock.MatchedBy(func(req *dto.AdminCreateOAuthClientRequest) bool {
+					return req.Name == "Billing" && req.Confidential
+				})).Return(&dto.AdminOAuthClientResponse{
+					ClientID:       "billing",
+					ClientSecret:   "secret",
+					OrganizationID: entities.DefaultOrganizationID.String(),
+					Name:           "Billing",
+					RedirectURIs:   []string{},
+					Scopes:         []string{"openid"},
+					GrantTypes:     []string{"client_credentials"},
+					CreatedAt:      createdAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: map[string]interface{}{
+				"client_id":       "billing",
+				"client_secret":   "secret",
+				"organization_id": entities.DefaultOrganizationID.String(),
+				"name":            "Billing",
+				"redirect_uris":   []interface{}{},
+				"scopes":          []interface{}{"openid"},
+				"grant_types":     []interface{}{"client_credentials"},
+				"created_at":      "2026-01-02T03:04:05Z",
+			},
+		},
+		{
+			name: "client credentials for a public client",
+			requestBody: map[string]interface{}{
+				"name":        "Billing",
+				"grant_types": []string{"client_credentials"},
+			},
+			mockSetup:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidGrantTypes),
+		},
+		{
+			name:           "missing name",
+			requestBody:    map[string]interface{}{"redirect_uris": []string{"https://app.example.com/callback"}},
+			mockSetup:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidRequest),
+		},
+		{
+			name: "service error",
+			requestBody: map[string]interface{}{
+				"name":          "Web",
+				"redirect_uris": []string{"https://app.example.com/callback"},
+			},
+			mockSetup: func(m *MockAdminService) {
+				m.On("CreateOAuthClient", mock.Anything).Return(nil, errors.ErrCreateOAuthClient).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   errorBody(errors.ErrCreateOAuthClient),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAdminService)
+			tt.mockSetup(mockSvc)
+
+			w := httptest.NewRecorder()
+			newTestAdminRouter(mockSvc, entities.SuperAdminRole).
+				ServeHTTP(w, newJSONRequest(http.MethodPost, "/admin/oauth/clients", tt.requestBody))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, decodeBody(t, w))
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+// TestBulkUpdateUsersHandler tests that a bulk action needs the permission of the action
+func TestBulkUpdateUsersHandler(t *testing.T) {
+	userIDs := []string{
+		"3d1f7a52-6c0e-4b8a-9f21-5e7d3c1b0a94",
+		"8e2b4c61-0d7f-4a39-b5e8-1c6f9a2d7e03",
+	}
+
+	tests := []struct {
+		name           string
+		permissions    []string
+		requestBody    map[string]interface{}
+		mockSetup      func(*MockAdminService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:        "users deactivated",
+			permissions: []string{entities.PermissionUsersStatusChange},
+			requestBody: map[string]interface{}{
+				"user_ids": userIDs,
+				"action":   entities.BulkActionChangeStatus,
+				"status":   "deactivated",
+			},
+			mockSetup: func(m *MockAdminService) {
+				m.On("BulkUpdateUsers", &entities.Principal{UserID: testAdminID, Role: entities.AdminRole}, &dto.AdminBulkUsersRequest{
+					UserIDs: userIDs,
+					Action:  entities.BulkActionChangeStatus,
+					Status:  "deactivated",
+				}).Return(&dto.AdminBulkUsersResponse{
+					Action:    entities.BulkActionChangeStatus,
+					Succeeded: 1,
+					Failed:    1,
+					Results: []dto.AdminBulkUserResult{
+						{UserID: userIDs[0], Succeeded: true},
+						{UserID: userIDs[1], Error: errors.ErrForbidden},
+					},
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"data": map[string]interface{}{
+					"action":    entities.BulkActionChangeStatus,
+					"dry_run":   false,
+					"succeeded": float64(1),
+					"failed":    float64(1),
+					"results": []interface{}{
+						map[string]interface{}{"user_id": userIDs[0], "succeeded": true},
+						map[string]interface{}{"user_id": userIDs[1], "succeeded": false, "error": errorBody(errors.ErrForbidden)["error"]},
+					},
+				},
+			},
+		},
+		{
+			name:        "permission of another action",
+			permissions: []string{entities.PermissionUsersStatusChange},
+			requestBody: map[string]interface{}{
+				"user_ids": userIDs,
+				"action":   entities.BulkActionDelete,
+			},
+			mockSetup:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   errorBody(errors.ErrForbidden),
+		},
+		{
+			name:        "invalid user ID",
+			permissions: []string{entities.PermissionUsersDelete},
+			requestBody: map[string]interface{}{
+				"user_ids": []string{"not-a-uuid"},
+				"action":   entities.BulkActionDelete,
+			},
+			mockSetup:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidBulkUsers),
+		},
+		{
+			name:        "unknown action",
+			permissions: []string{entities.PermissionUsersDelete},
+			requestBody: map[string]interface{}{
+				"user_ids": userIDs,
+				"action":   "promote",
+			},
+			mockSetup:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidBulkAction),
+		},
+		{
+			name:        "service error",
+			permissions: []string{entities.PermissionUsersDelete},
+			requestBody: map[string]interface{}{
+				"user_ids": userIDs,
+				"action":   entities.BulkActionDelete,
+			},
+			mockSetup: func(m *MockAdminService) {
+				m.On("BulkUpdateUsers", mock.Anything, mock.Anything).Return(nil, errors.ErrUpdateUser).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   errorBody(errors.ErrUpdateUser),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAdminService)
+			tt.mockSetup(mockSvc)
+
+			w := httptest.NewRecorder()
+			newTestAdminRouter(mockSvc, entities.AdminRole, tt.permissions...).
+				ServeHTTP(w, newJSONRequest(http.MethodPost, "/admin/users/bulk", tt.requestBody))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, decodeBody(t, w))
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+// TestExportUsersHandler tests that users are streamed in the requested format
+func TestExportUsersHandler(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	users := []*dto.AdminUserResponse{
+		{
+			ID:          "3d1f7a52-6c0e-4b8a-9f21-5e7d3c1b0a94",
+			PhoneNumber: "09123456789",
+			FirstName:   "Sara",
+			LastName:    "Ahmadi",
+			Email:       "sara@example.com",
+			Status:      "active",
+			Role:        "user",
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+		},
+		{
+			ID:          "8e2b4c61-0d7f-4a39-b5e8-1c6f9a2d7e03",
+			PhoneNumber: "09129876543",
+			FirstName:   "=HYPERLINK()",
+			Status:      "active",
+			Role:        "user",
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+		},
+	}
+	// exportUsers has the service write users until writing fails
+	exportUsers := func(users []*dto.AdminUserResponse) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			write := args.Get(2).(func(user *dto.AdminUserResponse) error)
+			for _, user := range users {
+				if write(user) != nil {
+					return
+				}
+			}
+		}
+	}
+
+	tests := []struct {
+		name                string
+		query               string
+		mockSetup           func(*MockAdminService)
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:  "csv",
+			query: "?format=csv",
+			mockSetup: func(m *MockAdminService) {
+				m.On("ExportUsers", &entities.Principal{UserID: testAdminID, Role: entities.AdminRole}, mock.MatchedBy(func(req *dto.AdminExportUsersRequest) bool {
+					return slices.Equal(req.Status, []string{"active"}) && slices.Equal(req.Role, []string{"user"})
+				}), mock.Anything).Run(exportUsers(users)).Return(nil).Once()
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody: "id,phone_number,first_name,last_name,email,status,role,created_at,updated_at\n" +
+				"3d1f7a52-6c0e-4b8a-9f21-5e7d3c1b0a94,09123456789,Sara,Ahmadi,sara@example.com,active,user,2026-01-02T03:04:05Z,2026-01-02T03:04:05Z\n" +
+				"8e2b4c61-0d7f-4a39-b5e8-1c6f9a2d7e03,09129876543,'=HYPERLINK(),,,active,user,2026-01-02T03:04:05Z,2026-01-02T03:04:05Z\n",
+		},
+		{
+			name:  "csv without users",
+			query: "?format=csv&status=deactivated",
+			mockSetup: func(m *MockAdminService) {
+				m.On("ExportUsers", mock.Anything, mock.MatchedBy(func(req *dto.AdminExportUsersRequest) bool {
+					return slices.Equal(req.Status, []string{"deactivated"})
+				}), mock.Anything).Return(nil).Once()
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "id,phone_number,first_name,last_name,email,status,role,created_at,updated_at\n",
+		},
+		{
+			name:  "ndjson of several roles",
+			query: "?format=ndjson&role=user,admin",
+			mockSetup: func(m *MockAdminService) {
+				m.On("ExportUsers", mock.Anything, mock.MatchedBy(func(req *dto.AdminExportUsersRequest) bool {
+					return slices.Equal(req.Role, []string{"user", "admin"})
+				}), mock.Anything).Run(exportUsers(users[:1])).Return(nil).Once()
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody: `{"id":"3d1f7a52-6c0e-4b8a-9f21-5e7d3c1b0a94","phone_number":"09123456789","first_name":"Sara","last_name":"Ahmadi",` +
+				`"email":"sara@example.com","status":"active","role":"user","created_at":"2026-01-02T03:04:05Z","updated_at":"2026-01-02T03:04:05Z"}` + "\n",
+		},
+		{
+			name:                "missing format",
+			query:               "",
+			mockSetup:           func(m *MockAdminService) {},
+			expectedStatus:      http.StatusBadRequest,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        `{"error":` + mustMarshal(errors.ErrInvalidTransferFormat) + `}`,
+		},
+		{
+			name:  "service error before the first user",
+			query: "?format=csv",
+			mockSetup: func(m *MockAdminService) {
+				m.On("ExportUsers", mock.Anything, mock.Anything, mock.Anything).Return(errors.ErrGetUsers).Once()
+			},
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        `{"error":` + mustMarshal(errors.ErrGetUsers) + `}`,
+		},
+		{
+			name:  "service error after the first user",
+			query: "?format=ndjson",
+			mockSetup: func(m *MockAdminService) {
+				m.On("ExportUsers", mock.Anything, mock.Anything, mock.Anything).Run(exportUsers(users[:1])).Return(errors.ErrGetUsers).Once()
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody: `{"id":"3d1f7a52-6c0e-4b8a-9f21-5e7d3c1b0a94","phone_number":"09123456789","first_name":"Sara","last_name":"Ahmadi",` +
+				`"email":"sara@example.com","status":"active","role":"user","created_at":"2026-01-02T03:04:05Z","updated_at":"2026-01-02T03:04:05Z"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAdminService)
+			tt.mockSetup(mockSvc)
+
+			w := httptest.NewRecorder()
+			newTestAdminRouter(mockSvc, entities.AdminRole).
+				ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users/export"+tt.query, nil))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedBody, w.Body.String())
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+// TestImportUsersHandler tests that rows failing validation are reported with the service's errors in file order
+func TestImportUsersHandler(t *testing.T) {
+	csvFile := "phone_number,password,first_name\n" +
+		"09123456789,Test123!@#,Sara\n" +
+		"12345,Test123!@#,Ali\n" +
+		"09129876543,,Reza\n" +
+		"09121112233,Test123!@#,Maryam\n"
+
+	tests := []struct {
+		name           string
+		query          string
+		body           string
+		mockSetup      func(*MockAdminService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:  "csv with invalid rows",
+			query: "?format=csv",
+			body:  csvFile,
+			mockSetup: func(m *MockAdminService) {
+				m.On("ImportUsers", &entities.Principal{UserID: testAdminID, Role: entities.AdminRole}, []dto.AdminImportUserRow{
+					{Row: 1, PhoneNumber: "09123456789", Password: "Test123!@#", FirstName: "Sara"},
+					{Row: 4, PhoneNumber: "09121112233", Password: "Test123!@#", FirstName: "Maryam"},
+				}).Return(&dto.AdminImportUsersResponse{
+					Imported: 1,
+					Failed:   1,
+					Errors:   []dto.AdminImportRowError{{Row: 4, Error: errors.ErrDuplicatePhoneNumber}},
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"data": map[string]interface{}{
+					"imported": float64(1),
+					"failed":   float64(3),
+					"errors": []interface{}{
+						map[string]interface{}{"row": float64(2), "error": errorBody(errors.ErrInvalidPhoneNumber)["error"]},
+						map[string]interface{}{"row": float64(3), "error": errorBody(errors.ErrImportPassword)["error"]},
+						map[string]interface{}{"row": float64(4), "error": errorBody(errors.ErrDuplicatePhoneNumber)["error"]},
+					},
+				},
+			},
+		},
+		{
+			name:  "ndjson",
+			query: "?format=ndjson",
+			body:  `{"phone_number":"09123456789","password":"Test123!@#","role":"admin"}` + "\n",
+			mockSetup: func(m *MockAdminService) {
+				m.On("ImportUsers", mock.Anything, []dto.AdminImportUserRow{
+					{Row: 1, PhoneNumber: "09123456789", Password: "Test123!@#", Role: "admin"},
+				}).Return(&dto.AdminImportUsersResponse{Imported: 1, Errors: []dto.AdminImportRowError{}}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"data": map[string]interface{}{
+					"imported": float64(1),
+					"failed":   float64(0),
+					"errors":   []interface{}{},
+				},
+			},
+		},
+		{
+			name:           "csv without phone number column",
+			query:          "?format=csv",
+			body:           "mobile,password\n09123456789,Test123!@#\n",
+			mockSetup:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidImportFile),
+		},
+		{
+			name:           "unknown format",
+			query:          "?format=xlsx",
+			body:           csvFile,
+			mockSetup:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidTransferFormat),
+		},
+		{
+			name:  "service error",
+			query: "?format=csv",
+			body:  csvFile,
+			mockSetup: func(m *MockAdminService) {
+				m.On("ImportUsers", mock.Anything, mock.Anything).Return(nil, errors.ErrCreateUser).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   errorBody(errors.ErrCreateUser),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAdminService)
+			tt.mockSetup(mockSvc)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/import"+tt.query, strings.NewReader(tt.body))
+			newTestAdminRouter(mockSvc, entities.AdminRole).ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, decodeBody(t, w))
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+// mustMarshal returns v encoded as JSON
+func mustMarshal(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
@@ -2,8 +2,10 @@ package controller
 
 import (
 	"context"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 
 	"github.com/amirdashtii/go_auth/controller/dto"
 	"github.com/amirdashtii/go_auth/controller/middleware"
@@ -44,10 +46,30 @@ func NewAuthRoutes(r *gin.Engine) {
 	h := NewAuthHTTPHandler()
 
 	authGroup := r.Group("/auth")
+	authGroup.Use(middleware.RateLimit("auth"))
 	authGroup.POST("/register", h.RegisterHandler)
 	authGroup.POST("/login", h.LoginHandler)
-	authGroup.POST("/logout", middleware.AuthMiddleware(), h.LogoutHandler)
+	authGroup.POST("/logout", middleware.SessionAuthMiddleware(), h.LogoutHandler)
+	authGroup.POST("/logout-all", middleware.SessionAuthMiddleware(), h.LogoutAllHandler)
 	authGroup.POST("/refresh-token", h.RefreshTokenHandler)
+	authGroup.POST("/otp/request", h.RequestOTPHandler)
+	authGroup.POST("/otp/verify", h.VerifyOTPHandler)
+	authGroup.POST("/password/forgot", h.ForgotPasswordHandler)
+	authGroup.POST("/password/reset", h.ResetPasswordHandler)
+	authGroup.GET("/email/verify", h.VerifyEmailHandler)
+	authGroup.POST("/mfa/verify", h.VerifyMFAHandler)
+	authGroup.POST("/webauthn/login/options", h.WebAuthnLoginOptionsHandler)
+	authGroup.POST("/webauthn/login/finish", h.WebAuthnLoginFinishHandler)
+
+	oauthRateLimit := middleware.RateLimit("oauth")
+
+	r.GET("/.well-known/jwks.json", oauthRateLimit, h.JWKSHandler)
+
+	r.GET("/.well-known/openid-configuration", oauthRateLimit, h.OpenIDConfigurationHandler)
+	r.GET("/authorize", oauthRateLimit, middleware.SessionAuthMiddleware(), h.AuthorizeHandler)
+	r.POST("/token", oauthRateLimit, h.TokenHandler)
+	r.POST("/oauth/token", oauthRateLimit, h.TokenHandler)
+	r.GET("/userinfo", oauthRateLimit, middleware.UserInfoAuthMiddleware(), h.UserInfoHandler)
 }
 
 // RegisterHandler godoc
@@ -110,7 +132,7 @@ func (h *AuthHTTPHandler) RegisterHandler(c *gin.Context) {
 
 // LoginHandler godoc
 // @Summary Login user
-// @Description Login user with phone number and password
+// @Description Login user with phone number and password. Users with two-factor authentication get an mfa_token to finish logging in at /auth/mfa/verify.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -118,6 +140,7 @@ func (h *AuthHTTPHandler) RegisterHandler(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /auth/login [post]
 func (h *AuthHTTPHandler) LoginHandler(c *gin.Context) {
@@ -157,18 +180,27 @@ func (h *AuthHTTPHandler) LoginHandler(c *gin.Context) {
 
 	tokens, err := h.svc.Login(ctx, req)
 	if err != nil {
+		if errors.IsRateLimitError(err) {
+			respondRateLimited(c, err)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err,
 		})
 		return
 	}
 
+	if tokens.MFAToken != "" {
+		c.JSON(http.StatusOK, gin.H{"mfa_required": true, "mfa_token": tokens.MFAToken})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
 }
 
 // LogoutHandler godoc
 // @Summary Logout user
-// @Description Logout user and invalidate tokens
+// @Description Logout the current session and invalidate its tokens
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -216,7 +248,9 @@ func (h *AuthHTTPHandler) LogoutHandler(c *gin.Context) {
 		return
 	}
 
-	err := h.svc.Logout(ctx, userIDStr)
+	sessionID := c.GetString("session_id")
+
+	err := h.svc.Logout(ctx, userIDStr, sessionID)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -230,6 +264,67 @@ func (h *AuthHTTPHandler) LogoutHandler(c *gin.Context) {
 	})
 }
 
+// LogoutAllHandler godoc
+// @Summary Logout from all devices
+// @Description Logout every session of the current user and invalidate their tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/logout-all [post]
+func (h *AuthHTTPHandler) LogoutAllHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling logout all request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		h.logger.Error("Invalid user ID type",
+			ports.F("error", errors.ErrInvalidUserIDType.Message.English),
+			ports.F("user_id", userID),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrInvalidUserIDType,
+		})
+		return
+	}
+
+	if err := h.svc.LogoutAll(ctx, userIDStr); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out from all devices successfully",
+	})
+}
+
 // RefreshTokenHandler godoc
 // @Summary Refresh access token
 // @Description Get new access token using refresh token
@@ -279,6 +374,497 @@ func (h *AuthHTTPHandler) RefreshTokenHandler(c *gin.Context) {
 
 	tokens, err := h.svc.RefreshToken(ctx, req.RefreshToken)
 	if err != nil {
+		if errors.IsAuthenticationError(err) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
+}
+
+// RequestOTPHandler godoc
+// @Summary Request a login code
+// @Description Send a one-time login code to the given phone number by SMS
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.OTPRequest true "OTP Request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/otp/request [post]
+func (h *AuthHTTPHandler) RequestOTPHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling OTP request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	var req dto.OTPRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+			ports.F("request", req),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	if err := validators.ValidateOTPRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if err := h.svc.RequestOTP(ctx, &req); err != nil {
+		if errors.IsRateLimitError(err) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verification code sent"})
+}
+
+// VerifyOTPHandler godoc
+// @Summary Login with a code
+// @Description Verify a one-time login code and get access and refresh tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.OTPVerifyRequest true "OTP Verify Request"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/otp/verify [post]
+func (h *AuthHTTPHandler) VerifyOTPHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling OTP verify request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	var req dto.OTPVerifyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	if err := validators.ValidateOTPVerifyRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	tokens, err := h.svc.VerifyOTP(ctx, &req)
+	if err != nil {
+		if errors.IsRateLimitError(err) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error": err,
+			})
+			return
+		}
+		if errors.IsAuthenticationError(err) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if tokens.MFAToken != "" {
+		c.JSON(http.StatusOK, gin.H{"mfa_required": true, "mfa_token": tokens.MFAToken})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
+}
+
+// ForgotPasswordHandler godoc
+// @Summary Request a password reset code
+// @Description Send a short-lived password reset code to the owner of the phone number. The response is the same whether or not the account exists.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordRequest true "Forgot Password Request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/password/forgot [post]
+func (h *AuthHTTPHandler) ForgotPasswordHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling forgot password request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	var req dto.ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	if err := validators.ValidateForgotPasswordRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if err := h.svc.ForgotPassword(ctx, &req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "If an account exists for this phone number, a reset code has been sent"})
+}
+
+// ResetPasswordHandler godoc
+// @Summary Reset the password with a code
+// @Description Set a new password with the code from /auth/password/forgot. Every session of the user is signed out.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordRequest true "Reset Password Request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/password/reset [post]
+func (h *AuthHTTPHandler) ResetPasswordHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling reset password request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	var req dto.ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	if err := validators.ValidateResetPasswordRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if err := h.svc.ResetPassword(ctx, &req); err != nil {
+		if errors.IsRateLimitError(err) {
+			respondRateLimited(c, err)
+			return
+		}
+		if errors.IsAuthenticationError(err) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
+// VerifyEmailHandler godoc
+// @Summary Confirm an email address
+// @Description Confirm the email address a verification link was sent to. A pending address replaces the current one.
+// @Tags auth
+// @Produce json
+// @Param token query string true "Verification token from the link"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/email/verify [get]
+func (h *AuthHTTPHandler) VerifyEmailHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling email verification",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	token := c.Query("token")
+	if token == "" {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	if err := h.svc.VerifyEmail(ctx, token); err != nil {
+		if errors.IsAuthenticationError(err) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err,
+			})
+			return
+		}
+		if errors.IsValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+}
+
+// VerifyMFAHandler godoc
+// @Summary Finish login with a second factor
+// @Description Exchange the mfa_token from login and an authenticator or recovery code for access and refresh tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.MFAVerifyRequest true "MFA Verify Request"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/mfa/verify [post]
+func (h *AuthHTTPHandler) VerifyMFAHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling MFA verify request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	var req dto.MFAVerifyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	if err := validators.ValidateMFAVerifyRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	tokens, err := h.svc.VerifyMFA(ctx, &req)
+	if err != nil {
+		if errors.IsRateLimitError(err) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error": err,
+			})
+			return
+		}
+		if errors.IsAuthenticationError(err) || errors.IsNotFoundError(err) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
+}
+
+// WebAuthnLoginOptionsHandler godoc
+// @Summary Start passkey login
+// @Description Get the options to pass to navigator.credentials.get() for signing in with a passkey
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.WebAuthnAssertionOptions
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/webauthn/login/options [post]
+func (h *AuthHTTPHandler) WebAuthnLoginOptionsHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling WebAuthn login options request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	options, err := h.svc.BeginWebAuthnLogin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// WebAuthnLoginFinishHandler godoc
+// @Summary Finish passkey login
+// @Description Exchange the credential navigator.credentials.get() returned for access and refresh tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.WebAuthnLoginRequest true "WebAuthn Login Request"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/webauthn/login/finish [post]
+func (h *AuthHTTPHandler) WebAuthnLoginFinishHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling WebAuthn login finish request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	var req dto.WebAuthnLoginRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	tokens, err := h.svc.FinishWebAuthnLogin(ctx, &req)
+	if err != nil {
+		if errors.IsAuthenticationError(err) || errors.IsNotFoundError(err) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err,
+			})
+			return
+		}
+		if errors.IsValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err,
 		})
@@ -287,3 +873,39 @@ func (h *AuthHTTPHandler) RefreshTokenHandler(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
 }
+
+// JWKSHandler godoc
+// @Summary Get token signing keys
+// @Description Get the public keys that verify access tokens as a JSON Web Key Set
+// @Tags auth
+// @Produce json
+// @Success 200 {object} entities.JSONWebKeySet
+// @Failure 500 {object} map[string]interface{}
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHTTPHandler) JWKSHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	keySet, err := h.svc.JWKS(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, keySet)
+}
+
+// respondRateLimited writes a 429 response for err, with a Retry-After header
+// and retry_after field in seconds when the error says how long to wait
+func respondRateLimited(c *gin.Context, err error) {
+	response := gin.H{"error": err}
+	if retryAfter, ok := errors.RetryAfter(err); ok {
+		seconds := int64(math.Ceil(retryAfter.Seconds()))
+		c.Header("Retry-After", strconv.FormatInt(seconds, 10))
+		response["retry_after"] = seconds
+	}
+	c.JSON(http.StatusTooManyRequests, response)
+}
@@ -4,13 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/infrastructure/logger"
 	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -33,8 +37,8 @@ func (m *MockAuthService) Login(ctx context.Context, req *dto.LoginRequest) (*en
 	return args.Get(0).(*entities.TokenPair), args.Error(1)
 }
 
-func (m *MockAuthService) Logout(ctx context.Context, userID string) error {
-	args := m.Called(userID)
+func (m *MockAuthService) Logout(ctx context.Context, userID, sessionID string) error {
+	args := m.Called(userID, sessionID)
 	return args.Error(0)
 }
 
@@ -46,11 +50,223 @@ func (m *MockAuthService) RefreshToken(ctx context.Context, refreshToken string)
 	return args.Get(0).(*entities.TokenPair), args.Error(1)
 }
 
-func (m *MockAuthService) ValidateToken(ctx context.Context, token string, userID string) error {
-	args := m.Called(token, userID)
+func (m *MockAuthService) ValidateToken(ctx context.Context, userID, sessionID, token string) error {
+	args := m.Called(userID, sessionID, token)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]dto.SessionResponse, error) {
+	args := m.Called(userID, currentSessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.SessionResponse), args.Error(1)
+}
+
+func (m *MockAuthService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	args := m.Called(userID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) LogoutAll(ctx context.Context, userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) JWKS(ctx context.Context) (*entities.JSONWebKeySet, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.JSONWebKeySet), args.Error(1)
+}
+
+func (m *MockAuthService) ValidateClientToken(ctx context.Context, clientID, tokenID string) error {
+	args := m.Called(clientID, tokenID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) OpenIDConfiguration(ctx context.Context) (*dto.OpenIDConfiguration, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.OpenIDConfiguration), args.Error(1)
+}
+
+func (m *MockAuthService) Authorize(ctx context.Context, userID string, req *dto.AuthorizeRequest) (string, error) {
+	args := m.Called(userID, req)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthService) ExchangeToken(ctx context.Context, req *dto.TokenRequest) (*dto.TokenResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.TokenResponse), args.Error(1)
+}
+
+func (m *MockAuthService) UserInfo(ctx context.Context, userID, sessionID string) (map[string]interface{}, error) {
+	args := m.Called(userID, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]interface{}), args.Error(1)
+}
+
+func (m *MockAuthService) CreateAPIKey(ctx context.Context, userID string, req *dto.CreateAPIKeyRequest) (*dto.APIKeyResponse, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.APIKeyResponse), args.Error(1)
+}
+
+func (m *MockAuthService) ListAPIKeys(ctx context.Context, userID string) ([]dto.APIKeyResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.APIKeyResponse), args.Error(1)
+}
+
+func (m *MockAuthService) RevokeAPIKey(ctx context.Context, userID, keyID string) error {
+	args := m.Called(userID, keyID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) AuthenticateAPIKey(ctx context.Context, key string) (*entities.User, *entities.APIKey, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*entities.User), args.Get(1).(*entities.APIKey), args.Error(2)
+}
+
+func (m *MockAuthService) EnrollTOTP(ctx context.Context, userID string) (*dto.TOTPEnrollmentResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.TOTPEnrollmentResponse), args.Error(1)
+}
+
+func (m *MockAuthService) ConfirmTOTP(ctx context.Context, userID, code string) (*dto.RecoveryCodesResponse, error) {
+	args := m.Called(userID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RecoveryCodesResponse), args.Error(1)
+}
+
+func (m *MockAuthService) VerifyMFA(ctx context.Context, req *dto.MFAVerifyRequest) (*entities.TokenPair, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthService) BeginWebAuthnRegistration(ctx context.Context, userID string) (*dto.WebAuthnRegistrationOptions, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.WebAuthnRegistrationOptions), args.Error(1)
+}
+
+func (m *MockAuthService) FinishWebAuthnRegistration(ctx context.Context, userID string, req *dto.WebAuthnRegistrationRequest) (*dto.WebAuthnCredentialResponse, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.WebAuthnCredentialResponse), args.Error(1)
+}
+
+func (m *MockAuthService) BeginWebAuthnLogin(ctx context.Context) (*dto.WebAuthnAssertionOptions, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.WebAuthnAssertionOptions), args.Error(1)
+}
+
+func (m *MockAuthService) FinishWebAuthnLogin(ctx context.Context, req *dto.WebAuthnLoginRequest) (*entities.TokenPair, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthService) ForgotPassword(ctx context.Context, req *dto.ForgotPasswordRequest) error {
+	args := m.Called(req)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResetPassword(ctx context.Context, req *dto.ResetPasswordRequest) error {
+	args := m.Called(req)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) RequestEmailVerification(ctx context.Context, userID string) error {
+	args := m.Called(userID)
 	return args.Error(0)
 }
 
+func (m *MockAuthService) VerifyEmail(ctx context.Context, token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) RequestPhoneNumberChange(ctx context.Context, userID string, req *dto.ChangePhoneNumberRequest) error {
+	args := m.Called(userID, req)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ConfirmPhoneNumberChange(ctx context.Context, userID string, req *dto.ConfirmPhoneNumberRequest) error {
+	args := m.Called(userID, req)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) RequestOTP(ctx context.Context, req *dto.OTPRequest) error {
+	args := m.Called(req)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) VerifyOTP(ctx context.Context, req *dto.OTPVerifyRequest) (*entities.TokenPair, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.TokenPair), args.Error(1)
+}
+
+func newTestLogger() ports.Logger {
+	return logger.NewZerologLogger(ports.LoggerConfig{
+		Level:       "fatal",
+		Environment: "test",
+		ServiceName: "go_auth",
+		Output:      io.Discard,
+	})
+}
+
+// errorBody is the JSON body of a response reporting err
+func errorBody(err error) map[string]interface{} {
+	data, _ := json.Marshal(gin.H{"error": err})
+	var body map[string]interface{}
+	_ = json.Unmarshal(data, &body)
+	return body
+}
+
+// decodeBody decodes the JSON body of a response
+func decodeBody(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body
+}
+
 func TestRegisterHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -82,10 +298,7 @@ func TestRegisterHandler(t *testing.T) {
 			},
 			mockSetup:      func(m *MockAuthService) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: map[string]interface{}{
-				"error":   "Invalid request format",
-				"details": "Key: 'RegisterRequest.Password' Error:Field validation for 'Password' failed on the 'required' tag",
-			},
+			expectedBody:   errorBody(errors.ErrInvalidRequest),
 		},
 		{
 			name: "registration service error",
@@ -94,13 +307,10 @@ func TestRegisterHandler(t *testing.T) {
 				"password":     "Test123!@#",
 			},
 			mockSetup: func(m *MockAuthService) {
-				m.On("Register", mock.AnythingOfType("*dto.RegisterRequest")).Return(errors.New("registration failed"))
-			},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody: map[string]interface{}{
-				"error":   "Registration failed",
-				"details": "registration failed",
+				m.On("Register", mock.AnythingOfType("*dto.RegisterRequest")).Return(errors.ErrCreateUser)
 			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   errorBody(errors.ErrCreateUser),
 		},
 	}
 
@@ -109,7 +319,7 @@ func TestRegisterHandler(t *testing.T) {
 			mockSvc := new(MockAuthService)
 			tt.mockSetup(mockSvc)
 
-			handler := &AuthHTTPHandler{svc: mockSvc}
+			handler := &AuthHTTPHandler{svc: mockSvc, logger: newTestLogger()}
 			router := gin.New()
 			router.POST("/register", handler.RegisterHandler)
 
@@ -154,7 +364,6 @@ func TestLoginHandler(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: map[string]interface{}{
-				"message": "Login successful",
 				"tokens": map[string]interface{}{
 					"access_token":  "access_token",
 					"refresh_token": "refresh_token",
@@ -168,9 +377,21 @@ func TestLoginHandler(t *testing.T) {
 			},
 			mockSetup:      func(m *MockAuthService) {},
 			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidRequest),
+		},
+		{
+			name: "login locked",
+			requestBody: map[string]interface{}{
+				"phone_number": "09123456789",
+				"password":     "Test123!@#",
+			},
+			mockSetup: func(m *MockAuthService) {
+				m.On("Login", mock.AnythingOfType("*dto.LoginRequest")).Return(nil, errors.ErrTooManyLoginAttempts.WithRetryAfter(90*time.Second))
+			},
+			expectedStatus: http.StatusTooManyRequests,
 			expectedBody: map[string]interface{}{
-				"error":   "Invalid request format",
-				"details": "Key: 'LoginRequest.Password' Error:Field validation for 'Password' failed on the 'required' tag",
+				"error":       errorBody(errors.ErrTooManyLoginAttempts)["error"],
+				"retry_after": float64(90),
 			},
 		},
 		{
@@ -180,13 +401,10 @@ func TestLoginHandler(t *testing.T) {
 				"password":     "Test123!@#",
 			},
 			mockSetup: func(m *MockAuthService) {
-				m.On("Login", mock.AnythingOfType("*dto.LoginRequest")).Return(nil, errors.New("login failed"))
-			},
-			expectedStatus: http.StatusUnauthorized,
-			expectedBody: map[string]interface{}{
-				"error":   "Login failed",
-				"details": "login failed",
+				m.On("Login", mock.AnythingOfType("*dto.LoginRequest")).Return(nil, errors.ErrInternalServer)
 			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   errorBody(errors.ErrInternalServer),
 		},
 	}
 
@@ -195,7 +413,7 @@ func TestLoginHandler(t *testing.T) {
 			mockSvc := new(MockAuthService)
 			tt.mockSetup(mockSvc)
 
-			handler := &AuthHTTPHandler{svc: mockSvc}
+			handler := &AuthHTTPHandler{svc: mockSvc, logger: newTestLogger()}
 			router := gin.New()
 			router.POST("/login", handler.LoginHandler)
 
@@ -230,11 +448,11 @@ func TestLogoutHandler(t *testing.T) {
 			name:   "successful logout",
 			userID: "user123",
 			mockSetup: func(m *MockAuthService) {
-				m.On("Logout", "user123").Return(nil)
+				m.On("Logout", "user123", "session123").Return(nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: map[string]interface{}{
-				"message": "Logout successful",
+				"message": "Logged out successfully",
 			},
 		},
 		{
@@ -242,30 +460,23 @@ func TestLogoutHandler(t *testing.T) {
 			userID:         nil,
 			mockSetup:      func(m *MockAuthService) {},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody: map[string]interface{}{
-				"error": "User ID not found",
-			},
+			expectedBody:   errorBody(errors.ErrUserNotAuthenticated),
 		},
 		{
 			name:           "invalid user ID type",
 			userID:         123,
 			mockSetup:      func(m *MockAuthService) {},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody: map[string]interface{}{
-				"error": "Invalid user ID type",
-			},
+			expectedBody:   errorBody(errors.ErrInvalidUserIDType),
 		},
 		{
 			name:   "logout service error",
 			userID: "user123",
 			mockSetup: func(m *MockAuthService) {
-				m.On("Logout", "user123").Return(errors.New("logout failed"))
-			},
-			expectedStatus: http.StatusUnauthorized,
-			expectedBody: map[string]interface{}{
-				"error":   "Logout failed",
-				"details": "logout failed",
+				m.On("Logout", "user123", "session123").Return(errors.ErrInternalServer)
 			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   errorBody(errors.ErrInternalServer),
 		},
 	}
 
@@ -274,11 +485,12 @@ func TestLogoutHandler(t *testing.T) {
 			mockSvc := new(MockAuthService)
 			tt.mockSetup(mockSvc)
 
-			handler := &AuthHTTPHandler{svc: mockSvc}
+			handler := &AuthHTTPHandler{svc: mockSvc, logger: newTestLogger()}
 			router := gin.New()
 			router.POST("/logout", func(c *gin.Context) {
 				if tt.userID != nil {
 					c.Set("user_id", tt.userID)
+					c.Set("session_id", "session123")
 				}
 				handler.LogoutHandler(c)
 			})
@@ -321,7 +533,6 @@ func TestRefreshTokenHandler(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: map[string]interface{}{
-				"message": "Token refreshed successfully",
 				"tokens": map[string]interface{}{
 					"access_token":  "new_access_token",
 					"refresh_token": "new_refresh_token",
@@ -335,10 +546,7 @@ func TestRefreshTokenHandler(t *testing.T) {
 			},
 			mockSetup:      func(m *MockAuthService) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: map[string]interface{}{
-				"error":   "Invalid request format",
-				"details": "Key: 'RefreshTokenRequest.RefreshToken' Error:Field validation for 'RefreshToken' failed on the 'required' tag",
-			},
+			expectedBody:   errorBody(errors.ErrInvalidRequest),
 		},
 		{
 			name: "refresh token service error",
@@ -346,13 +554,10 @@ func TestRefreshTokenHandler(t *testing.T) {
 				"refresh_token": "invalid_token",
 			},
 			mockSetup: func(m *MockAuthService) {
-				m.On("RefreshToken", "invalid_token").Return(nil, errors.New("token refresh failed"))
+				m.On("RefreshToken", "invalid_token").Return(nil, errors.ErrInvalidToken)
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody: map[string]interface{}{
-				"error":   "Token refresh failed",
-				"details": "token refresh failed",
-			},
+			expectedBody:   errorBody(errors.ErrInvalidToken),
 		},
 	}
 
@@ -361,7 +566,7 @@ func TestRefreshTokenHandler(t *testing.T) {
 			mockSvc := new(MockAuthService)
 			tt.mockSetup(mockSvc)
 
-			handler := &AuthHTTPHandler{svc: mockSvc}
+			handler := &AuthHTTPHandler{svc: mockSvc, logger: newTestLogger()}
 			router := gin.New()
 			router.POST("/refresh-token", handler.RefreshTokenHandler)
 
@@ -381,3 +586,487 @@ func TestRefreshTokenHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyMFAHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		requestBody    map[string]interface{}
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name: "successful verification with an authenticator code",
+			requestBody: map[string]interface{}{
+				"mfa_token": "mfa_token",
+				"code":      "123456",
+			},
+			mockSetup: func(m *MockAuthService) {
+				m.On("VerifyMFA", &dto.MFAVerifyRequest{MFAToken: "mfa_token", Code: "123456"}).Return(&entities.TokenPair{
+					AccessToken:  "access_token",
+					RefreshToken: "refresh_token",
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"tokens": map[string]interface{}{
+					"access_token":  "access_token",
+					"refresh_token": "refresh_token",
+				},
+			},
+		},
+		{
+			name: "successful verification with a recovery code",
+			requestBody: map[string]interface{}{
+				"mfa_token": "mfa_token",
+				"code":      "3f9a1-c07e2",
+			},
+			mockSetup: func(m *MockAuthService) {
+				m.On("VerifyMFA", &dto.MFAVerifyRequest{MFAToken: "mfa_token", Code: "3f9a1-c07e2"}).Return(&entities.TokenPair{
+					AccessToken:  "access_token",
+					RefreshToken: "refresh_token",
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"tokens": map[string]interface{}{
+					"access_token":  "access_token",
+					"refresh_token": "refresh_token",
+				},
+			},
+		},
+		{
+			name: "missing mfa token",
+			requestBody: map[string]interface{}{
+				"code": "123456",
+			},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidRequest),
+		},
+		{
+			name: "malformed code",
+			requestBody: map[string]interface{}{
+				"mfa_token": "mfa_token",
+				"code":      "12345",
+			},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidMFACodeValue),
+		},
+		{
+			name: "wrong code",
+			requestBody: map[string]interface{}{
+				"mfa_token": "mfa_token",
+				"code":      "654321",
+			},
+			mockSetup: func(m *MockAuthService) {
+				m.On("VerifyMFA", mock.AnythingOfType("*dto.MFAVerifyRequest")).Return(nil, errors.ErrInvalidMFACode)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   errorBody(errors.ErrInvalidMFACode),
+		},
+		{
+			name: "too many attempts",
+			requestBody: map[string]interface{}{
+				"mfa_token": "mfa_token",
+				"code":      "654321",
+			},
+			mockSetup: func(m *MockAuthService) {
+				m.On("VerifyMFA", mock.AnythingOfType("*dto.MFAVerifyRequest")).Return(nil, errors.ErrMFAAttemptsExceeded)
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   errorBody(errors.ErrMFAAttemptsExceeded),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAuthService)
+			tt.mockSetup(mockSvc)
+
+			handler := &AuthHTTPHandler{svc: mockSvc, logger: newTestLogger()}
+			router := gin.New()
+			router.POST("/mfa/verify", handler.VerifyMFAHandler)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/mfa/verify", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedBody, response)
+		})
+	}
+}
+
+func TestWebAuthnLoginOptionsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name: "successful options",
+			mockSetup: func(m *MockAuthService) {
+				m.On("BeginWebAuthnLogin").Return(&dto.WebAuthnAssertionOptions{
+					Challenge:        "Y2hhbGxlbmdl",
+					Timeout:          300000,
+					RPID:             "auth.example.com",
+					AllowCredentials: []dto.WebAuthnCredentialDescriptor{},
+					UserVerification: "preferred",
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"challenge":        "Y2hhbGxlbmdl",
+				"timeout":          float64(300000),
+				"rpId":             "auth.example.com",
+				"allowCredentials": []interface{}{},
+				"userVerification": "preferred",
+			},
+		},
+		{
+			name: "service error",
+			mockSetup: func(m *MockAuthService) {
+				m.On("BeginWebAuthnLogin").Return(nil, errors.ErrInternalServer)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   errorBody(errors.ErrInternalServer),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAuthService)
+			tt.mockSetup(mockSvc)
+
+			handler := &AuthHTTPHandler{svc: mockSvc, logger: newTestLogger()}
+			router := gin.New()
+			router.POST("/webauthn/login/options", handler.WebAuthnLoginOptionsHandler)
+
+			req := httptest.NewRequest(http.MethodPost, "/webauthn/login/options", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedBody, response)
+		})
+	}
+}
+
+func TestWebAuthnLoginFinishHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	assertion := map[string]interface{}{
+		"id":   "Y3JlZGVudGlhbA",
+		"type": "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    "eyJ0eXBlIjoid2ViYXV0aG4uZ2V0In0",
+			"authenticatorData": "SZYN5YgOjGh0NBcPZHZgW4",
+			"signature":         "MEUCIQ",
+			"userHandle":        "dXNlcg",
+		},
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    map[string]interface{}
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:        "successful login",
+			requestBody: assertion,
+			mockSetup: func(m *MockAuthService) {
+				m.On("FinishWebAuthnLogin", mock.MatchedBy(func(req *dto.WebAuthnLoginRequest) bool {
+					return req.ID == "Y3JlZGVudGlhbA" && req.Response.Signature == "MEUCIQ"
+				})).Return(&entities.TokenPair{
+					AccessToken:  "access_token",
+					RefreshToken: "refresh_token",
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"tokens": map[string]interface{}{
+					"access_token":  "access_token",
+					"refresh_token": "refresh_token",
+				},
+			},
+		},
+		{
+			name: "missing signature",
+			requestBody: map[string]interface{}{
+				"id":   "Y3JlZGVudGlhbA",
+				"type": "public-key",
+				"response": map[string]interface{}{
+					"clientDataJSON":    "eyJ0eXBlIjoid2ViYXV0aG4uZ2V0In0",
+					"authenticatorData": "SZYN5YgOjGh0NBcPZHZgW4",
+				},
+			},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidRequest),
+		},
+		{
+			name:        "invalid signature",
+			requestBody: assertion,
+			mockSetup: func(m *MockAuthService) {
+				m.On("FinishWebAuthnLogin", mock.AnythingOfType("*dto.WebAuthnLoginRequest")).Return(nil, errors.ErrInvalidWebAuthnResponse)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   errorBody(errors.ErrInvalidWebAuthnResponse),
+		},
+		{
+			name:        "unknown passkey",
+			requestBody: assertion,
+			mockSetup: func(m *MockAuthService) {
+				m.On("FinishWebAuthnLogin", mock.AnythingOfType("*dto.WebAuthnLoginRequest")).Return(nil, errors.ErrWebAuthnCredentialNotFound)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   errorBody(errors.ErrWebAuthnCredentialNotFound),
+		},
+		{
+			name:        "unsupported algorithm",
+			requestBody: assertion,
+			mockSetup: func(m *MockAuthService) {
+				m.On("FinishWebAuthnLogin", mock.AnythingOfType("*dto.WebAuthnLoginRequest")).Return(nil, errors.ErrUnsupportedWebAuthnKey)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrUnsupportedWebAuthnKey),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAuthService)
+			tt.mockSetup(mockSvc)
+
+			handler := &AuthHTTPHandler{svc: mockSvc, logger: newTestLogger()}
+			router := gin.New()
+			router.POST("/webauthn/login/finish", handler.WebAuthnLoginFinishHandler)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/webauthn/login/finish", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedBody, response)
+		})
+	}
+}
+
+func TestForgotPasswordHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		requestBody    map[string]interface{}
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name: "reset code requested",
+			requestBody: map[string]interface{}{
+				"phone_number": "09123456789",
+			},
+			mockSetup: func(m *MockAuthService) {
+				m.On("ForgotPassword", &dto.ForgotPasswordRequest{PhoneNumber: "09123456789"}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"message": "If an account exists for this phone number, a reset code has been sent",
+			},
+		},
+		{
+			name:           "missing phone number",
+			requestBody:    map[string]interface{}{},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidRequest),
+		},
+		{
+			name: "invalid phone number",
+			requestBody: map[string]interface{}{
+				"phone_number": "12345",
+			},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidPhoneNumber),
+		},
+		{
+			name: "forgot password service error",
+			requestBody: map[string]interface{}{
+				"phone_number": "09123456789",
+			},
+			mockSetup: func(m *MockAuthService) {
+				m.On("ForgotPassword", mock.AnythingOfType("*dto.ForgotPasswordRequest")).Return(errors.ErrInternalServer)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   errorBody(errors.ErrInternalServer),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAuthService)
+			tt.mockSetup(mockSvc)
+
+			handler := &AuthHTTPHandler{svc: mockSvc, logger: newTestLogger()}
+			router := gin.New()
+			router.POST("/password/forgot", handler.ForgotPasswordHandler)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/password/forgot", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedBody, response)
+		})
+	}
+}
+
+func TestResetPasswordHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	validRequest := map[string]interface{}{
+		"phone_number": "09123456789",
+		"code":         "123456",
+		"new_password": "NewPass123!@#",
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    map[string]interface{}
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedHeader string
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:        "successful reset",
+			requestBody: validRequest,
+			mockSetup: func(m *MockAuthService) {
+				m.On("ResetPassword", &dto.ResetPasswordRequest{
+					PhoneNumber: "09123456789",
+					Code:        "123456",
+					NewPassword: "NewPass123!@#",
+				}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"message": "Password reset successfully",
+			},
+		},
+		{
+			name: "missing code",
+			requestBody: map[string]interface{}{
+				"phone_number": "09123456789",
+				"new_password": "NewPass123!@#",
+			},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidRequest),
+		},
+		{
+			name: "weak new password",
+			requestBody: map[string]interface{}{
+				"phone_number": "09123456789",
+				"code":         "123456",
+				"new_password": "password",
+			},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidPassword),
+		},
+		{
+			name:        "wrong code",
+			requestBody: validRequest,
+			mockSetup: func(m *MockAuthService) {
+				m.On("ResetPassword", mock.AnythingOfType("*dto.ResetPasswordRequest")).Return(errors.ErrInvalidOTP)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   errorBody(errors.ErrInvalidOTP),
+		},
+		{
+			name:        "too many attempts",
+			requestBody: validRequest,
+			mockSetup: func(m *MockAuthService) {
+				m.On("ResetPassword", mock.AnythingOfType("*dto.ResetPasswordRequest")).Return(errors.ErrOTPAttemptsExceeded.WithRetryAfter(120 * time.Second))
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedHeader: "120",
+			expectedBody: map[string]interface{}{
+				"error":       errorBody(errors.ErrOTPAttemptsExceeded)["error"],
+				"retry_after": float64(120),
+			},
+		},
+		{
+			name:        "reset password service error",
+			requestBody: validRequest,
+			mockSetup: func(m *MockAuthService) {
+				m.On("ResetPassword", mock.AnythingOfType("*dto.ResetPasswordRequest")).Return(errors.ErrUpdateUser)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   errorBody(errors.ErrUpdateUser),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAuthService)
+			tt.mockSetup(mockSvc)
+
+			handler := &AuthHTTPHandler{svc: mockSvc, logger: newTestLogger()}
+			router := gin.New()
+			router.POST("/password/reset", handler.ResetPasswordHandler)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/password/reset", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			require.Equal(t, tt.expectedHeader, w.Header().Get("Retry-After"))
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedBody, response)
+		})
+	}
+}
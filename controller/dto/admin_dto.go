@@ -2,15 +2,29 @@ package dto
 
 import "time"
 
+// AdminGetUsersRequest filters the user list. Status and Role take several
+// values, repeated or separated by commas. Search matches part of the name,
+// phone number or email. CreatedFrom and CreatedTo are RFC 3339 times, Cursor
+// is the next_cursor of the previous page.
 type AdminGetUsersRequest struct {
-	Status string `json:"status" validate:"omitempty,status"`
-	Role   string `json:"role" validate:"omitempty,role"`
-	Sort   string `json:"sort" validate:"omitempty,sort"`
-	Order  string `json:"order" validate:"omitempty,order"`
+	Status      []string `form:"status" validate:"omitempty,dive,status"`
+	Role        []string `form:"role" validate:"omitempty,dive,role"`
+	Search      string   `form:"search" validate:"omitempty,max=100"`
+	CreatedFrom string   `form:"created_from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	CreatedTo   string   `form:"created_to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Sort        string   `form:"sort" validate:"omitempty,sort"`
+	Order       string   `form:"order" validate:"omitempty,order"`
+	Limit       int      `form:"limit" validate:"omitempty,min=1,max=200"`
+	Cursor      string   `form:"cursor"`
 }
 
+// AdminUserListResponse is a page of users. Total counts every user matching
+// the filters, NextCursor is empty on the last page.
+// swagger:model
 type AdminUserListResponse struct {
-	Users []AdminUserResponse `json:"users"`
+	Users      []AdminUserResponse `json:"users"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+	Total      int                 `json:"total"`
 }
 
 type AdminUserResponse struct {
@@ -45,3 +59,170 @@ type AdminUserUpdateRoleRequest struct {
 type AdminUserUpdateStatusRequest struct {
 	Status string `json:"status" binding:"required,status"`
 }
+
+// AdminExportUsersRequest selects the users to export with the filters of
+// the user list, in csv or ndjson. Limit and Cursor are ignored, every
+// matching user is exported.
+type AdminExportUsersRequest struct {
+	AdminGetUsersRequest
+	Format string `form:"format" binding:"required" validate:"oneof=csv ndjson"`
+}
+
+// AdminImportUsersRequest gives the format of an import file, csv or ndjson
+type AdminImportUsersRequest struct {
+	Format string `form:"format" binding:"required" validate:"oneof=csv ndjson"`
+}
+
+// AdminImportUserRow is one user of an import file, a CSV row or an NDJSON
+// line. PasswordHash is the hash of the password in another system, given
+// instead of a Password: a bcrypt hash or a PHC string of argon2id, scrypt,
+// PBKDF2 or salted SHA. Row is the number of the row in the file, from 1.
+// swagger:model
+type AdminImportUserRow struct {
+	Row          int    `json:"-"`
+	PhoneNumber  string `json:"phone_number" validate:"required,phone"`
+	FirstName    string `json:"first_name" validate:"omitempty,min=2,max=50"`
+	LastName     string `json:"last_name" validate:"omitempty,min=2,max=50"`
+	Email        string `json:"email" validate:"omitempty,email,max=100"`
+	Password     string `json:"password" validate:"required_without=PasswordHash,excluded_with=PasswordHash,omitempty,password"`
+	PasswordHash string `json:"password_hash" validate:"omitempty,max=512"`
+	Role         string `json:"role" validate:"omitempty,role"`
+	Status       string `json:"status" validate:"omitempty,status"`
+}
+
+// AdminImportRowError tells why a row of an import wasn't imported
+// swagger:model
+type AdminImportRowError struct {
+	Row   int   `json:"row"`
+	Error error `json:"error"`
+}
+
+// AdminImportUsersResponse reports an import. Errors lists the rows that
+// weren't imported in the order of the file.
+// swagger:model
+type AdminImportUsersResponse struct {
+	Imported int                   `json:"imported"`
+	Failed   int                   `json:"failed"`
+	Errors   []AdminImportRowError `json:"errors"`
+}
+
+// AdminBulkUsersRequest applies one action to many users. Status is the new
+// status for change_status and Role the new role for change_role. With
+// DryRun every user is checked but none is changed.
+// swagger:model
+type AdminBulkUsersRequest struct {
+	UserIDs []string `json:"user_ids" binding:"required" validate:"min=1,max=100,unique,dive,uuid"`
+	Action  string   `json:"action" binding:"required" validate:"oneof=change_status change_role delete revoke_sessions"`
+	Status  string   `json:"status" validate:"required_if=Action change_status,omitempty,status"`
+	Role    string   `json:"role" validate:"required_if=Action change_role,omitempty,role"`
+	DryRun  bool     `json:"dry_run"`
+}
+
+// AdminBulkUserResult is the outcome of a bulk action for one user. Error
+// tells why it failed, or would fail in a dry run.
+// swagger:model
+type AdminBulkUserResult struct {
+	UserID    string `json:"user_id"`
+	Succeeded bool   `json:"succeeded"`
+	Error     error  `json:"error,omitempty"`
+}
+
+// AdminBulkUsersResponse reports a bulk action user by user, in the order of
+// the request
+// swagger:model
+type AdminBulkUsersResponse struct {
+	Action    string                `json:"action"`
+	DryRun    bool                  `json:"dry_run"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Results   []AdminBulkUserResult `json:"results"`
+}
+
+// AdminCreateOAuthClientRequest is used for registering an application that
+// signs its users in through go_auth, or a backend service that uses the
+// client_credentials grant. Confidential clients get a secret.
+// swagger:model
+type AdminCreateOAuthClientRequest struct {
+	Name         string   `json:"name" binding:"required" validate:"min=2,max=100"`
+	RedirectURIs []string `json:"redirect_uris" validate:"omitempty,dive,redirect_uri"`
+	Scopes       []string `json:"scopes" validate:"omitempty,dive,scope"`
+	GrantTypes   []string `json:"grant_types" validate:"omitempty,dive,grant_type"`
+	Confidential bool     `json:"confidential"`
+}
+
+// AdminOAuthClientResponse describes a registered OAuth client. ClientSecret
+// is only returned when the client is created.
+// swagger:model
+type AdminOAuthClientResponse struct {
+	ClientID       string    `json:"client_id"`
+	ClientSecret   string    `json:"client_secret,omitempty"`
+	OrganizationID string    `json:"organization_id"`
+	Name           string    `json:"name"`
+	RedirectURIs   []string  `json:"redirect_uris"`
+	Scopes         []string  `json:"scopes"`
+	GrantTypes     []string  `json:"grant_types"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AdminAuditEventsRequest filters the audit log. From and To are RFC 3339
+// times, Cursor is the next_cursor of the previous page.
+type AdminAuditEventsRequest struct {
+	Type     string `form:"type" validate:"omitempty,max=100"`
+	ActorID  string `form:"actor_id" validate:"omitempty,uuid"`
+	TargetID string `form:"target_id" validate:"omitempty,uuid"`
+	From     string `form:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To       string `form:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Limit    int    `form:"limit" validate:"omitempty,min=1,max=200"`
+	Cursor   string `form:"cursor"`
+}
+
+// AdminAuditChange is the value of a field before and after a change
+// swagger:model
+type AdminAuditChange struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// AdminAuditEventResponse describes one entry of the audit log
+// swagger:model
+type AdminAuditEventResponse struct {
+	ID        string                      `json:"id"`
+	Type      string                      `json:"type"`
+	ActorID   string                      `json:"actor_id,omitempty"`
+	TargetID  string                      `json:"target_id,omitempty"`
+	IP        string                      `json:"ip"`
+	UserAgent string                      `json:"user_agent"`
+	Changes   map[string]AdminAuditChange `json:"changes,omitempty"`
+	Details   map[string]string           `json:"details,omitempty"`
+	CreatedAt time.Time                   `json:"created_at"`
+}
+
+// AdminAuditEventListResponse is a page of the audit log, newest first.
+// NextCursor is empty on the last page.
+// swagger:model
+type AdminAuditEventListResponse struct {
+	Events     []AdminAuditEventResponse `json:"events"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
+}
+
+// AdminCreateOrganizationRequest creates an organization together with its
+// first user, a super admin who manages the organization's users. The slug
+// is what clients send in the X-Organization header.
+// swagger:model
+type AdminCreateOrganizationRequest struct {
+	Slug             string `json:"slug" binding:"required" validate:"slug"`
+	Name             string `json:"name" binding:"required" validate:"max=100"`
+	OwnerPhoneNumber string `json:"owner_phone_number" binding:"required" validate:"phone"`
+	OwnerPassword    string `json:"owner_password" binding:"required" validate:"password,min=8"`
+}
+
+// AdminOrganizationResponse describes an organization. OwnerID is only
+// returned when the organization is created.
+// swagger:model
+type AdminOrganizationResponse struct {
+	ID        string    `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	OwnerID   string    `json:"owner_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
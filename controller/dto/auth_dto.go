@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // RegisterRequest is used for user registration
 // swagger:model
 type RegisterRequest struct {
@@ -7,11 +9,13 @@ type RegisterRequest struct {
 	Password    string `json:"password" binding:"required" validate:"password,min=8"`
 }
 
-// LoginRequest is used for user login
+// LoginRequest is used for user login with a phone number or a verified email
 // swagger:model
 type LoginRequest struct {
-	PhoneNumber string `json:"phone_number" binding:"required" validate:"phone"`
+	PhoneNumber string `json:"phone_number" validate:"required_without=Email,excluded_with=Email,omitempty,phone"`
+	Email       string `json:"email" validate:"omitempty,email,max=100"`
 	Password    string `json:"password" binding:"required" validate:"password,min=8"`
+	DeviceName  string `json:"device_name" validate:"omitempty,max=100"`
 }
 
 // RefreshTokenRequest is used for refreshing JWT token
@@ -19,3 +23,50 @@ type LoginRequest struct {
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required" validate:"required"`
 }
+
+// OTPRequest is used for requesting a one-time login code by SMS
+// swagger:model
+type OTPRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required" validate:"phone"`
+}
+
+// OTPVerifyRequest is used for logging in with a one-time code
+// swagger:model
+type OTPVerifyRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required" validate:"phone"`
+	Code        string `json:"code" binding:"required" validate:"otp"`
+	DeviceName  string `json:"device_name" validate:"omitempty,max=100"`
+}
+
+// ForgotPasswordRequest is used for requesting a password reset code
+// swagger:model
+type ForgotPasswordRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required" validate:"phone"`
+}
+
+// ResetPasswordRequest is used for setting a new password with a reset code
+// swagger:model
+type ResetPasswordRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required" validate:"phone"`
+	Code        string `json:"code" binding:"required" validate:"otp"`
+	NewPassword string `json:"new_password" binding:"required" validate:"password,min=8"`
+}
+
+// MFAVerifyRequest is used for finishing a login with a second factor
+// swagger:model
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required" validate:"required"`
+	Code     string `json:"code" binding:"required" validate:"mfa_code"`
+}
+
+// SessionResponse describes one of the user's active sessions
+// swagger:model
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
@@ -1,11 +1,16 @@
 package dto
 
+import "time"
+
 type UserProfileResponse struct {
-	ID          string `json:"id"`
-	PhoneNumber string `json:"phone_number"`
-	FirstName   string `json:"first_name"`
-	LastName    string `json:"last_name"`
-	Email       string `json:"email"`
+	ID            string `json:"id"`
+	PhoneNumber   string `json:"phone_number"`
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	// PendingEmail is a new address waiting to be confirmed
+	PendingEmail string `json:"pending_email,omitempty"`
 }
 
 // UserUpdateRequest is used for updating user profile
@@ -21,3 +26,55 @@ type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password" validate:"required,password"`
 	NewPassword string `json:"new_password" validate:"required,password"`
 }
+
+// CreateAPIKeyRequest is used for creating a personal API key
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,min=1,max=100"`
+	Scopes    []string   `json:"scopes" validate:"omitempty,dive,api_key_scope"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// APIKeyResponse describes one of the user's API keys. Key is only set when
+// the key is created, it can't be shown again.
+// swagger:model
+type APIKeyResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	Key       string     `json:"key,omitempty"`
+}
+
+// TOTPEnrollmentResponse holds the secret of an authenticator being set up,
+// both as is and as an otpauth:// URI for QR codes
+// swagger:model
+type TOTPEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// ChangePhoneNumberRequest is used for starting a change of the phone number
+// the user logs in with
+type ChangePhoneNumberRequest struct {
+	PhoneNumber string `json:"phone_number" validate:"required,mobile"`
+}
+
+// ConfirmPhoneNumberRequest is used for finishing a phone number change with
+// the code sent to the new number
+type ConfirmPhoneNumberRequest struct {
+	Code string `json:"code" validate:"required,otp"`
+}
+
+// ConfirmTOTPRequest is used for confirming an authenticator with its first code
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" validate:"required,otp"`
+}
+
+// RecoveryCodesResponse holds one-time codes for logging in without the
+// authenticator. They are only shown once.
+// swagger:model
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,97 @@
+package dto
+
+import "time"
+
+// The WebAuthn types follow the JSON form of the browser API, so clients can
+// pass them to navigator.credentials and back. Binary values are base64url
+// strings without padding.
+
+// WebAuthnRegistrationOptions are the options for navigator.credentials.create()
+// swagger:model
+type WebAuthnRegistrationOptions struct {
+	Challenge              string                         `json:"challenge"`
+	RP                     WebAuthnRelyingPartyEntity     `json:"rp"`
+	User                   WebAuthnUserEntity             `json:"user"`
+	PubKeyCredParams       []WebAuthnCredentialParameters `json:"pubKeyCredParams"`
+	Timeout                int64                          `json:"timeout"`
+	ExcludeCredentials     []WebAuthnCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection WebAuthnAuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                         `json:"attestation"`
+}
+
+// WebAuthnAssertionOptions are the options for navigator.credentials.get()
+// swagger:model
+type WebAuthnAssertionOptions struct {
+	Challenge        string                         `json:"challenge"`
+	Timeout          int64                          `json:"timeout"`
+	RPID             string                         `json:"rpId"`
+	AllowCredentials []WebAuthnCredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                         `json:"userVerification"`
+}
+
+type WebAuthnRelyingPartyEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type WebAuthnUserEntity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type WebAuthnCredentialParameters struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+type WebAuthnCredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+type WebAuthnAuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// WebAuthnRegistrationRequest is the credential navigator.credentials.create() returned
+// swagger:model
+type WebAuthnRegistrationRequest struct {
+	ID       string                      `json:"id" binding:"required"`
+	Type     string                      `json:"type" binding:"required"`
+	Response WebAuthnAttestationResponse `json:"response" binding:"required"`
+}
+
+type WebAuthnAttestationResponse struct {
+	ClientDataJSON    string   `json:"clientDataJSON" binding:"required"`
+	AttestationObject string   `json:"attestationObject" binding:"required"`
+	Transports        []string `json:"transports"`
+}
+
+// WebAuthnLoginRequest is the credential navigator.credentials.get() returned
+// swagger:model
+type WebAuthnLoginRequest struct {
+	ID         string                    `json:"id" binding:"required"`
+	Type       string                    `json:"type" binding:"required"`
+	Response   WebAuthnAssertionResponse `json:"response" binding:"required"`
+	DeviceName string                    `json:"device_name" binding:"max=100"`
+}
+
+type WebAuthnAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON" binding:"required"`
+	AuthenticatorData string `json:"authenticatorData" binding:"required"`
+	Signature         string `json:"signature" binding:"required"`
+	UserHandle        string `json:"userHandle"`
+}
+
+// WebAuthnCredentialResponse describes a registered passkey
+// swagger:model
+type WebAuthnCredentialResponse struct {
+	ID         string     `json:"id"`
+	Transports []string   `json:"transports"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
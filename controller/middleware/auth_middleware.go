@@ -1,19 +1,78 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
+	"strings"
 
-	"github.com/amirdashtii/go_auth/config"
 	"github.com/amirdashtii/go_auth/internal/core/entities"
 	"github.com/amirdashtii/go_auth/internal/core/errors"
 	"github.com/amirdashtii/go_auth/internal/core/service"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// credentials are the kinds of credentials a route accepts besides the
+// access token of a user's own session
+type credentials int
+
+const (
+	// apiKeys are the personal API keys of users
+	apiKeys credentials = 1 << iota
+	// clientTokens are tokens issued to machine clients with the
+	// client_credentials grant
+	clientTokens
+	// delegatedTokens are access tokens issued to an OpenID Connect client
+	// for a user who signed in to it
+	delegatedTokens
+)
+
+// AuthMiddleware authenticates users by the access token of their session or
+// by an API key
 func AuthMiddleware() gin.HandlerFunc {
-	authService := service.NewAuthService()
+	return authenticate(apiKeys)
+}
 
+// SessionAuthMiddleware authenticates users by the access token of their
+// session only, for routes that manage credentials. An API key could
+// otherwise be used to create more keys or take over the account.
+func SessionAuthMiddleware() gin.HandlerFunc {
+	return authenticate(0)
+}
+
+// AdminAuthMiddleware authenticates like AuthMiddleware, and also machine
+// clients by their access token. Every route behind it must check a
+// permission, which machine clients only have within their scope.
+func AdminAuthMiddleware() gin.HandlerFunc {
+	return authenticate(apiKeys | clientTokens)
+}
+
+// UserInfoAuthMiddleware authenticates users for the OpenID Connect userinfo
+// endpoint, the only route that accepts the tokens issued to OpenID Connect
+// clients. Those tokens only let the client read what the user consented to.
+func UserInfoAuthMiddleware() gin.HandlerFunc {
+	return authenticate(delegatedTokens)
+}
+
+// authenticator checks the credentials requests are authenticated with
+type authenticator interface {
+	ParseToken(token string) (jwt.MapClaims, error)
+	ValidateToken(ctx context.Context, userID, sessionID, token string) error
+	ValidateClientToken(ctx context.Context, clientID, tokenID string) error
+	AuthenticateAPIKey(ctx context.Context, key string) (*entities.User, *entities.APIKey, error)
+}
+
+// authenticate returns a middleware that accepts the access tokens of user
+// sessions and the credentials in accepted. Other credentials are refused
+// with 403.
+func authenticate(accepted credentials) gin.HandlerFunc {
+	return authenticateWith(service.NewAuthService(), accepted)
+}
+
+// authenticateWith is authenticate with the credentials checked by
+// authService
+func authenticateWith(authService authenticator, accepted credentials) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
 		if ctx.Err() != nil {
@@ -33,53 +92,135 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// API keys carry the same user context as an access token
+		if key, ok := strings.CutPrefix(token, "ApiKey "); ok {
+			user, apiKey, err := authService.AuthenticateAPIKey(ctx, key)
+			if err != nil {
+				status := http.StatusUnauthorized
+				if !errors.IsAuthenticationError(err) {
+					status = http.StatusInternalServerError
+				}
+				c.JSON(status, gin.H{
+					"error": err,
+				})
+				c.Abort()
+				return
+			}
+
+			if accepted&apiKeys == 0 {
+				refuseCredential(c)
+				return
+			}
+
+			if !setOrganization(c, user.OrganizationID) {
+				c.JSON(http.StatusForbidden, gin.H{
+					"error": errors.ErrOrganizationMismatch,
+				})
+				c.Abort()
+				return
+			}
+
+			setActor(c, user.ID.String())
+			c.Set("user_id", user.ID.String())
+			c.Set("role", user.Role.String())
+			c.Set("api_key_id", apiKey.ID.String())
+			c.Set("scope", strings.Join(apiKey.Scopes, " "))
+			c.Next()
+			return
+		}
+
 		if len(token) > 7 && token[0:7] == "Bearer " {
 			token = token[7:]
 		}
 
-		config, err := config.LoadConfig()
+		claims, err := authService.ParseToken(token)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": errors.ErrLoadConfig,
+				"error": errors.ErrParseToken,
 			})
 			c.Abort()
 			return
 		}
 
-		jwtSecret := config.JWT.Secret
-
-		parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": errors.ErrParseToken,
+		tokenType := claims["token_type"].(string)
+		if tokenType != "access" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": errors.ErrInvalidTokenType,
 			})
 			c.Abort()
 			return
 		}
 
-		claims, ok := parsedToken.Claims.(jwt.MapClaims)
+		// Tokens issued with the client_credentials grant belong to a
+		// client instead of a user
+		if _, ok := claims["user_id"]; !ok {
+			clientID, _ := claims["client_id"].(string)
+			subject, _ := claims["sub"].(string)
+			tokenID, _ := claims["jti"].(string)
+			if clientID == "" || subject != clientID || tokenID == "" {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error": errors.ErrInvalidTokenClaims,
+				})
+				c.Abort()
+				return
+			}
+
+			if err := authService.ValidateClientToken(ctx, clientID, tokenID); err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error": err,
+				})
+				c.Abort()
+				return
+			}
+
+			if accepted&clientTokens == 0 {
+				refuseCredential(c)
+				return
+			}
+
+			// A client can only reach the users of its own organization
+			organizationID, ok := organizationClaim(claims)
+			if !ok {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error": errors.ErrInvalidTokenClaims,
+				})
+				c.Abort()
+				return
+			}
+			if !setOrganization(c, organizationID) {
+				c.JSON(http.StatusForbidden, gin.H{
+					"error": errors.ErrOrganizationMismatch,
+				})
+				c.Abort()
+				return
+			}
+
+			scope, _ := claims["scope"].(string)
+			c.Set("client_id", clientID)
+			c.Set("scope", scope)
+			c.Next()
+			return
+		}
+
+		userID, ok := claims["user_id"].(string)
 		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{
+			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": errors.ErrInvalidTokenClaims,
 			})
 			c.Abort()
 			return
 		}
 
-		tokenType := claims["token_type"].(string)
-		if tokenType != "access" {
+		sessionID, ok := claims["session_id"].(string)
+		if !ok {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": errors.ErrInvalidTokenType,
+				"error": errors.ErrInvalidTokenClaims,
 			})
 			c.Abort()
 			return
 		}
 
-		userID := claims["user_id"].(string)
-
-		err = authService.ValidateToken(ctx, userID, token)
+		err = authService.ValidateToken(ctx, userID, sessionID, token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": err,
@@ -88,6 +229,28 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Tokens of OpenID Connect clients carry the client they were issued to
+		if _, ok := claims["client_id"]; ok && accepted&delegatedTokens == 0 {
+			refuseCredential(c)
+			return
+		}
+
+		organizationID, ok := organizationClaim(claims)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": errors.ErrInvalidTokenClaims,
+			})
+			c.Abort()
+			return
+		}
+		if !setOrganization(c, organizationID) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": errors.ErrOrganizationMismatch,
+			})
+			c.Abort()
+			return
+		}
+
 		roleClaim := claims["role"]
 		var roleString string
 
@@ -100,8 +263,47 @@ func AuthMiddleware() gin.HandlerFunc {
 			roleString = "Unknown"
 		}
 
+		setActor(c, userID)
 		c.Set("user_id", userID)
+		c.Set("session_id", sessionID)
 		c.Set("role", roleString)
+		// Set when the user signed in to another app through OpenID Connect
+		if clientID, ok := claims["client_id"].(string); ok {
+			scope, _ := claims["scope"].(string)
+			c.Set("client_id", clientID)
+			c.Set("scope", scope)
+		}
 		c.Next()
 	}
 }
+
+// organizationClaim returns the organization a token was issued in. Tokens
+// issued before organizations existed are for the default one.
+func organizationClaim(claims jwt.MapClaims) (uuid.UUID, bool) {
+	claim, ok := claims["org"].(string)
+	if !ok {
+		return entities.DefaultOrganizationID, true
+	}
+	organizationID, err := uuid.Parse(claim)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return organizationID, true
+}
+
+// refuseCredential answers 403 to a request authenticated with a kind of
+// credential its route doesn't accept
+func refuseCredential(c *gin.Context) {
+	c.JSON(http.StatusForbidden, gin.H{
+		"error": errors.ErrCredentialNotAllowed,
+	})
+	c.Abort()
+}
+
+// setActor records the authenticated user in the client info of the request
+// context, so services can tell who made a change
+func setActor(c *gin.Context, userID string) {
+	info := entities.ClientInfoFromContext(c.Request.Context())
+	info.UserID = userID
+	c.Request = c.Request.WithContext(entities.WithClientInfo(c.Request.Context(), info))
+}
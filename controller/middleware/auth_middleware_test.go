@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/infrastructure/logger"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// staticTokens accepts the tokens listed, each with its claims
+type staticTokens map[string]jwt.MapClaims
+
+func (t staticTokens) ParseToken(token string) (jwt.MapClaims, error) {
+	claims, ok := t[token]
+	if !ok {
+		return nil, errors.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (t staticTokens) ValidateToken(ctx context.Context, userID, sessionID, token string) error {
+	return nil
+}
+
+func (t staticTokens) ValidateClientToken(ctx context.Context, clientID, tokenID string) error {
+	return nil
+}
+
+func (t staticTokens) AuthenticateAPIKey(ctx context.Context, key string) (*entities.User, *entities.APIKey, error) {
+	return nil, nil, errors.ErrInvalidAPIKey
+}
+
+func newTestAuthRouter(tokens staticTokens, organizations staticOrganizations) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	testLogger := logger.NewZerologLogger(ports.LoggerConfig{
+		Level:       "fatal",
+		Environment: "test",
+		ServiceName: "go_auth",
+		Output:      io.Discard,
+	})
+
+	r := gin.New()
+	r.Use(NewOrganizationMiddleware(organizations, testLogger))
+	r.GET("/", authenticateWith(tokens, apiKeys|clientTokens), func(c *gin.Context) {
+		c.String(http.StatusOK, entities.OrganizationIDFromContext(c.Request.Context()).String())
+	})
+	return r
+}
+
+func sendAuthRequest(r *gin.Engine, token, slug string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if slug != "" {
+		req.Header.Set(OrganizationHeader, slug)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestAuthMiddleware_ClientTokenOrganization tests that a machine client is
+// scoped to its own organization and can't name another one
+func TestAuthMiddleware_ClientTokenOrganization(t *testing.T) {
+	acme := &entities.Organization{ID: uuid.New(), Slug: "acme"}
+	globex := &entities.Organization{ID: uuid.New(), Slug: "globex"}
+	tokens := staticTokens{
+		"acme-client": {
+			"sub":        "billing-job",
+			"client_id":  "billing-job",
+			"org":        acme.ID.String(),
+			"scope":      "users:read",
+			"token_type": "access",
+			"jti":        uuid.NewString(),
+		},
+		// Issued before clients were bound to an organization
+		"legacy-client": {
+			"sub":        "report-job",
+			"client_id":  "report-job",
+			"scope":      "users:read",
+			"token_type": "access",
+			"jti":        uuid.NewString(),
+		},
+	}
+	r := newTestAuthRouter(tokens, staticOrganizations{"acme": acme, "globex": globex})
+
+	w := sendAuthRequest(r, "acme-client", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, acme.ID.String(), w.Body.String())
+
+	w = sendAuthRequest(r, "acme-client", "acme")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, acme.ID.String(), w.Body.String())
+
+	w = sendAuthRequest(r, "acme-client", "globex")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = sendAuthRequest(r, "legacy-client", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, entities.DefaultOrganizationID.String(), w.Body.String())
+
+	w = sendAuthRequest(r, "legacy-client", "acme")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+// TestAuthMiddleware_UserTokenOrganization tests that a user's token can't be used in another organization
+func TestAuthMiddleware_UserTokenOrganization(t *testing.T) {
+	acme := &entities.Organization{ID: uuid.New(), Slug: "acme"}
+	globex := &entities.Organization{ID: uuid.New(), Slug: "globex"}
+	tokens := staticTokens{
+		"acme-user": {
+			"user_id":    uuid.NewString(),
+			"session_id": uuid.NewString(),
+			"org":        acme.ID.String(),
+			"role":       entities.AdminRole.String(),
+			"token_type": "access",
+		},
+	}
+	r := newTestAuthRouter(tokens, staticOrganizations{"acme": acme, "globex": globex})
+
+	w := sendAuthRequest(r, "acme-user", "acme")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, acme.ID.String(), w.Body.String())
+
+	w = sendAuthRequest(r, "acme-user", "globex")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
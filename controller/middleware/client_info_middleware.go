@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/gin-gonic/gin"
+)
+
+// ClientInfoMiddleware stores the client IP and user agent on the request
+// context so services can record where a request came from
+func ClientInfoMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := entities.WithClientInfo(c.Request.Context(), entities.ClientInfo{
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
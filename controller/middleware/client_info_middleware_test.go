@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClientInfoRouter(t *testing.T, trustedProxies []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	require.NoError(t, r.SetTrustedProxies(trustedProxies))
+	r.Use(ClientInfoMiddleware())
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, entities.ClientInfoFromContext(c.Request.Context()).IP)
+	})
+	return r
+}
+
+func sendClientInfoRequest(r *gin.Engine, remoteIP, forwardedFor string) string {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteIP + ":1234"
+	req.Header.Set("X-Forwarded-For", forwardedFor)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Body.String()
+}
+
+// TestClientInfo_IgnoresSpoofedForwardedFor tests that the IP login failures
+// are counted against can't be picked by the client
+func TestClientInfo_IgnoresSpoofedForwardedFor(t *testing.T) {
+	r := newTestClientInfoRouter(t, nil)
+
+	assert.Equal(t, "203.0.113.7", sendClientInfoRequest(r, "203.0.113.7", "198.51.100.1"))
+}
+
+// TestClientInfo_TrustedProxy tests that the address forwarded by a trusted proxy is used
+func TestClientInfo_TrustedProxy(t *testing.T) {
+	r := newTestClientInfoRouter(t, []string{"10.0.0.0/8"})
+
+	assert.Equal(t, "198.51.100.1", sendClientInfoRequest(r, "10.0.0.1", "198.51.100.1"))
+	// A client can't get around it by connecting directly
+	assert.Equal(t, "203.0.113.7", sendClientInfoRequest(r, "203.0.113.7", "198.51.100.1"))
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/infrastructure/logger"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// staticOrganizations knows the organizations listed by slug
+type staticOrganizations map[string]*entities.Organization
+
+func (o staticOrganizations) FindOrganizationBySlug(ctx context.Context, slug string) (*entities.Organization, error) {
+	organization, ok := o[slug]
+	if !ok {
+		return nil, errors.ErrOrganizationNotFound
+	}
+	return organization, nil
+}
+
+func newTestOrganizationRouter(organizations staticOrganizations) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	testLogger := logger.NewZerologLogger(ports.LoggerConfig{
+		Level:       "fatal",
+		Environment: "test",
+		ServiceName: "go_auth",
+		Output:      io.Discard,
+	})
+
+	r := gin.New()
+	r.Use(NewOrganizationMiddleware(organizations, testLogger))
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, entities.OrganizationIDFromContext(c.Request.Context()).String())
+	})
+	return r
+}
+
+func sendOrganizationRequest(r *gin.Engine, slug string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if slug != "" {
+		req.Header.Set(OrganizationHeader, slug)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestOrganizationMiddleware tests that requests are scoped to the organization named in the header
+func TestOrganizationMiddleware(t *testing.T) {
+	acme := &entities.Organization{ID: uuid.New(), Slug: "acme"}
+	r := newTestOrganizationRouter(staticOrganizations{"acme": acme})
+
+	w := sendOrganizationRequest(r, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, entities.DefaultOrganizationID.String(), w.Body.String())
+
+	w = sendOrganizationRequest(r, "acme")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, acme.ID.String(), w.Body.String())
+
+	w = sendOrganizationRequest(r, "unknown")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
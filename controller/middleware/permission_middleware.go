@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/amirdashtii/go_auth/infrastructure/logger"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/amirdashtii/go_auth/internal/core/service"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	permissionServiceOnce sync.Once
+	permissionService     ports.PermissionService
+)
+
+// RequirePermission lets a request through only if the role of the
+// authenticated user was granted permission. It runs after AuthMiddleware.
+func RequirePermission(permission string) gin.HandlerFunc {
+	appLogger := logger.NewZerologLogger(ports.LoggerConfig{
+		Level:       "info",
+		Environment: "development",
+		ServiceName: "go_auth",
+		Output:      os.Stdout,
+	})
+
+	return NewPermissionMiddleware(permission, sharedPermissionService(), appLogger)
+}
+
+// HasPermission reports whether the role of the authenticated user was
+// granted permission, for handlers whose permission depends on the request
+// body. It runs after AuthMiddleware.
+func HasPermission(c *gin.Context, permission string) (bool, error) {
+	if !scopeAllows(c, permission) {
+		return false, nil
+	}
+	if isMachineClient(c) {
+		return true, nil
+	}
+	return sharedPermissionService().HasPermission(c.Request.Context(), c.GetString("role"), permission)
+}
+
+// isMachineClient reports whether the request was authenticated with the
+// token of a machine client, which has no user or role
+func isMachineClient(c *gin.Context) bool {
+	return c.GetString("client_id") != "" && c.GetString("user_id") == ""
+}
+
+// scopeAllows reports whether the scope of the request's credential allows
+// permission. API keys and machine clients only have the permissions of
+// their scope that are among the API key and client scopes.
+func scopeAllows(c *gin.Context, permission string) bool {
+	var scopes []string
+	switch {
+	case c.GetString("api_key_id") != "":
+		scopes = entities.APIKeyScopes
+	case isMachineClient(c):
+		scopes = entities.ClientScopes
+	default:
+		return true
+	}
+	return slices.Contains(scopes, permission) &&
+		slices.Contains(strings.Fields(c.GetString("scope")), permission)
+}
+
+func sharedPermissionService() ports.PermissionService {
+	permissionServiceOnce.Do(func() {
+		permissionService = service.NewPermissionService()
+	})
+	return permissionService
+}
+
+// NewPermissionMiddleware answers 401 to requests without a user role or a
+// machine client, 403 to credentials whose scope doesn't allow permission,
+// and 403 to users whose role wasn't granted permission by permissions
+func NewPermissionMiddleware(permission string, permissions ports.PermissionService, logger ports.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		if role == "" && !isMachineClient(c) {
+			logger.Error("User not authenticated",
+				ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+			)
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": errors.ErrUserNotAuthenticated,
+			})
+			c.Abort()
+			return
+		}
+
+		if !scopeAllows(c, permission) {
+			logger.Error("Credential scope does not allow permission",
+				ports.F("error", errors.ErrInsufficientScope.Message.English),
+				ports.F("client_id", c.GetString("client_id")),
+				ports.F("api_key_id", c.GetString("api_key_id")),
+				ports.F("permission", permission),
+			)
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": errors.ErrInsufficientScope,
+			})
+			c.Abort()
+			return
+		}
+
+		// The scope is all a machine client is granted
+		if isMachineClient(c) {
+			c.Next()
+			return
+		}
+
+		allowed, err := permissions.HasPermission(c.Request.Context(), role, permission)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err,
+			})
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			logger.Error("User not authorized",
+				ports.F("error", errors.ErrForbidden.Message.English),
+				ports.F("user_id", c.GetString("user_id")),
+				ports.F("permission", permission),
+			)
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": errors.ErrForbidden,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/infrastructure/logger"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// staticPermissions grants the permissions listed for each role
+type staticPermissions map[string][]string
+
+func (p staticPermissions) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	return slices.Contains(p[role], permission), nil
+}
+
+func newTestPermissionRouter(permission string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	testLogger := logger.NewZerologLogger(ports.LoggerConfig{
+		Level:       "fatal",
+		Environment: "test",
+		ServiceName: "go_auth",
+		Output:      io.Discard,
+	})
+
+	permissions := staticPermissions{
+		entities.SuperAdminRole.String(): {entities.PermissionUsersRead, entities.PermissionKeysRotate},
+		entities.AdminRole.String():      {entities.PermissionUsersRead},
+	}
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if role := c.GetHeader("X-Test-Role"); role != "" {
+			c.Set("role", role)
+		}
+		if keyID := c.GetHeader("X-Test-API-Key"); keyID != "" {
+			c.Set("api_key_id", keyID)
+			c.Set("scope", c.GetHeader("X-Test-Scope"))
+		}
+		if clientID := c.GetHeader("X-Test-Client"); clientID != "" {
+			c.Set("client_id", clientID)
+			c.Set("scope", c.GetHeader("X-Test-Scope"))
+		}
+	})
+	r.GET("/", NewPermissionMiddleware(permission, permissions, testLogger), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func sendPermissionRequest(r *gin.Engine, role string) int {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if role != "" {
+		req.Header.Set("X-Test-Role", role)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+// TestRequirePermission tests that only roles granted the permission get through
+func TestRequirePermission(t *testing.T) {
+	r := newTestPermissionRouter(entities.PermissionKeysRotate)
+
+	assert.Equal(t, http.StatusOK, sendPermissionRequest(r, entities.SuperAdminRole.String()))
+	assert.Equal(t, http.StatusForbidden, sendPermissionRequest(r, entities.AdminRole.String()))
+	assert.Equal(t, http.StatusForbidden, sendPermissionRequest(r, entities.UserRole.String()))
+	assert.Equal(t, http.StatusUnauthorized, sendPermissionRequest(r, ""))
+}
+
+func sendClientPermissionRequest(r *gin.Engine, scope string) int {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Test-Client", "billing-job")
+	req.Header.Set("X-Test-Scope", scope)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+// TestRequirePermission_MachineClient tests that machine clients only get through with the permission in their scope, and only for client scopes
+func TestRequirePermission_MachineClient(t *testing.T) {
+	r := newTestPermissionRouter(entities.PermissionUsersRead)
+	assert.Equal(t, http.StatusOK, sendClientPermissionRequest(r, "audit:read users:read"))
+	assert.Equal(t, http.StatusForbidden, sendClientPermissionRequest(r, "audit:read"))
+	assert.Equal(t, http.StatusForbidden, sendClientPermissionRequest(r, ""))
+
+	r = newTestPermissionRouter(entities.PermissionKeysRotate)
+	assert.Equal(t, http.StatusForbidden, sendClientPermissionRequest(r, "keys:rotate"))
+}
+
+func sendAPIKeyPermissionRequest(r *gin.Engine, role, scope string) int {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Test-Role", role)
+	req.Header.Set("X-Test-API-Key", "key-1")
+	req.Header.Set("X-Test-Scope", scope)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+// TestRequirePermission_APIKey tests that API keys need the permission both in their scope and in the role of their user
+func TestRequirePermission_APIKey(t *testing.T) {
+	r := newTestPermissionRouter(entities.PermissionKeysRotate)
+
+	assert.Equal(t, http.StatusOK, sendAPIKeyPermissionRequest(r, entities.SuperAdminRole.String(), "users:read keys:rotate"))
+	assert.Equal(t, http.StatusForbidden, sendAPIKeyPermissionRequest(r, entities.SuperAdminRole.String(), "users:read"))
+	assert.Equal(t, http.StatusForbidden, sendAPIKeyPermissionRequest(r, entities.SuperAdminRole.String(), ""))
+	assert.Equal(t, http.StatusForbidden, sendAPIKeyPermissionRequest(r, entities.AdminRole.String(), "keys:rotate"))
+}
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/infrastructure/logger"
+	"github.com/amirdashtii/go_auth/infrastructure/repository"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRateLimitRouter(rule config.RateLimitRule, setUser func(c *gin.Context)) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	testLogger := logger.NewZerologLogger(ports.LoggerConfig{
+		Level:       "fatal",
+		Environment: "test",
+		ServiceName: "go_auth",
+		Output:      io.Discard,
+	})
+
+	r := gin.New()
+	if setUser != nil {
+		r.Use(setUser)
+	}
+	r.Use(NewRateLimitMiddleware("test", rule, repository.NewMemoryRateLimiter(), testLogger))
+	r.GET("/a", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/b", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func sendRateLimitRequest(r *gin.Engine, path, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestRateLimit_ByIP tests that each address gets its own limit and the headers describe it
+func TestRateLimit_ByIP(t *testing.T) {
+	r := newTestRateLimitRouter(config.RateLimitRule{Requests: 2, Window: time.Minute, KeyBy: RateLimitByIP}, nil)
+
+	w := sendRateLimitRequest(r, "/a", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+	assert.NotEmpty(t, w.Header().Get("RateLimit-Reset"))
+
+	w = sendRateLimitRequest(r, "/b", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	w = sendRateLimitRequest(r, "/a", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.Greater(t, retryAfter, 0)
+	assert.LessOrEqual(t, retryAfter, 120)
+
+	// Another address isn't affected
+	w = sendRateLimitRequest(r, "/a", "10.0.0.2")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// TestRateLimit_ByIPBehindProxy tests that X-Forwarded-For only picks the address when it comes from a trusted proxy
+func TestRateLimit_ByIPBehindProxy(t *testing.T) {
+	r := newTestRateLimitRouter(config.RateLimitRule{Requests: 1, Window: time.Minute, KeyBy: RateLimitByIP}, nil)
+	require.NoError(t, r.SetTrustedProxies([]string{"10.0.0.0/8"}))
+
+	send := func(remoteIP, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/a", nil)
+		req.RemoteAddr = remoteIP + ":1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// A client connecting directly can't get a fresh limit by making up addresses
+	assert.Equal(t, http.StatusOK, send("203.0.113.7", "198.51.100.1"))
+	assert.Equal(t, http.StatusTooManyRequests, send("203.0.113.7", "198.51.100.2"))
+
+	// Clients behind the proxy are told apart by the address it forwards
+	assert.Equal(t, http.StatusOK, send("10.0.0.1", "198.51.100.3"))
+	assert.Equal(t, http.StatusOK, send("10.0.0.1", "198.51.100.4"))
+	assert.Equal(t, http.StatusTooManyRequests, send("10.0.0.1", "198.51.100.3"))
+}
+
+// TestRateLimit_ByUser tests that authenticated users are limited separately even from one address
+func TestRateLimit_ByUser(t *testing.T) {
+	setUser := func(c *gin.Context) {
+		if userID := c.GetHeader("X-Test-User"); userID != "" {
+			c.Set("user_id", userID)
+		}
+	}
+	r := newTestRateLimitRouter(config.RateLimitRule{Requests: 1, Window: time.Minute, KeyBy: RateLimitByUser}, setUser)
+
+	send := func(userID string) int {
+		req := httptest.NewRequest(http.MethodGet, "/a", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Test-User", userID)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("alice"))
+	assert.Equal(t, http.StatusTooManyRequests, send("alice"))
+	assert.Equal(t, http.StatusOK, send("bob"))
+	// Without a user the address is limited
+	assert.Equal(t, http.StatusOK, send(""))
+	assert.Equal(t, http.StatusTooManyRequests, send(""))
+}
+
+// TestRateLimit_ByRoute tests that a route limit is shared by every client and routes don't share limits
+func TestRateLimit_ByRoute(t *testing.T) {
+	r := newTestRateLimitRouter(config.RateLimitRule{Requests: 1, Window: time.Minute, KeyBy: RateLimitByRoute}, nil)
+
+	assert.Equal(t, http.StatusOK, sendRateLimitRequest(r, "/a", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, sendRateLimitRequest(r, "/a", "10.0.0.2").Code)
+	assert.Equal(t, http.StatusOK, sendRateLimitRequest(r, "/b", "10.0.0.2").Code)
+}
+
+// TestRateLimit_CountsRejectedCredentials tests that a limit ahead of authentication counts requests the auth step rejects
+func TestRateLimit_CountsRejectedCredentials(t *testing.T) {
+	rejectAll := func(c *gin.Context) { c.AbortWithStatus(http.StatusUnauthorized) }
+	r := newTestRateLimitRouter(config.RateLimitRule{Requests: 2, Window: time.Minute, KeyBy: RateLimitByIP}, nil)
+	r.Use(rejectAll)
+	r.GET("/c", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	assert.Equal(t, http.StatusUnauthorized, sendRateLimitRequest(r, "/c", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusUnauthorized, sendRateLimitRequest(r, "/c", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, sendRateLimitRequest(r, "/c", "10.0.0.1").Code)
+}
+
+// TestMemoryRateLimiter_SlidingWindow tests that requests of the previous window count by how much of it still overlaps
+func TestMemoryRateLimiter_SlidingWindow(t *testing.T) {
+	limiter := repository.NewMemoryRateLimiter()
+	start := time.Unix(1700000040, 0) // the start of a one minute window
+
+	now := start
+	limiter.SetClock(func() time.Time { return now })
+
+	for i := 0; i < 4; i++ {
+		result, err := limiter.Allow(t.Context(), "key", 4, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+
+	// A quarter into the next window three quarters of the old requests
+	// still count, leaving room for one
+	now = start.Add(75 * time.Second)
+	result, err := limiter.Allow(t.Context(), "key", 4, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	result, err = limiter.Allow(t.Context(), "key", 4, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 15*time.Second, result.RetryAfter)
+
+	now = now.Add(result.RetryAfter)
+	result, err = limiter.Allow(t.Context(), "key", 4, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+}
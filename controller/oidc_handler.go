@@ -0,0 +1,239 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/amirdashtii/go_auth/internal/core/service"
+	"github.com/gin-gonic/gin"
+)
+
+// OpenIDConfigurationHandler godoc
+// @Summary Get OpenID Connect discovery document
+// @Description Get the endpoints and capabilities of the OpenID Connect provider
+// @Tags oidc
+// @Produce json
+// @Success 200 {object} dto.OpenIDConfiguration
+// @Failure 500 {object} map[string]interface{}
+// @Router /.well-known/openid-configuration [get]
+func (h *AuthHTTPHandler) OpenIDConfigurationHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	configuration, err := h.svc.OpenIDConfiguration(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, configuration)
+}
+
+// AuthorizeHandler godoc
+// @Summary Authorize an OAuth client
+// @Description Issue an authorization code for the signed in user and redirect back to the client. Only the code response type with an S256 PKCE challenge is supported.
+// @Tags oidc
+// @Produce json
+// @Security BearerAuth
+// @Param response_type query string true "Must be code"
+// @Param client_id query string true "Client ID"
+// @Param redirect_uri query string true "Registered redirect URI"
+// @Param scope query string true "Space separated scopes, must include openid"
+// @Param state query string false "Opaque value returned to the client"
+// @Param nonce query string false "Value copied into the ID token"
+// @Param code_challenge query string true "PKCE code challenge"
+// @Param code_challenge_method query string true "Must be S256"
+// @Success 302
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /authorize [get]
+func (h *AuthHTTPHandler) AuthorizeHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling authorize request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	var req dto.AuthorizeRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+			ports.F("request", req),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	redirectURI, err := h.svc.Authorize(ctx, userID, &req)
+	if redirectURI != "" {
+		// The client is known, so it gets the result, even a failed one
+		c.Redirect(http.StatusFound, redirectURI)
+		return
+	}
+
+	if errors.IsAuthenticationError(err) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": err,
+		})
+		return
+	}
+	if errors.IsValidationError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": err,
+	})
+}
+
+// TokenHandler godoc
+// @Summary OAuth token endpoint
+// @Description Redeem an authorization code or refresh token, or get a token for a backend service with client_credentials. Confidential clients authenticate with HTTP Basic or client_secret in the body.
+// @Tags oidc
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "authorization_code, refresh_token or client_credentials"
+// @Param code formData string false "Authorization code"
+// @Param redirect_uri formData string false "Redirect URI used to get the code"
+// @Param code_verifier formData string false "PKCE code verifier"
+// @Param refresh_token formData string false "Refresh token"
+// @Param client_id formData string false "Client ID"
+// @Param client_secret formData string false "Client secret"
+// @Param scope formData string false "Space separated scopes requested with client_credentials"
+// @Success 200 {object} dto.TokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /token [post]
+// @Router /oauth/token [post]
+func (h *AuthHTTPHandler) TokenHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	var req dto.TokenRequest
+
+	if err := c.ShouldBind(&req); err != nil {
+		h.logger.Error("Invalid token request",
+			ports.F("error", err),
+		)
+		oauthError(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	if clientID, clientSecret, ok := c.Request.BasicAuth(); ok {
+		req.ClientID = clientID
+		req.ClientSecret = clientSecret
+	}
+
+	tokens, err := h.svc.ExchangeToken(ctx, &req)
+	if err != nil {
+		oauthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// UserInfoHandler godoc
+// @Summary Get claims about the signed in user
+// @Description Get the OpenID Connect claims the access token's scope allows
+// @Tags oidc
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /userinfo [get]
+func (h *AuthHTTPHandler) UserInfoHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		h.logger.Error("Context cancelled while handling userinfo request",
+			ports.F("error", ctx.Err()),
+			ports.F("path", c.Request.URL.Path),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors.ErrContextCancelled.ErrorPersian(),
+		})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	claims, err := h.svc.UserInfo(ctx, userID, c.GetString("session_id"))
+	if err != nil {
+		if errors.IsNotFoundError(err) || errors.IsAuthenticationError(err) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, claims)
+}
+
+// oauthError writes err in the error response format of RFC 6749
+func oauthError(c *gin.Context, err error) {
+	code := service.OAuthErrorCode(err)
+
+	status := http.StatusBadRequest
+	switch code {
+	case "invalid_client":
+		status = http.StatusUnauthorized
+		c.Header("WWW-Authenticate", `Basic realm="go_auth"`)
+	case "server_error":
+		status = http.StatusInternalServerError
+	}
+
+	response := gin.H{"error": code}
+	if e, ok := err.(*errors.CustomError); ok {
+		response["error_description"] = e.Message.English
+	}
+	c.JSON(status, response)
+}
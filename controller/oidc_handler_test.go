@@ -0,0 +1,370 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestOIDCRouter serves the OpenID Connect endpoints of handler. The
+// authenticated endpoints act as userID with sessionID, or as no one when
+// userID is empty.
+func newTestOIDCRouter(svc *MockAuthService, userID, sessionID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	handler := &AuthHTTPHandler{svc: svc, logger: newTestLogger()}
+	authenticated := func(c *gin.Context) {
+		if userID != "" {
+			c.Set("user_id", userID)
+			c.Set("session_id", sessionID)
+		}
+	}
+
+	r := gin.New()
+	r.GET("/.well-known/jwks.json", handler.JWKSHandler)
+	r.GET("/.well-known/openid-configuration", handler.OpenIDConfigurationHandler)
+	r.GET("/authorize", authenticated, handler.AuthorizeHandler)
+	r.POST("/token", handler.TokenHandler)
+	r.GET("/userinfo", authenticated, handler.UserInfoHandler)
+	return r
+}
+
+// TestJWKSHandler tests that the signing keys are published and may be cached
+func TestJWKSHandler(t *testing.T) {
+	mockSvc := new(MockAuthService)
+	mockSvc.On("JWKS").Return(&entities.JSONWebKeySet{Keys: []entities.JSONWebKey{
+		{KeyType: "OKP", Use: "sig", Algorithm: "EdDSA", KeyID: "key-1", Curve: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+	}}, nil).Once()
+
+	w := httptest.NewRecorder()
+	newTestOIDCRouter(mockSvc, "", "").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+	assert.Equal(t, map[string]interface{}{"keys": []interface{}{map[string]interface{}{
+		"kty": "OKP",
+		"use": "sig",
+		"alg": "EdDSA",
+		"kid": "key-1",
+		"crv": "Ed25519",
+		"x":   "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+	}}}, decodeBody(t, w))
+	mockSvc.AssertExpectations(t)
+}
+
+// TestOpenIDConfigurationHandler tests that the discovery document is served and may be cached
+func TestOpenIDConfigurationHandler(t *testing.T) {
+	mockSvc := new(MockAuthService)
+	mockSvc.On("OpenIDConfiguration").Return(&dto.OpenIDConfiguration{
+		Issuer:                "https://auth.example.com",
+		AuthorizationEndpoint: "https://auth.example.com/authorize",
+		TokenEndpoint:         "https://auth.example.com/token",
+		JWKSURI:               "https://auth.example.com/.well-known/jwks.json",
+	}, nil).Once()
+
+	w := httptest.NewRecorder()
+	newTestOIDCRouter(mockSvc, "", "").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+	body := decodeBody(t, w)
+	assert.Equal(t, "https://auth.example.com", body["issuer"])
+	assert.Equal(t, "https://auth.example.com/token", body["token_endpoint"])
+	assert.Equal(t, "https://auth.example.com/.well-known/jwks.json", body["jwks_uri"])
+	mockSvc.AssertExpectations(t)
+}
+
+// TestAuthorizeHandler tests that the result of an authorization goes back to a known client and errors to the user otherwise
+func TestAuthorizeHandler(t *testing.T) {
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {"test-client"},
+		"redirect_uri":          {"https://app.example.com/callback"},
+		"scope":                 {"openid profile"},
+		"state":                 {"xyz"},
+		"code_challenge":        {"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"},
+		"code_challenge_method": {"S256"},
+	}.Encode()
+
+	tests := []struct {
+		name             string
+		userID           string
+		query            string
+		mockSetup        func(*MockAuthService)
+		expectedStatus   int
+		expectedLocation string
+		expectedBody     map[string]interface{}
+	}{
+		{
+			name:   "code issued",
+			userID: "user123",
+			query:  query,
+			mockSetup: func(m *MockAuthService) {
+				m.On("Authorize", "user123", mock.MatchedBy(func(req *dto.AuthorizeRequest) bool {
+					return req.ClientID == "test-client" && req.State == "xyz" && req.CodeChallengeMethod == "S256"
+				})).Return("https://app.example.com/callback?code=abc&state=xyz", nil).Once()
+			},
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://app.example.com/callback?code=abc&state=xyz",
+		},
+		{
+			name:   "error reported to the client",
+			userID: "user123",
+			query:  query,
+			mockSetup: func(m *MockAuthService) {
+				m.On("Authorize", "user123", mock.Anything).
+					Return("https://app.example.com/callback?error=invalid_scope&state=xyz", errors.ErrInvalidScope).Once()
+			},
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://app.example.com/callback?error=invalid_scope&state=xyz",
+		},
+		{
+			name:   "unknown client",
+			userID: "user123",
+			query:  query,
+			mockSetup: func(m *MockAuthService) {
+				m.On("Authorize", "user123", mock.Anything).Return("", errors.ErrInvalidClient).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   errorBody(errors.ErrInvalidClient),
+		},
+		{
+			name:   "unregistered redirect URI",
+			userID: "user123",
+			query:  query,
+			mockSetup: func(m *MockAuthService) {
+				m.On("Authorize", "user123", mock.Anything).Return("", errors.ErrInvalidRedirectURI).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidRedirectURI),
+		},
+		{
+			name:           "missing parameters",
+			userID:         "user123",
+			query:          "client_id=test-client",
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidRequest),
+		},
+		{
+			name:           "not signed in",
+			query:          query,
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   errorBody(errors.ErrUserNotAuthenticated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAuthService)
+			tt.mockSetup(mockSvc)
+
+			w := httptest.NewRecorder()
+			newTestOIDCRouter(mockSvc, tt.userID, "session123").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/authorize?"+tt.query, nil))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedLocation != "" {
+				assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+			} else {
+				assert.Equal(t, tt.expectedBody, decodeBody(t, w))
+			}
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+// TestTokenHandler tests that the token endpoint takes client credentials from HTTP Basic and answers in the format of RFC 6749
+func TestTokenHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		form           url.Values
+		basicAuth      []string
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+		expectedHeader map[string]string
+	}{
+		{
+			name: "authorization code redeemed",
+			form: url.Values{
+				"grant_type":    {"authorization_code"},
+				"code":          {"the-code"},
+				"redirect_uri":  {"https://app.example.com/callback"},
+				"code_verifier": {"dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"},
+				"client_id":     {"test-client"},
+			},
+			mockSetup: func(m *MockAuthService) {
+				m.On("ExchangeToken", mock.MatchedBy(func(req *dto.TokenRequest) bool {
+					return req.GrantType == "authorization_code" && req.Code == "the-code" && req.ClientID == "test-client"
+				})).Return(&dto.TokenResponse{
+					AccessToken:  "access_token",
+					TokenType:    "Bearer",
+					ExpiresIn:    900,
+					RefreshToken: "refresh_token",
+					IDToken:      "id_token",
+					Scope:        "openid",
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"access_token":  "access_token",
+				"token_type":    "Bearer",
+				"expires_in":    float64(900),
+				"refresh_token": "refresh_token",
+				"id_token":      "id_token",
+				"scope":         "openid",
+			},
+			expectedHeader: map[string]string{"Cache-Control": "no-store", "Pragma": "no-cache"},
+		},
+		{
+			name:      "client credentials with HTTP Basic",
+			form:      url.Values{"grant_type": {"client_credentials"}, "client_id": {"ignored"}},
+			basicAuth: []string{"service-client", "secret"},
+			mockSetup: func(m *MockAuthService) {
+				m.On("ExchangeToken", mock.MatchedBy(func(req *dto.TokenRequest) bool {
+					return req.ClientID == "service-client" && req.ClientSecret == "secret"
+				})).Return(&dto.TokenResponse{AccessToken: "access_token", TokenType: "Bearer", ExpiresIn: 900}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"access_token": "access_token",
+				"token_type":   "Bearer",
+				"expires_in":   float64(900),
+			},
+		},
+		{
+			name: "code already redeemed",
+			form: url.Values{"grant_type": {"authorization_code"}, "code": {"the-code"}, "client_id": {"test-client"}},
+			mockSetup: func(m *MockAuthService) {
+				m.On("ExchangeToken", mock.Anything).Return(nil, errors.ErrInvalidGrant).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error":             "invalid_grant",
+				"error_description": errors.ErrInvalidGrant.Message.English,
+			},
+		},
+		{
+			name:      "wrong client secret",
+			form:      url.Values{"grant_type": {"client_credentials"}},
+			basicAuth: []string{"service-client", "wrong"},
+			mockSetup: func(m *MockAuthService) {
+				m.On("ExchangeToken", mock.Anything).Return(nil, errors.ErrInvalidClient).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody: map[string]interface{}{
+				"error":             "invalid_client",
+				"error_description": errors.ErrInvalidClient.Message.English,
+			},
+			expectedHeader: map[string]string{"WWW-Authenticate": `Basic realm="go_auth"`},
+		},
+		{
+			name:           "missing grant type",
+			form:           url.Values{"client_id": {"test-client"}},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error":             "invalid_request",
+				"error_description": errors.ErrInvalidRequest.Message.English,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAuthService)
+			tt.mockSetup(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.basicAuth != nil {
+				req.SetBasicAuth(tt.basicAuth[0], tt.basicAuth[1])
+			}
+			w := httptest.NewRecorder()
+			newTestOIDCRouter(mockSvc, "", "").ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, decodeBody(t, w))
+			for name, value := range tt.expectedHeader {
+				assert.Equal(t, value, w.Header().Get(name), name)
+			}
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUserInfoHandler tests that the claims of the signed in user are returned for their session
+func TestUserInfoHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:   "claims returned",
+			userID: "user123",
+			mockSetup: func(m *MockAuthService) {
+				m.On("UserInfo", "user123", "session123").Return(map[string]interface{}{
+					"sub":  "user123",
+					"name": "Sara Ahmadi",
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"sub":  "user123",
+				"name": "Sara Ahmadi",
+			},
+		},
+		{
+			name:   "session ended",
+			userID: "user123",
+			mockSetup: func(m *MockAuthService) {
+				m.On("UserInfo", "user123", "session123").Return(nil, errors.ErrSessionNotFound).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   errorBody(errors.ErrSessionNotFound),
+		},
+		{
+			name:   "service error",
+			userID: "user123",
+			mockSetup: func(m *MockAuthService) {
+				m.On("UserInfo", "user123", "session123").Return(nil, errors.ErrGetUser).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   errorBody(errors.ErrGetUser),
+		},
+		{
+			name:           "not signed in",
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   errorBody(errors.ErrUserNotAuthenticated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAuthService)
+			tt.mockSetup(mockSvc)
+
+			w := httptest.NewRecorder()
+			newTestOIDCRouter(mockSvc, tt.userID, "session123").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/userinfo", nil))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, decodeBody(t, w))
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -18,12 +18,14 @@ import (
 )
 
 type UserHTTPHandler struct {
-	svc    ports.UserService
-	logger ports.Logger
+	svc     ports.UserService
+	authSvc ports.AuthService
+	logger  ports.Logger
 }
 
 func NewUserHTTPHandler() *UserHTTPHandler {
 	svc := service.NewUserService()
+	authSvc := service.NewAuthService()
 
 	// Initialize logger with both file and console output
 	loggerConfig := ports.LoggerConfig{
@@ -36,8 +38,9 @@ func NewUserHTTPHandler() *UserHTTPHandler {
 	appLogger := logger.NewZerologLogger(loggerConfig)
 
 	return &UserHTTPHandler{
-		svc:    svc,
-		logger: appLogger,
+		svc:     svc,
+		authSvc: authSvc,
+		logger:  appLogger,
 	}
 }
 
@@ -45,11 +48,33 @@ func NewUserRoutes(r *gin.Engine) {
 	h := NewUserHTTPHandler()
 
 	userGroup := r.Group("/users")
-	userGroup.Use(middleware.AuthMiddleware())
+	userGroup.Use(middleware.AuthenticateRateLimit(), middleware.AuthMiddleware(), middleware.RateLimit("users"))
 	userGroup.GET("/me", h.GetUserProfileHandler)
 	userGroup.PUT("/me", h.UpdateUserProfileHandler)
-	userGroup.PUT("/me/change-password", h.ChangePasswordHandler)
-	userGroup.DELETE("/me", h.DeleteUserProfileHandler)
+
+	// Credentials and the account itself can't be managed with an API key
+	userCredentialsGroup := r.Group("/users")
+	userCredentialsGroup.Use(middleware.AuthenticateRateLimit(), middleware.SessionAuthMiddleware(), middleware.RateLimit("users"))
+	userCredentialsGroup.PUT("/me/change-password", h.ChangePasswordHandler)
+	userCredentialsGroup.DELETE("/me", h.DeleteUserProfileHandler)
+
+	profileGroup := r.Group("/profile")
+	profileGroup.Use(middleware.AuthenticateRateLimit(), middleware.AuthMiddleware(), middleware.RateLimit("profile"))
+	profileGroup.POST("/me/email/verification", h.RequestEmailVerificationHandler)
+
+	profileCredentialsGroup := r.Group("/profile")
+	profileCredentialsGroup.Use(middleware.AuthenticateRateLimit(), middleware.SessionAuthMiddleware(), middleware.RateLimit("profile"))
+	profileCredentialsGroup.GET("/me/sessions", h.ListSessionsHandler)
+	profileCredentialsGroup.DELETE("/me/sessions/:id", h.RevokeSessionHandler)
+	profileCredentialsGroup.POST("/me/phone", h.ChangePhoneNumberHandler)
+	profileCredentialsGroup.POST("/me/phone/confirm", h.ConfirmPhoneNumberHandler)
+	profileCredentialsGroup.POST("/me/api-keys", h.CreateAPIKeyHandler)
+	profileCredentialsGroup.GET("/me/api-keys", h.ListAPIKeysHandler)
+	profileCredentialsGroup.DELETE("/me/api-keys/:id", h.RevokeAPIKeyHandler)
+	profileCredentialsGroup.POST("/me/mfa/totp", h.EnrollTOTPHandler)
+	profileCredentialsGroup.POST("/me/mfa/totp/confirm", h.ConfirmTOTPHandler)
+	profileCredentialsGroup.POST("/me/webauthn/register/options", h.WebAuthnRegisterOptionsHandler)
+	profileCredentialsGroup.POST("/me/webauthn/register/finish", h.WebAuthnRegisterFinishHandler)
 }
 
 // GetUserProfileHandler godoc
@@ -190,14 +215,38 @@ func (h *UserHTTPHandler) UpdateUserProfileHandler(c *gin.Context) {
 	}
 
 	if err := h.svc.UpdateProfile(ctx, &userIDUUID, &req); err != nil {
+		if errors.IsValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err,
 		})
 		return
 	}
 
+	message := "Profile updated successfully"
+	if req.Email != "" {
+		// The profile is saved either way, a link that couldn't be sent can
+		// be requested again from /profile/me/email/verification
+		err := h.authSvc.RequestEmailVerification(ctx, userID.(string))
+		switch {
+		case err == nil:
+			message = "Profile updated successfully, open the link sent to your email to confirm it"
+		case err == errors.ErrNoEmailToVerify || errors.IsRateLimitError(err):
+		default:
+			h.logger.Error("Error sending email verification link",
+				ports.F("error", err),
+				ports.F("user_id", userID),
+			)
+			message = "Profile updated successfully, but the confirmation email could not be sent"
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Profile updated successfully",
+		"message": message,
 	})
 }
 
@@ -332,3 +381,682 @@ func (h *UserHTTPHandler) DeleteUserProfileHandler(c *gin.Context) {
 		"message": "Profile deleted successfully",
 	})
 }
+
+// ListSessionsHandler godoc
+// @Summary List user sessions
+// @Description List the devices the current user is logged in on
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/me/sessions [get]
+func (h *UserHTTPHandler) ListSessionsHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	sessions, err := h.authSvc.ListSessions(ctx, userID.(string), c.GetString("session_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// RevokeSessionHandler godoc
+// @Summary Revoke a user session
+// @Description Log out one of the current user's sessions and invalidate its tokens
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/me/sessions/{id} [delete]
+func (h *UserHTTPHandler) RevokeSessionHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	id := c.Param("id")
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		h.logger.Error("Invalid session ID",
+			ports.F("error", errors.ErrInvalidSessionID.Message.English),
+			ports.F("session_id", id),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidSessionID,
+		})
+		return
+	}
+
+	if err := h.authSvc.RevokeSession(ctx, userID.(string), sessionID.String()); err != nil {
+		if errors.IsNotFoundError(err) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Session revoked successfully",
+	})
+}
+
+// RequestEmailVerificationHandler godoc
+// @Summary Send an email verification link
+// @Description Email a link that confirms the current user's pending address, or their current one if it was never confirmed
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/me/email/verification [post]
+func (h *UserHTTPHandler) RequestEmailVerificationHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	if err := h.authSvc.RequestEmailVerification(ctx, userID.(string)); err != nil {
+		if errors.IsRateLimitError(err) {
+			respondRateLimited(c, err)
+			return
+		}
+		if errors.IsValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err,
+			})
+			return
+		}
+		if errors.IsNotFoundError(err) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Verification link sent",
+	})
+}
+
+// ChangePhoneNumberHandler godoc
+// @Summary Start a phone number change
+// @Description Send a verification code to the new phone number. The current number keeps working until the code is confirmed.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ChangePhoneNumberRequest true "Change Phone Number Request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/me/phone [post]
+func (h *UserHTTPHandler) ChangePhoneNumberHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	var req dto.ChangePhoneNumberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	if err := validators.ValidateChangePhoneNumberRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if err := h.authSvc.RequestPhoneNumberChange(ctx, userID.(string), &req); err != nil {
+		if errors.IsRateLimitError(err) {
+			respondRateLimited(c, err)
+			return
+		}
+		if errors.IsValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Verification code sent to the new phone number",
+	})
+}
+
+// ConfirmPhoneNumberHandler godoc
+// @Summary Confirm a phone number change
+// @Description Replace the current user's phone number with the new one using the code sent to it. The old number is notified.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ConfirmPhoneNumberRequest true "Confirm Phone Number Request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/me/phone/confirm [post]
+func (h *UserHTTPHandler) ConfirmPhoneNumberHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	var req dto.ConfirmPhoneNumberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	if err := validators.ValidateConfirmPhoneNumberRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if err := h.authSvc.ConfirmPhoneNumberChange(ctx, userID.(string), &req); err != nil {
+		if errors.IsRateLimitError(err) {
+			respondRateLimited(c, err)
+			return
+		}
+		if errors.IsAuthenticationError(err) || errors.IsValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err,
+			})
+			return
+		}
+		if errors.IsNotFoundError(err) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Phone number changed successfully",
+	})
+}
+
+// CreateAPIKeyHandler godoc
+// @Summary Create an API key
+// @Description Create an API key for the current user. The key is only returned in this response.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateAPIKeyRequest true "Create API Key Request"
+// @Success 201 {object} dto.APIKeyResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/me/api-keys [post]
+func (h *UserHTTPHandler) CreateAPIKeyHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+			ports.F("request", req),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	if err := validators.ValidateCreateAPIKeyRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	key, err := h.authSvc.CreateAPIKey(ctx, userID.(string), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+// ListAPIKeysHandler godoc
+// @Summary List API keys
+// @Description List the current user's API keys without their secrets
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/me/api-keys [get]
+func (h *UserHTTPHandler) ListAPIKeysHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	keys, err := h.authSvc.ListAPIKeys(ctx, userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"api_keys": keys})
+}
+
+// RevokeAPIKeyHandler godoc
+// @Summary Revoke an API key
+// @Description Delete one of the current user's API keys so it can no longer be used
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/me/api-keys/{id} [delete]
+func (h *UserHTTPHandler) RevokeAPIKeyHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	id := c.Param("id")
+	keyID, err := uuid.Parse(id)
+	if err != nil {
+		h.logger.Error("Invalid API key ID",
+			ports.F("error", errors.ErrInvalidAPIKeyID.Message.English),
+			ports.F("key_id", id),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidAPIKeyID,
+		})
+		return
+	}
+
+	if err := h.authSvc.RevokeAPIKey(ctx, userID.(string), keyID.String()); err != nil {
+		if errors.IsNotFoundError(err) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "API key revoked successfully",
+	})
+}
+
+// EnrollTOTPHandler godoc
+// @Summary Start setting up an authenticator app
+// @Description Get a new TOTP secret and otpauth:// URI for the current user. Two-factor authentication is turned on once a code is confirmed.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.TOTPEnrollmentResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/me/mfa/totp [post]
+func (h *UserHTTPHandler) EnrollTOTPHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	enrollment, err := h.authSvc.EnrollTOTP(ctx, userID.(string))
+	if err != nil {
+		if errors.IsValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmTOTPHandler godoc
+// @Summary Confirm an authenticator app
+// @Description Turn on two-factor authentication with a code from the authenticator being set up. The recovery codes are only returned in this response.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ConfirmTOTPRequest true "Confirm TOTP Request"
+// @Success 200 {object} dto.RecoveryCodesResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/me/mfa/totp/confirm [post]
+func (h *UserHTTPHandler) ConfirmTOTPHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	var req dto.ConfirmTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	if err := validators.ValidateConfirmTOTPRequest(&req, h.logger); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	codes, err := h.authSvc.ConfirmTOTP(ctx, userID.(string), req.Code)
+	if err != nil {
+		if errors.IsRateLimitError(err) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error": err,
+			})
+			return
+		}
+		if errors.IsNotFoundError(err) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err,
+			})
+			return
+		}
+		if errors.IsAuthenticationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, codes)
+}
+
+// WebAuthnRegisterOptionsHandler godoc
+// @Summary Start registering a passkey
+// @Description Get the options to pass to navigator.credentials.create() for adding a passkey to the current user
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.WebAuthnRegistrationOptions
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/me/webauthn/register/options [post]
+func (h *UserHTTPHandler) WebAuthnRegisterOptionsHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	options, err := h.authSvc.BeginWebAuthnRegistration(ctx, userID.(string))
+	if err != nil {
+		if errors.IsValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err,
+			})
+			return
+		}
+		if errors.IsNotFoundError(err) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// WebAuthnRegisterFinishHandler godoc
+// @Summary Finish registering a passkey
+// @Description Save the credential navigator.credentials.create() returned as a passkey of the current user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.WebAuthnRegistrationRequest true "WebAuthn Registration Request"
+// @Success 201 {object} dto.WebAuthnCredentialResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/me/webauthn/register/finish [post]
+func (h *UserHTTPHandler) WebAuthnRegisterFinishHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User not authenticated",
+			ports.F("error", errors.ErrUserNotAuthenticated.Message.English),
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": errors.ErrUserNotAuthenticated,
+		})
+		return
+	}
+
+	var req dto.WebAuthnRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request",
+			ports.F("error", errors.ErrInvalidRequest.Message.English),
+		)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errors.ErrInvalidRequest,
+		})
+		return
+	}
+
+	credential, err := h.authSvc.FinishWebAuthnRegistration(ctx, userID.(string), &req)
+	if err != nil {
+		if errors.IsValidationError(err) || errors.IsAuthenticationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, credential)
+}
@@ -0,0 +1,500 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testUserID = "7c0f5d2e-8a41-4b6f-9e3d-2a1b0c9d8e7f"
+
+// newTestUserRouter serves the profile endpoints of a handler using authSvc.
+// The endpoints act as userID, or as no one when userID is empty.
+func newTestUserRouter(authSvc *MockAuthService, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	handler := &UserHTTPHandler{authSvc: authSvc, logger: newTestLogger()}
+	authenticated := func(c *gin.Context) {
+		if userID != "" {
+			c.Set("user_id", userID)
+		}
+	}
+
+	r := gin.New()
+	r.Use(authenticated)
+	r.POST("/profile/me/phone", handler.ChangePhoneNumberHandler)
+	r.POST("/profile/me/phone/confirm", handler.ConfirmPhoneNumberHandler)
+	r.POST("/profile/me/mfa/totp", handler.EnrollTOTPHandler)
+	r.POST("/profile/me/mfa/totp/confirm", handler.ConfirmTOTPHandler)
+	r.POST("/profile/me/webauthn/register/options", handler.WebAuthnRegisterOptionsHandler)
+	r.POST("/profile/me/webauthn/register/finish", handler.WebAuthnRegisterFinishHandler)
+	return r
+}
+
+// TestEnrollTOTPHandler tests that a new secret is returned for the current user
+func TestEnrollTOTPHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:   "successful enrollment",
+			userID: testUserID,
+			mockSetup: func(m *MockAuthService) {
+				m.On("EnrollTOTP", testUserID).Return(&dto.TOTPEnrollmentResponse{
+					Secret:     "JBSWY3DPEHPK3PXP",
+					OTPAuthURI: "otpauth://totp/go_auth:09123456789?secret=JBSWY3DPEHPK3PXP&issuer=go_auth",
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"secret":      "JBSWY3DPEHPK3PXP",
+				"otpauth_uri": "otpauth://totp/go_auth:09123456789?secret=JBSWY3DPEHPK3PXP&issuer=go_auth",
+			},
+		},
+		{
+			name:   "already enabled",
+			userID: testUserID,
+			mockSetup: func(m *MockAuthService) {
+				m.On("EnrollTOTP", testUserID).Return(nil, errors.ErrMFAAlreadyEnabled).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrMFAAlreadyEnabled),
+		},
+		{
+			name:           "not authenticated",
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   errorBody(errors.ErrUserNotAuthenticated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAuthService)
+			tt.mockSetup(mockSvc)
+
+			w := httptest.NewRecorder()
+			newTestUserRouter(mockSvc, tt.userID).
+				ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/profile/me/mfa/totp", nil))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, decodeBody(t, w))
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+// TestConfirmTOTPHandler tests that confirming a code returns the recovery codes
+func TestConfirmTOTPHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    map[string]interface{}
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:        "successful confirmation",
+			requestBody: map[string]interface{}{"code": "123456"},
+			mockSetup: func(m *MockAuthService) {
+				m.On("ConfirmTOTP", testUserID, "123456").Return(&dto.RecoveryCodesResponse{
+					RecoveryCodes: []string{"3f9a1-c07e2", "b41d8-9e5a0"},
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"recovery_codes": []interface{}{"3f9a1-c07e2", "b41d8-9e5a0"},
+			},
+		},
+		{
+			name:           "malformed code",
+			requestBody:    map[string]interface{}{"code": "12ab"},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidOTPCode),
+		},
+		{
+			name:        "wrong code",
+			requestBody: map[string]interface{}{"code": "654321"},
+			mockSetup: func(m *MockAuthService) {
+				m.On("ConfirmTOTP", testUserID, "654321").Return(nil, errors.ErrInvalidMFACode).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidMFACode),
+		},
+		{
+			name:        "no enrollment in progress",
+			requestBody: map[string]interface{}{"code": "123456"},
+			mockSetup: func(m *MockAuthService) {
+				m.On("ConfirmTOTP", testUserID, "123456").Return(nil, errors.ErrMFAEnrollmentNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   errorBody(errors.ErrMFAEnrollmentNotFound),
+		},
+		{
+			name:        "too many attempts",
+			requestBody: map[string]interface{}{"code": "123456"},
+			mockSetup: func(m *MockAuthService) {
+				m.On("ConfirmTOTP", testUserID, "123456").Return(nil, errors.ErrMFAAttemptsExceeded).Once()
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   errorBody(errors.ErrMFAAttemptsExceeded),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAuthService)
+			tt.mockSetup(mockSvc)
+
+			w := httptest.NewRecorder()
+			newTestUserRouter(mockSvc, testUserID).
+				ServeHTTP(w, newJSONRequest(http.MethodPost, "/profile/me/mfa/totp/confirm", tt.requestBody))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, decodeBody(t, w))
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+// TestWebAuthnRegisterOptionsHandler tests that the creation options for a new passkey are returned
+func TestWebAuthnRegisterOptionsHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:   "successful options",
+			userID: testUserID,
+			mockSetup: func(m *MockAuthService) {
+				m.On("BeginWebAuthnRegistration", testUserID).Return(&dto.WebAuthnRegistrationOptions{
+					Challenge: "Y2hhbGxlbmdl",
+					RP:        dto.WebAuthnRelyingPartyEntity{ID: "auth.example.com", Name: "go_auth"},
+					User:      dto.WebAuthnUserEntity{ID: "dXNlcg", Name: "09123456789", DisplayName: "09123456789"},
+					PubKeyCredParams: []dto.WebAuthnCredentialParameters{
+						{Type: "public-key", Alg: -7},
+					},
+					Timeout:            300000,
+					ExcludeCredentials: []dto.WebAuthnCredentialDescriptor{},
+					AuthenticatorSelection: dto.WebAuthnAuthenticatorSelection{
+						ResidentKey:      "preferred",
+						UserVerification: "preferred",
+					},
+					Attestation: "none",
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"challenge": "Y2hhbGxlbmdl",
+				"rp":        map[string]interface{}{"id": "auth.example.com", "name": "go_auth"},
+				"user":      map[string]interface{}{"id": "dXNlcg", "name": "09123456789", "displayName": "09123456789"},
+				"pubKeyCredParams": []interface{}{
+					map[string]interface{}{"type": "public-key", "alg": float64(-7)},
+				},
+				"timeout":            float64(300000),
+				"excludeCredentials": []interface{}{},
+				"authenticatorSelection": map[string]interface{}{
+					"residentKey":        "preferred",
+					"requireResidentKey": false,
+					"userVerification":   "preferred",
+				},
+				"attestation": "none",
+			},
+		},
+		{
+			name:   "user not found",
+			userID: testUserID,
+			mockSetup: func(m *MockAuthService) {
+				m.On("BeginWebAuthnRegistration", testUserID).Return(nil, errors.ErrUserNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   errorBody(errors.ErrUserNotFound),
+		},
+		{
+			name:           "not authenticated",
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   errorBody(errors.ErrUserNotAuthenticated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAuthService)
+			tt.mockSetup(mockSvc)
+
+			w := httptest.NewRecorder()
+			newTestUserRouter(mockSvc, tt.userID).
+				ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/profile/me/webauthn/register/options", nil))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, decodeBody(t, w))
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+// TestWebAuthnRegisterFinishHandler tests that a verified credential is saved as a passkey
+func TestWebAuthnRegisterFinishHandler(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	credential := map[string]interface{}{
+		"id":   "Y3JlZGVudGlhbA",
+		"type": "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    "eyJ0eXBlIjoid2ViYXV0aG4uY3JlYXRlIn0",
+			"attestationObject": "o2NmbXRkbm9uZQ",
+			"transports":        []string{"internal"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    map[string]interface{}
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:        "successful registration",
+			requestBody: credential,
+			mockSetup: func(m *MockAuthService) {
+				m.On("FinishWebAuthnRegistration", testUserID, mock.MatchedBy(func(req *dto.WebAuthnRegistrationRequest) bool {
+					return req.ID == "Y3JlZGVudGlhbA" && req.Response.AttestationObject == "o2NmbXRkbm9uZQ"
+				})).Return(&dto.WebAuthnCredentialResponse{
+					ID:         "Y3JlZGVudGlhbA",
+					Transports: []string{"internal"},
+					CreatedAt:  createdAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: map[string]interface{}{
+				"id":         "Y3JlZGVudGlhbA",
+				"transports": []interface{}{"internal"},
+				"created_at": "2026-01-02T03:04:05Z",
+			},
+		},
+		{
+			name: "missing attestation",
+			requestBody: map[string]interface{}{
+				"id":       "Y3JlZGVudGlhbA",
+				"type":     "public-key",
+				"response": map[string]interface{}{"clientDataJSON": "eyJ0eXBlIjoid2ViYXV0aG4uY3JlYXRlIn0"},
+			},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidRequest),
+		},
+		{
+			name:        "expired challenge",
+			requestBody: credential,
+			mockSetup: func(m *MockAuthService) {
+				m.On("FinishWebAuthnRegistration", testUserID, mock.Anything).Return(nil, errors.ErrWebAuthnChallengeNotFound).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrWebAuthnChallengeNotFound),
+		},
+		{
+			name:        "already registered",
+			requestBody: credential,
+			mockSetup: func(m *MockAuthService) {
+				m.On("FinishWebAuthnRegistration", testUserID, mock.Anything).Return(nil, errors.ErrWebAuthnCredentialExists).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrWebAuthnCredentialExists),
+		},
+		{
+			name:        "service error",
+			requestBody: credential,
+			mockSetup: func(m *MockAuthService) {
+				m.On("FinishWebAuthnRegistration", testUserID, mock.Anything).Return(nil, errors.ErrCreateWebAuthnCredential).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   errorBody(errors.ErrCreateWebAuthnCredential),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAuthService)
+			tt.mockSetup(mockSvc)
+
+			w := httptest.NewRecorder()
+			newTestUserRouter(mockSvc, testUserID).
+				ServeHTTP(w, newJSONRequest(http.MethodPost, "/profile/me/webauthn/register/finish", tt.requestBody))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, decodeBody(t, w))
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+// TestChangePhoneNumberHandler tests that a code is sent to a new phone number
+func TestChangePhoneNumberHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    map[string]interface{}
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedHeader string
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:        "code sent",
+			requestBody: map[string]interface{}{"phone_number": "09129876543"},
+			mockSetup: func(m *MockAuthService) {
+				m.On("RequestPhoneNumberChange", testUserID, &dto.ChangePhoneNumberRequest{PhoneNumber: "09129876543"}).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"message": "Verification code sent to the new phone number",
+			},
+		},
+		{
+			name:           "invalid phone number",
+			requestBody:    map[string]interface{}{"phone_number": "9129876543"},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidPhoneNumber),
+		},
+		{
+			name:        "phone number taken",
+			requestBody: map[string]interface{}{"phone_number": "09129876543"},
+			mockSetup: func(m *MockAuthService) {
+				m.On("RequestPhoneNumberChange", testUserID, mock.Anything).Return(errors.ErrDuplicatePhoneNumber).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrDuplicatePhoneNumber),
+		},
+		{
+			name:        "code sent recently",
+			requestBody: map[string]interface{}{"phone_number": "09129876543"},
+			mockSetup: func(m *MockAuthService) {
+				m.On("RequestPhoneNumberChange", testUserID, mock.Anything).Return(errors.ErrOTPResendTooSoon.WithRetryAfter(45 * time.Second)).Once()
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedHeader: "45",
+			expectedBody: map[string]interface{}{
+				"error":       errorBody(errors.ErrOTPResendTooSoon)["error"],
+				"retry_after": float64(45),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAuthService)
+			tt.mockSetup(mockSvc)
+
+			w := httptest.NewRecorder()
+			newTestUserRouter(mockSvc, testUserID).
+				ServeHTTP(w, newJSONRequest(http.MethodPost, "/profile/me/phone", tt.requestBody))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedHeader, w.Header().Get("Retry-After"))
+			assert.Equal(t, tt.expectedBody, decodeBody(t, w))
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+// TestConfirmPhoneNumberHandler tests that the phone number is replaced with a valid code
+func TestConfirmPhoneNumberHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		requestBody    map[string]interface{}
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:        "phone number changed",
+			userID:      testUserID,
+			requestBody: map[string]interface{}{"code": "123456"},
+			mockSetup: func(m *MockAuthService) {
+				m.On("ConfirmPhoneNumberChange", testUserID, &dto.ConfirmPhoneNumberRequest{Code: "123456"}).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"message": "Phone number changed successfully",
+			},
+		},
+		{
+			name:           "malformed code",
+			userID:         testUserID,
+			requestBody:    map[string]interface{}{"code": "1234567"},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidOTPCode),
+		},
+		{
+			name:        "wrong code",
+			userID:      testUserID,
+			requestBody: map[string]interface{}{"code": "123456"},
+			mockSetup: func(m *MockAuthService) {
+				m.On("ConfirmPhoneNumberChange", testUserID, mock.Anything).Return(errors.ErrInvalidOTP).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   errorBody(errors.ErrInvalidOTP),
+		},
+		{
+			name:        "no change in progress",
+			userID:      testUserID,
+			requestBody: map[string]interface{}{"code": "123456"},
+			mockSetup: func(m *MockAuthService) {
+				m.On("ConfirmPhoneNumberChange", testUserID, mock.Anything).Return(errors.ErrPhoneChangeNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   errorBody(errors.ErrPhoneChangeNotFound),
+		},
+		{
+			name:        "too many attempts",
+			userID:      testUserID,
+			requestBody: map[string]interface{}{"code": "123456"},
+			mockSetup: func(m *MockAuthService) {
+				m.On("ConfirmPhoneNumberChange", testUserID, mock.Anything).Return(errors.ErrOTPAttemptsExceeded).Once()
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   errorBody(errors.ErrOTPAttemptsExceeded),
+		},
+		{
+			name:           "not authenticated",
+			requestBody:    map[string]interface{}{"code": "123456"},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   errorBody(errors.ErrUserNotAuthenticated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockAuthService)
+			tt.mockSetup(mockSvc)
+
+			w := httptest.NewRecorder()
+			newTestUserRouter(mockSvc, tt.userID).
+				ServeHTTP(w, newJSONRequest(http.MethodPost, "/profile/me/phone/confirm", tt.requestBody))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, decodeBody(t, w))
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
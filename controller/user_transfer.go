@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+)
+
+const (
+	// maxImportRows is how many users one import can create
+	maxImportRows = 5000
+	// maxImportSize is the largest import file accepted, in bytes
+	maxImportSize = 10 << 20
+)
+
+// userExportColumns are the columns of a CSV export, and the fields of an
+// NDJSON export
+var userExportColumns = []string{"id", "phone_number", "first_name", "last_name", "email", "status", "role", "created_at", "updated_at"}
+
+// userExportWriter writes exported users to the response in a format
+type userExportWriter interface {
+	Write(user *dto.AdminUserResponse) error
+	Flush() error
+}
+
+// exportContentTypes are the content types of the export formats
+var exportContentTypes = map[string]string{
+	"csv":    "text/csv; charset=utf-8",
+	"ndjson": "application/x-ndjson",
+}
+
+func newUserExportWriter(format string, w io.Writer) userExportWriter {
+	if format == "ndjson" {
+		return &ndjsonUserWriter{encoder: json.NewEncoder(w)}
+	}
+	return &csvUserWriter{writer: csv.NewWriter(w)}
+}
+
+// csvUserWriter writes users as CSV rows under a header row
+type csvUserWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvUserWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	w.headerWritten = true
+	return w.writer.Write(userExportColumns)
+}
+
+func (w *csvUserWriter) Write(user *dto.AdminUserResponse) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	return w.writer.Write([]string{
+		user.ID,
+		user.PhoneNumber,
+		csvText(user.FirstName),
+		csvText(user.LastName),
+		csvText(user.Email),
+		user.Status,
+		user.Role,
+		user.CreatedAt.Format(time.RFC3339),
+		user.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+// csvText returns a text cell that spreadsheets show as is. Users choose
+// their names and email, and a cell starting like a formula would otherwise
+// be run when the export is opened.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// Flush writes the header of an export without users too
+func (w *csvUserWriter) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// ndjsonUserWriter writes users as one JSON object a line
+type ndjsonUserWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonUserWriter) Write(user *dto.AdminUserResponse) error {
+	return w.encoder.Encode(user)
+}
+
+func (w *ndjsonUserWriter) Flush() error {
+	return nil
+}
+
+// readImportRows reads the users of an import file. Rows that can't be
+// parsed are reported as row errors and the rest are still read; an error
+// is returned only when the file as a whole can't be read. Rows are
+// numbered from 1, not counting the CSV header or blank NDJSON lines.
+func readImportRows(format string, r io.Reader) ([]dto.AdminImportUserRow, []dto.AdminImportRowError, error) {
+	if format == "ndjson" {
+		return readNDJSONImportRows(r)
+	}
+	return readCSVImportRows(r)
+}
+
+func readCSVImportRows(r io.Reader) ([]dto.AdminImportUserRow, []dto.AdminImportRowError, error) {
+	reader := csv.NewReader(skipBOM(r))
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, importReadError(err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["phone_number"]; !ok {
+		return nil, nil, errors.ErrInvalidImportFile
+	}
+	// Only as many rows as the header has columns are accepted
+	reader.FieldsPerRecord = len(header)
+
+	var (
+		rows      []dto.AdminImportUserRow
+		rowErrors []dto.AdminImportRowError
+	)
+	for number := 1; ; number++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if number > maxImportRows {
+			return nil, nil, errors.ErrImportTooLarge
+		}
+		if err != nil {
+			if _, ok := err.(*csv.ParseError); !ok {
+				return nil, nil, importReadError(err)
+			}
+			rowErrors = append(rowErrors, dto.AdminImportRowError{Row: number, Error: errors.ErrInvalidImportRow})
+			continue
+		}
+
+		value := func(column string) string {
+			if i, ok := columns[column]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		rows = append(rows, dto.AdminImportUserRow{
+			Row:          number,
+			PhoneNumber:  value("phone_number"),
+			FirstName:    value("first_name"),
+			LastName:     value("last_name"),
+			Email:        value("email"),
+			Password:     value("password"),
+			PasswordHash: value("password_hash"),
+			Role:         value("role"),
+			Status:       value("status"),
+		})
+	}
+	return rows, rowErrors, nil
+}
+
+func readNDJSONImportRows(r io.Reader) ([]dto.AdminImportUserRow, []dto.AdminImportRowError, error) {
+	scanner := bufio.NewScanner(skipBOM(r))
+
+	var (
+		rows      []dto.AdminImportUserRow
+		rowErrors []dto.AdminImportRowError
+	)
+	number := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		number++
+		if number > maxImportRows {
+			return nil, nil, errors.ErrImportTooLarge
+		}
+
+		var row dto.AdminImportUserRow
+		if err := json.Unmarshal(line, &row); err != nil {
+			rowErrors = append(rowErrors, dto.AdminImportRowError{Row: number, Error: errors.ErrInvalidImportRow})
+			continue
+		}
+		row.Row = number
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, importReadError(err)
+	}
+	return rows, rowErrors, nil
+}
+
+// importReadError returns the error for an import file that couldn't be
+// read
+func importReadError(err error) error {
+	if _, ok := err.(*http.MaxBytesError); ok {
+		return errors.ErrImportTooLarge
+	}
+	return errors.ErrInvalidImportFile
+}
+
+// skipBOM drops the byte order mark spreadsheet programs put at the start
+// of UTF-8 files
+func skipBOM(r io.Reader) io.Reader {
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		buffered.Discard(3)
+	}
+	return buffered
+}
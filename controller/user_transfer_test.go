@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCSVUserWriter_EscapesFormulas tests that names and emails starting like a formula are exported as text
+func TestCSVUserWriter_EscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	writer := newUserExportWriter("csv", &buf)
+
+	require.NoError(t, writer.Write(&dto.AdminUserResponse{
+		ID:          "3f1c2a4e-7b1d-4c55-9a0e-2b8f6d1e9c10",
+		PhoneNumber: "+989123456789",
+		FirstName:   "=HYPERLINK(\"http://example.com\")",
+		LastName:    "-Rezaei",
+		Email:       "@ali",
+		Status:      "Active",
+		Role:        "User",
+		CreatedAt:   time.Unix(1700000000, 0).UTC(),
+		UpdatedAt:   time.Unix(1700000000, 0).UTC(),
+	}))
+	require.NoError(t, writer.Flush())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, userExportColumns, records[0])
+	assert.Equal(t, "+989123456789", records[1][1])
+	assert.Equal(t, "'=HYPERLINK(\"http://example.com\")", records[1][2])
+	assert.Equal(t, "'-Rezaei", records[1][3])
+	assert.Equal(t, "'@ali", records[1][4])
+}
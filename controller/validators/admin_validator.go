@@ -2,6 +2,10 @@ package validators
 
 import (
 	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/amirdashtii/go_auth/controller/dto"
 	"github.com/amirdashtii/go_auth/internal/core/entities"
@@ -12,12 +16,20 @@ import (
 
 var adminValidate *validator.Validate
 
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{2,49}$`)
+
 func init() {
 	adminValidate = validator.New()
 	adminValidate.RegisterValidation("role", validateRole)
 	adminValidate.RegisterValidation("status", validateStatus)
 	adminValidate.RegisterValidation("sort", validateSort)
 	adminValidate.RegisterValidation("order", validateOrder)
+	adminValidate.RegisterValidation("redirect_uri", validateRedirectURI)
+	adminValidate.RegisterValidation("scope", validateScope)
+	adminValidate.RegisterValidation("grant_type", validateGrantType)
+	adminValidate.RegisterValidation("phone", ValidatePhoneNumber)
+	adminValidate.RegisterValidation("password", ValidateAuthPassword)
+	adminValidate.RegisterValidation("slug", validateSlug)
 }
 
 // validateRole checks if the role is valid without hardcoding role types
@@ -69,6 +81,44 @@ func validateOrder(fl validator.FieldLevel) bool {
 	return validOrders[order]
 }
 
+// validateRedirectURI accepts absolute URIs without a fragment, as required
+// for OAuth redirection endpoints
+func validateRedirectURI(fl validator.FieldLevel) bool {
+	u, err := url.Parse(fl.Field().String())
+	if err != nil {
+		return false
+	}
+	return u.IsAbs() && u.Fragment == ""
+}
+
+// validateScope checks the scope-token syntax of RFC 6749. Which scopes a
+// client can be granted is checked when it is created.
+func validateScope(fl validator.FieldLevel) bool {
+	scope := fl.Field().String()
+	if scope == "" {
+		return false
+	}
+	for _, r := range scope {
+		if r < 0x21 || r > 0x7e || r == '"' || r == '\\' {
+			return false
+		}
+	}
+	return true
+}
+
+func validateGrantType(fl validator.FieldLevel) bool {
+	return slices.Contains([]string{
+		entities.GrantTypeAuthorizationCode,
+		entities.GrantTypeRefreshToken,
+		entities.GrantTypeClientCredentials,
+	}, fl.Field().String())
+}
+
+// validateSlug accepts lowercase letters, digits and hyphens, so slugs are
+// safe to use in headers and URLs
+func validateSlug(fl validator.FieldLevel) bool {
+	return slugPattern.MatchString(fl.Field().String())
+}
 
 func getAdminCustomErrorMessage(field string) error {
 	switch field {
@@ -90,6 +140,20 @@ func getAdminCustomErrorMessage(field string) error {
 		return errors.ErrInvalidEmail
 	case "Password":
 		return errors.ErrInvalidPassword
+	case "Name":
+		return errors.ErrInvalidClientName
+	case "RedirectURIs":
+		return errors.ErrInvalidRedirectURI
+	case "Scopes":
+		return errors.ErrInvalidScope
+	case "GrantTypes":
+		return errors.ErrInvalidGrantTypes
+	case "UserIDs":
+		return errors.ErrInvalidBulkUsers
+	case "Action":
+		return errors.ErrInvalidBulkAction
+	case "Type", "ActorID", "TargetID", "From", "To", "Limit":
+		return errors.ErrInvalidAuditFilter
   
 	default:
 		return errors.New(errors.ValidationError, fmt.Sprintf("%s Field is invalid.", field), fmt.Sprintf("فیلد %s نامعتبر است.", field), nil)
@@ -99,11 +163,16 @@ func getAdminCustomErrorMessage(field string) error {
 func ValidateGetUsersRequest(req *dto.AdminGetUsersRequest, logger ports.Logger) error {
 	if err := adminValidate.Struct(req); err != nil {
 		if validationErrs, ok := err.(validator.ValidationErrors); ok {
-			field := validationErrs[0].Field()
+			// Errors in a list element are reported as Field[index]
+			field, _, _ := strings.Cut(validationErrs[0].Field(), "[")
 			logger.Error("Validation error",
 				ports.F("error", err),
 				ports.F("field", field),
 			)
+			switch field {
+			case "Search", "CreatedFrom", "CreatedTo", "Limit":
+				return errors.ErrInvalidUserFilter
+			}
 			return getAdminCustomErrorMessage(field)
 		}
 		logger.Error("Validation error",
@@ -148,14 +217,6 @@ func ValidateChangeRoleRequest(req *dto.AdminUserUpdateRoleRequest, logger ports
 		return errors.ErrInvalidRequest
 	}
 
-	roleType := entities.ParseRoleType(req.Role)
-	if roleType == entities.SuperAdminRole {
-		logger.Error("Validation error",
-			ports.F("error", "cannot change role to super admin"),
-		)
-		return errors.New(errors.ValidationError, "cannot change role to super admin", "نمی‌توان نقش را به نقش مدیر کل به عنوان مدیر کل تغییر داد.", nil)
-	}
-
 	return nil
 }
 
@@ -185,3 +246,167 @@ func ValidateChangeStatusRequest(req *dto.AdminUserUpdateStatusRequest, logger p
 
 	return nil
 }
+
+func ValidateBulkUsersRequest(req *dto.AdminBulkUsersRequest, logger ports.Logger) error {
+	if err := adminValidate.Struct(req); err != nil {
+		if validationErrs, ok := err.(validator.ValidationErrors); ok {
+			// Errors in a list element are reported as Field[index]
+			field, _, _ := strings.Cut(validationErrs[0].Field(), "[")
+			logger.Error("Validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+			)
+			return getAdminCustomErrorMessage(field)
+		}
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+		return errors.ErrInvalidRequest
+	}
+	return nil
+}
+
+func ValidateCreateOAuthClientRequest(req *dto.AdminCreateOAuthClientRequest, logger ports.Logger) error {
+	if err := adminValidate.Struct(req); err != nil {
+		if validationErrs, ok := err.(validator.ValidationErrors); ok {
+			// Errors in a list element are reported as Field[index]
+			field, _, _ := strings.Cut(validationErrs[0].Field(), "[")
+			logger.Error("Validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+			)
+			return getAdminCustomErrorMessage(field)
+		}
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+		return errors.ErrInvalidRequest
+	}
+
+	// Only a client with a secret can authenticate as itself
+	if slices.Contains(req.GrantTypes, entities.GrantTypeClientCredentials) && !req.Confidential {
+		logger.Error("Validation error",
+			ports.F("error", "client_credentials requires a confidential client"),
+		)
+		return errors.ErrInvalidGrantTypes
+	}
+
+	// Clients that sign users in need somewhere to send them back to
+	signsUsersIn := len(req.GrantTypes) == 0 || slices.Contains(req.GrantTypes, entities.GrantTypeAuthorizationCode)
+	if signsUsersIn && len(req.RedirectURIs) == 0 {
+		logger.Error("Validation error",
+			ports.F("error", "redirect URI is required"),
+		)
+		return errors.ErrInvalidRedirectURI
+	}
+
+	return nil
+}
+
+func ValidateListAuditEventsRequest(req *dto.AdminAuditEventsRequest, logger ports.Logger) error {
+	if err := adminValidate.Struct(req); err != nil {
+		if validationErrs, ok := err.(validator.ValidationErrors); ok {
+			field := validationErrs[0].Field()
+			logger.Error("Validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+			)
+			return getAdminCustomErrorMessage(field)
+		}
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+		return errors.ErrInvalidRequest
+	}
+	return nil
+}
+
+func ValidateCreateOrganizationRequest(req *dto.AdminCreateOrganizationRequest, logger ports.Logger) error {
+	if err := adminValidate.Struct(req); err != nil {
+		if validationErrs, ok := err.(validator.ValidationErrors); ok {
+			field := validationErrs[0].Field()
+			logger.Error("Validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+			)
+			switch field {
+			case "Slug":
+				return errors.ErrInvalidOrganizationSlug
+			case "Name":
+				return errors.ErrInvalidOrganizationName
+			case "OwnerPhoneNumber":
+				return errors.ErrInvalidPhoneNumber
+			case "OwnerPassword":
+				return errors.ErrInvalidPassword
+			}
+			return getAdminCustomErrorMessage(field)
+		}
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+		return errors.ErrInvalidRequest
+	}
+	return nil
+}
+
+func ValidateExportUsersRequest(req *dto.AdminExportUsersRequest, logger ports.Logger) error {
+	if err := adminValidate.Struct(req); err != nil {
+		if validationErrs, ok := err.(validator.ValidationErrors); ok {
+			// Errors in a list element are reported as Field[index]
+			field, _, _ := strings.Cut(validationErrs[0].Field(), "[")
+			logger.Error("Validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+			)
+			switch field {
+			case "Format":
+				return errors.ErrInvalidTransferFormat
+			case "Search", "CreatedFrom", "CreatedTo", "Limit":
+				return errors.ErrInvalidUserFilter
+			}
+			return getAdminCustomErrorMessage(field)
+		}
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+		return errors.ErrInvalidRequest
+	}
+	return nil
+}
+
+func ValidateImportUsersRequest(req *dto.AdminImportUsersRequest, logger ports.Logger) error {
+	if err := adminValidate.Struct(req); err != nil {
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+		return errors.ErrInvalidTransferFormat
+	}
+	return nil
+}
+
+// ValidateImportUserRow checks one row of an import. Its error is reported
+// for the row rather than failing the import.
+func ValidateImportUserRow(row *dto.AdminImportUserRow, logger ports.Logger) error {
+	if err := adminValidate.Struct(row); err != nil {
+		if validationErrs, ok := err.(validator.ValidationErrors); ok {
+			field := validationErrs[0].Field()
+			logger.Debug("Import row validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+				ports.F("row", row.Row),
+			)
+			switch {
+			case field == "Password" && validationErrs[0].Tag() != "password":
+				return errors.ErrImportPassword
+			case field == "PasswordHash":
+				return errors.ErrInvalidPasswordHash
+			}
+			return getAdminCustomErrorMessage(field)
+		}
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+		return errors.ErrInvalidRequest
+	}
+	return nil
+}
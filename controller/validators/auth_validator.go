@@ -16,6 +16,8 @@ func init() {
     authValidate = validator.New()
     authValidate.RegisterValidation("phone", ValidatePhoneNumber)
     authValidate.RegisterValidation("password", ValidateAuthPassword)
+    authValidate.RegisterValidation("otp", ValidateOTPCode)
+    authValidate.RegisterValidation("mfa_code", ValidateMFACode)
 }
 
 func ValidatePhoneNumber(fl validator.FieldLevel) bool {
@@ -38,14 +40,33 @@ func ValidateAuthPassword(fl validator.FieldLevel) bool {
     return hasUpper && hasLower && hasNumber
 }
 
+func ValidateOTPCode(fl validator.FieldLevel) bool {
+    code := fl.Field().String()
+    pattern := `^[0-9]{6}$`
+    matched, _ := regexp.MatchString(pattern, code)
+    return matched
+}
+
+// ValidateMFACode accepts an authenticator code or a recovery code
+func ValidateMFACode(fl validator.FieldLevel) bool {
+    code := fl.Field().String()
+    pattern := `^([0-9]{6}|[0-9a-fA-F]{5}-?[0-9a-fA-F]{5})$`
+    matched, _ := regexp.MatchString(pattern, code)
+    return matched
+}
+
 func getAuthCustomErrorMessage(field string) error {
     switch field {
     case "PhoneNumber":
         return errors.ErrInvalidPhoneNumber
-    case "Password":
+    case "Email":
+        return errors.ErrInvalidEmail
+    case "Password", "NewPassword":
         return errors.ErrInvalidPassword
     case "RefreshToken":
         return errors.ErrInvalidRefreshToken
+    case "Code":
+        return errors.ErrInvalidOTPCode
     default:
         return errors.New(errors.ValidationError, fmt.Sprintf("Field %s is invalid.", field), fmt.Sprintf("فیلد %s نامعتبر است.", field), nil)
     }
@@ -77,6 +98,10 @@ func ValidateLoginRequest(req *dto.LoginRequest, logger ports.Logger) error {
 				ports.F("error", err),
 				ports.F("field", field),
 			)
+            switch validationErrs[0].Tag() {
+            case "required_without", "excluded_with":
+                return errors.ErrInvalidLoginIdentifier
+            }
             return getAuthCustomErrorMessage(field)
         }
 		logger.Error("Validation error",
@@ -101,4 +126,100 @@ func ValidateRefreshTokenRequest(req *dto.RefreshTokenRequest, logger ports.Logg
 		)
     }
     return nil
-}
\ No newline at end of file
+}
+
+func ValidateOTPRequest(req *dto.OTPRequest, logger ports.Logger) error {
+    if err := authValidate.Struct(req); err != nil {
+        if validationErrs, ok := err.(validator.ValidationErrors); ok {
+            field := validationErrs[0].Field()
+            logger.Error("Validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+			)
+            return getAuthCustomErrorMessage(field)
+        }
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+        return errors.ErrInvalidRequest
+    }
+    return nil
+}
+
+func ValidateOTPVerifyRequest(req *dto.OTPVerifyRequest, logger ports.Logger) error {
+    if err := authValidate.Struct(req); err != nil {
+        if validationErrs, ok := err.(validator.ValidationErrors); ok {
+            field := validationErrs[0].Field()
+            logger.Error("Validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+			)
+            return getAuthCustomErrorMessage(field)
+        }
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+        return errors.ErrInvalidRequest
+    }
+    return nil
+}
+
+func ValidateForgotPasswordRequest(req *dto.ForgotPasswordRequest, logger ports.Logger) error {
+    if err := authValidate.Struct(req); err != nil {
+        if validationErrs, ok := err.(validator.ValidationErrors); ok {
+            field := validationErrs[0].Field()
+            logger.Error("Validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+			)
+            return getAuthCustomErrorMessage(field)
+        }
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+        return errors.ErrInvalidRequest
+    }
+    return nil
+}
+
+func ValidateResetPasswordRequest(req *dto.ResetPasswordRequest, logger ports.Logger) error {
+    if err := authValidate.Struct(req); err != nil {
+        if validationErrs, ok := err.(validator.ValidationErrors); ok {
+            field := validationErrs[0].Field()
+            logger.Error("Validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+			)
+            return getAuthCustomErrorMessage(field)
+        }
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+        return errors.ErrInvalidRequest
+    }
+    return nil
+}
+
+func ValidateMFAVerifyRequest(req *dto.MFAVerifyRequest, logger ports.Logger) error {
+    if err := authValidate.Struct(req); err != nil {
+        if validationErrs, ok := err.(validator.ValidationErrors); ok {
+            field := validationErrs[0].Field()
+            logger.Error("Validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+			)
+            switch field {
+            case "MFAToken":
+                return errors.ErrInvalidMFAToken
+            case "Code":
+                return errors.ErrInvalidMFACodeValue
+            }
+            return getAuthCustomErrorMessage(field)
+        }
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+        return errors.ErrInvalidRequest
+    }
+    return nil
+}
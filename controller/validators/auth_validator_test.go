@@ -119,6 +119,49 @@ func TestValidateAuthPassword(t *testing.T) {
 	}
 }
 
+func TestValidateOTPCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected bool
+	}{
+		{
+			name:     "valid code",
+			code:     "012345",
+			expected: true,
+		},
+		{
+			name:     "invalid code - too short",
+			code:     "12345",
+			expected: false,
+		},
+		{
+			name:     "invalid code - too long",
+			code:     "1234567",
+			expected: false,
+		},
+		{
+			name:     "invalid code - contains letters",
+			code:     "12345a",
+			expected: false,
+		},
+	}
+
+	v := validator.New()
+	v.RegisterValidation("otp", ValidateOTPCode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Var(tt.code, "otp")
+			if tt.expected {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
 func TestValidateRegisterRequest(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -193,6 +236,38 @@ func TestValidateLoginRequest(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid email",
+			request: &dto.LoginRequest{
+				Email:    "user@example.com",
+				Password: "Test1234",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid email",
+			request: &dto.LoginRequest{
+				Email:    "user.example.com",
+				Password: "Test1234",
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing phone and email",
+			request: &dto.LoginRequest{
+				Password: "Test1234",
+			},
+			wantErr: true,
+		},
+		{
+			name: "both phone and email",
+			request: &dto.LoginRequest{
+				PhoneNumber: "09123456789",
+				Email:       "user@example.com",
+				Password:    "Test1234",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -3,8 +3,12 @@ package validators
 import (
 	"fmt"
 	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
 	"github.com/amirdashtii/go_auth/internal/core/errors"
 	"github.com/amirdashtii/go_auth/internal/core/ports"
 	"github.com/go-playground/validator/v10"
@@ -17,6 +21,16 @@ func init() {
 	userValidate.RegisterValidation("password", validatePassword)
 	userValidate.RegisterValidation("phone", validatePhone)
 	userValidate.RegisterValidation("name", validateName)
+	userValidate.RegisterValidation("api_key_scope", validateAPIKeyScope)
+	userValidate.RegisterValidation("otp", ValidateOTPCode)
+	// Login phone numbers are stored as 09XXXXXXXXX
+	userValidate.RegisterValidation("mobile", ValidatePhoneNumber)
+}
+
+// validateAPIKeyScope checks that an API key scope is one of the permissions
+// keys can be granted
+func validateAPIKeyScope(fl validator.FieldLevel) bool {
+	return slices.Contains(entities.APIKeyScopes, fl.Field().String())
 }
 
 func validatePassword(fl validator.FieldLevel) bool {
@@ -68,6 +82,12 @@ func getUserCustomErrorMessage(field string) error {
 		return errors.ErrInvalidOldPassword
 	case "NewPassword":
 		return errors.ErrInvalidNewPassword
+	case "Name":
+		return errors.ErrInvalidAPIKeyName
+	case "Scopes":
+		return errors.ErrInvalidAPIKeyScope
+	case "Code":
+		return errors.ErrInvalidOTPCode
 	default:
 		return errors.New(errors.ValidationError, fmt.Sprintf("%s Field is invalid.", field), fmt.Sprintf("فیلد %s نامعتبر است.", field), nil)
 	}
@@ -114,3 +134,81 @@ func ValidateChangePasswordRequest(req *dto.ChangePasswordRequest, logger ports.
 
 	return nil
 }
+
+func ValidateCreateAPIKeyRequest(req *dto.CreateAPIKeyRequest, logger ports.Logger) error {
+	if err := userValidate.Struct(req); err != nil {
+		if validationErrs, ok := err.(validator.ValidationErrors); ok {
+			// Errors on list elements are reported as Field[index]
+			field, _, _ := strings.Cut(validationErrs[0].Field(), "[")
+			logger.Error("Validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+			)
+			return getUserCustomErrorMessage(field)
+		}
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		logger.Error("Validation error",
+			ports.F("error", errors.ErrInvalidAPIKeyExpiry.Message.English),
+			ports.F("expires_at", req.ExpiresAt),
+		)
+		return errors.ErrInvalidAPIKeyExpiry
+	}
+
+	return nil
+}
+
+func ValidateConfirmTOTPRequest(req *dto.ConfirmTOTPRequest, logger ports.Logger) error {
+	if err := userValidate.Struct(req); err != nil {
+		if validationErrs, ok := err.(validator.ValidationErrors); ok {
+			field := validationErrs[0].Field()
+			logger.Error("Validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+			)
+			return getUserCustomErrorMessage(field)
+		}
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+	}
+	return nil
+}
+
+func ValidateChangePhoneNumberRequest(req *dto.ChangePhoneNumberRequest, logger ports.Logger) error {
+	if err := userValidate.Struct(req); err != nil {
+		if validationErrs, ok := err.(validator.ValidationErrors); ok {
+			field := validationErrs[0].Field()
+			logger.Error("Validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+			)
+			return getUserCustomErrorMessage(field)
+		}
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+	}
+	return nil
+}
+
+func ValidateConfirmPhoneNumberRequest(req *dto.ConfirmPhoneNumberRequest, logger ports.Logger) error {
+	if err := userValidate.Struct(req); err != nil {
+		if validationErrs, ok := err.(validator.ValidationErrors); ok {
+			field := validationErrs[0].Field()
+			logger.Error("Validation error",
+				ports.F("error", err),
+				ports.F("field", field),
+			)
+			return getUserCustomErrorMessage(field)
+		}
+		logger.Error("Validation error",
+			ports.F("error", err),
+		)
+	}
+	return nil
+}
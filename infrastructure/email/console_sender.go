@@ -0,0 +1,73 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+// consoleSender is a stand-in email provider that writes messages to an
+// io.Writer instead of delivering them. It is meant for development and tests.
+type consoleSender struct {
+	mu     sync.Mutex
+	output io.Writer
+	logger ports.Logger
+}
+
+// NewConsoleSender creates a new email sender that writes messages to output
+func NewConsoleSender(output io.Writer, logger ports.Logger) ports.EmailSender {
+	return &consoleSender{
+		output: output,
+		logger: logger,
+	}
+}
+
+// NewFileSender creates a new email sender that appends messages to logs/email.log
+func NewFileSender(logger ports.Logger) (ports.EmailSender, error) {
+	// Create logs directory if it doesn't exist
+	logsDir := "logs"
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		return nil, err
+	}
+
+	emailFile, err := os.OpenFile(
+		filepath.Join(logsDir, "email.log"),
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
+		0644,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConsoleSender(emailFile, logger), nil
+}
+
+func (s *consoleSender) Send(ctx context.Context, to, subject, body string) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while sending email",
+			ports.F("error", ctx.Err()),
+			ports.F("to", to),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := fmt.Fprintf(s.output, "[%s] Email to %s: %s\n%s\n", time.Now().Format(time.RFC3339), to, subject, body)
+	if err != nil {
+		s.logger.Error("Error sending email",
+			ports.F("error", err),
+			ports.F("to", to),
+		)
+		return errors.ErrSendEmail
+	}
+	return nil
+}
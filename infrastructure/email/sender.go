@@ -0,0 +1,31 @@
+package email
+
+import (
+	"os"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+// NewSender creates the email sender selected by cfg. The console and file
+// senders don't deliver anything, so they are refused outside development.
+func NewSender(cfg config.EmailConfig, environment string, logger ports.Logger) (ports.EmailSender, error) {
+	switch cfg.Sender {
+	case "console", "file":
+		if environment != "development" {
+			return nil, errors.ErrUnsupportedEmailSender
+		}
+		if cfg.Sender == "file" {
+			return NewFileSender(logger)
+		}
+		return NewConsoleSender(os.Stdout, logger), nil
+	case "smtp":
+		if cfg.SMTPHost == "" || cfg.SMTPPort == 0 || cfg.From == "" {
+			return nil, errors.ErrUnsupportedEmailSender
+		}
+		return NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From, logger), nil
+	default:
+		return nil, errors.ErrUnsupportedEmailSender
+	}
+}
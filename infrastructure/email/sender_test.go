@@ -0,0 +1,178 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/infrastructure/logger"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogger() ports.Logger {
+	return logger.NewZerologLogger(ports.LoggerConfig{
+		Level:       "fatal",
+		Environment: "test",
+		ServiceName: "go_auth",
+		Output:      io.Discard,
+	})
+}
+
+// smtpMessage is what the fake SMTP server received for one message
+type smtpMessage struct {
+	from string
+	to   string
+	data string
+}
+
+// newSMTPServer starts a fake SMTP server that accepts one message, or
+// rejects its recipient with rcptReply when set, and returns its address
+func newSMTPServer(t *testing.T, rcptReply string) (string, int, <-chan smtpMessage) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan smtpMessage, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+		reply("220 localhost ESMTP")
+
+		var msg smtpMessage
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.TrimRight(line, "\r\n")
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				msg.from = strings.Trim(strings.TrimPrefix(command, "MAIL FROM:"), "<>")
+				reply("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				if rcptReply != "" {
+					reply(rcptReply)
+					continue
+				}
+				msg.to = strings.Trim(strings.TrimPrefix(command, "RCPT TO:"), "<>")
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				msg.data = data.String()
+				received <- msg
+				reply("250 OK")
+			case command == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, received
+}
+
+// TestNewSender tests that the console sender is only allowed in development
+func TestNewSender(t *testing.T) {
+	smtp := config.EmailConfig{Sender: "smtp", SMTPHost: "smtp.example.com", SMTPPort: 587, From: "no-reply@example.com"}
+	withoutFrom := smtp
+	withoutFrom.From = ""
+
+	tests := []struct {
+		name        string
+		cfg         config.EmailConfig
+		environment string
+		wantErr     error
+	}{
+		{"console in development", config.EmailConfig{Sender: "console"}, "development", nil},
+		{"console in production", config.EmailConfig{Sender: "console"}, "production", errors.ErrUnsupportedEmailSender},
+		{"file in production", config.EmailConfig{Sender: "file"}, "production", errors.ErrUnsupportedEmailSender},
+		{"smtp in production", smtp, "production", nil},
+		{"smtp without sender address", withoutFrom, "production", errors.ErrUnsupportedEmailSender},
+		{"smtp without host", config.EmailConfig{Sender: "smtp", SMTPPort: 587, From: "no-reply@example.com"}, "production", errors.ErrUnsupportedEmailSender},
+		{"unknown sender", config.EmailConfig{Sender: "carrier-pigeon"}, "development", errors.ErrUnsupportedEmailSender},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender, err := NewSender(tt.cfg, tt.environment, newTestLogger())
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				assert.Nil(t, sender)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, sender)
+		})
+	}
+}
+
+// TestSMTPSender tests that messages are handed to the SMTP server with their headers
+func TestSMTPSender(t *testing.T) {
+	host, port, received := newSMTPServer(t, "")
+	sender := NewSMTPSender(host, port, "", "", "no-reply@example.com", newTestLogger())
+
+	err := sender.Send(context.Background(), "sara@example.com", "Verify your email", "Open https://auth.example.com/auth/email/verify?token=abc to verify.")
+	require.NoError(t, err)
+
+	msg := <-received
+	assert.Equal(t, "no-reply@example.com", msg.from)
+	assert.Equal(t, "sara@example.com", msg.to)
+
+	headers, body, ok := strings.Cut(msg.data, "\r\n\r\n")
+	require.True(t, ok)
+	assert.Contains(t, headers, "To: sara@example.com\r\n")
+	assert.Contains(t, headers, "Subject: Verify your email\r\n")
+	decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(body)))
+	require.NoError(t, err)
+	assert.Equal(t, "Open https://auth.example.com/auth/email/verify?token=abc to verify.", strings.TrimRight(string(decoded), "\r\n"))
+}
+
+// TestSMTPSender_Rejected tests that a message the server refuses, or with headers in its address, is reported
+func TestSMTPSender_Rejected(t *testing.T) {
+	host, port, _ := newSMTPServer(t, "550 No such user")
+	sender := NewSMTPSender(host, port, "", "", "no-reply@example.com", newTestLogger())
+
+	err := sender.Send(context.Background(), "nobody@example.com", "Verify your email", "body")
+	assert.Equal(t, errors.ErrSendEmail, err)
+
+	err = sender.Send(context.Background(), "sara@example.com\r\nBcc: eve@example.com", "Verify your email", "body")
+	assert.Equal(t, errors.ErrSendEmail, err)
+
+	// Nothing listens on the port of a closed server
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedPort := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	err = NewSMTPSender("127.0.0.1", closedPort, "", "", "no-reply@example.com", newTestLogger()).Send(context.Background(), "sara@example.com", "Verify your email", "body")
+	assert.Equal(t, errors.ErrSendEmail, err)
+}
@@ -0,0 +1,146 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+const smtpTimeout = 10 * time.Second
+
+// smtpSender delivers messages through an SMTP server. The connection is
+// upgraded with STARTTLS when the server offers it.
+type smtpSender struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	logger   ports.Logger
+}
+
+// NewSMTPSender creates a new email sender that sends messages from the
+// address from through the SMTP server at host and port, logging in with
+// username and password when username is set
+func NewSMTPSender(host string, port int, username, password, from string, logger ports.Logger) ports.EmailSender {
+	return &smtpSender{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+		logger:   logger,
+	}
+}
+
+func (s *smtpSender) Send(ctx context.Context, to, subject, body string) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while sending email",
+			ports.F("error", ctx.Err()),
+			ports.F("to", to),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	// A line break would let the address add headers of its own
+	if strings.ContainsAny(to, "\r\n") {
+		return errors.ErrSendEmail
+	}
+
+	message, err := s.message(to, subject, body)
+	if err != nil {
+		return errors.ErrSendEmail
+	}
+
+	if err := s.deliver(ctx, to, message); err != nil {
+		s.logger.Error("Error sending email",
+			ports.F("error", err),
+			ports.F("to", to),
+		)
+		return errors.ErrSendEmail
+	}
+	return nil
+}
+
+// deliver hands message for to over to the SMTP server
+func (s *smtpSender) deliver(ctx context.Context, to string, message []byte) error {
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.host, strconv.Itoa(s.port)))
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(smtpTimeout)); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	// PlainAuth refuses to send the password over a connection that isn't
+	// encrypted, unless the server is on localhost
+	if s.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// message formats a plain text email with its headers
+func (s *smtpSender) message(to, subject, body string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	if _, err := w.Write([]byte(body)); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
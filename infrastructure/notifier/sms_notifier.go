@@ -0,0 +1,35 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+// smsNotifier reaches users by text message on their phone number
+type smsNotifier struct {
+	sender ports.SMSSender
+	logger ports.Logger
+}
+
+// NewSMSNotifier creates a notifier that sends messages with sender
+func NewSMSNotifier(sender ports.SMSSender, logger ports.Logger) ports.Notifier {
+	return &smsNotifier{
+		sender: sender,
+		logger: logger,
+	}
+}
+
+func (n *smsNotifier) Notify(ctx context.Context, user *entities.User, message string) error {
+	if ctx.Err() != nil {
+		n.logger.Error("Context cancelled while notifying user",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", user.ID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	return n.sender.Send(ctx, user.PhoneNumber, message)
+}
@@ -0,0 +1,298 @@
+package password
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Imported hashes are verified on every login, so their cost parameters are
+// capped to keep a hash from tying up the server
+const (
+	maxArgon2Memory      = 64 << 10 // KiB
+	maxArgon2Time        = 10
+	maxScryptMemory      = 64 << 20 // bytes, 128·r·N
+	maxScryptLogN        = 20
+	maxScryptBlockSize   = 64
+	maxScryptParallelism = 16
+	maxPBKDF2Iterations  = 1_000_000
+	minDerivedKeyLength  = 16
+	maxDerivedKeyLength  = 64
+)
+
+// The salt and hash length of new argon2id hashes, in bytes
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// hasher hashes passwords with argon2id and verifies bcrypt hashes as well
+// as PHC strings of argon2id, scrypt, PBKDF2 and salted SHA
+type hasher struct {
+	policy config.PasswordConfig
+}
+
+// NewHasher creates a password hasher that hashes with argon2id at the cost
+// of cfg. Hashes of other algorithms or of a lower cost need a rehash.
+func NewHasher(cfg config.PasswordConfig) (ports.PasswordHasher, error) {
+	// argon2id needs 8 KiB of memory a lane, and hashes are only verified
+	// up to the caps of imported ones
+	if cfg.Argon2Parallelism == 0 || cfg.Argon2Time == 0 || cfg.Argon2Time > maxArgon2Time ||
+		cfg.Argon2Memory < 8*uint32(cfg.Argon2Parallelism) || cfg.Argon2Memory > maxArgon2Memory {
+		return nil, errors.ErrInvalidPasswordPolicy
+	}
+	return &hasher{policy: cfg}, nil
+}
+
+func (h *hasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.policy.Argon2Time, h.policy.Argon2Memory, h.policy.Argon2Parallelism, argon2KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.policy.Argon2Memory, h.policy.Argon2Time, h.policy.Argon2Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *hasher) Verify(password, hash string) (bool, error) {
+	if isBcrypt(hash) {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return false, nil
+		}
+		if err != nil {
+			return false, errors.ErrInvalidPasswordHash
+		}
+		return true, nil
+	}
+
+	parsed, derive, err := parse(hash)
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(derive(password), parsed.hash) == 1, nil
+}
+
+// NeedsRehash is true for hashes of other algorithms than argon2id, and for
+// argon2id hashes with any parameter below the policy
+func (h *hasher) NeedsRehash(hash string) bool {
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		return true
+	}
+	parsed, _, err := parse(hash)
+	if err != nil {
+		return true
+	}
+	memory, _ := parsed.param("m", maxArgon2Memory)
+	passes, _ := parsed.param("t", maxArgon2Time)
+	threads, _ := parsed.param("p", 255)
+	return parsed.version != argon2.Version ||
+		uint32(memory) < h.policy.Argon2Memory ||
+		uint32(passes) < h.policy.Argon2Time ||
+		uint8(threads) < h.policy.Argon2Parallelism
+}
+
+func (h *hasher) Supports(hash string) bool {
+	if isBcrypt(hash) {
+		_, err := bcrypt.Cost([]byte(hash))
+		return err == nil
+	}
+	_, _, err := parse(hash)
+	return err == nil
+}
+
+// isBcrypt reports whether hash is in the format of bcrypt, which predates
+// PHC strings
+func isBcrypt(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// phcHash is a hash in the PHC string format,
+// $id[$v=version][$param=value(,param=value)*]$salt$hash
+type phcHash struct {
+	id      string
+	version int
+	params  map[string]string
+	salt    []byte
+	hash    []byte
+}
+
+// param returns the value of the integer parameter name, which must be
+// between 1 and max
+func (p *phcHash) param(name string, max int) (int, error) {
+	value, err := strconv.Atoi(p.params[name])
+	if err != nil || value < 1 || value > max {
+		return 0, errors.ErrInvalidPasswordHash
+	}
+	return value, nil
+}
+
+// keyDerivation derives the hash of a password with the parameters and salt
+// of a stored hash
+type keyDerivation func(password string) []byte
+
+// schemes check the parameters of a hash of each supported algorithm and
+// return how to derive hashes to compare with it
+var schemes = map[string]func(p *phcHash) (keyDerivation, error){
+	"argon2id":      argon2idScheme,
+	"scrypt":        scryptScheme,
+	"pbkdf2-sha1":   pbkdf2Scheme(sha1.New),
+	"pbkdf2-sha256": pbkdf2Scheme(sha256.New),
+	"pbkdf2-sha512": pbkdf2Scheme(sha512.New),
+	"sha1":          saltedSHAScheme(sha1.New),
+	"sha256":        saltedSHAScheme(sha256.New),
+	"sha512":        saltedSHAScheme(sha512.New),
+}
+
+// parse splits a PHC string and checks it is of a supported algorithm with
+// valid parameters
+func parse(hash string) (*phcHash, keyDerivation, error) {
+	fields := strings.Split(hash, "$")
+	if len(fields) < 4 || fields[0] != "" {
+		return nil, nil, errors.ErrInvalidPasswordHash
+	}
+
+	parsed := &phcHash{id: fields[1], params: map[string]string{}}
+	scheme, ok := schemes[parsed.id]
+	if !ok {
+		return nil, nil, errors.ErrInvalidPasswordHash
+	}
+
+	// The salt and hash are last, after the optional version and parameters
+	for i, field := range fields[2 : len(fields)-2] {
+		if version, ok := strings.CutPrefix(field, "v="); ok && i == 0 {
+			v, err := strconv.Atoi(version)
+			if err != nil {
+				return nil, nil, errors.ErrInvalidPasswordHash
+			}
+			parsed.version = v
+			continue
+		}
+		for _, param := range strings.Split(field, ",") {
+			name, value, ok := strings.Cut(param, "=")
+			if !ok {
+				return nil, nil, errors.ErrInvalidPasswordHash
+			}
+			parsed.params[name] = value
+		}
+	}
+
+	var err error
+	if parsed.salt, err = decodeBase64(fields[len(fields)-2]); err != nil {
+		return nil, nil, errors.ErrInvalidPasswordHash
+	}
+	if parsed.hash, err = decodeBase64(fields[len(fields)-1]); err != nil ||
+		len(parsed.hash) < minDerivedKeyLength || len(parsed.hash) > maxDerivedKeyLength {
+		return nil, nil, errors.ErrInvalidPasswordHash
+	}
+
+	derive, err := scheme(parsed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parsed, derive, nil
+}
+
+// decodeBase64 decodes the salt or hash of a PHC string, which is standard
+// base64 without padding, though some systems pad it
+func decodeBase64(value string) ([]byte, error) {
+	return base64.RawStdEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+// argon2idScheme verifies $argon2id$v=19$m=<KiB>,t=<passes>,p=<lanes>
+func argon2idScheme(p *phcHash) (keyDerivation, error) {
+	if p.version != 0 && p.version != argon2.Version {
+		return nil, errors.ErrInvalidPasswordHash
+	}
+	memory, err := p.param("m", maxArgon2Memory)
+	if err != nil {
+		return nil, err
+	}
+	passes, err := p.param("t", maxArgon2Time)
+	if err != nil {
+		return nil, err
+	}
+	threads, err := p.param("p", 255)
+	if err != nil {
+		return nil, err
+	}
+	return func(password string) []byte {
+		return argon2.IDKey([]byte(password), p.salt, uint32(passes), uint32(memory), uint8(threads), uint32(len(p.hash)))
+	}, nil
+}
+
+// scryptScheme verifies $scrypt$ln=<log2 N>,r=<block size>,p=<parallelism>
+func scryptScheme(p *phcHash) (keyDerivation, error) {
+	logN, err := p.param("ln", maxScryptLogN)
+	if err != nil {
+		return nil, err
+	}
+	blockSize, err := p.param("r", maxScryptBlockSize)
+	if err != nil {
+		return nil, err
+	}
+	parallelism, err := p.param("p", maxScryptParallelism)
+	if err != nil {
+		return nil, err
+	}
+	if 128*blockSize<<logN > maxScryptMemory {
+		return nil, errors.ErrInvalidPasswordHash
+	}
+	return func(password string) []byte {
+		key, err := scrypt.Key([]byte(password), p.salt, 1<<logN, blockSize, parallelism, len(p.hash))
+		if err != nil {
+			return nil
+		}
+		return key
+	}, nil
+}
+
+// pbkdf2Scheme verifies $pbkdf2-<digest>$i=<iterations>
+func pbkdf2Scheme(digest func() hash.Hash) func(p *phcHash) (keyDerivation, error) {
+	return func(p *phcHash) (keyDerivation, error) {
+		iterations, err := p.param("i", maxPBKDF2Iterations)
+		if err != nil {
+			return nil, err
+		}
+		return func(password string) []byte {
+			key, err := pbkdf2.Key(digest, password, p.salt, iterations, len(p.hash))
+			if err != nil {
+				return nil
+			}
+			return key
+		}, nil
+	}
+}
+
+// saltedSHAScheme verifies $<digest>$salt$hash, where hash is the digest of
+// the salt followed by the password
+func saltedSHAScheme(digest func() hash.Hash) func(p *phcHash) (keyDerivation, error) {
+	return func(p *phcHash) (keyDerivation, error) {
+		if len(p.params) != 0 || len(p.hash) != digest().Size() {
+			return nil, errors.ErrInvalidPasswordHash
+		}
+		return func(password string) []byte {
+			h := digest()
+			h.Write(p.salt)
+			h.Write([]byte(password))
+			return h.Sum(nil)
+		}, nil
+	}
+}
@@ -0,0 +1,145 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testPolicy is the default policy, which the legacy argon2id hash below is
+// weaker than
+var testPolicy = config.PasswordConfig{Argon2Memory: 64 * 1024, Argon2Time: 3, Argon2Parallelism: 4}
+
+func newTestHasher(t *testing.T, policy config.PasswordConfig) *hasher {
+	h, err := NewHasher(policy)
+	require.NoError(t, err)
+	return h.(*hasher)
+}
+
+// TestVerify_LegacyHashes tests that hashes of every supported algorithm match their password only
+func TestVerify_LegacyHashes(t *testing.T) {
+	hasher := newTestHasher(t, testPolicy)
+
+	tests := []struct {
+		name     string
+		password string
+		hash     string
+	}{
+		{"bcrypt", "Legacy1!", mustBcrypt(t, "Legacy1!")},
+		{"argon2id", "password", "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"},
+		{"scrypt", "Legacy1!", "$scrypt$ln=10,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$vHLdzj/rdy7ovDD7u7lBgho3MTFOM4F0PcajDMBmGmg"},
+		{"pbkdf2-sha256", "Legacy1!", "$pbkdf2-sha256$i=1000$c2FsdHNhbHRzYWx0c2FsdA$70cUhXcSYOUIjWzWnSYy3dcYSct7zJGo2jkT03TJR7g"},
+		{"pbkdf2-sha1", "Legacy1!", "$pbkdf2-sha1$i=1000$c2FsdHNhbHRzYWx0c2FsdA$HXbcEdC6KlE4JJ6RSI8Dp09R1EM"},
+		{"salted sha256", "Legacy1!", "$sha256$c2FsdHNhbHRzYWx0c2FsdA$sQwWYC52H9FCI6wR4ETnC3EZC9ZIhON5zpVxzv2YY9k"},
+		{"salted sha1", "Legacy1!", "$sha1$c2FsdHNhbHRzYWx0c2FsdA$lkzJ9V1s2H4opk+slrWuBCMDskE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, hasher.Supports(tt.hash))
+			assert.True(t, hasher.NeedsRehash(tt.hash))
+
+			ok, err := hasher.Verify(tt.password, tt.hash)
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = hasher.Verify("wrong"+tt.password, tt.hash)
+			require.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func mustBcrypt(t *testing.T, password string) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	return string(hash)
+}
+
+// TestHash tests that new hashes are argon2id with the parameters of the policy
+func TestHash(t *testing.T) {
+	hasher := newTestHasher(t, config.PasswordConfig{Argon2Memory: 1024, Argon2Time: 2, Argon2Parallelism: 2})
+
+	hash, err := hasher.Hash("Password1!")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=2,p=2$"))
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	ok, err := hasher.Verify("Password1!", hash)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	other, err := hasher.Hash("Password1!")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "every hash has its own salt")
+}
+
+// TestNeedsRehash tests that hashes with any parameter below the policy are rehashed, and stronger ones kept
+func TestNeedsRehash(t *testing.T) {
+	policy := config.PasswordConfig{Argon2Memory: 1024, Argon2Time: 2, Argon2Parallelism: 2}
+	hasher := newTestHasher(t, policy)
+
+	for _, weaker := range []config.PasswordConfig{
+		{Argon2Memory: 512, Argon2Time: 2, Argon2Parallelism: 2},
+		{Argon2Memory: 1024, Argon2Time: 1, Argon2Parallelism: 2},
+		{Argon2Memory: 1024, Argon2Time: 2, Argon2Parallelism: 1},
+	} {
+		hash, err := newTestHasher(t, weaker).Hash("Password1!")
+		require.NoError(t, err)
+		assert.True(t, hasher.NeedsRehash(hash), hash)
+	}
+
+	stronger, err := newTestHasher(t, config.PasswordConfig{Argon2Memory: 2048, Argon2Time: 3, Argon2Parallelism: 2}).Hash("Password1!")
+	require.NoError(t, err)
+	assert.False(t, hasher.NeedsRehash(stronger))
+
+	assert.True(t, hasher.NeedsRehash(mustBcrypt(t, "Password1!")))
+}
+
+// TestNewHasher_InvalidPolicy tests that policies argon2id can't hash with, or above the caps of verified hashes, are refused
+func TestNewHasher_InvalidPolicy(t *testing.T) {
+	for _, policy := range []config.PasswordConfig{
+		{},
+		{Argon2Memory: 64 * 1024, Argon2Time: 0, Argon2Parallelism: 4},
+		{Argon2Memory: 64 * 1024, Argon2Time: 3, Argon2Parallelism: 0},
+		{Argon2Memory: 16, Argon2Time: 3, Argon2Parallelism: 4},
+		{Argon2Memory: 64*1024 + 1, Argon2Time: 3, Argon2Parallelism: 4},
+		{Argon2Memory: 64 * 1024, Argon2Time: 11, Argon2Parallelism: 4},
+	} {
+		_, err := NewHasher(policy)
+		assert.Equal(t, errors.ErrInvalidPasswordPolicy, err, "%+v", policy)
+	}
+}
+
+// TestSupports_Invalid tests that malformed hashes, unknown algorithms and costs above the limits are refused
+func TestSupports_Invalid(t *testing.T) {
+	hasher := newTestHasher(t, testPolicy)
+
+	for _, hash := range []string{
+		"",
+		"plaintext",
+		"$md5$c2FsdA$c2FsdHNhbHRzYWx0c2FsdA",
+		"$pbkdf2-sha256$c2FsdHNhbHRzYWx0c2FsdA$70cUhXcSYOUIjWzWnSYy3dcYSct7zJGo2jkT03TJR7g",
+		"$pbkdf2-sha256$i=1000$not base64$70cUhXcSYOUIjWzWnSYy3dcYSct7zJGo2jkT03TJR7g",
+		"$scrypt$ln=30,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$vHLdzj/rdy7ovDD7u7lBgho3MTFOM4F0PcajDMBmGmg",
+		// Above the caps
+		"$scrypt$ln=17,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$vHLdzj/rdy7ovDD7u7lBgho3MTFOM4F0PcajDMBmGmg",
+		"$scrypt$ln=14,r=8,p=17$c2FsdHNhbHRzYWx0c2FsdA$vHLdzj/rdy7ovDD7u7lBgho3MTFOM4F0PcajDMBmGmg",
+		"$argon2id$v=19$m=131072,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=65536,t=11,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$pbkdf2-sha256$i=1000001$c2FsdHNhbHRzYWx0c2FsdA$70cUhXcSYOUIjWzWnSYy3dcYSct7zJGo2jkT03TJR7g",
+		"$pbkdf2-sha256$i=1000$c2FsdHNhbHRzYWx0c2FsdA$AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8gISIjJCUmJygpKissLS4vMDEyMzQ1Njc4OTo7PD0+P0A",
+		"$argon2id$v=16$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$sha256$c2FsdHNhbHRzYWx0c2FsdA$lkzJ9V1s2H4opk+slrWuBCMDskE",
+		"$2a$10$short",
+	} {
+		assert.False(t, hasher.Supports(hash), hash)
+
+		_, err := hasher.Verify("Password1!", hash)
+		assert.Error(t, err, hash)
+	}
+}
@@ -79,3 +79,139 @@ func (r *RedisRepository) FindToken(ctx context.Context, userID string) (string,
 	}
 	return val, nil
 }
+
+func (r *RedisRepository) TakeToken(ctx context.Context, key string) (string, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while taking token",
+			ports.F("error", ctx.Err()),
+			ports.F("key", key),
+		)
+		return "", errors.ErrContextCancelled
+	}
+
+	val, err := r.client.GetDel(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", errors.ErrTokenNotFound
+		}
+		r.logger.Error("Error taking token",
+			ports.F("error", err),
+			ports.F("key", key),
+		)
+		return "", errors.ErrGetToken
+	}
+	return val, nil
+}
+
+func (r *RedisRepository) IncrementCounter(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while incrementing counter",
+			ports.F("error", ctx.Err()),
+			ports.F("key", key),
+		)
+		return 0, errors.ErrContextCancelled
+	}
+
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, expiration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Error incrementing counter",
+			ports.F("error", err),
+			ports.F("key", key),
+		)
+		return 0, errors.ErrIncrementCounter
+	}
+	return incr.Val(), nil
+}
+
+func (r *RedisRepository) AddToSet(ctx context.Context, key, member string, expiration time.Duration) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while adding to set",
+			ports.F("error", ctx.Err()),
+			ports.F("key", key),
+			ports.F("member", member),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.SAdd(ctx, key, member)
+	pipe.Expire(ctx, key, expiration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Error adding to set",
+			ports.F("error", err),
+			ports.F("key", key),
+			ports.F("member", member),
+		)
+		return errors.ErrAddToken
+	}
+	return nil
+}
+
+func (r *RedisRepository) AddToSetIfAbsent(ctx context.Context, key, member string, expiration time.Duration) (bool, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while adding to set",
+			ports.F("error", ctx.Err()),
+			ports.F("key", key),
+			ports.F("member", member),
+		)
+		return false, errors.ErrContextCancelled
+	}
+
+	// SADD is atomic and counts only members that weren't in the set yet
+	pipe := r.client.TxPipeline()
+	added := pipe.SAdd(ctx, key, member)
+	pipe.Expire(ctx, key, expiration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Error adding to set",
+			ports.F("error", err),
+			ports.F("key", key),
+			ports.F("member", member),
+		)
+		return false, errors.ErrAddToken
+	}
+	return added.Val() == 1, nil
+}
+
+func (r *RedisRepository) RemoveFromSet(ctx context.Context, key, member string) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while removing from set",
+			ports.F("error", ctx.Err()),
+			ports.F("key", key),
+			ports.F("member", member),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	err := r.client.SRem(ctx, key, member).Err()
+	if err != nil {
+		r.logger.Error("Error removing from set",
+			ports.F("error", err),
+			ports.F("key", key),
+			ports.F("member", member),
+		)
+		return errors.ErrRemoveToken
+	}
+	return nil
+}
+
+func (r *RedisRepository) FindSetMembers(ctx context.Context, key string) ([]string, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while finding set members",
+			ports.F("error", ctx.Err()),
+			ports.F("key", key),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	members, err := r.client.SMembers(ctx, key).Result()
+	if err != nil {
+		r.logger.Error("Error getting set members",
+			ports.F("error", err),
+			ports.F("key", key),
+		)
+		return nil, errors.ErrGetToken
+	}
+	return members, nil
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/amirdashtii/go_auth/internal/core/entities"
 	"github.com/amirdashtii/go_auth/internal/core/errors"
 	"github.com/amirdashtii/go_auth/internal/core/ports"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type PGAdminRepository struct {
@@ -24,22 +27,60 @@ func NewPGAdminRepository(db *sql.DB, logger ports.Logger) ports.AdminRepository
 	}
 }
 
-func (r *PGAdminRepository) FindUsers(ctx context.Context, status *entities.StatusType, role *entities.RoleType, sort, order *string) ([]entities.User, error) {
+// userSortColumns are the columns users can be listed by, with the type the
+// value in a cursor is compared as. The sort of a request is only ever used
+// to look a column up here.
+var userSortColumns = map[string]struct{ column, cast string }{
+	"created_at": {"created_at", "timestamp"},
+	"updated_at": {"updated_at", "timestamp"},
+	"email":      {"COALESCE(email, '')", "text"},
+	"first_name": {"COALESCE(first_name, '')", "text"},
+	"last_name":  {"COALESCE(last_name, '')", "text"},
+	"role":       {"role", "integer"},
+	"status":     {"status", "integer"},
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (r *PGAdminRepository) FindUsers(ctx context.Context, filter *entities.UserFilter) ([]entities.User, error) {
 	if ctx.Err() != nil {
 		r.logger.Error("Context cancelled while finding users",
 			ports.F("error", ctx.Err()),
-			ports.F("status", status),
-			ports.F("role", role),
 		)
 		return nil, errors.ErrContextCancelled
 	}
-	query := fmt.Sprintf(`
-	SELECT * FROM users
-	WHERE status = $1 AND role = $2
-	ORDER BY %s %s
-	`, *sort, *order)
 
-	rows, err := r.db.QueryContext(ctx, query, status, role)
+	sort, ok := userSortColumns[filter.Sort]
+	if !ok {
+		return nil, errors.ErrInvalidSortField
+	}
+
+	var args []any
+	// arg adds a query argument and returns its placeholder
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions := userConditions(ctx, filter, arg)
+	order, compare := "ASC", ">"
+	if filter.Descending {
+		order, compare = "DESC", "<"
+	}
+	// Pages continue after the last user of the previous one instead of
+	// skipping rows, so they stay fast however deep the listing goes
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s (%s::%s, %s)", sort.column, compare, arg(filter.After.Value), sort.cast, arg(filter.After.ID)))
+	}
+
+	query := `
+	SELECT id, organization_id, phone_number, first_name, last_name, email, email_verified_at, COALESCE(pending_email, ''), password, status, role, created_at, updated_at, deleted_at
+	FROM users
+	WHERE ` + strings.Join(conditions, " AND ") + "\n"
+	query += fmt.Sprintf("\tORDER BY %s %s, id %s\n\tLIMIT %s", sort.column, order, order, arg(filter.Limit))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -50,10 +91,13 @@ func (r *PGAdminRepository) FindUsers(ctx context.Context, status *entities.Stat
 		var user entities.User
 		err := rows.Scan(
 			&user.ID,
+			&user.OrganizationID,
 			&user.PhoneNumber,
 			&user.FirstName,
 			&user.LastName,
 			&user.Email,
+			&user.EmailVerifiedAt,
+			&user.PendingEmail,
 			&user.Password,
 			&user.Status,
 			&user.Role,
@@ -72,6 +116,65 @@ func (r *PGAdminRepository) FindUsers(ctx context.Context, status *entities.Stat
 	return users, nil
 }
 
+func (r *PGAdminRepository) CountUsers(ctx context.Context, filter *entities.UserFilter) (int, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while counting users",
+			ports.F("error", ctx.Err()),
+		)
+		return 0, errors.ErrContextCancelled
+	}
+
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	query := `SELECT COUNT(*) FROM users WHERE ` + strings.Join(userConditions(ctx, filter, arg), " AND ")
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// userConditions returns the conditions of the WHERE clause selecting the
+// users of filter, leaving out its cursor
+func userConditions(ctx context.Context, filter *entities.UserFilter, arg func(value any) string) []string {
+	conditions := []string{"organization_id = " + arg(entities.OrganizationIDFromContext(ctx))}
+
+	if filter.Search != "" {
+		pattern := arg("%" + likeEscaper.Replace(filter.Search) + "%")
+		conditions = append(conditions, fmt.Sprintf(
+			"(COALESCE(first_name, '') || ' ' || COALESCE(last_name, '') ILIKE %[1]s OR phone_number ILIKE %[1]s OR COALESCE(email, '') ILIKE %[1]s)",
+			pattern,
+		))
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]int64, 0, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			statuses = append(statuses, int64(status))
+		}
+		conditions = append(conditions, "status = ANY("+arg(pq.Array(statuses))+")")
+	}
+	if len(filter.Roles) > 0 {
+		roles := make([]int64, 0, len(filter.Roles))
+		for _, role := range filter.Roles {
+			roles = append(roles, int64(role))
+		}
+		conditions = append(conditions, "role = ANY("+arg(pq.Array(roles))+")")
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= "+arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "created_at < "+arg(*filter.CreatedTo))
+	}
+
+	return conditions
+}
+
 func (r *PGAdminRepository) AdminGetUserByID(ctx context.Context, id *uuid.UUID) (*entities.User, error) {
 	if ctx.Err() != nil {
 		r.logger.Error("Context cancelled while getting user by ID",
@@ -80,16 +183,19 @@ func (r *PGAdminRepository) AdminGetUserByID(ctx context.Context, id *uuid.UUID)
 		)
 		return nil, errors.ErrContextCancelled
 	}
-	query := `SELECT * FROM users WHERE id = $1`
-	row := r.db.QueryRowContext(ctx, query, id)
+	query := `SELECT id, organization_id, phone_number, first_name, last_name, email, email_verified_at, COALESCE(pending_email, ''), password, status, role, created_at, updated_at, deleted_at FROM users WHERE id = $1 AND organization_id = $2`
+	row := r.db.QueryRowContext(ctx, query, id, entities.OrganizationIDFromContext(ctx))
 
 	var user entities.User
 	err := row.Scan(
 		&user.ID,
+		&user.OrganizationID,
 		&user.PhoneNumber,
 		&user.FirstName,
 		&user.LastName,
 		&user.Email,
+		&user.EmailVerifiedAt,
+		&user.PendingEmail,
 		&user.Password,
 		&user.Status,
 		&user.Role,
@@ -110,6 +216,48 @@ func (r *PGAdminRepository) AdminGetUserByID(ctx context.Context, id *uuid.UUID)
 	return &user, nil
 }
 
+func (r *PGAdminRepository) AdminCreateUser(ctx context.Context, user *entities.User) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while creating user",
+			ports.F("error", ctx.Err()),
+			ports.F("phone_number", user.PhoneNumber),
+		)
+		return errors.ErrContextCancelled
+	}
+	query := `
+		INSERT INTO users (id, organization_id, phone_number, first_name, last_name, email, password, status, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		user.ID,
+		user.OrganizationID,
+		user.PhoneNumber,
+		user.FirstName,
+		user.LastName,
+		user.Email,
+		user.Password,
+		user.Status,
+		user.Role,
+		user.CreatedAt,
+		user.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Database error in AdminCreateUser",
+			ports.F("error", err),
+			ports.F("phone_number", user.PhoneNumber),
+		)
+		if err.Error() == "pq: duplicate key value violates unique constraint \"users_phone_number_key\"" {
+			return errors.ErrDuplicatePhoneNumber
+		}
+		if err.Error() == "pq: duplicate key value violates unique constraint \"users_email_key\"" {
+			return errors.ErrDuplicateEmail
+		}
+		return errors.ErrCreateUser
+	}
+	return nil
+}
+
 func (r *PGAdminRepository) AdminUpdateUser(ctx context.Context, user *entities.User) error {
 	if ctx.Err() != nil {
 		r.logger.Error("Context cancelled while updating user",
@@ -138,6 +286,8 @@ func (r *PGAdminRepository) AdminUpdateUser(ctx context.Context, user *entities.
 		i++
 	}
 	if user.Email != "" {
+		// The owner hasn't confirmed an address set by an admin
+		query += "email_verified_at = CASE WHEN email = $" + fmt.Sprint(i) + " THEN email_verified_at END, "
 		query += "email = $" + fmt.Sprint(i) + ", "
 		args = append(args, user.Email)
 		i++
@@ -145,8 +295,8 @@ func (r *PGAdminRepository) AdminUpdateUser(ctx context.Context, user *entities.
 
 	query += "updated_at = NOW()"
 
-	query += " WHERE id = $" + fmt.Sprint(i)
-	args = append(args, user.ID)
+	query += " WHERE id = $" + fmt.Sprint(i) + " AND organization_id = $" + fmt.Sprint(i+1)
+	args = append(args, user.ID, entities.OrganizationIDFromContext(ctx))
 
 	_, err := r.db.Exec(query, args...)
 	if err != nil {
@@ -179,9 +329,17 @@ func (r *PGAdminRepository) AdminChangeUserRole(ctx context.Context, id *uuid.UU
 		)
 		return errors.ErrContextCancelled
 	}
-	query := `UPDATE users SET role = $1 WHERE id = $2`
-	_, err := r.db.ExecContext(ctx, query, role, id)
+	query := `UPDATE users SET role = $1 WHERE id = $2 AND organization_id = $3`
+	var err error
+	if *role == entities.SuperAdminRole {
+		_, err = r.db.ExecContext(ctx, query, role, id, entities.OrganizationIDFromContext(ctx))
+	} else {
+		err = r.execUnlessLastSuperAdmin(ctx, id, query, role, id, entities.OrganizationIDFromContext(ctx))
+	}
 	if err != nil {
+		if err == errors.ErrLastSuperAdmin {
+			return err
+		}
 		r.logger.Error("Database error in AdminChangeUserRole",
 			ports.F("error", err),
 			ports.F("user_id", id),
@@ -203,9 +361,17 @@ func (r *PGAdminRepository) AdminChangeUserStatus(ctx context.Context, id *uuid.
 		)
 		return errors.ErrContextCancelled
 	}
-	query := `UPDATE users SET status = $1 WHERE id = $2`
-	_, err := r.db.ExecContext(ctx, query, status, id)
+	query := `UPDATE users SET status = $1 WHERE id = $2 AND organization_id = $3`
+	var err error
+	if *status == entities.Active {
+		_, err = r.db.ExecContext(ctx, query, status, id, entities.OrganizationIDFromContext(ctx))
+	} else {
+		err = r.execUnlessLastSuperAdmin(ctx, id, query, status, id, entities.OrganizationIDFromContext(ctx))
+	}
 	if err != nil {
+		if err == errors.ErrLastSuperAdmin {
+			return err
+		}
 		r.logger.Error("Database error in AdminChangeUserStatus",
 			ports.F("error", err),
 			ports.F("user_id", id),
@@ -226,9 +392,12 @@ func (r *PGAdminRepository) AdminDeleteUser(ctx context.Context, id *uuid.UUID)
 		)
 		return errors.ErrContextCancelled
 	}
-	query := `UPDATE users SET deleted_at = $1, status = $2 WHERE id = $3`
-	_, err := r.db.ExecContext(ctx, query, time.Now(), entities.Deleted, id)
+	query := `UPDATE users SET deleted_at = $1, status = $2 WHERE id = $3 AND organization_id = $4`
+	err := r.execUnlessLastSuperAdmin(ctx, id, query, time.Now(), entities.Deleted, id, entities.OrganizationIDFromContext(ctx))
 	if err != nil {
+		if err == errors.ErrLastSuperAdmin {
+			return err
+		}
 		r.logger.Error("Database error in AdminDeleteUser",
 			ports.F("error", err),
 			ports.F("user_id", id),
@@ -240,3 +409,142 @@ func (r *PGAdminRepository) AdminDeleteUser(ctx context.Context, id *uuid.UUID)
 	}
 	return nil
 }
+
+// AdminBulkUpdateUsers makes update to every user in ids in one statement,
+// unless it would take the last active super admin of the organization out
+func (r *PGAdminRepository) AdminBulkUpdateUsers(ctx context.Context, ids []uuid.UUID, update *entities.UserBulkUpdate) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while updating users",
+			ports.F("error", ctx.Err()),
+			ports.F("users", len(ids)),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var set string
+	switch {
+	case update.Delete:
+		set = "deleted_at = " + arg(time.Now()) + ", status = " + arg(entities.Deleted)
+	case update.Status != nil:
+		set = "status = " + arg(*update.Status)
+	case update.Role != nil:
+		set = "role = " + arg(*update.Role)
+	default:
+		return errors.ErrInvalidRequest
+	}
+
+	userIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		userIDs = append(userIDs, id.String())
+	}
+	query := "UPDATE users SET " + set + " WHERE id = ANY(" + arg(pq.Array(userIDs)) + "::uuid[]) AND organization_id = " + arg(entities.OrganizationIDFromContext(ctx))
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Error("Database error in AdminBulkUpdateUsers",
+			ports.F("error", err),
+		)
+		return errors.ErrUpdateUser
+	}
+	// Also rolls a dry run back
+	defer tx.Rollback()
+
+	removesSuperAdmins := update.Delete ||
+		(update.Status != nil && *update.Status != entities.Active) ||
+		(update.Role != nil && *update.Role != entities.SuperAdminRole)
+	if removesSuperAdmins {
+		if err := r.lockUnlessLastSuperAdmin(ctx, tx, ids); err != nil {
+			if err == errors.ErrLastSuperAdmin {
+				return err
+			}
+			r.logger.Error("Database error in AdminBulkUpdateUsers",
+				ports.F("error", err),
+			)
+			return errors.ErrUpdateUser
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Database error in AdminBulkUpdateUsers",
+			ports.F("error", err),
+		)
+		return errors.ErrUpdateUser
+	}
+	// A user removed since it was looked up would be left out silently
+	if updated, err := result.RowsAffected(); err != nil || updated != int64(len(ids)) {
+		return errors.ErrUserNotFound
+	}
+
+	if update.DryRun {
+		return nil
+	}
+	if err := tx.Commit(); err != nil {
+		r.logger.Error("Database error in AdminBulkUpdateUsers",
+			ports.F("error", err),
+		)
+		return errors.ErrUpdateUser
+	}
+	return nil
+}
+
+// execUnlessLastSuperAdmin runs query, which takes the user id out of the
+// active super admins, unless they are the only one left in the
+// organization
+func (r *PGAdminRepository) execUnlessLastSuperAdmin(ctx context.Context, id *uuid.UUID, query string, args ...any) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.lockUnlessLastSuperAdmin(ctx, tx, []uuid.UUID{*id}); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lockUnlessLastSuperAdmin locks the active super admins of the organization
+// and returns ErrLastSuperAdmin if the users ids include all of them. The lock
+// keeps two of them from removing each other at the same time.
+func (r *PGAdminRepository) lockUnlessLastSuperAdmin(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) error {
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM users WHERE role = $1 AND status = $2 AND organization_id = $3 FOR UPDATE`, entities.SuperAdminRole, entities.Active, entities.OrganizationIDFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	var removed, remaining int
+	for rows.Next() {
+		var superAdminID uuid.UUID
+		if err := rows.Scan(&superAdminID); err != nil {
+			rows.Close()
+			return err
+		}
+		if slices.Contains(ids, superAdminID) {
+			removed++
+		} else {
+			remaining++
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if removed > 0 && remaining == 0 {
+		r.logger.Warn("Refused to remove the last super admin",
+			ports.F("user_ids", ids),
+		)
+		return errors.ErrLastSuperAdmin
+	}
+	return nil
+}
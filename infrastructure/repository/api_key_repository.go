@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type PGAPIKeyRepository struct {
+	db     *sql.DB
+	logger ports.Logger
+}
+
+func NewPGAPIKeyRepository(db *sql.DB, logger ports.Logger) ports.APIKeyRepository {
+	return &PGAPIKeyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *PGAPIKeyRepository) CreateAPIKey(ctx context.Context, key *entities.APIKey) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while creating API key",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", key.UserID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	query := `
+		INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		key.ID,
+		key.UserID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		pq.Array(key.Scopes),
+		key.ExpiresAt,
+		key.CreatedAt,
+	)
+
+	if err != nil {
+		r.logger.Error("Database error in CreateAPIKey",
+			ports.F("error", err),
+			ports.F("user_id", key.UserID),
+		)
+		return errors.ErrCreateAPIKey
+	}
+
+	return nil
+}
+
+func (r *PGAPIKeyRepository) FindAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]entities.APIKey, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while finding API keys",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	query := `
+	SELECT k.id, k.user_id, u.organization_id, k.name, k.prefix, k.key_hash, k.scopes, k.expires_at, k.created_at
+	FROM api_keys k
+	JOIN users u ON u.id = k.user_id
+	WHERE k.user_id = $1
+	ORDER BY k.created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		r.logger.Error("Database error in FindAPIKeysByUserID",
+			ports.F("error", err),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrGetAPIKeys
+	}
+	defer rows.Close()
+
+	var keys []entities.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			r.logger.Error("Database error in FindAPIKeysByUserID",
+				ports.F("error", err),
+				ports.F("user_id", userID),
+			)
+			return nil, errors.ErrGetAPIKeys
+		}
+		keys = append(keys, *key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.ErrGetAPIKeys
+	}
+
+	return keys, nil
+}
+
+func (r *PGAPIKeyRepository) FindAPIKeyByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while finding API key",
+			ports.F("error", ctx.Err()),
+			ports.F("prefix", prefix),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	query := `
+	SELECT k.id, k.user_id, u.organization_id, k.name, k.prefix, k.key_hash, k.scopes, k.expires_at, k.created_at
+	FROM api_keys k
+	JOIN users u ON u.id = k.user_id
+	WHERE k.prefix = $1
+	`
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, prefix))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrAPIKeyNotFound
+		}
+		r.logger.Error("Database error in FindAPIKeyByPrefix",
+			ports.F("error", err),
+			ports.F("prefix", prefix),
+		)
+		return nil, errors.ErrGetAPIKeys
+	}
+
+	return key, nil
+}
+
+func (r *PGAPIKeyRepository) DeleteAPIKey(ctx context.Context, userID, keyID uuid.UUID) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while deleting API key",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+			ports.F("key_id", keyID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	query := `DELETE FROM api_keys WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, keyID, userID)
+	if err != nil {
+		r.logger.Error("Database error in DeleteAPIKey",
+			ports.F("error", err),
+			ports.F("key_id", keyID),
+		)
+		return errors.ErrDeleteAPIKey
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.ErrDeleteAPIKey
+	}
+	if rowsAffected == 0 {
+		return errors.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// scanAPIKey reads an API key from a row selected by the queries above
+func scanAPIKey(row interface{ Scan(...any) error }) (*entities.APIKey, error) {
+	var (
+		key       entities.APIKey
+		expiresAt sql.NullTime
+	)
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.OrganizationID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		pq.Array(&key.Scopes),
+		&expiresAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	return &key, nil
+}
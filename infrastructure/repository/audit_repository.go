@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type PGAuditRepository struct {
+	db     *sql.DB
+	logger ports.Logger
+}
+
+func NewPGAuditRepository(db *sql.DB, logger ports.Logger) ports.AuditRepository {
+	return &PGAuditRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *PGAuditRepository) CreateEvent(ctx context.Context, event *entities.AuditEvent) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while creating audit event",
+			ports.F("error", ctx.Err()),
+			ports.F("event_type", event.Type),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	changes, err := nullableJSON(event.Changes, len(event.Changes))
+	if err != nil {
+		return errors.ErrCreateAuditEvent
+	}
+	details, err := nullableJSON(event.Details, len(event.Details))
+	if err != nil {
+		return errors.ErrCreateAuditEvent
+	}
+
+	query := `
+		INSERT INTO audit_events (id, organization_id, event_type, actor_id, target_id, ip, user_agent, changes, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		event.ID,
+		event.OrganizationID,
+		event.Type,
+		event.ActorID,
+		event.TargetID,
+		event.IP,
+		event.UserAgent,
+		changes,
+		details,
+		event.CreatedAt,
+	)
+
+	if err != nil {
+		r.logger.Error("Database error in CreateEvent",
+			ports.F("error", err),
+			ports.F("event_type", event.Type),
+		)
+		return errors.ErrCreateAuditEvent
+	}
+
+	return nil
+}
+
+func (r *PGAuditRepository) FindEvents(ctx context.Context, filter *entities.AuditFilter) ([]entities.AuditEvent, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while finding audit events",
+			ports.F("error", ctx.Err()),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	var (
+		conditions []string
+		args       []any
+	)
+	// arg adds a query argument and returns its placeholder
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions = append(conditions, "organization_id = "+arg(entities.OrganizationIDFromContext(ctx)))
+
+	if filter.Type != "" {
+		conditions = append(conditions, "event_type = "+arg(filter.Type))
+	}
+	if filter.ActorID != nil {
+		conditions = append(conditions, "actor_id = "+arg(*filter.ActorID))
+	}
+	if filter.TargetID != nil {
+		conditions = append(conditions, "target_id = "+arg(*filter.TargetID))
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= "+arg(*filter.From))
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < "+arg(*filter.To))
+	}
+	// Pages continue after the last event of the previous one instead of
+	// skipping rows, so events recorded meanwhile don't shift them
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < (%s, %s)", arg(filter.After.CreatedAt), arg(filter.After.ID)))
+	}
+
+	query := `
+	SELECT id, organization_id, event_type, actor_id, target_id, COALESCE(ip, ''), COALESCE(user_agent, ''), changes, details, created_at
+	FROM audit_events
+	WHERE ` + strings.Join(conditions, " AND ") + "\n"
+	query += "ORDER BY created_at DESC, id DESC\n\tLIMIT " + arg(filter.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Database error in FindEvents",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrGetAuditEvents
+	}
+	defer rows.Close()
+
+	var events []entities.AuditEvent
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			r.logger.Error("Database error in FindEvents",
+				ports.F("error", err),
+			)
+			return nil, errors.ErrGetAuditEvents
+		}
+		events = append(events, *event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.ErrGetAuditEvents
+	}
+
+	return events, nil
+}
+
+// scanAuditEvent reads an audit event from a row selected by FindEvents
+func scanAuditEvent(row interface{ Scan(...any) error }) (*entities.AuditEvent, error) {
+	var (
+		event             entities.AuditEvent
+		actorID, targetID uuid.NullUUID
+		changes, details  []byte
+	)
+	err := row.Scan(
+		&event.ID,
+		&event.OrganizationID,
+		&event.Type,
+		&actorID,
+		&targetID,
+		&event.IP,
+		&event.UserAgent,
+		&changes,
+		&details,
+		&event.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if actorID.Valid {
+		event.ActorID = &actorID.UUID
+	}
+	if targetID.Valid {
+		event.TargetID = &targetID.UUID
+	}
+	if changes != nil {
+		if err := json.Unmarshal(changes, &event.Changes); err != nil {
+			return nil, err
+		}
+	}
+	if details != nil {
+		if err := json.Unmarshal(details, &event.Details); err != nil {
+			return nil, err
+		}
+	}
+	return &event, nil
+}
+
+// nullableJSON encodes v for a JSONB column, or returns nil to store NULL
+// when it has no entries
+func nullableJSON(v any, entries int) (any, error) {
+	if entries == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
@@ -32,12 +32,13 @@ func (r *PGAuthRepository) Create(ctx context.Context, user *entities.User) erro
 	}
 
 	query := `
-		INSERT INTO users (id, phone_number, password, first_name, last_name, email, status, role, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO users (id, organization_id, phone_number, password, first_name, last_name, email, status, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
 		user.ID,
+		user.OrganizationID,
 		user.PhoneNumber,
 		user.Password,
 		user.FirstName,
@@ -74,19 +75,22 @@ func (r *PGAuthRepository) FindUserByPhoneNumber(ctx context.Context, phoneNumbe
 	}
 
 	query := `
-	SELECT id, phone_number, password, first_name, last_name, email, status, role, created_at, updated_at, deleted_at
+	SELECT id, organization_id, phone_number, password, first_name, last_name, email, email_verified_at, COALESCE(pending_email, ''), status, role, created_at, updated_at, deleted_at
 	FROM users
-	WHERE phone_number = $1
+	WHERE phone_number = $1 AND organization_id = $2
 `
 
 	var user entities.User
-	err := r.db.QueryRowContext(ctx, query, phoneNumber).Scan(
+	err := r.db.QueryRowContext(ctx, query, phoneNumber, entities.OrganizationIDFromContext(ctx)).Scan(
 		&user.ID,
+		&user.OrganizationID,
 		&user.PhoneNumber,
 		&user.Password,
 		&user.FirstName,
 		&user.LastName,
 		&user.Email,
+		&user.EmailVerifiedAt,
+		&user.PendingEmail,
 		&user.Status,
 		&user.Role,
 		&user.CreatedAt,
@@ -119,19 +123,22 @@ func (r *PGAuthRepository) FindUserByID(ctx context.Context, id uuid.UUID) (*ent
 	}
 
 	query := `
-	SELECT id, phone_number, password, first_name, last_name, email, status, role, created_at, updated_at, deleted_at
+	SELECT id, organization_id, phone_number, password, first_name, last_name, email, email_verified_at, COALESCE(pending_email, ''), status, role, created_at, updated_at, deleted_at
 	FROM users
-	WHERE id = $1
+	WHERE id = $1 AND organization_id = $2
 	`
 
 	var user entities.User
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id, entities.OrganizationIDFromContext(ctx)).Scan(
 		&user.ID,
+		&user.OrganizationID,
 		&user.PhoneNumber,
 		&user.Password,
 		&user.FirstName,
 		&user.LastName,
 		&user.Email,
+		&user.EmailVerifiedAt,
+		&user.PendingEmail,
 		&user.Status,
 		&user.Role,
 		&user.CreatedAt,
@@ -153,3 +160,143 @@ func (r *PGAuthRepository) FindUserByID(ctx context.Context, id uuid.UUID) (*ent
 
 	return &user, nil
 }
+
+func (r *PGAuthRepository) UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while updating user password",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", id),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	query := "UPDATE users SET password = $1, updated_at = NOW() WHERE id = $2 AND organization_id = $3"
+	result, err := r.db.ExecContext(ctx, query, hashedPassword, id, entities.OrganizationIDFromContext(ctx))
+	if err != nil {
+		r.logger.Error("Database error in UpdatePassword",
+			ports.F("error", err),
+			ports.F("user_id", id),
+		)
+		return errors.ErrUpdateUser
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return errors.ErrUserNotFound
+	}
+	return nil
+}
+
+// UpdatePhoneNumber replaces the phone number of the user
+func (r *PGAuthRepository) UpdatePhoneNumber(ctx context.Context, id uuid.UUID, phoneNumber string) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while updating user phone number",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", id),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	query := "UPDATE users SET phone_number = $1, updated_at = NOW() WHERE id = $2 AND organization_id = $3"
+	result, err := r.db.ExecContext(ctx, query, phoneNumber, id, entities.OrganizationIDFromContext(ctx))
+	if err != nil {
+		r.logger.Error("Database error in UpdatePhoneNumber",
+			ports.F("error", err),
+			ports.F("user_id", id),
+		)
+		if err.Error() == "pq: duplicate key value violates unique constraint \"users_phone_number_key\"" {
+			return errors.ErrDuplicatePhoneNumber
+		}
+		return errors.ErrUpdateUser
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return errors.ErrUserNotFound
+	}
+	return nil
+}
+
+// FindUserByVerifiedEmail finds the user an email address belongs to. Only
+// confirmed addresses are matched, since an unconfirmed one may belong to
+// someone else.
+func (r *PGAuthRepository) FindUserByVerifiedEmail(ctx context.Context, email string) (*entities.User, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while finding user by email",
+			ports.F("error", ctx.Err()),
+			ports.F("email", email),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	query := `
+	SELECT id, organization_id, phone_number, password, first_name, last_name, email, email_verified_at, COALESCE(pending_email, ''), status, role, created_at, updated_at, deleted_at
+	FROM users
+	WHERE LOWER(email) = LOWER($1) AND email_verified_at IS NOT NULL AND organization_id = $2
+	`
+
+	var user entities.User
+	err := r.db.QueryRowContext(ctx, query, email, entities.OrganizationIDFromContext(ctx)).Scan(
+		&user.ID,
+		&user.OrganizationID,
+		&user.PhoneNumber,
+		&user.Password,
+		&user.FirstName,
+		&user.LastName,
+		&user.Email,
+		&user.EmailVerifiedAt,
+		&user.PendingEmail,
+		&user.Status,
+		&user.Role,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.DeletedAt,
+	)
+
+	if err != nil {
+		r.logger.Error("Database error in FindUserByVerifiedEmail",
+			ports.F("error", err),
+			ports.F("email", email),
+		)
+
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrUserNotFound
+		}
+		return nil, errors.ErrGetUser
+	}
+
+	return &user, nil
+}
+
+// ConfirmEmail marks email as the verified address of the user. email has to
+// be the user's current or pending address, otherwise ErrUserNotFound is
+// returned.
+func (r *PGAuthRepository) ConfirmEmail(ctx context.Context, id uuid.UUID, email string) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while confirming email",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", id),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	query := `
+	UPDATE users
+	SET email = $2, pending_email = NULL, email_verified_at = NOW(), updated_at = NOW()
+	WHERE id = $1 AND organization_id = $3 AND deleted_at IS NULL AND (email = $2 OR pending_email = $2)
+	`
+	result, err := r.db.ExecContext(ctx, query, id, email, entities.OrganizationIDFromContext(ctx))
+	if err != nil {
+		r.logger.Error("Database error in ConfirmEmail",
+			ports.F("error", err),
+			ports.F("user_id", id),
+		)
+		if err.Error() == "pq: duplicate key value violates unique constraint \"users_email_key\"" {
+			return errors.ErrDuplicateEmail
+		}
+		return errors.ErrUpdateUser
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return errors.ErrUserNotFound
+	}
+	return nil
+}
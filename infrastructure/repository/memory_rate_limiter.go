@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+// MemoryRateLimiter keeps its counters in the process. Limits aren't shared
+// between instances, so it is meant for tests and single instance setups.
+type MemoryRateLimiter struct {
+	mu       sync.Mutex
+	counters map[string]*windowCounter
+	now      func() time.Time
+}
+
+type windowCounter struct {
+	index    int64
+	previous int64
+	current  int64
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		counters: make(map[string]*windowCounter),
+		now:      time.Now,
+	}
+}
+
+// SetClock replaces the time source, so tests can move between windows
+func (m *MemoryRateLimiter) SetClock(now func() time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = now
+}
+
+func (m *MemoryRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*ports.RateLimitResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index, elapsed := slidingWindowPosition(m.now(), window)
+
+	counter, ok := m.counters[key]
+	if !ok {
+		counter = &windowCounter{index: index}
+		m.counters[key] = counter
+	}
+	switch {
+	case counter.index == index-1:
+		counter.previous, counter.current = counter.current, 0
+	case counter.index < index-1:
+		counter.previous, counter.current = 0, 0
+	}
+	counter.index = index
+
+	weight := slidingWindowWeight(window, elapsed)
+	allowed := float64(counter.previous)*weight+float64(counter.current)+1 <= float64(limit)
+	if allowed {
+		counter.current++
+	}
+
+	return slidingWindowResult(limit, window, elapsed, allowed, counter.previous, counter.current), nil
+}
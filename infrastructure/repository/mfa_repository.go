@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type PGMFARepository struct {
+	db     *sql.DB
+	logger ports.Logger
+}
+
+func NewPGMFARepository(db *sql.DB, logger ports.Logger) ports.MFARepository {
+	return &PGMFARepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// EnableMFA stores the user's authenticator together with a new set of
+// recovery codes, replacing any previous ones
+func (r *PGMFARepository) EnableMFA(ctx context.Context, mfa *entities.UserMFA, recoveryCodeHashes []string) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while enabling MFA",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", mfa.UserID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Error("Database error in EnableMFA",
+			ports.F("error", err),
+			ports.F("user_id", mfa.UserID),
+		)
+		return errors.ErrEnableMFA
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO user_mfa (user_id, totp_secret, enabled_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET totp_secret = EXCLUDED.totp_secret, enabled_at = EXCLUDED.enabled_at
+	`
+	if _, err := tx.ExecContext(ctx, query, mfa.UserID, mfa.TOTPSecret, mfa.EnabledAt); err != nil {
+		r.logger.Error("Database error in EnableMFA",
+			ports.F("error", err),
+			ports.F("user_id", mfa.UserID),
+		)
+		return errors.ErrEnableMFA
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, mfa.UserID); err != nil {
+		r.logger.Error("Database error in EnableMFA",
+			ports.F("error", err),
+			ports.F("user_id", mfa.UserID),
+		)
+		return errors.ErrEnableMFA
+	}
+
+	for _, codeHash := range recoveryCodeHashes {
+		query := `INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES ($1, $2)`
+		if _, err := tx.ExecContext(ctx, query, mfa.UserID, codeHash); err != nil {
+			r.logger.Error("Database error in EnableMFA",
+				ports.F("error", err),
+				ports.F("user_id", mfa.UserID),
+			)
+			return errors.ErrEnableMFA
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.Error("Database error in EnableMFA",
+			ports.F("error", err),
+			ports.F("user_id", mfa.UserID),
+		)
+		return errors.ErrEnableMFA
+	}
+
+	return nil
+}
+
+func (r *PGMFARepository) FindMFAByUserID(ctx context.Context, userID uuid.UUID) (*entities.UserMFA, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while finding MFA",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	query := `
+	SELECT user_id, totp_secret, enabled_at
+	FROM user_mfa
+	WHERE user_id = $1
+	`
+
+	var mfa entities.UserMFA
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&mfa.UserID,
+		&mfa.TOTPSecret,
+		&mfa.EnabledAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrMFANotEnabled
+		}
+		r.logger.Error("Database error in FindMFAByUserID",
+			ports.F("error", err),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrGetMFA
+	}
+
+	return &mfa, nil
+}
+
+// UseRecoveryCode marks one of the user's unused recovery codes as used
+func (r *PGMFARepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while using recovery code",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	query := `
+		UPDATE mfa_recovery_codes
+		SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		r.logger.Error("Database error in UseRecoveryCode",
+			ports.F("error", err),
+			ports.F("user_id", userID),
+		)
+		return errors.ErrGetMFA
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.ErrGetMFA
+	}
+	if rowsAffected == 0 {
+		return errors.ErrRecoveryCodeNotFound
+	}
+
+	return nil
+}
+
+// DeleteMFA turns off two-factor authentication for the user, removing the
+// authenticator and its recovery codes
+func (r *PGMFARepository) DeleteMFA(ctx context.Context, userID uuid.UUID) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while deleting MFA",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id = $1`, userID)
+	if err != nil {
+		r.logger.Error("Database error in DeleteMFA",
+			ports.F("error", err),
+			ports.F("user_id", userID),
+		)
+		return errors.ErrDeleteMFA
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.ErrDeleteMFA
+	}
+	if rowsAffected == 0 {
+		return errors.ErrMFANotEnabled
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/lib/pq"
+)
+
+type PGOAuthClientRepository struct {
+	db     *sql.DB
+	logger ports.Logger
+}
+
+func NewPGOAuthClientRepository(db *sql.DB, logger ports.Logger) ports.OAuthClientRepository {
+	return &PGOAuthClientRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *PGOAuthClientRepository) CreateClient(ctx context.Context, client *entities.OAuthClient) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while creating OAuth client",
+			ports.F("error", ctx.Err()),
+			ports.F("client_id", client.ID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	query := `
+		INSERT INTO oauth_clients (id, organization_id, name, secret_hash, redirect_uris, scopes, grant_types, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		client.ID,
+		client.OrganizationID,
+		client.Name,
+		sql.NullString{String: client.SecretHash, Valid: client.SecretHash != ""},
+		pq.Array(client.RedirectURIs),
+		pq.Array(client.Scopes),
+		pq.Array(client.GrantTypes),
+		client.CreatedAt,
+		client.UpdatedAt,
+	)
+
+	if err != nil {
+		r.logger.Error("Database error in CreateClient",
+			ports.F("error", err),
+			ports.F("client_id", client.ID),
+		)
+		return errors.ErrCreateOAuthClient
+	}
+
+	return nil
+}
+
+func (r *PGOAuthClientRepository) FindClientByID(ctx context.Context, clientID string) (*entities.OAuthClient, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while finding OAuth client",
+			ports.F("error", ctx.Err()),
+			ports.F("client_id", clientID),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	query := `
+	SELECT id, organization_id, name, secret_hash, redirect_uris, scopes, grant_types, created_at, updated_at
+	FROM oauth_clients
+	WHERE id = $1
+	`
+
+	var (
+		client     entities.OAuthClient
+		secretHash sql.NullString
+	)
+	err := r.db.QueryRowContext(ctx, query, clientID).Scan(
+		&client.ID,
+		&client.OrganizationID,
+		&client.Name,
+		&secretHash,
+		pq.Array(&client.RedirectURIs),
+		pq.Array(&client.Scopes),
+		pq.Array(&client.GrantTypes),
+		&client.CreatedAt,
+		&client.UpdatedAt,
+	)
+
+	if err != nil {
+		r.logger.Error("Database error in FindClientByID",
+			ports.F("error", err),
+			ports.F("client_id", clientID),
+		)
+
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrOAuthClientNotFound
+		}
+		return nil, errors.ErrGetOAuthClient
+	}
+
+	client.SecretHash = secretHash.String
+	return &client, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+type PGOrganizationRepository struct {
+	db     *sql.DB
+	logger ports.Logger
+}
+
+func NewPGOrganizationRepository(db *sql.DB, logger ports.Logger) ports.OrganizationRepository {
+	return &PGOrganizationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *PGOrganizationRepository) CreateOrganization(ctx context.Context, organization *entities.Organization, owner *entities.User) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while creating organization",
+			ports.F("error", ctx.Err()),
+			ports.F("slug", organization.Slug),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Error("Database error in CreateOrganization",
+			ports.F("error", err),
+			ports.F("slug", organization.Slug),
+		)
+		return errors.ErrCreateOrganization
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO organizations (id, slug, name, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`,
+		organization.ID,
+		organization.Slug,
+		organization.Name,
+		organization.CreatedAt,
+		organization.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Database error in CreateOrganization",
+			ports.F("error", err),
+			ports.F("slug", organization.Slug),
+		)
+		if err.Error() == "pq: duplicate key value violates unique constraint \"organizations_slug_key\"" {
+			return errors.ErrDuplicateOrganization
+		}
+		return errors.ErrCreateOrganization
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO users (id, organization_id, phone_number, password, first_name, last_name, email, status, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`,
+		owner.ID,
+		organization.ID,
+		owner.PhoneNumber,
+		owner.Password,
+		owner.FirstName,
+		owner.LastName,
+		owner.Email,
+		owner.Status,
+		owner.Role,
+		owner.CreatedAt,
+		owner.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Database error in CreateOrganization",
+			ports.F("error", err),
+			ports.F("slug", organization.Slug),
+		)
+		return errors.ErrCreateOrganization
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.Error("Database error in CreateOrganization",
+			ports.F("error", err),
+			ports.F("slug", organization.Slug),
+		)
+		return errors.ErrCreateOrganization
+	}
+	return nil
+}
+
+func (r *PGOrganizationRepository) FindOrganizationBySlug(ctx context.Context, slug string) (*entities.Organization, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while finding organization",
+			ports.F("error", ctx.Err()),
+			ports.F("slug", slug),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	query := `
+	SELECT id, slug, name, created_at, updated_at
+	FROM organizations
+	WHERE slug = $1
+	`
+
+	var organization entities.Organization
+	err := r.db.QueryRowContext(ctx, query, slug).Scan(
+		&organization.ID,
+		&organization.Slug,
+		&organization.Name,
+		&organization.CreatedAt,
+		&organization.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrOrganizationNotFound
+		}
+		r.logger.Error("Database error in FindOrganizationBySlug",
+			ports.F("error", err),
+			ports.F("slug", slug),
+		)
+		return nil, errors.ErrGetOrganizations
+	}
+
+	return &organization, nil
+}
+
+func (r *PGOrganizationRepository) FindOrganizations(ctx context.Context) ([]entities.Organization, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while finding organizations",
+			ports.F("error", ctx.Err()),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	query := `
+	SELECT id, slug, name, created_at, updated_at
+	FROM organizations
+	ORDER BY created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Error("Database error in FindOrganizations",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrGetOrganizations
+	}
+	defer rows.Close()
+
+	var organizations []entities.Organization
+	for rows.Next() {
+		var organization entities.Organization
+		err := rows.Scan(
+			&organization.ID,
+			&organization.Slug,
+			&organization.Name,
+			&organization.CreatedAt,
+			&organization.UpdatedAt,
+		)
+		if err != nil {
+			r.logger.Error("Database error in FindOrganizations",
+				ports.F("error", err),
+			)
+			return nil, errors.ErrGetOrganizations
+		}
+		organizations = append(organizations, organization)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.ErrGetOrganizations
+	}
+
+	return organizations, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+type PGPermissionRepository struct {
+	db     *sql.DB
+	logger ports.Logger
+}
+
+func NewPGPermissionRepository(db *sql.DB, logger ports.Logger) ports.PermissionRepository {
+	return &PGPermissionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *PGPermissionRepository) FindPermissionsByRole(ctx context.Context, role string) ([]string, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while finding permissions",
+			ports.F("error", ctx.Err()),
+			ports.F("role", role),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	query := `
+	SELECT rp.permission
+	FROM role_permissions rp
+	JOIN roles r ON r.id = rp.role_id
+	WHERE r.name = $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, role)
+	if err != nil {
+		r.logger.Error("Database error in FindPermissionsByRole",
+			ports.F("error", err),
+			ports.F("role", role),
+		)
+		return nil, errors.ErrGetPermissions
+	}
+	defer rows.Close()
+
+	var permissions []string
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			r.logger.Error("Database error in FindPermissionsByRole",
+				ports.F("error", err),
+				ports.F("role", role),
+			)
+			return nil, errors.ErrGetPermissions
+		}
+		permissions = append(permissions, permission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.ErrGetPermissions
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript counts a request unless the estimate of the last
+// window's requests would go over the limit. KEYS are the counters of the
+// current and previous fixed windows, ARGV the limit, the counter lifetime
+// in milliseconds and the weight of the previous window.
+var slidingWindowScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local previous = tonumber(redis.call('GET', KEYS[2]) or '0')
+if previous * tonumber(ARGV[3]) + current + 1 > tonumber(ARGV[1]) then
+	return {0, previous, current}
+end
+current = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return {1, previous, current}
+`)
+
+// RedisRateLimiter keeps its counters in Redis so every instance of the
+// service shares the same limits
+type RedisRateLimiter struct {
+	client *redis.Client
+	logger ports.Logger
+}
+
+func NewRedisRateLimiter(logger ports.Logger) (*RedisRateLimiter, error) {
+	config, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.Redis.Addr,
+		Password: config.Redis.Password,
+		DB:       config.Redis.DB,
+	})
+	if _, err := client.Ping(context.Background()).Result(); err != nil {
+		return nil, err
+	}
+
+	return &RedisRateLimiter{client: client, logger: logger}, nil
+}
+
+func (r *RedisRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*ports.RateLimitResult, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while checking rate limit",
+			ports.F("error", ctx.Err()),
+			ports.F("key", key),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	index, elapsed := slidingWindowPosition(time.Now(), window)
+	keys := []string{
+		key + ":" + strconv.FormatInt(index, 10),
+		key + ":" + strconv.FormatInt(index-1, 10),
+	}
+	weight := slidingWindowWeight(window, elapsed)
+
+	values, err := slidingWindowScript.Run(ctx, r.client, keys,
+		limit,
+		(2 * window).Milliseconds(),
+		strconv.FormatFloat(weight, 'f', -1, 64),
+	).Int64Slice()
+	if err != nil || len(values) != 3 {
+		r.logger.Error("Error checking rate limit",
+			ports.F("error", err),
+			ports.F("key", key),
+		)
+		return nil, errors.ErrRateLimiter
+	}
+
+	return slidingWindowResult(limit, window, elapsed, values[0] == 1, values[1], values[2]), nil
+}
+
+// slidingWindowPosition returns the fixed window t falls in and how far into
+// it t is
+func slidingWindowPosition(t time.Time, window time.Duration) (int64, time.Duration) {
+	nanos := t.UnixNano()
+	return nanos / int64(window), time.Duration(nanos % int64(window))
+}
+
+// slidingWindowWeight is the part of the previous fixed window that is still
+// inside the sliding window
+func slidingWindowWeight(window, elapsed time.Duration) float64 {
+	return float64(window-elapsed) / float64(window)
+}
+
+// slidingWindowResult describes a key's usage from the counts of the previous
+// and current fixed windows, after the request was counted if allowed
+func slidingWindowResult(limit int, window, elapsed time.Duration, allowed bool, previous, current int64) *ports.RateLimitResult {
+	weight := slidingWindowWeight(window, elapsed)
+	used := int(math.Ceil(float64(previous)*weight + float64(current)))
+
+	result := &ports.RateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: max(limit-used, 0),
+		Reset:     window - elapsed,
+	}
+	if allowed {
+		return result
+	}
+
+	// Find when the estimate leaves room for one more request. Inside the
+	// current window only the previous window's share goes down, after it
+	// the current count becomes the one fading out.
+	if int64(limit) > current && previous > 0 {
+		wait := float64(window-elapsed) - float64(int64(limit)-current-1)*float64(window)/float64(previous)
+		result.RetryAfter = time.Duration(math.Max(wait, 0))
+	} else {
+		next := float64(window) * (1 - float64(limit-1)/float64(current))
+		result.RetryAfter = window - elapsed + time.Duration(math.Max(next, 0))
+	}
+	return result
+}
@@ -32,18 +32,21 @@ func (r *PGUserRepository) FindUserByID(ctx context.Context, id *uuid.UUID) (*en
 		return nil, errors.ErrContextCancelled
 	}
 	query := `
-	SELECT id, phone_number, first_name, last_name, email, password, status, role, created_at, updated_at, deleted_at
+	SELECT id, organization_id, phone_number, first_name, last_name, email, email_verified_at, COALESCE(pending_email, ''), password, status, role, created_at, updated_at, deleted_at
 	FROM users
-	WHERE id = $1
+	WHERE id = $1 AND organization_id = $2
 	`
 
 	var user entities.User
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id, entities.OrganizationIDFromContext(ctx)).Scan(
 		&user.ID,
+		&user.OrganizationID,
 		&user.PhoneNumber,
 		&user.FirstName,
 		&user.LastName,
 		&user.Email,
+		&user.EmailVerifiedAt,
+		&user.PendingEmail,
 		&user.Password,
 		&user.Status,
 		&user.Role,
@@ -98,6 +101,11 @@ func (r *PGUserRepository) Update(ctx context.Context, user *entities.User) erro
 		args = append(args, user.Email)
 		i++
 	}
+	if user.PendingEmail != "" {
+		query += "pending_email = $" + fmt.Sprint(i) + ", "
+		args = append(args, user.PendingEmail)
+		i++
+	}
 	if user.Password != "" {
 		query += "password = $" + fmt.Sprint(i) + ", "
 		args = append(args, user.Password)
@@ -106,8 +114,8 @@ func (r *PGUserRepository) Update(ctx context.Context, user *entities.User) erro
 
 	query += "updated_at = NOW()"
 
-	query += " WHERE id = $" + fmt.Sprint(i)
-	args = append(args, user.ID)
+	query += " WHERE id = $" + fmt.Sprint(i) + " AND organization_id = $" + fmt.Sprint(i+1)
+	args = append(args, user.ID, entities.OrganizationIDFromContext(ctx))
 
 	_, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
@@ -137,8 +145,8 @@ func (r *PGUserRepository) Delete(ctx context.Context, id *uuid.UUID) error {
 		)
 		return errors.ErrContextCancelled
 	}
-	query := `UPDATE users SET deleted_at = NOW(), status = $2 WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id, entities.Deleted)
+	query := `UPDATE users SET deleted_at = NOW(), status = $2 WHERE id = $1 AND organization_id = $3`
+	_, err := r.db.ExecContext(ctx, query, id, entities.Deleted, entities.OrganizationIDFromContext(ctx))
 	if err != nil {
 		r.logger.Error("Database error in Delete",
 			ports.F("error", err),
@@ -161,12 +169,13 @@ func (r *PGUserRepository) CreateUser(ctx context.Context, user *entities.User)
 		return errors.ErrContextCancelled
 	}
 	query := `
-		INSERT INTO users (id, phone_number, first_name, last_name, email, password, status, role, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO users (id, organization_id, phone_number, first_name, last_name, email, password, status, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
 		user.ID,
+		user.OrganizationID,
 		user.PhoneNumber,
 		user.FirstName,
 		user.LastName,
@@ -202,8 +211,8 @@ func (r *PGUserRepository) UpdatePassword(ctx context.Context, userID *uuid.UUID
 		)
 		return errors.ErrContextCancelled
 	}
-	query := "UPDATE users SET password = $1 WHERE id = $2"
-	_, err := r.db.ExecContext(ctx, query, hashedPassword, userID)
+	query := "UPDATE users SET password = $1 WHERE id = $2 AND organization_id = $3"
+	_, err := r.db.ExecContext(ctx, query, hashedPassword, userID, entities.OrganizationIDFromContext(ctx))
 	if err != nil {
 		r.logger.Error("Database error in UpdatePassword",
 			ports.F("error", err),
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type PGWebAuthnRepository struct {
+	db     *sql.DB
+	logger ports.Logger
+}
+
+func NewPGWebAuthnRepository(db *sql.DB, logger ports.Logger) ports.WebAuthnRepository {
+	return &PGWebAuthnRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *PGWebAuthnRepository) CreateCredential(ctx context.Context, credential *entities.WebAuthnCredential) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while creating WebAuthn credential",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", credential.UserID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	query := `
+		INSERT INTO webauthn_credentials (id, user_id, credential_id, public_key, sign_count, transports, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		credential.ID,
+		credential.UserID,
+		credential.CredentialID,
+		credential.PublicKey,
+		int64(credential.SignCount),
+		pq.Array(credential.Transports),
+		credential.CreatedAt,
+	)
+
+	if err != nil {
+		r.logger.Error("Database error in CreateCredential",
+			ports.F("error", err),
+			ports.F("user_id", credential.UserID),
+		)
+
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return errors.ErrWebAuthnCredentialExists
+		}
+		return errors.ErrCreateWebAuthnCredential
+	}
+
+	return nil
+}
+
+func (r *PGWebAuthnRepository) FindCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]entities.WebAuthnCredential, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while finding WebAuthn credentials",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	query := `
+	SELECT id, user_id, credential_id, public_key, sign_count, transports, created_at, last_used_at
+	FROM webauthn_credentials
+	WHERE user_id = $1
+	ORDER BY created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		r.logger.Error("Database error in FindCredentialsByUserID",
+			ports.F("error", err),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrGetWebAuthnCredential
+	}
+	defer rows.Close()
+
+	var credentials []entities.WebAuthnCredential
+	for rows.Next() {
+		credential, err := scanWebAuthnCredential(rows)
+		if err != nil {
+			r.logger.Error("Database error in FindCredentialsByUserID",
+				ports.F("error", err),
+				ports.F("user_id", userID),
+			)
+			return nil, errors.ErrGetWebAuthnCredential
+		}
+		credentials = append(credentials, *credential)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.ErrGetWebAuthnCredential
+	}
+
+	return credentials, nil
+}
+
+func (r *PGWebAuthnRepository) FindCredentialByCredentialID(ctx context.Context, credentialID []byte) (*entities.WebAuthnCredential, error) {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while finding WebAuthn credential",
+			ports.F("error", ctx.Err()),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	query := `
+	SELECT id, user_id, credential_id, public_key, sign_count, transports, created_at, last_used_at
+	FROM webauthn_credentials
+	WHERE credential_id = $1
+	`
+
+	credential, err := scanWebAuthnCredential(r.db.QueryRowContext(ctx, query, credentialID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrWebAuthnCredentialNotFound
+		}
+		r.logger.Error("Database error in FindCredentialByCredentialID",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrGetWebAuthnCredential
+	}
+
+	return credential, nil
+}
+
+// UpdateCredentialSignCount records the counter of the credential's latest
+// signature and when it was used
+func (r *PGWebAuthnRepository) UpdateCredentialSignCount(ctx context.Context, id uuid.UUID, signCount uint32) error {
+	if ctx.Err() != nil {
+		r.logger.Error("Context cancelled while updating WebAuthn credential",
+			ports.F("error", ctx.Err()),
+			ports.F("id", id),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	query := `UPDATE webauthn_credentials SET sign_count = $1, last_used_at = CURRENT_TIMESTAMP WHERE id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, int64(signCount), id); err != nil {
+		r.logger.Error("Database error in UpdateCredentialSignCount",
+			ports.F("error", err),
+			ports.F("id", id),
+		)
+		return errors.ErrUpdateWebAuthnCredential
+	}
+
+	return nil
+}
+
+// scanWebAuthnCredential reads a credential from a row selected by the queries above
+func scanWebAuthnCredential(row interface{ Scan(...any) error }) (*entities.WebAuthnCredential, error) {
+	var (
+		credential entities.WebAuthnCredential
+		signCount  int64
+		lastUsedAt sql.NullTime
+	)
+	err := row.Scan(
+		&credential.ID,
+		&credential.UserID,
+		&credential.CredentialID,
+		&credential.PublicKey,
+		&signCount,
+		pq.Array(&credential.Transports),
+		&credential.CreatedAt,
+		&lastUsedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	credential.SignCount = uint32(signCount)
+	if lastUsedAt.Valid {
+		credential.LastUsedAt = &lastUsedAt.Time
+	}
+	return &credential, nil
+}
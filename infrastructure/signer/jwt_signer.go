@@ -0,0 +1,531 @@
+package signer
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// defaultSecretKeyID is the kid of an HS256 key when none is configured. It
+// is not derived from the secret so nothing about the secret leaks.
+const defaultSecretKeyID = "default"
+
+// signingKey is a key together with the algorithm it signs with
+type signingKey struct {
+	id     string
+	method jwt.SigningMethod
+	// private is a []byte secret for HS256 or a crypto.Signer otherwise
+	private interface{}
+	// public is nil for HS256
+	public crypto.PublicKey
+}
+
+// jwtSigner signs tokens with the active key and verifies them with the key
+// named by their kid header. Without a keyring directory the configured key
+// is the only one. With a keyring, keys are shared through the directory and
+// every process picks up a rotation the next time it uses the signer.
+type jwtSigner struct {
+	mu sync.RWMutex
+
+	// configured is the key from the configuration, active until the first rotation
+	configured *signingKey
+	keyringDir string
+	// manifest is the loaded keyring.json and checkedAt when it was last
+	// compared with the file
+	manifest  []byte
+	checkedAt time.Time
+	// retiredUntil is when each retired key stops verifying tokens
+	retiredUntil map[string]time.Time
+
+	active *signingKey
+	keys   map[string]*signingKey
+	logger ports.Logger
+}
+
+// NewJWTSigner creates a new token signer from the JWT configuration
+func NewJWTSigner(cfg config.JWTConfig, logger ports.Logger) (ports.TokenSigner, error) {
+	key, err := loadSigningKey(cfg)
+	if err != nil {
+		logger.Error("Error loading signing key",
+			ports.F("error", err),
+			ports.F("algorithm", cfg.Algorithm),
+		)
+		return nil, err
+	}
+
+	s := &jwtSigner{
+		configured: key,
+		keyringDir: cfg.KeyringDir,
+		active:     key,
+		keys:       map[string]*signingKey{key.id: key},
+		logger:     logger,
+	}
+
+	if s.keyringDir != "" {
+		if err := os.MkdirAll(s.keyringDir, 0700); err != nil {
+			logger.Error("Error creating keyring directory",
+				ports.F("error", err),
+				ports.F("dir", s.keyringDir),
+			)
+			return nil, errors.ErrLoadSigningKey
+		}
+		if err := s.reload(); err != nil {
+			logger.Error("Error loading keyring",
+				ports.F("error", err),
+				ports.F("dir", s.keyringDir),
+			)
+			return nil, errors.ErrLoadSigningKey
+		}
+	}
+
+	return s, nil
+}
+
+func (s *jwtSigner) Sign(claims jwt.MapClaims) (string, error) {
+	s.refresh()
+
+	s.mu.RLock()
+	active := s.active
+	s.mu.RUnlock()
+
+	token := jwt.NewWithClaims(active.method, claims)
+	token.Header["kid"] = active.id
+
+	tokenString, err := token.SignedString(active.private)
+	if err != nil {
+		s.logger.Error("Error signing token",
+			ports.F("error", err),
+			ports.F("kid", active.id),
+		)
+		return "", errors.ErrTokenCreation
+	}
+	return tokenString, nil
+}
+
+func (s *jwtSigner) Verify(token string) (jwt.MapClaims, error) {
+	s.refresh()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	parsedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		// Tokens issued before kids were introduced have none and can only
+		// have been signed by the configured key
+		kid, ok := t.Header["kid"].(string)
+		if !ok {
+			kid = s.configured.id
+		}
+		key, ok := s.keys[kid]
+		if !ok {
+			return nil, errors.ErrUnknownSigningKey
+		}
+
+		// The algorithm is bound to the key, never taken from the token
+		if t.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %q", t.Method.Alg())
+		}
+
+		if key.public == nil {
+			return key.private, nil
+		}
+		return key.public, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.ErrInvalidTokenClaims
+	}
+	return claims, nil
+}
+
+func (s *jwtSigner) PublicKeys() []entities.JSONWebKey {
+	s.refresh()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]entities.JSONWebKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		if key.public == nil {
+			continue
+		}
+		jwk, err := publicJWK(key)
+		if err != nil {
+			s.logger.Error("Error encoding public key",
+				ports.F("error", err),
+				ports.F("kid", key.id),
+			)
+			continue
+		}
+		keys = append(keys, *jwk)
+	}
+	return keys
+}
+
+func (s *jwtSigner) Rotate() (string, error) {
+	if s.keyringDir == "" {
+		return "", errors.ErrKeyRotationDisabled
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reload(); err != nil {
+		s.logger.Error("Error loading keyring",
+			ports.F("error", err),
+			ports.F("dir", s.keyringDir),
+		)
+		return "", errors.ErrRotateSigningKey
+	}
+
+	m, err := readManifest(s.keyringDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return "", errors.ErrRotateSigningKey
+		}
+		// First rotation, the configured key becomes the first retired key
+		m = &manifest{
+			Active: s.configured.id,
+			Keys: []manifestKey{{
+				KeyID:     s.configured.id,
+				Algorithm: s.configured.method.Alg(),
+				CreatedAt: time.Now().UTC(),
+			}},
+		}
+	}
+
+	key, entry, err := generateKey(s.keyringDir, s.configured.method.Alg())
+	if err != nil {
+		s.logger.Error("Error generating signing key",
+			ports.F("error", err),
+		)
+		return "", errors.ErrRotateSigningKey
+	}
+
+	now := time.Now().UTC()
+	keys := make([]manifestKey, 0, len(m.Keys)+1)
+	for _, k := range m.Keys {
+		if k.KeyID == m.Active {
+			k.RetiredAt = &now
+		}
+		if k.expired(now) {
+			if k.File != "" {
+				os.Remove(filepath.Join(s.keyringDir, filepath.Base(k.File)))
+			}
+			continue
+		}
+		keys = append(keys, k)
+	}
+
+	retired := m.Active
+	m.Active = key.id
+	m.Keys = append(keys, *entry)
+
+	if err := writeManifest(s.keyringDir, m); err != nil {
+		s.logger.Error("Error writing keyring",
+			ports.F("error", err),
+			ports.F("dir", s.keyringDir),
+		)
+		return "", errors.ErrRotateSigningKey
+	}
+
+	if err := s.reload(); err != nil {
+		return "", errors.ErrRotateSigningKey
+	}
+
+	s.logger.Info("Signing key rotated",
+		ports.F("kid", key.id),
+		ports.F("retired_kid", retired),
+	)
+	return key.id, nil
+}
+
+// refresh reloads the keyring if another process has changed it. The file
+// is checked at most once every keyringCheckInterval.
+func (s *jwtSigner) refresh() {
+	if s.keyringDir == "" {
+		return
+	}
+
+	now := time.Now()
+	s.mu.RLock()
+	fresh := now.Sub(s.checkedAt) < keyringCheckInterval && !s.hasExpiredKeys(now)
+	s.mu.RUnlock()
+	if fresh {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reload(); err != nil {
+		s.logger.Error("Error reloading keyring, keeping the loaded keys",
+			ports.F("error", err),
+			ports.F("dir", s.keyringDir),
+		)
+	}
+}
+
+// reload reads the keyring when keyring.json has changed since it was last
+// loaded. Retired keys are dropped once their tokens can no longer be valid.
+// The caller must hold the write lock.
+func (s *jwtSigner) reload() error {
+	now := time.Now()
+
+	data, err := os.ReadFile(filepath.Join(s.keyringDir, manifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.checkedAt = now
+			return nil
+		}
+		return err
+	}
+
+	if bytes.Equal(data, s.manifest) && !s.hasExpiredKeys(now) {
+		s.checkedAt = now
+		return nil
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	keys := make(map[string]*signingKey, len(m.Keys))
+	for i := range m.Keys {
+		entry := &m.Keys[i]
+		if entry.expired(now) {
+			continue
+		}
+
+		if entry.File == "" {
+			if entry.KeyID == s.configured.id {
+				keys[entry.KeyID] = s.configured
+			}
+			continue
+		}
+
+		key, err := loadKeyringKey(s.keyringDir, entry)
+		if err != nil {
+			return err
+		}
+		keys[key.id] = key
+	}
+
+	active, ok := keys[m.Active]
+	if !ok {
+		return errors.ErrUnknownSigningKey
+	}
+
+	s.active = active
+	s.keys = keys
+	s.retiredUntil = make(map[string]time.Time)
+	for _, entry := range m.Keys {
+		if entry.RetiredAt != nil {
+			s.retiredUntil[entry.KeyID] = entry.RetiredAt.Add(retiredKeyValidity)
+		}
+	}
+	s.manifest = data
+	s.checkedAt = now
+	return nil
+}
+
+// hasExpiredKeys reports whether a loaded retired key has outlived its tokens
+func (s *jwtSigner) hasExpiredKeys(now time.Time) bool {
+	for kid, until := range s.retiredUntil {
+		if _, ok := s.keys[kid]; ok && now.After(until) {
+			return true
+		}
+	}
+	return false
+}
+
+func loadSigningKey(cfg config.JWTConfig) (*signingKey, error) {
+	algorithm := strings.ToUpper(cfg.Algorithm)
+	if algorithm == "" {
+		algorithm = jwt.SigningMethodHS256.Alg()
+	}
+
+	if algorithm == jwt.SigningMethodHS256.Alg() {
+		if cfg.Secret == "" {
+			return nil, errors.ErrLoadSigningKey
+		}
+		id := cfg.KeyID
+		if id == "" {
+			id = defaultSecretKeyID
+		}
+		return &signingKey{
+			id:      id,
+			method:  jwt.SigningMethodHS256,
+			private: []byte(cfg.Secret),
+		}, nil
+	}
+
+	privateKey, err := readPrivateKey(cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAsymmetricKey(algorithm, cfg.KeyID, privateKey)
+}
+
+// newAsymmetricKey checks that privateKey suits algorithm. When id is empty
+// the RFC 7638 thumbprint of the public key is used as the kid.
+func newAsymmetricKey(algorithm, id string, privateKey crypto.Signer) (*signingKey, error) {
+	var method jwt.SigningMethod
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey:
+		if algorithm == jwt.SigningMethodRS256.Alg() {
+			method = jwt.SigningMethodRS256
+		}
+	case *ecdsa.PrivateKey:
+		if algorithm == jwt.SigningMethodES256.Alg() && k.Curve == elliptic.P256() {
+			method = jwt.SigningMethodES256
+		}
+	case ed25519.PrivateKey:
+		if algorithm == strings.ToUpper(jwt.SigningMethodEdDSA.Alg()) {
+			method = jwt.SigningMethodEdDSA
+		}
+	}
+	if method == nil {
+		return nil, errors.ErrUnsupportedSigningAlg
+	}
+
+	key := &signingKey{
+		id:      id,
+		method:  method,
+		private: privateKey,
+		public:  privateKey.Public(),
+	}
+
+	if key.id == "" {
+		thumbprint, err := keyThumbprint(key)
+		if err != nil {
+			return nil, err
+		}
+		key.id = thumbprint
+	}
+
+	return key, nil
+}
+
+// readPrivateKey reads a PKCS#8, PKCS#1 or SEC 1 PEM encoded private key
+func readPrivateKey(path string) (crypto.Signer, error) {
+	if path == "" {
+		return nil, errors.ErrLoadSigningKey
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.ErrLoadSigningKey
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.ErrUnsupportedSigningAlg
+		}
+		return signer, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.ErrLoadSigningKey
+}
+
+func publicJWK(key *signingKey) (*entities.JSONWebKey, error) {
+	jwk := &entities.JSONWebKey{
+		Use:       "sig",
+		Algorithm: key.method.Alg(),
+		KeyID:     key.id,
+	}
+
+	switch pub := key.public.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(bigEndianInt(pub.E))
+	case *ecdsa.PublicKey:
+		ecdhKey, err := pub.ECDH()
+		if err != nil {
+			return nil, err
+		}
+		// Uncompressed point: 0x04 || X || Y
+		point := ecdhKey.Bytes()
+		size := (len(point) - 1) / 2
+		jwk.KeyType = "EC"
+		jwk.Curve = pub.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(point[1 : 1+size])
+		jwk.Y = base64.RawURLEncoding.EncodeToString(point[1+size:])
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	default:
+		return nil, errors.ErrUnsupportedSigningAlg
+	}
+
+	return jwk, nil
+}
+
+// keyThumbprint computes the RFC 7638 thumbprint of a public key
+func keyThumbprint(key *signingKey) (string, error) {
+	jwk, err := publicJWK(key)
+	if err != nil {
+		return "", err
+	}
+
+	// Required members only, in lexicographic order
+	var members string
+	switch jwk.KeyType {
+	case "RSA":
+		members = fmt.Sprintf(`{"e":%q,"kty":%q,"n":%q}`, jwk.E, jwk.KeyType, jwk.N)
+	case "EC":
+		members = fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q,"y":%q}`, jwk.Curve, jwk.KeyType, jwk.X, jwk.Y)
+	case "OKP":
+		members = fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q}`, jwk.Curve, jwk.KeyType, jwk.X)
+	}
+
+	sum := sha256.Sum256([]byte(members))
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func bigEndianInt(n int) []byte {
+	var b []byte
+	for ; n > 0; n >>= 8 {
+		b = append([]byte{byte(n)}, b...)
+	}
+	return b
+}
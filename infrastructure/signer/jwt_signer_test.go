@@ -0,0 +1,168 @@
+package signer
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/infrastructure/logger"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogger() ports.Logger {
+	return logger.NewZerologLogger(ports.LoggerConfig{
+		Level:       "fatal",
+		Environment: "test",
+		ServiceName: "go_auth",
+		Output:      io.Discard,
+	})
+}
+
+// writeTestKey stores key as a PKCS#8 PEM file and returns its path
+func writeTestKey(t *testing.T, key crypto.Signer) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "signing_key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+	return path
+}
+
+func testClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"user_id": "user123",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// TestJWTSigner_Asymmetric tests signing and verifying with every supported asymmetric algorithm
+func TestJWTSigner_Asymmetric(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		algorithm string
+		key       crypto.Signer
+		keyType   string
+	}{
+		{name: "RS256", algorithm: "RS256", key: rsaKey, keyType: "RSA"},
+		{name: "ES256", algorithm: "ES256", key: ecKey, keyType: "EC"},
+		{name: "EdDSA", algorithm: "EdDSA", key: edKey, keyType: "OKP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenSigner, err := NewJWTSigner(config.JWTConfig{
+				Algorithm:      tt.algorithm,
+				PrivateKeyFile: writeTestKey(t, tt.key),
+			}, newTestLogger())
+			require.NoError(t, err)
+
+			token, err := tokenSigner.Sign(testClaims())
+			require.NoError(t, err)
+
+			claims, err := tokenSigner.Verify(token)
+			require.NoError(t, err)
+			assert.Equal(t, "user123", claims["user_id"])
+
+			keys := tokenSigner.PublicKeys()
+			require.Len(t, keys, 1)
+			assert.Equal(t, tt.keyType, keys[0].KeyType)
+			assert.Equal(t, tt.algorithm, keys[0].Algorithm)
+			assert.Equal(t, "sig", keys[0].Use)
+			assert.NotEmpty(t, keys[0].KeyID)
+
+			// The kid header names the published key
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, keys[0].KeyID, parsed.Header["kid"])
+		})
+	}
+}
+
+// TestJWTSigner_Secret tests that HS256 tokens carry a kid and publish no keys
+func TestJWTSigner_Secret(t *testing.T) {
+	tokenSigner, err := NewJWTSigner(config.JWTConfig{Algorithm: "HS256", Secret: "secret"}, newTestLogger())
+	require.NoError(t, err)
+
+	token, err := tokenSigner.Sign(testClaims())
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, defaultSecretKeyID, parsed.Header["kid"])
+
+	_, err = tokenSigner.Verify(token)
+	assert.NoError(t, err)
+	assert.Empty(t, tokenSigner.PublicKeys())
+
+	// Tokens issued before kids were added are still accepted
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims()).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	_, err = tokenSigner.Verify(legacy)
+	assert.NoError(t, err)
+}
+
+// TestJWTSigner_RejectsForeignTokens tests tokens with an unknown kid or a swapped algorithm
+func TestJWTSigner_RejectsForeignTokens(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tokenSigner, err := NewJWTSigner(config.JWTConfig{
+		Algorithm:      "RS256",
+		PrivateKeyFile: writeTestKey(t, rsaKey),
+		KeyID:          "primary",
+	}, newTestLogger())
+	require.NoError(t, err)
+
+	// Unknown kid
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	foreign := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims())
+	foreign.Header["kid"] = "other"
+	foreignToken, err := foreign.SignedString(other)
+	require.NoError(t, err)
+	_, err = tokenSigner.Verify(foreignToken)
+	assert.Error(t, err)
+
+	// HS256 token using the public key as the secret
+	publicDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	confused.Header["kid"] = "primary"
+	confusedToken, err := confused.SignedString(publicDER)
+	require.NoError(t, err)
+	_, err = tokenSigner.Verify(confusedToken)
+	assert.Error(t, err)
+}
+
+// TestNewJWTSigner_MismatchedKey tests that a key that doesn't fit the algorithm is refused
+func TestNewJWTSigner_MismatchedKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, err = NewJWTSigner(config.JWTConfig{
+		Algorithm:      "RS256",
+		PrivateKeyFile: writeTestKey(t, ecKey),
+	}, newTestLogger())
+	assert.Error(t, err)
+}
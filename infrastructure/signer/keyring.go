@@ -0,0 +1,190 @@
+package signer
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	manifestFile = "keyring.json"
+
+	// retiredKeyValidity is how long a retired key keeps verifying tokens. It
+	// matches the lifetime of a refresh token, the longest lived token we issue.
+	retiredKeyValidity = 7 * 24 * time.Hour
+
+	// keyringCheckInterval is how often keyring.json is checked for rotations
+	// made by other processes
+	keyringCheckInterval = 5 * time.Second
+
+	secretPEMType = "HMAC SECRET"
+)
+
+// manifestKey describes one key of the keyring. File is empty for the key
+// loaded from the configuration, which lives outside the keyring directory.
+type manifestKey struct {
+	KeyID     string     `json:"kid"`
+	Algorithm string     `json:"alg"`
+	File      string     `json:"file,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RetiredAt *time.Time `json:"retired_at,omitempty"`
+}
+
+// manifest is the content of keyring.json. It is shared by every process
+// that uses the keyring directory and only ever replaced as a whole.
+type manifest struct {
+	Active string        `json:"active"`
+	Keys   []manifestKey `json:"keys"`
+}
+
+func (k *manifestKey) expired(now time.Time) bool {
+	return k.RetiredAt != nil && now.Sub(*k.RetiredAt) > retiredKeyValidity
+}
+
+func readManifest(dir string) (*manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// writeManifest replaces keyring.json atomically so other processes never
+// read a partial file
+func writeManifest(dir string, m *manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, manifestFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, manifestFile))
+}
+
+// generateKey creates a new key for algorithm and stores it in dir
+func generateKey(dir, algorithm string) (*signingKey, *manifestKey, error) {
+	algorithm = strings.ToUpper(algorithm)
+
+	var (
+		key   *signingKey
+		block *pem.Block
+	)
+
+	if algorithm == jwt.SigningMethodHS256.Alg() {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, nil, err
+		}
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return nil, nil, err
+		}
+
+		key = &signingKey{
+			id:      base64.RawURLEncoding.EncodeToString(id),
+			method:  jwt.SigningMethodHS256,
+			private: secret,
+		}
+		block = &pem.Block{Type: secretPEMType, Bytes: secret}
+	} else {
+		var (
+			privateKey crypto.Signer
+			err        error
+		)
+		switch algorithm {
+		case jwt.SigningMethodRS256.Alg():
+			privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		case jwt.SigningMethodES256.Alg():
+			privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		case strings.ToUpper(jwt.SigningMethodEdDSA.Alg()):
+			_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+		default:
+			return nil, nil, errors.ErrUnsupportedSigningAlg
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key, err = newAsymmetricKey(algorithm, "", privateKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	}
+
+	file := key.id + ".pem"
+	if err := os.WriteFile(filepath.Join(dir, file), pem.EncodeToMemory(block), 0600); err != nil {
+		return nil, nil, err
+	}
+
+	return key, &manifestKey{
+		KeyID:     key.id,
+		Algorithm: key.method.Alg(),
+		File:      file,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// loadKeyringKey reads a key that was written by generateKey
+func loadKeyringKey(dir string, entry *manifestKey) (*signingKey, error) {
+	path := filepath.Join(dir, filepath.Base(entry.File))
+
+	if entry.Algorithm == jwt.SigningMethodHS256.Alg() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode(data)
+		if block == nil || block.Type != secretPEMType {
+			return nil, errors.ErrLoadSigningKey
+		}
+		return &signingKey{
+			id:      entry.KeyID,
+			method:  jwt.SigningMethodHS256,
+			private: block.Bytes,
+		}, nil
+	}
+
+	privateKey, err := readPrivateKey(path)
+	if err != nil {
+		return nil, err
+	}
+	return newAsymmetricKey(strings.ToUpper(entry.Algorithm), entry.KeyID, privateKey)
+}
@@ -0,0 +1,110 @@
+package signer
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tokenKeyID(t *testing.T, token string) string {
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+// TestRotate_KeepsRetiredKeys tests that tokens signed before a rotation stay valid
+func TestRotate_KeepsRetiredKeys(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	cfg := config.JWTConfig{
+		Algorithm:      "EdDSA",
+		PrivateKeyFile: writeTestKey(t, edKey),
+		KeyringDir:     t.TempDir(),
+	}
+
+	tokenSigner, err := NewJWTSigner(cfg, newTestLogger())
+	require.NoError(t, err)
+	// A second instance stands in for another process sharing the keyring
+	otherSigner, err := NewJWTSigner(cfg, newTestLogger())
+	require.NoError(t, err)
+
+	oldToken, err := tokenSigner.Sign(testClaims())
+	require.NoError(t, err)
+
+	kid, err := tokenSigner.Rotate()
+	require.NoError(t, err)
+	assert.NotEqual(t, tokenKeyID(t, oldToken), kid)
+
+	otherSigner.(*jwtSigner).checkedAt = time.Time{}
+	newToken, err := otherSigner.Sign(testClaims())
+	require.NoError(t, err)
+	assert.Equal(t, kid, tokenKeyID(t, newToken))
+
+	for _, s := range []interface {
+		Verify(string) (jwt.MapClaims, error)
+	}{tokenSigner, otherSigner} {
+		_, err = s.Verify(oldToken)
+		assert.NoError(t, err)
+		_, err = s.Verify(newToken)
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, otherSigner.PublicKeys(), 2)
+}
+
+// TestRotate_DropsExpiredKeys tests that a retired key stops verifying once its tokens have expired
+func TestRotate_DropsExpiredKeys(t *testing.T) {
+	cfg := config.JWTConfig{
+		Algorithm:  "HS256",
+		Secret:     "secret",
+		KeyringDir: t.TempDir(),
+	}
+
+	tokenSigner, err := NewJWTSigner(cfg, newTestLogger())
+	require.NoError(t, err)
+
+	oldToken, err := tokenSigner.Sign(testClaims())
+	require.NoError(t, err)
+
+	_, err = tokenSigner.Rotate()
+	require.NoError(t, err)
+
+	// Move the retirement of the configured key back past the validity window
+	m, err := readManifest(cfg.KeyringDir)
+	require.NoError(t, err)
+	retiredAt := time.Now().Add(-retiredKeyValidity - time.Hour)
+	for i := range m.Keys {
+		if m.Keys[i].KeyID == defaultSecretKeyID {
+			m.Keys[i].RetiredAt = &retiredAt
+		}
+	}
+	require.NoError(t, writeManifest(cfg.KeyringDir, m))
+	// Don't wait for the next scheduled check
+	tokenSigner.(*jwtSigner).checkedAt = time.Time{}
+
+	_, err = tokenSigner.Verify(oldToken)
+	assert.Error(t, err)
+
+	newToken, err := tokenSigner.Sign(testClaims())
+	require.NoError(t, err)
+	_, err = tokenSigner.Verify(newToken)
+	assert.NoError(t, err)
+}
+
+// TestRotate_WithoutKeyring tests that rotation needs a keyring directory
+func TestRotate_WithoutKeyring(t *testing.T) {
+	tokenSigner, err := NewJWTSigner(config.JWTConfig{Algorithm: "HS256", Secret: "secret"}, newTestLogger())
+	require.NoError(t, err)
+
+	_, err = tokenSigner.Rotate()
+	assert.Equal(t, errors.ErrKeyRotationDisabled, err)
+}
@@ -0,0 +1,73 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+// consoleSender is a stand-in SMS provider that writes messages to an
+// io.Writer instead of delivering them. It is meant for development and tests.
+type consoleSender struct {
+	mu     sync.Mutex
+	output io.Writer
+	logger ports.Logger
+}
+
+// NewConsoleSender creates a new SMS sender that writes messages to output
+func NewConsoleSender(output io.Writer, logger ports.Logger) ports.SMSSender {
+	return &consoleSender{
+		output: output,
+		logger: logger,
+	}
+}
+
+// NewFileSender creates a new SMS sender that appends messages to logs/sms.log
+func NewFileSender(logger ports.Logger) (ports.SMSSender, error) {
+	// Create logs directory if it doesn't exist
+	logsDir := "logs"
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		return nil, err
+	}
+
+	smsFile, err := os.OpenFile(
+		filepath.Join(logsDir, "sms.log"),
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
+		0644,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConsoleSender(smsFile, logger), nil
+}
+
+func (s *consoleSender) Send(ctx context.Context, phoneNumber, message string) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while sending SMS",
+			ports.F("error", ctx.Err()),
+			ports.F("phone_number", phoneNumber),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := fmt.Fprintf(s.output, "[%s] SMS to %s: %s\n", time.Now().Format(time.RFC3339), phoneNumber, message)
+	if err != nil {
+		s.logger.Error("Error sending SMS",
+			ports.F("error", err),
+			ports.F("phone_number", phoneNumber),
+		)
+		return errors.ErrSendSMS
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package sms
+
+import (
+	"os"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+// NewSender creates the SMS sender selected by cfg. The console and file
+// senders don't deliver anything, so they are refused outside development.
+func NewSender(cfg config.SMSConfig, environment string, logger ports.Logger) (ports.SMSSender, error) {
+	switch cfg.Sender {
+	case "console", "file":
+		if environment != "development" {
+			return nil, errors.ErrUnsupportedSMSSender
+		}
+		if cfg.Sender == "file" {
+			return NewFileSender(logger)
+		}
+		return NewConsoleSender(os.Stdout, logger), nil
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			return nil, errors.ErrUnsupportedSMSSender
+		}
+		return NewWebhookSender(cfg.WebhookURL, cfg.WebhookToken, logger), nil
+	default:
+		return nil, errors.ErrUnsupportedSMSSender
+	}
+}
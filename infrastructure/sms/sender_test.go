@@ -0,0 +1,81 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/infrastructure/logger"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogger() ports.Logger {
+	return logger.NewZerologLogger(ports.LoggerConfig{
+		Level:       "fatal",
+		Environment: "test",
+		ServiceName: "go_auth",
+		Output:      io.Discard,
+	})
+}
+
+// TestNewSender tests that the console sender is only allowed in development
+func TestNewSender(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.SMSConfig
+		environment string
+		wantErr     error
+	}{
+		{"console in development", config.SMSConfig{Sender: "console"}, "development", nil},
+		{"console in production", config.SMSConfig{Sender: "console"}, "production", errors.ErrUnsupportedSMSSender},
+		{"console without environment", config.SMSConfig{Sender: "console"}, "", errors.ErrUnsupportedSMSSender},
+		{"file in production", config.SMSConfig{Sender: "file"}, "production", errors.ErrUnsupportedSMSSender},
+		{"webhook in production", config.SMSConfig{Sender: "webhook", WebhookURL: "https://sms.example.com"}, "production", nil},
+		{"webhook without url", config.SMSConfig{Sender: "webhook"}, "production", errors.ErrUnsupportedSMSSender},
+		{"unknown sender", config.SMSConfig{Sender: "carrier-pigeon"}, "development", errors.ErrUnsupportedSMSSender},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender, err := NewSender(tt.cfg, tt.environment, newTestLogger())
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				assert.Nil(t, sender)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, sender)
+		})
+	}
+}
+
+// TestWebhookSender tests that messages are posted to the gateway and rejected deliveries are reported
+func TestWebhookSender(t *testing.T) {
+	var received webhookMessage
+	var authorization string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(server.URL, "gateway-token", newTestLogger())
+
+	err := sender.Send(context.Background(), "09123456789", "Your verification code is 123456.")
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer gateway-token", authorization)
+	assert.Equal(t, webhookMessage{To: "09123456789", Message: "Your verification code is 123456."}, received)
+
+	status = http.StatusBadGateway
+	err = sender.Send(context.Background(), "09123456789", "Your verification code is 123456.")
+	assert.Equal(t, errors.ErrSendSMS, err)
+}
@@ -0,0 +1,85 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// webhookSender delivers messages by posting them as JSON to the HTTP
+// endpoint of an SMS gateway
+type webhookSender struct {
+	url    string
+	token  string
+	client *http.Client
+	logger ports.Logger
+}
+
+type webhookMessage struct {
+	To      string `json:"to"`
+	Message string `json:"message"`
+}
+
+// NewWebhookSender creates a new SMS sender that posts messages to url,
+// authenticated with token as a bearer token when it is set
+func NewWebhookSender(url, token string, logger ports.Logger) ports.SMSSender {
+	return &webhookSender{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: webhookTimeout},
+		logger: logger,
+	}
+}
+
+func (s *webhookSender) Send(ctx context.Context, phoneNumber, message string) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while sending SMS",
+			ports.F("error", ctx.Err()),
+			ports.F("phone_number", phoneNumber),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	body, err := json.Marshal(webhookMessage{To: phoneNumber, Message: message})
+	if err != nil {
+		return errors.ErrSendSMS
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		s.logger.Error("Error creating SMS request",
+			ports.F("error", err),
+		)
+		return errors.ErrSendSMS
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.logger.Error("Error sending SMS",
+			ports.F("error", err),
+			ports.F("phone_number", phoneNumber),
+		)
+		return errors.ErrSendSMS
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		s.logger.Error("SMS gateway rejected message",
+			ports.F("status", resp.StatusCode),
+			ports.F("phone_number", phoneNumber),
+		)
+		return errors.ErrSendSMS
+	}
+	return nil
+}
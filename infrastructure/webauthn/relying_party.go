@@ -0,0 +1,255 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+// Flags of the authenticator data
+const (
+	flagUserPresent   = 0x01
+	flagUserVerified  = 0x04
+	flagAttestedData  = 0x40
+	authDataMinLength = 37
+)
+
+// relyingParty verifies registration and authentication ceremonies for
+// go_auth. Attestation is not requested, so the attestation statement of a
+// new credential is not checked: a passkey is trusted because the signed in
+// user registered it, not because of who made the authenticator.
+type relyingParty struct {
+	id       string
+	name     string
+	origins  []string
+	rpIDHash [32]byte
+	logger   ports.Logger
+}
+
+// clientData is the part of CollectedClientData the relying party checks
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// authenticatorData is the data an authenticator signs. The attested
+// credential data is only there when a credential was created.
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	credentialID []byte
+	publicKey    []byte
+}
+
+func NewRelyingParty(cfg config.WebAuthnConfig, logger ports.Logger) ports.WebAuthnRelyingParty {
+	return &relyingParty{
+		id:       cfg.RPID,
+		name:     cfg.RPName,
+		origins:  cfg.Origins,
+		rpIDHash: sha256.Sum256([]byte(cfg.RPID)),
+		logger:   logger,
+	}
+}
+
+func (rp *relyingParty) ID() string {
+	return rp.id
+}
+
+func (rp *relyingParty) Name() string {
+	return rp.name
+}
+
+func (rp *relyingParty) Algorithms() []int {
+	return slices.Clone(supportedAlgorithms)
+}
+
+func (rp *relyingParty) VerifyRegistration(clientDataJSON, attestationObject []byte) (*ports.WebAuthnAttestation, error) {
+	challenge, err := rp.checkClientData(clientDataJSON, "webauthn.create")
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, rest, err := decodeCBOR(attestationObject)
+	if err != nil || len(rest) != 0 {
+		rp.logger.Error("Invalid WebAuthn attestation object",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+	object, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+	rawAuthData, ok := object["authData"].([]byte)
+	if !ok {
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		rp.logger.Error("Invalid WebAuthn authenticator data",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+	if authData.flags&flagAttestedData == 0 {
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+	if err := rp.checkAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+
+	if _, err := parseCOSEKey(authData.publicKey); err != nil {
+		rp.logger.Error("Unsupported WebAuthn public key",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrUnsupportedWebAuthnKey
+	}
+
+	return &ports.WebAuthnAttestation{
+		Challenge:    challenge,
+		CredentialID: authData.credentialID,
+		PublicKey:    authData.publicKey,
+		SignCount:    authData.signCount,
+	}, nil
+}
+
+func (rp *relyingParty) VerifyAssertion(publicKey, clientDataJSON, rawAuthData, signature []byte) (*ports.WebAuthnAssertion, error) {
+	challenge, err := rp.checkClientData(clientDataJSON, "webauthn.get")
+	if err != nil {
+		return nil, err
+	}
+
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		rp.logger.Error("Invalid WebAuthn authenticator data",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+	if err := rp.checkAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+
+	key, err := parseCOSEKey(publicKey)
+	if err != nil {
+		rp.logger.Error("Invalid stored WebAuthn public key",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrUnsupportedWebAuthnKey
+	}
+
+	// The authenticator signs its data followed by the hash of the client data
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(bytes.Clone(rawAuthData), clientDataHash[:]...)
+	if err := key.verify(signed, signature); err != nil {
+		rp.logger.Error("Invalid WebAuthn signature",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+
+	return &ports.WebAuthnAssertion{
+		Challenge: challenge,
+		SignCount: authData.signCount,
+	}, nil
+}
+
+// checkClientData checks the client data of a ceremony came from an allowed
+// origin and returns the challenge in it
+func (rp *relyingParty) checkClientData(clientDataJSON []byte, ceremony string) ([]byte, error) {
+	var data clientData
+	if err := json.Unmarshal(clientDataJSON, &data); err != nil {
+		rp.logger.Error("Invalid WebAuthn client data",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+
+	if data.Type != ceremony {
+		rp.logger.Error("Unexpected WebAuthn ceremony",
+			ports.F("type", data.Type),
+			ports.F("expected", ceremony),
+		)
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+
+	if data.CrossOrigin || !slices.Contains(rp.origins, data.Origin) {
+		rp.logger.Error("WebAuthn origin not allowed",
+			ports.F("origin", data.Origin),
+		)
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+
+	challenge, err := base64.RawURLEncoding.DecodeString(data.Challenge)
+	if err != nil || len(challenge) == 0 {
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+
+	return challenge, nil
+}
+
+// checkAuthenticatorData checks the data was made for this relying party with
+// the user present and verified. Passkeys are the only factor, so user
+// verification is required.
+func (rp *relyingParty) checkAuthenticatorData(authData *authenticatorData) error {
+	if subtle.ConstantTimeCompare(authData.rpIDHash, rp.rpIDHash[:]) != 1 {
+		rp.logger.Error("WebAuthn RP ID hash mismatch")
+		return errors.ErrInvalidWebAuthnResponse
+	}
+	if authData.flags&flagUserPresent == 0 || authData.flags&flagUserVerified == 0 {
+		rp.logger.Error("WebAuthn user not present or not verified",
+			ports.F("flags", authData.flags),
+		)
+		return errors.ErrInvalidWebAuthnResponse
+	}
+	return nil
+}
+
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < authDataMinLength {
+		return nil, fmt.Errorf("authenticator data too short")
+	}
+
+	authData := &authenticatorData{
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	if authData.flags&flagAttestedData == 0 {
+		return authData, nil
+	}
+
+	// AAGUID, then the length of the credential ID, the ID and the COSE key
+	rest := data[authDataMinLength:]
+	if len(rest) < 18 {
+		return nil, fmt.Errorf("attested credential data too short")
+	}
+	idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if idLength == 0 || len(rest) < idLength {
+		return nil, fmt.Errorf("invalid credential ID length")
+	}
+	authData.credentialID = bytes.Clone(rest[:idLength])
+	rest = rest[idLength:]
+
+	_, afterKey, err := decodeCBOR(rest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential public key: %w", err)
+	}
+	authData.publicKey = bytes.Clone(rest[:len(rest)-len(afterKey)])
+
+	return authData, nil
+}
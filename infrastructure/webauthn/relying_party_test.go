@@ -0,0 +1,122 @@
+package webauthn
+
+import (
+	"io"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/infrastructure/logger"
+	"github.com/amirdashtii/go_auth/infrastructure/webauthn/webauthntest"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://app.example.com"
+)
+
+func newTestRelyingParty() ports.WebAuthnRelyingParty {
+	return NewRelyingParty(config.WebAuthnConfig{
+		RPID:    testRPID,
+		RPName:  "go_auth",
+		Origins: []string{testOrigin},
+	}, logger.NewZerologLogger(ports.LoggerConfig{
+		Level:       "fatal",
+		Environment: "test",
+		ServiceName: "go_auth",
+		Output:      io.Discard,
+	}))
+}
+
+// TestRelyingParty_Ceremonies tests registering a credential and signing in with it
+func TestRelyingParty_Ceremonies(t *testing.T) {
+	rp := newTestRelyingParty()
+	authenticator := webauthntest.New(testRPID, testOrigin)
+
+	clientDataJSON, attestationObject := authenticator.Register([]byte("registration-challenge"), []byte("user-handle"))
+	attestation, err := rp.VerifyRegistration(clientDataJSON, attestationObject)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("registration-challenge"), attestation.Challenge)
+	assert.Equal(t, authenticator.CredentialID, attestation.CredentialID)
+	assert.Equal(t, authenticator.PublicKey(), attestation.PublicKey)
+
+	clientDataJSON, authData, signature := authenticator.Assert([]byte("login-challenge"))
+	assertion, err := rp.VerifyAssertion(attestation.PublicKey, clientDataJSON, authData, signature)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("login-challenge"), assertion.Challenge)
+	assert.Equal(t, uint32(1), assertion.SignCount)
+}
+
+// TestRelyingParty_InvalidResponses tests that responses not meant for this relying party are rejected
+func TestRelyingParty_InvalidResponses(t *testing.T) {
+	rp := newTestRelyingParty()
+
+	tests := []struct {
+		name   string
+		modify func(a *webauthntest.Authenticator)
+	}{
+		{"other origin", func(a *webauthntest.Authenticator) { a.Origin = "https://evil.example" }},
+		{"other relying party", func(a *webauthntest.Authenticator) { a.RPID = "evil.example" }},
+		{"user not verified", func(a *webauthntest.Authenticator) { a.Flags = webauthntest.FlagUserPresent }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := webauthntest.New(testRPID, testOrigin)
+			tt.modify(authenticator)
+
+			_, err := rp.VerifyRegistration(authenticator.Register([]byte("challenge"), []byte("user")))
+			assert.Equal(t, errors.ErrInvalidWebAuthnResponse, err)
+
+			clientDataJSON, authData, signature := authenticator.Assert([]byte("challenge"))
+			_, err = rp.VerifyAssertion(authenticator.PublicKey(), clientDataJSON, authData, signature)
+			assert.Equal(t, errors.ErrInvalidWebAuthnResponse, err)
+		})
+	}
+}
+
+// TestRelyingParty_WrongCeremonyOrKey tests that assertions need the right key and can't be replayed as registrations
+func TestRelyingParty_WrongCeremonyOrKey(t *testing.T) {
+	rp := newTestRelyingParty()
+	authenticator := webauthntest.New(testRPID, testOrigin)
+	other := webauthntest.New(testRPID, testOrigin)
+
+	clientDataJSON, authData, signature := authenticator.Assert([]byte("challenge"))
+
+	_, err := rp.VerifyAssertion(other.PublicKey(), clientDataJSON, authData, signature)
+	assert.Equal(t, errors.ErrInvalidWebAuthnResponse, err)
+
+	signature[len(signature)-1] ^= 0xff
+	_, err = rp.VerifyAssertion(authenticator.PublicKey(), clientDataJSON, authData, signature)
+	assert.Equal(t, errors.ErrInvalidWebAuthnResponse, err)
+
+	_, err = rp.VerifyRegistration(clientDataJSON, authData)
+	assert.Equal(t, errors.ErrInvalidWebAuthnResponse, err)
+}
+
+// TestDecodeCBOR tests decoding the data items authenticators use
+func TestDecodeCBOR(t *testing.T) {
+	// {"a": [1, -2, h'ff'], 3: true}
+	value, rest, err := decodeCBOR([]byte{0xa2, 0x61, 0x61, 0x83, 0x01, 0x21, 0x41, 0xff, 0x03, 0xf5, 0x00})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x00}, rest)
+	assert.Equal(t, map[interface{}]interface{}{
+		"a":      []interface{}{int64(1), int64(-2), []byte{0xff}},
+		int64(3): true,
+	}, value)
+
+	// A byte string longer than the data
+	_, _, err = decodeCBOR([]byte{0x5a, 0xff, 0xff, 0xff, 0xff, 0x00})
+	assert.Error(t, err)
+
+	// An array claiming more items than could fit
+	_, _, err = decodeCBOR([]byte{0x9a, 0xff, 0xff, 0xff, 0xff})
+	assert.Error(t, err)
+
+	// Indefinite length byte string
+	_, _, err = decodeCBOR([]byte{0x5f, 0x41, 0x00, 0xff})
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+// Package webauthntest provides a software WebAuthn authenticator for tests.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+)
+
+// Flags of the authenticator data
+const (
+	FlagUserPresent  = 0x01
+	FlagUserVerified = 0x04
+	FlagAttestedData = 0x40
+)
+
+// Authenticator holds a single ES256 credential for a relying party. Origin
+// and Flags can be changed to make invalid responses.
+type Authenticator struct {
+	RPID         string
+	Origin       string
+	Flags        byte
+	CredentialID []byte
+	UserHandle   []byte
+	SignCount    uint32
+
+	key *ecdsa.PrivateKey
+}
+
+// New returns an authenticator for the relying party rpID used from origin
+func New(rpID, origin string) *Authenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		panic(err)
+	}
+
+	return &Authenticator{
+		RPID:         rpID,
+		Origin:       origin,
+		Flags:        FlagUserPresent | FlagUserVerified,
+		CredentialID: credentialID,
+		key:          key,
+	}
+}
+
+// Register creates the credential and returns the clientDataJSON and
+// attestationObject of a navigator.credentials.create() response
+func (a *Authenticator) Register(challenge, userHandle []byte) ([]byte, []byte) {
+	a.UserHandle = userHandle
+	clientDataJSON := a.clientData("webauthn.create", challenge)
+
+	// AAGUID of zeros, then the credential ID and its COSE key
+	attested := make([]byte, 18, 18+len(a.CredentialID))
+	binary.BigEndian.PutUint16(attested[16:], uint16(len(a.CredentialID)))
+	attested = append(attested, a.CredentialID...)
+	attested = append(attested, a.PublicKey()...)
+
+	authData := append(a.authenticatorData(a.Flags|FlagAttestedData), attested...)
+
+	var object []byte
+	object = append(object, 0xa3)
+	object = appendText(object, "fmt")
+	object = appendText(object, "none")
+	object = appendText(object, "attStmt")
+	object = append(object, 0xa0)
+	object = appendText(object, "authData")
+	object = appendBytes(object, authData)
+
+	return clientDataJSON, object
+}
+
+// Assert signs challenge with the credential and returns the
+// clientDataJSON, authenticatorData and signature of a
+// navigator.credentials.get() response
+func (a *Authenticator) Assert(challenge []byte) ([]byte, []byte, []byte) {
+	a.SignCount++
+	clientDataJSON := a.clientData("webauthn.get", challenge)
+	authData := a.authenticatorData(a.Flags)
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte(nil), authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		panic(err)
+	}
+
+	return clientDataJSON, authData, signature
+}
+
+// PublicKey returns the credential's public key as a COSE key
+func (a *Authenticator) PublicKey() []byte {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+
+	// {1: 2 (EC2), 3: -7 (ES256), -1: 1 (P-256), -2: x, -3: y}
+	key := []byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21}
+	key = appendBytes(key, x)
+	key = append(key, 0x22)
+	return appendBytes(key, y)
+}
+
+func (a *Authenticator) clientData(ceremony string, challenge []byte) []byte {
+	data, err := json.Marshal(map[string]interface{}{
+		"type":        ceremony,
+		"challenge":   base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func (a *Authenticator) authenticatorData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.RPID))
+	data := append([]byte(nil), rpIDHash[:]...)
+	data = append(data, flags)
+	return binary.BigEndian.AppendUint32(data, a.SignCount)
+}
+
+func appendText(data []byte, s string) []byte {
+	return append(appendHeader(data, 3, len(s)), s...)
+}
+
+func appendBytes(data []byte, b []byte) []byte {
+	return append(appendHeader(data, 2, len(b)), b...)
+}
+
+// appendHeader appends the initial bytes of a CBOR data item
+func appendHeader(data []byte, major byte, length int) []byte {
+	switch {
+	case length < 24:
+		return append(data, major<<5|byte(length))
+	case length < 1<<8:
+		return append(data, major<<5|24, byte(length))
+	case length < 1<<16:
+		return binary.BigEndian.AppendUint16(append(data, major<<5|25), uint16(length))
+	}
+	return binary.BigEndian.AppendUint32(append(data, major<<5|26), uint32(length))
+}
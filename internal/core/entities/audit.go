@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Types of audit events
+const (
+	AuditLoginSucceeded      = "auth.login.succeeded"
+	AuditLoginFailed         = "auth.login.failed"
+	AuditLogout              = "auth.logout"
+	AuditLogoutAll           = "auth.logout.all"
+	AuditTokenRefreshed      = "auth.token.refreshed"
+	AuditPasswordReset       = "auth.password.reset"
+	AuditPhoneNumberChanged  = "user.phone_number.changed"
+	AuditProfileUpdated      = "user.profile.updated"
+	AuditPasswordChanged     = "user.password.changed"
+	AuditSessionRevoked      = "user.session.revoked"
+	AuditUserUpdated         = "admin.user.updated"
+	AuditUserRoleChanged     = "admin.user.role_changed"
+	AuditUserStatusChanged   = "admin.user.status_changed"
+	AuditUserDeleted         = "admin.user.deleted"
+	AuditUserSessionsRevoked = "admin.user.sessions_revoked"
+	AuditUserMFAReset        = "admin.user.mfa_reset"
+	AuditUserUnlocked        = "admin.user.unlocked"
+	AuditUserImported        = "admin.user.imported"
+	AuditUsersExported       = "admin.users.exported"
+	AuditOrganizationCreated = "admin.organization.created"
+)
+
+// AuditChange is the value of a field before and after an audited change
+type AuditChange struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// AuditEvent records a security-relevant action. ActorID is who did it and
+// TargetID the user it was done to; both are the same user for actions on
+// their own account, and ActorID is unset when nobody could be identified,
+// like a failed login with an unknown phone number.
+type AuditEvent struct {
+	ID             uuid.UUID              `json:"id"`
+	OrganizationID uuid.UUID              `json:"organization_id"`
+	Type           string                 `json:"type"`
+	ActorID        *uuid.UUID             `json:"actor_id,omitempty"`
+	TargetID       *uuid.UUID             `json:"target_id,omitempty"`
+	IP             string                 `json:"ip"`
+	UserAgent      string                 `json:"user_agent"`
+	Changes        map[string]AuditChange `json:"changes,omitempty"`
+	Details        map[string]string      `json:"details,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+// AuditFilter selects audit events, newest first. Unset fields match every
+// event. After continues a listing from the last event of the previous page.
+type AuditFilter struct {
+	Type     string
+	ActorID  *uuid.UUID
+	TargetID *uuid.UUID
+	From     *time.Time
+	To       *time.Time
+	After    *AuditCursor
+	Limit    int
+}
+
+// AuditCursor is the position of an event in a listing
+type AuditCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
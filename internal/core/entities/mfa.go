@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserMFA is the TOTP authenticator a user confirmed as their second factor.
+// The secret is needed to check codes, so unlike the recovery codes it can't
+// be stored hashed.
+type UserMFA struct {
+	UserID     uuid.UUID `json:"user_id"`
+	TOTPSecret string    `json:"-"`
+	EnabledAt  time.Time `json:"enabled_at"`
+}
@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scopes that can be granted to OAuth clients
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+	ScopePhone   = "phone"
+)
+
+// SupportedScopes lists the OpenID Connect scopes users can grant to clients
+var SupportedScopes = []string{ScopeOpenID, ScopeProfile, ScopeEmail, ScopePhone}
+
+// Grant types a client can be registered for
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+// OAuthClient is an application that signs its users in through go_auth, or
+// a backend service that authenticates as itself with the client_credentials
+// grant. Public clients, such as mobile and single page apps, have no secret
+// and rely on PKCE alone. A client acting as itself can only reach the users
+// of OrganizationID.
+type OAuthClient struct {
+	ID             string    `json:"client_id"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Name           string    `json:"name"`
+	SecretHash     string    `json:"-"`
+	RedirectURIs   []string  `json:"redirect_uris"`
+	Scopes         []string  `json:"scopes"`
+	GrantTypes     []string  `json:"grant_types"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// IsConfidential reports whether the client authenticates with a secret
+func (c *OAuthClient) IsConfidential() bool {
+	return c.SecretHash != ""
+}
+
+// HasRedirectURI reports whether uri is registered for the client. URIs are
+// compared exactly, as required by OAuth 2.0 Security Best Current Practice.
+func (c *OAuthClient) HasRedirectURI(uri string) bool {
+	return slices.Contains(c.RedirectURIs, uri)
+}
+
+// AllowsScope reports whether the client may request scope
+func (c *OAuthClient) AllowsScope(scope string) bool {
+	return slices.Contains(c.Scopes, scope)
+}
+
+// AllowsGrantType reports whether the client may use grantType
+func (c *OAuthClient) AllowsGrantType(grantType string) bool {
+	return slices.Contains(c.GrantTypes, grantType)
+}
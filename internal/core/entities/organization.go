@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultOrganizationID is the organization of requests that don't name one.
+// Every user from before organizations existed belongs to it, and its admins
+// run the service itself, like rotating keys and creating organizations.
+var DefaultOrganizationID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Organization is a tenant of the service, such as one of the products using
+// it. Every organization has its own directory of users: a user belongs to
+// exactly one organization, is unknown to the others, and their Role is
+// their role in it.
+type Organization struct {
+	ID        uuid.UUID `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type organizationKey struct{}
+
+// WithOrganization returns a copy of ctx scoped to the organization id
+func WithOrganization(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, organizationKey{}, id)
+}
+
+// OrganizationIDFromContext returns the organization ctx is scoped to, or
+// the default organization if it isn't scoped to one
+func OrganizationIDFromContext(ctx context.Context) uuid.UUID {
+	if id, ok := ctx.Value(organizationKey{}).(uuid.UUID); ok {
+		return id
+	}
+	return DefaultOrganizationID
+}
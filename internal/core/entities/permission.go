@@ -0,0 +1,46 @@
+package entities
+
+// Permissions a role can be granted. Which roles have them is stored in the
+// role_permissions table.
+const (
+	PermissionUsersRead          = "users:read"
+	PermissionUsersWrite         = "users:write"
+	PermissionUsersRoleChange    = "users:role:change"
+	PermissionUsersStatusChange  = "users:status:change"
+	PermissionUsersDelete        = "users:delete"
+	PermissionUsersMFAReset      = "users:mfa:reset"
+	PermissionUsersUnlock        = "users:unlock"
+	PermissionUsersRevokeSession = "users:sessions:revoke"
+	PermissionUsersExport        = "users:export"
+	PermissionUsersImport        = "users:import"
+	PermissionAuditRead          = "audit:read"
+	PermissionKeysRotate         = "keys:rotate"
+	PermissionOAuthClientsWrite  = "oauth_clients:write"
+	PermissionOrganizationsRead  = "organizations:read"
+	PermissionOrganizationsWrite = "organizations:write"
+)
+
+// ClientScopes are the scopes machine clients can be granted, each allowing
+// the permission of the same name. Clients have no user their changes could
+// be audited against, so they can only read.
+var ClientScopes = []string{PermissionUsersRead, PermissionAuditRead, PermissionOrganizationsRead}
+
+// APIKeyScopes are the scopes API keys can be granted, each allowing the
+// permission of the same name as long as the role of the key's user has it
+var APIKeyScopes = []string{
+	PermissionUsersRead,
+	PermissionUsersWrite,
+	PermissionUsersRoleChange,
+	PermissionUsersStatusChange,
+	PermissionUsersDelete,
+	PermissionUsersMFAReset,
+	PermissionUsersUnlock,
+	PermissionUsersRevokeSession,
+	PermissionUsersExport,
+	PermissionUsersImport,
+	PermissionAuditRead,
+	PermissionKeysRotate,
+	PermissionOAuthClientsWrite,
+	PermissionOrganizationsRead,
+	PermissionOrganizationsWrite,
+}
@@ -1,6 +1,9 @@
 package entities
 
 type TokenPair struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// MFAToken is returned instead of the tokens above when the user still
+	// has to enter a second factor to finish logging in
+	MFAToken string `json:"mfa_token,omitempty"`
 }
@@ -28,6 +28,19 @@ func (r RoleType) String() string {
 	}
 }
 
+// Level orders roles by how much they can do, the role values themselves
+// aren't ordered
+func (r RoleType) Level() int {
+	switch r {
+	case SuperAdminRole:
+		return 2
+	case AdminRole:
+		return 1
+	default:
+		return 0
+	}
+}
+
 func ParseRoleType(s string) RoleType {
 	switch strings.ToLower(s) {
 	case "superadmin":
@@ -76,15 +89,66 @@ func ParseStatusType(s string) StatusType {
 }
 
 type User struct {
-	ID          uuid.UUID  `json:"id"`
-	PhoneNumber string     `json:"phone_number"`
-	Password    string     `json:"password"`
-	FirstName   string     `json:"first_name"`
-	LastName    string     `json:"last_name"`
-	Email       string     `json:"email"`
-	Status      StatusType `json:"status"`
-	Role        RoleType   `json:"role"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	DeletedAt   *time.Time `json:"deleted_at"`
+	ID              uuid.UUID  `json:"id"`
+	OrganizationID  uuid.UUID  `json:"organization_id"`
+	PhoneNumber     string     `json:"phone_number"`
+	Password        string     `json:"password"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	Email           string     `json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// PendingEmail is a new address the user asked for that isn't confirmed
+	// yet. It replaces Email once its verification link is opened.
+	PendingEmail string     `json:"pending_email"`
+	Status       StatusType `json:"status"`
+	Role         RoleType   `json:"role"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at"`
+}
+
+// Principal is the authenticated user making a request
+type Principal struct {
+	UserID uuid.UUID
+	Role   RoleType
+}
+
+// UserFilter selects users of an organization. Empty fields match every
+// user. Users are ordered by the Sort column and then by ID, After continues
+// a listing from the last user of the previous page.
+type UserFilter struct {
+	Search      string
+	Statuses    []StatusType
+	Roles       []RoleType
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Sort        string
+	Descending  bool
+	After       *UserCursor
+	Limit       int
+}
+
+// UserCursor is the position of a user in a listing, the value of the sort
+// column and the ID of the user
+type UserCursor struct {
+	Value string
+	ID    uuid.UUID
+}
+
+// Actions an admin can apply to many users at once
+const (
+	BulkActionChangeStatus   = "change_status"
+	BulkActionChangeRole     = "change_role"
+	BulkActionDelete         = "delete"
+	BulkActionRevokeSessions = "revoke_sessions"
+)
+
+// UserBulkUpdate is the change made to every user of a bulk action. Delete
+// soft deletes them. With DryRun the change is checked against the database
+// and rolled back.
+type UserBulkUpdate struct {
+	Status *StatusType
+	Role   *RoleType
+	Delete bool
+	DryRun bool
 }
@@ -15,10 +15,20 @@ var (
 	ErrGetUser      = New(InternalError, "Failed to get user", "خطا در دریافت اطلاعات کاربر", nil)
 
 	// Admin related errors
-	ErrChangeRole   = New(InternalError, "Failed to change user role", "خطا در تغییر نقش کاربر", nil)
-	ErrChangeStatus = New(InternalError, "Failed to change user status", "خطا در تغییر وضعیت کاربر", nil)
-	ErrDeleteUser   = New(InternalError, "Failed to delete user", "خطا در حذف کاربر", nil)
-	ErrForbidden    = New(AuthorizationError, "Access denied", "شما دسترسی لازم برای انجام این عملیات را ندارید", nil)
+	ErrChangeRole        = New(InternalError, "Failed to change user role", "خطا در تغییر نقش کاربر", nil)
+	ErrChangeStatus      = New(InternalError, "Failed to change user status", "خطا در تغییر وضعیت کاربر", nil)
+	ErrDeleteUser        = New(InternalError, "Failed to delete user", "خطا در حذف کاربر", nil)
+	ErrForbidden         = New(AuthorizationError, "Access denied", "شما دسترسی لازم برای انجام این عملیات را ندارید", nil)
+	ErrInvalidUserFilter = New(ValidationError, "User filter is invalid", "فیلتر کاربران نامعتبر است", nil)
+	ErrInvalidUserCursor = New(ValidationError, "Cursor is invalid or was issued for another sort order", "نشانگر صفحه نامعتبر است یا برای ترتیب دیگری صادر شده است", nil)
+	ErrInvalidBulkAction = New(ValidationError, "Bulk action must be change_status, change_role, delete or revoke_sessions", "عملیات گروهی باید یکی از change_status، change_role، delete یا revoke_sessions باشد", nil)
+	ErrInvalidBulkUsers  = New(ValidationError, "Between 1 and 100 distinct user IDs are required", "بین ۱ تا ۱۰۰ شناسه کاربر متمایز لازم است", nil)
+
+	// Role hierarchy related errors
+	ErrCannotModifySelf  = New(AuthorizationError, "Admins can't change their own account through admin operations", "مدیران نمی‌توانند حساب کاربری خود را از طریق عملیات مدیریتی تغییر دهند", nil)
+	ErrTargetRoleTooHigh = New(AuthorizationError, "Users with a role at or above your own can't be modified", "کاربرانی با نقش هم‌سطح یا بالاتر از شما قابل تغییر نیستند", nil)
+	ErrRoleAboveOwn      = New(AuthorizationError, "A role higher than your own can't be granted", "نقشی بالاتر از نقش خودتان قابل اعطا نیست", nil)
+	ErrLastSuperAdmin    = New(ValidationError, "The last active super admin can't be demoted, deactivated or deleted", "آخرین مدیر کل فعال قابل تنزل، غیرفعال‌سازی یا حذف نیست", nil)
 
 	// General errors
 	ErrInternalServer    = New(InternalError, "Internal server error", "خطای داخلی سرور", nil)
@@ -30,6 +40,8 @@ var (
 	ErrInvalidCredentials   = New(AuthenticationError, "Invalid credentials", "نام کاربری یا رمز عبور اشتباه است", nil)
 	ErrAccountDeactivated   = New(AuthenticationError, "Account is deactivated", "حساب کاربری غیرفعال است", nil)
 	ErrUserNotAuthenticated = New(AuthenticationError, "Authentication required", "لطفاً ابتدا وارد حساب کاربری خود شوید", nil)
+	ErrCredentialNotAllowed = New(AuthorizationError, "This credential can't be used for this request", "این اعتبارنامه برای این درخواست قابل استفاده نیست", nil)
+	ErrInsufficientScope    = New(AuthorizationError, "The scope of this credential doesn't allow this request", "دامنه دسترسی این اعتبارنامه اجازه این درخواست را نمی‌دهد", nil)
 
 	// Token related errors
 	ErrInvalidToken       = New(AuthenticationError, "Invalid token", "توکن نامعتبر است", nil)
@@ -43,14 +55,130 @@ var (
 	ErrParseToken         = New(AuthenticationError, "Failed to parse token", "خطا در تجزیه توکن", nil)
 	ErrInvalidTokenClaims = New(AuthenticationError, "Invalid token claims", "اطلاعات توکن نامعتبر است", nil)
 	ErrInvalidTokenType   = New(AuthenticationError, "Invalid token type", "نوع توکن نامعتبر است", nil)
+	ErrUnknownSigningKey  = New(AuthenticationError, "Token was signed with an unknown key", "توکن با کلید ناشناخته امضا شده است", nil)
+
+	// Session related errors
+	ErrSessionNotFound    = New(NotFoundError, "Session not found", "نشست یافت نشد", nil)
+	ErrInvalidSessionID   = New(ValidationError, "Invalid session ID", "شناسه نشست نامعتبر است", nil)
+	ErrRefreshTokenReused = New(AuthenticationError, "Refresh token has already been used, please log in again", "توکن تازه‌سازی قبلا استفاده شده است، لطفا دوباره وارد شوید", nil)
+	ErrRevokeSessions     = New(InternalError, "Failed to revoke sessions", "خطا در لغو نشست‌ها", nil)
+	ErrSessionRevoked     = New(AuthenticationError, "Session has been revoked, please log in again", "نشست لغو شده است، لطفا دوباره وارد شوید", nil)
 
 	// User operation errors
 	ErrLogin          = New(AuthenticationError, "Failed to login", "خطا در ورود", nil)
 	ErrLogout         = New(InternalError, "Failed to logout", "خطا در خروج", nil)
 	ErrChangePassword = New(InternalError, "Failed to change password", "خطا در تغییر رمز عبور", nil)
 
+	// OTP related errors
+	ErrInvalidOTP          = New(AuthenticationError, "Invalid or expired verification code", "کد تایید نامعتبر یا منقضی شده است", nil)
+	ErrOTPResendTooSoon    = New(RateLimitError, "Verification code was sent recently, please wait before requesting a new one", "کد تایید به تازگی ارسال شده است، لطفاً قبل از درخواست مجدد کمی صبر کنید", nil)
+	ErrOTPAttemptsExceeded = New(RateLimitError, "Too many invalid verification attempts", "تعداد تلاش\u200cهای ناموفق برای کد تایید بیش از حد مجاز است", nil)
+	ErrGenerateOTP         = New(InternalError, "Failed to generate verification code", "خطا در ایجاد کد تایید", nil)
+	ErrSendSMS             = New(InternalError, "Failed to send SMS", "خطا در ارسال پیامک", nil)
+	ErrIncrementCounter    = New(InternalError, "Failed to increment counter", "خطا در افزایش شمارنده", nil)
+
+	// OAuth related errors
+	ErrCreateOAuthClient       = New(InternalError, "Failed to create OAuth client", "خطا در ایجاد کلاینت OAuth", nil)
+	ErrGetOAuthClient          = New(InternalError, "Failed to get OAuth client", "خطا در دریافت کلاینت OAuth", nil)
+	ErrOAuthClientNotFound     = New(NotFoundError, "OAuth client not found", "کلاینت OAuth یافت نشد", nil)
+	ErrInvalidClient           = New(AuthenticationError, "Client authentication failed", "احراز هویت کلاینت ناموفق بود", nil)
+	ErrInvalidGrant            = New(AuthenticationError, "Authorization grant is invalid, expired or was already used", "مجوز دسترسی نامعتبر، منقضی یا قبلا استفاده شده است", nil)
+	ErrInvalidRedirectURI      = New(ValidationError, "Redirect URI is not registered for this client", "آدرس بازگشت برای این کلاینت ثبت نشده است", nil)
+	ErrUnsupportedResponseType = New(ValidationError, "Only the code response type is supported", "فقط نوع پاسخ code پشتیبانی می‌شود", nil)
+	ErrUnsupportedGrantType    = New(ValidationError, "Grant type is not supported", "نوع مجوز پشتیبانی نمی‌شود", nil)
+	ErrInvalidScope            = New(ValidationError, "Requested scope is invalid or not allowed for this client", "دامنه دسترسی درخواستی نامعتبر است یا برای این کلاینت مجاز نیست", nil)
+	ErrUnauthorizedClient      = New(AuthorizationError, "Client is not allowed to use this grant type", "کلاینت مجاز به استفاده از این نوع مجوز نیست", nil)
+	ErrInvalidCodeChallenge    = New(ValidationError, "A PKCE code challenge using the S256 method is required", "ارسال چالش PKCE با روش S256 الزامی است", nil)
+
+	// API key related errors
+	ErrCreateAPIKey       = New(InternalError, "Failed to create API key", "خطا در ایجاد کلید API", nil)
+	ErrGetAPIKeys         = New(InternalError, "Failed to get API keys", "خطا در دریافت کلیدهای API", nil)
+	ErrDeleteAPIKey       = New(InternalError, "Failed to revoke API key", "خطا در لغو کلید API", nil)
+	ErrAPIKeyNotFound     = New(NotFoundError, "API key not found", "کلید API یافت نشد", nil)
+	ErrInvalidAPIKey      = New(AuthenticationError, "Invalid or expired API key", "کلید API نامعتبر یا منقضی شده است", nil)
+	ErrInvalidAPIKeyScope = New(ValidationError, "API key scopes must be permissions, such as users:read", "دامنه‌های کلید API باید از دسترسی‌ها باشند، مانند users:read", nil)
+
+	// MFA related errors
+	ErrEnableMFA             = New(InternalError, "Failed to enable two-factor authentication", "خطا در فعال‌سازی احراز هویت دو مرحله‌ای", nil)
+	ErrGetMFA                = New(InternalError, "Failed to get two-factor authentication settings", "خطا در دریافت تنظیمات احراز هویت دو مرحله‌ای", nil)
+	ErrDeleteMFA             = New(InternalError, "Failed to reset two-factor authentication", "خطا در بازنشانی احراز هویت دو مرحله‌ای", nil)
+	ErrGenerateMFASecret     = New(InternalError, "Failed to generate two-factor authentication secret", "خطا در ایجاد کلید احراز هویت دو مرحله‌ای", nil)
+	ErrMFANotEnabled         = New(NotFoundError, "Two-factor authentication is not enabled", "احراز هویت دو مرحله‌ای فعال نیست", nil)
+	ErrMFAEnrollmentNotFound = New(NotFoundError, "No two-factor authentication setup in progress, start again", "فرآیند فعال‌سازی احراز هویت دو مرحله‌ای یافت نشد، دوباره شروع کنید", nil)
+	ErrRecoveryCodeNotFound  = New(NotFoundError, "Recovery code not found", "کد بازیابی یافت نشد", nil)
+	ErrMFAAlreadyEnabled     = New(ValidationError, "Two-factor authentication is already enabled", "احراز هویت دو مرحله‌ای قبلا فعال شده است", nil)
+	ErrInvalidMFACode        = New(AuthenticationError, "Invalid two-factor authentication code", "کد احراز هویت دو مرحله‌ای نامعتبر است", nil)
+	ErrMFAAttemptsExceeded   = New(RateLimitError, "Too many invalid two-factor authentication attempts", "تعداد تلاش‌های ناموفق برای احراز هویت دو مرحله‌ای بیش از حد مجاز است", nil)
+
+	// WebAuthn related errors
+	ErrCreateWebAuthnCredential   = New(InternalError, "Failed to save passkey", "خطا در ذخیره کلید عبور", nil)
+	ErrGetWebAuthnCredential      = New(InternalError, "Failed to get passkey", "خطا در دریافت کلید عبور", nil)
+	ErrUpdateWebAuthnCredential   = New(InternalError, "Failed to update passkey", "خطا در بروزرسانی کلید عبور", nil)
+	ErrWebAuthnCredentialNotFound = New(NotFoundError, "Passkey not found", "کلید عبور یافت نشد", nil)
+	ErrWebAuthnCredentialExists   = New(ValidationError, "Passkey is already registered", "کلید عبور قبلا ثبت شده است", nil)
+	ErrUnsupportedWebAuthnKey     = New(ValidationError, "Passkey algorithm is not supported", "الگوریتم کلید عبور پشتیبانی نمی‌شود", nil)
+	ErrInvalidWebAuthnResponse    = New(AuthenticationError, "Passkey response is invalid", "پاسخ کلید عبور نامعتبر است", nil)
+	ErrWebAuthnChallengeNotFound  = New(AuthenticationError, "Passkey challenge is invalid or expired", "چالش کلید عبور نامعتبر یا منقضی شده است", nil)
+	ErrWebAuthnSignCount          = New(AuthenticationError, "Passkey signature counter went backwards, the authenticator may have been cloned", "شمارنده امضای کلید عبور کاهش یافته است، ممکن است دستگاه احراز هویت کپی شده باشد", nil)
+
+	// Email verification related errors
+	ErrInvalidVerificationLink  = New(AuthenticationError, "Email verification link is invalid or expired", "لینک تایید ایمیل نامعتبر یا منقضی شده است", nil)
+	ErrNoEmailToVerify          = New(ValidationError, "There is no unverified email address to confirm", "ایمیل تایید نشده‌ای برای تایید وجود ندارد", nil)
+	ErrVerificationEmailTooSoon = New(RateLimitError, "A verification email was sent recently, please wait before requesting a new one", "ایمیل تایید به تازگی ارسال شده است، لطفاً قبل از درخواست مجدد کمی صبر کنید", nil)
+	ErrSendEmail                = New(InternalError, "Failed to send email", "خطا در ارسال ایمیل", nil)
+	ErrInvalidLoginIdentifier   = New(ValidationError, "Either a phone number or an email is required, but not both", "شماره موبایل یا ایمیل (فقط یکی از آن‌ها) الزامی است", nil)
+
+	// Phone number change related errors
+	ErrPhoneChangeNotFound          = New(NotFoundError, "No phone number change in progress, start again", "درخواست تغییر شماره موبایل یافت نشد، دوباره شروع کنید", nil)
+	ErrPhoneChangeNeedsVerification = New(ValidationError, "Phone number can only be changed with a verification code sent to the new number", "شماره موبایل فقط با کد تایید ارسال شده به شماره جدید قابل تغییر است", nil)
+
+	// Audit log related errors
+	ErrCreateAuditEvent   = New(InternalError, "Failed to record audit event", "خطا در ثبت رویداد ممیزی", nil)
+	ErrGetAuditEvents     = New(InternalError, "Failed to get audit events", "خطا در دریافت رویدادهای ممیزی", nil)
+	ErrInvalidAuditCursor = New(ValidationError, "Cursor is invalid", "نشانگر صفحه نامعتبر است", nil)
+	ErrInvalidAuditFilter = New(ValidationError, "Audit filter is invalid", "فیلتر رویدادهای ممیزی نامعتبر است", nil)
+
+	// Permission related errors
+	ErrGetPermissions = New(InternalError, "Failed to get permissions", "خطا در دریافت دسترسی‌ها", nil)
+
+	// User import and export related errors
+	ErrInvalidTransferFormat = New(ValidationError, "Format must be csv or ndjson", "قالب باید csv یا ndjson باشد", nil)
+	ErrInvalidImportFile     = New(ValidationError, "Import file is invalid, a CSV file needs a header row with a phone_number column", "فایل ورودی نامعتبر است، فایل CSV باید سطر عنوان با ستون phone_number داشته باشد", nil)
+	ErrImportTooLarge        = New(ValidationError, "An import can have at most 5000 rows and 10 MB", "هر بارگذاری حداکثر می‌تواند ۵۰۰۰ سطر و ۱۰ مگابایت داشته باشد", nil)
+	ErrInvalidImportRow      = New(ValidationError, "Row is not valid CSV or JSON", "سطر CSV یا JSON معتبر نیست", nil)
+	ErrImportPassword        = New(ValidationError, "Each row needs either a password or a password_hash", "هر سطر باید یا رمز عبور یا هش رمز عبور داشته باشد", nil)
+	ErrInvalidPasswordHash   = New(ValidationError, "Password hash must be a bcrypt hash or a PHC string of argon2id, scrypt, PBKDF2 or salted SHA", "هش رمز عبور باید از نوع bcrypt یا رشته PHC از argon2id، scrypt، PBKDF2 یا SHA با نمک باشد", nil)
+	ErrDuplicateImportRow    = New(ValidationError, "Phone number appears in an earlier row", "این شماره تلفن در سطر قبلی آمده است", nil)
+	ErrExportUsers           = New(InternalError, "Failed to export users", "خطا در خروجی گرفتن از کاربران", nil)
+
+	// Organization related errors
+	ErrOrganizationNotFound    = New(NotFoundError, "Organization not found", "سازمان یافت نشد", nil)
+	ErrOrganizationMismatch    = New(AuthorizationError, "Credentials belong to another organization", "اعتبارنامه متعلق به سازمان دیگری است", nil)
+	ErrDuplicateOrganization   = New(ValidationError, "An organization with this slug already exists", "سازمانی با این شناسه قبلاً ثبت شده است", nil)
+	ErrCreateOrganization      = New(InternalError, "Failed to create organization", "خطا در ایجاد سازمان", nil)
+	ErrGetOrganizations        = New(InternalError, "Failed to get organizations", "خطا در دریافت سازمان‌ها", nil)
+	ErrInvalidOrganizationSlug = New(ValidationError, "Organization slug must be 3 to 50 lowercase letters, digits or hyphens", "شناسه سازمان باید بین ۳ تا ۵۰ حرف کوچک، عدد یا خط تیره باشد", nil)
+	ErrInvalidOrganizationName = New(ValidationError, "Organization name is required and must be at most 100 characters", "نام سازمان الزامی است و حداکثر ۱۰۰ کاراکتر می‌تواند باشد", nil)
+
+	// Login lockout related errors
+	ErrAccountLocked        = New(RateLimitError, "Too many failed login attempts, the account is temporarily locked", "به دلیل تلاش‌های ناموفق زیاد، حساب کاربری به طور موقت قفل شده است", nil)
+	ErrTooManyLoginAttempts = New(RateLimitError, "Too many failed login attempts from this address, please try again later", "تلاش‌های ناموفق ورود از این آدرس بیش از حد مجاز است، لطفاً بعداً تلاش کنید", nil)
+
+	// Rate limit related errors
+	ErrRateLimitExceeded = New(RateLimitError, "Too many requests, please try again later", "تعداد درخواست‌ها بیش از حد مجاز است، لطفاً بعداً تلاش کنید", nil)
+	ErrRateLimiter       = New(InternalError, "Failed to check rate limit", "خطا در بررسی محدودیت تعداد درخواست", nil)
+
 	// Configuration related errors
-	ErrLoadConfig = New(InternalError, "Failed to load configuration", "خطا در بارگذاری تنظیمات", nil)
+	ErrLoadConfig             = New(InternalError, "Failed to load configuration", "خطا در بارگذاری تنظیمات", nil)
+	ErrLoadSigningKey         = New(ConfigError, "Failed to load token signing key", "خطا در بارگذاری کلید امضای توکن", nil)
+	ErrUnsupportedSigningAlg  = New(ConfigError, "Unsupported token signing algorithm", "الگوریتم امضای توکن پشتیبانی نمی‌شود", nil)
+	ErrKeyRotationDisabled    = New(ConfigError, "Signing key rotation requires a keyring directory", "چرخش کلید امضا نیازمند پوشه کلیدها است", nil)
+	ErrRotateSigningKey       = New(InternalError, "Failed to rotate token signing key", "خطا در چرخش کلید امضای توکن", nil)
+	ErrInvalidRateLimitRule   = New(ConfigError, "Rate limit rule is invalid", "قانون محدودیت تعداد درخواست نامعتبر است", nil)
+	ErrInvalidPasswordPolicy  = New(ConfigError, "Password hashing cost is invalid", "هزینه هش رمز عبور نامعتبر است", nil)
+	ErrMissingOTPSecret       = New(ConfigError, "OTP secret is not configured", "کلید رمز یک‌بارمصرف تنظیم نشده است", nil)
+	ErrUnsupportedSMSSender   = New(ConfigError, "SMS sender is missing or not allowed in this environment", "ارسال‌کننده پیامک تنظیم نشده یا در این محیط مجاز نیست", nil)
+	ErrUnsupportedEmailSender = New(ConfigError, "Email sender is missing or not allowed in this environment", "ارسال‌کننده ایمیل تنظیم نشده یا در این محیط مجاز نیست", nil)
 
 	// Validation errors
 	ErrInvalidSortField    = New(ValidationError, "Sort field is invalid", "فیلد مرتب\u200cسازی نامعتبر است", nil)
@@ -65,6 +193,14 @@ var (
 	ErrInvalidOldPassword  = New(ValidationError, "Old password is invalid", "رمز عبور قدیمی نامعتبر است", nil)
 	ErrInvalidNewPassword  = New(ValidationError, "New password must be at least 8 characters and include uppercase, lowercase, and a number", "رمز عبور جدید باید حداقل ۸ کاراکتر و شامل حروف بزرگ، کوچک و عدد باشد", nil)
 	ErrInvalidRefreshToken = New(ValidationError, "Refresh token is invalid", "توکن بروزرسانی نامعتبر است", nil)
+	ErrInvalidOTPCode      = New(ValidationError, "Verification code must be 6 digits", "کد تایید باید ۶ رقم باشد", nil)
+	ErrInvalidClientName   = New(ValidationError, "Client name must be between 2 and 100 characters", "نام کلاینت باید بین ۲ تا ۱۰۰ کاراکتر باشد", nil)
+	ErrInvalidAPIKeyName   = New(ValidationError, "API key name must be between 1 and 100 characters", "نام کلید API باید بین ۱ تا ۱۰۰ کاراکتر باشد", nil)
+	ErrInvalidAPIKeyExpiry = New(ValidationError, "API key expiry must be in the future", "زمان انقضای کلید API باید در آینده باشد", nil)
+	ErrInvalidAPIKeyID     = New(ValidationError, "Invalid API key ID", "شناسه کلید API نامعتبر است", nil)
+	ErrInvalidMFACodeValue = New(ValidationError, "Code must be a 6 digit authenticator code or a recovery code", "کد باید یک کد ۶ رقمی برنامه احراز هویت یا کد بازیابی باشد", nil)
+	ErrInvalidMFAToken     = New(ValidationError, "MFA token is invalid", "توکن احراز هویت دو مرحله‌ای نامعتبر است", nil)
+	ErrInvalidGrantTypes   = New(ValidationError, "Grant types are invalid, client_credentials requires a confidential client", "نوع مجوزها نامعتبر است، client_credentials نیازمند کلاینت محرمانه است", nil)
 
 	ErrContextCancelled = New(InternalError, "Operation cancelled due to context cancellation", "عملیات به دلیل لغو درخواست متوقف شد", nil)
 )
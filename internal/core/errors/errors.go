@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type ErrorType string
 
@@ -13,6 +16,7 @@ const (
 	DatabaseError       ErrorType = "DATABASE_ERROR"
 	ConfigError         ErrorType = "CONFIG_ERROR"
 	TokenError          ErrorType = "TOKEN_ERROR"
+	RateLimitError      ErrorType = "RATE_LIMIT_ERROR"
 )
 
 type ErrorMessage struct {
@@ -24,6 +28,9 @@ type CustomError struct {
 	Type    ErrorType
 	Message ErrorMessage
 	Err     error
+	// RetryAfter is how long a client should wait before retrying a rate
+	// limited request, when known
+	RetryAfter time.Duration `json:"-"`
 }
 
 func (e *CustomError) Error() string {
@@ -44,6 +51,14 @@ func (e *CustomError) Unwrap() error {
 	return e.Err
 }
 
+// WithRetryAfter returns a copy of e telling the client to wait d before
+// retrying
+func (e *CustomError) WithRetryAfter(d time.Duration) *CustomError {
+	withRetry := *e
+	withRetry.RetryAfter = d
+	return &withRetry
+}
+
 func New(errorType ErrorType, messageEn, messageFa string, err error) *CustomError {
 	return &CustomError{
 		Type: errorType,
@@ -110,3 +125,19 @@ func IsTokenError(err error) bool {
 	}
 	return false
 }
+
+func IsRateLimitError(err error) bool {
+	if customErr, ok := err.(*CustomError); ok {
+		return customErr.Type == RateLimitError
+	}
+	return false
+}
+
+// RetryAfter returns how long the client should wait before retrying after
+// err, if the error says
+func RetryAfter(err error) (time.Duration, bool) {
+	if customErr, ok := err.(*CustomError); ok && customErr.RetryAfter > 0 {
+		return customErr.RetryAfter, true
+	}
+	return 0, false
+}
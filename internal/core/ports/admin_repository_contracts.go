@@ -8,10 +8,13 @@ import (
 )
 
 type AdminRepository interface {
-	FindUsers(ctx context.Context, status *entities.StatusType, role *entities.RoleType, sort, order *string) ([]entities.User, error)
+	FindUsers(ctx context.Context, filter *entities.UserFilter) ([]entities.User, error)
+	CountUsers(ctx context.Context, filter *entities.UserFilter) (int, error)
 	AdminGetUserByID(ctx context.Context, id *uuid.UUID) (*entities.User, error)
+	AdminCreateUser(ctx context.Context, user *entities.User) error
 	AdminUpdateUser(ctx context.Context, user *entities.User) error
 	AdminChangeUserRole(ctx context.Context, id *uuid.UUID, role *entities.RoleType) error
 	AdminChangeUserStatus(ctx context.Context, id *uuid.UUID, status *entities.StatusType) error
 	AdminDeleteUser(ctx context.Context, id *uuid.UUID) error
+	AdminBulkUpdateUsers(ctx context.Context, ids []uuid.UUID, update *entities.UserBulkUpdate) error
 }
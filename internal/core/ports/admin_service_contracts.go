@@ -9,10 +9,20 @@ import (
 )
 
 type AdminService interface {
-	GetUsers(ctx context.Context, status *entities.StatusType, role *entities.RoleType, sort, order *string) ([]dto.AdminUserResponse, error)
+	GetUsers(ctx context.Context, req *dto.AdminGetUsersRequest) (*dto.AdminUserListResponse, error)
 	AdminGetUserByID(ctx context.Context, userID *uuid.UUID) (*dto.AdminUserResponse, error)
-	AdminUpdateUser(ctx context.Context, userID *uuid.UUID, updateReq *dto.AdminUserUpdateRequest) error
-	ChangeUserRole(ctx context.Context, userID *uuid.UUID, updateRole *entities.RoleType) error
-	ChangeUserStatus(ctx context.Context, userID *uuid.UUID, updateStatus *entities.StatusType) error
-	AdminDeleteUser(ctx context.Context, userID *uuid.UUID) error
+	AdminUpdateUser(ctx context.Context, actor *entities.Principal, userID *uuid.UUID, updateReq *dto.AdminUserUpdateRequest) error
+	ChangeUserRole(ctx context.Context, actor *entities.Principal, userID *uuid.UUID, updateRole *entities.RoleType) error
+	ChangeUserStatus(ctx context.Context, actor *entities.Principal, userID *uuid.UUID, updateStatus *entities.StatusType) error
+	AdminDeleteUser(ctx context.Context, actor *entities.Principal, userID *uuid.UUID) error
+	ResetUserMFA(ctx context.Context, actor *entities.Principal, userID *uuid.UUID) error
+	UnlockUser(ctx context.Context, actor *entities.Principal, userID *uuid.UUID) error
+	BulkUpdateUsers(ctx context.Context, actor *entities.Principal, req *dto.AdminBulkUsersRequest) (*dto.AdminBulkUsersResponse, error)
+	ExportUsers(ctx context.Context, actor *entities.Principal, req *dto.AdminExportUsersRequest, write func(user *dto.AdminUserResponse) error) error
+	ImportUsers(ctx context.Context, actor *entities.Principal, rows []dto.AdminImportUserRow) (*dto.AdminImportUsersResponse, error)
+	RotateSigningKey(ctx context.Context) (string, error)
+	CreateOAuthClient(ctx context.Context, req *dto.AdminCreateOAuthClientRequest) (*dto.AdminOAuthClientResponse, error)
+	ListAuditEvents(ctx context.Context, req *dto.AdminAuditEventsRequest) (*dto.AdminAuditEventListResponse, error)
+	CreateOrganization(ctx context.Context, req *dto.AdminCreateOrganizationRequest) (*dto.AdminOrganizationResponse, error)
+	ListOrganizations(ctx context.Context) ([]dto.AdminOrganizationResponse, error)
 }
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *entities.APIKey) error
+	FindAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]entities.APIKey, error)
+	FindAPIKeyByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error)
+	DeleteAPIKey(ctx context.Context, userID, keyID uuid.UUID) error
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+)
+
+type AuditRepository interface {
+	CreateEvent(ctx context.Context, event *entities.AuditEvent) error
+	FindEvents(ctx context.Context, filter *entities.AuditFilter) ([]entities.AuditEvent, error)
+}
@@ -11,4 +11,8 @@ type AuthRepository interface {
 	Create(ctx context.Context, user *entities.User) error
 	FindUserByPhoneNumber(ctx context.Context, phoneNumber *string) (*entities.User, error)
 	FindUserByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error
+	UpdatePhoneNumber(ctx context.Context, id uuid.UUID, phoneNumber string) error
+	FindUserByVerifiedEmail(ctx context.Context, email string) (*entities.User, error)
+	ConfirmEmail(ctx context.Context, id uuid.UUID, email string) error
 }
@@ -10,7 +10,35 @@ import (
 type AuthService interface {
 	Register(ctx context.Context, registerReq *dto.RegisterRequest) error
 	Login(ctx context.Context, loginReq *dto.LoginRequest) (*entities.TokenPair, error)
-	Logout(ctx context.Context, userID string) error
+	Logout(ctx context.Context, userID, sessionID string) error
 	RefreshToken(ctx context.Context, refreshToken string) (*entities.TokenPair, error)
-	ValidateToken(ctx context.Context, userID, token string) error
+	ValidateToken(ctx context.Context, userID, sessionID, token string) error
+	ValidateClientToken(ctx context.Context, clientID, tokenID string) error
+	RequestOTP(ctx context.Context, otpReq *dto.OTPRequest) error
+	VerifyOTP(ctx context.Context, verifyReq *dto.OTPVerifyRequest) (*entities.TokenPair, error)
+	ListSessions(ctx context.Context, userID, currentSessionID string) ([]dto.SessionResponse, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	LogoutAll(ctx context.Context, userID string) error
+	JWKS(ctx context.Context) (*entities.JSONWebKeySet, error)
+	OpenIDConfiguration(ctx context.Context) (*dto.OpenIDConfiguration, error)
+	Authorize(ctx context.Context, userID string, req *dto.AuthorizeRequest) (string, error)
+	ExchangeToken(ctx context.Context, req *dto.TokenRequest) (*dto.TokenResponse, error)
+	UserInfo(ctx context.Context, userID, sessionID string) (map[string]interface{}, error)
+	CreateAPIKey(ctx context.Context, userID string, req *dto.CreateAPIKeyRequest) (*dto.APIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, userID string) ([]dto.APIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID string) error
+	AuthenticateAPIKey(ctx context.Context, key string) (*entities.User, *entities.APIKey, error)
+	EnrollTOTP(ctx context.Context, userID string) (*dto.TOTPEnrollmentResponse, error)
+	ConfirmTOTP(ctx context.Context, userID, code string) (*dto.RecoveryCodesResponse, error)
+	VerifyMFA(ctx context.Context, req *dto.MFAVerifyRequest) (*entities.TokenPair, error)
+	BeginWebAuthnRegistration(ctx context.Context, userID string) (*dto.WebAuthnRegistrationOptions, error)
+	FinishWebAuthnRegistration(ctx context.Context, userID string, req *dto.WebAuthnRegistrationRequest) (*dto.WebAuthnCredentialResponse, error)
+	BeginWebAuthnLogin(ctx context.Context) (*dto.WebAuthnAssertionOptions, error)
+	FinishWebAuthnLogin(ctx context.Context, req *dto.WebAuthnLoginRequest) (*entities.TokenPair, error)
+	ForgotPassword(ctx context.Context, req *dto.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req *dto.ResetPasswordRequest) error
+	RequestEmailVerification(ctx context.Context, userID string) error
+	VerifyEmail(ctx context.Context, token string) error
+	RequestPhoneNumberChange(ctx context.Context, userID string, req *dto.ChangePhoneNumberRequest) error
+	ConfirmPhoneNumberChange(ctx context.Context, userID string, req *dto.ConfirmPhoneNumberRequest) error
 }
@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+// EmailSender delivers email messages to an address
+type EmailSender interface {
+	// Send delivers a message with subject and body to the given address
+	Send(ctx context.Context, to, subject, body string) error
+}
@@ -9,4 +9,14 @@ type InMemoryRespositoryContracts interface {
 	AddToken(ctx context.Context, userID, token string, expiration time.Duration) error
 	RemoveToken(ctx context.Context, userID string) error
 	FindToken(ctx context.Context, userID string) (string, error)
+	// TakeToken returns the value at key and deletes it in one step, so
+	// only one of concurrent callers gets it
+	TakeToken(ctx context.Context, key string) (string, error)
+	IncrementCounter(ctx context.Context, key string, expiration time.Duration) (int64, error)
+	AddToSet(ctx context.Context, key, member string, expiration time.Duration) error
+	// AddToSetIfAbsent adds member to the set at key in one step and reports
+	// whether it was added, so only one of concurrent callers wins
+	AddToSetIfAbsent(ctx context.Context, key, member string, expiration time.Duration) (bool, error)
+	RemoveFromSet(ctx context.Context, key, member string) error
+	FindSetMembers(ctx context.Context, key string) ([]string, error)
 }
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+type MFARepository interface {
+	EnableMFA(ctx context.Context, mfa *entities.UserMFA, recoveryCodeHashes []string) error
+	FindMFAByUserID(ctx context.Context, userID uuid.UUID) (*entities.UserMFA, error)
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error
+	DeleteMFA(ctx context.Context, userID uuid.UUID) error
+}
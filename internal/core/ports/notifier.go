@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+)
+
+// Notifier delivers account messages, such as password reset codes, to a
+// user over whichever channel the implementation supports
+type Notifier interface {
+	Notify(ctx context.Context, user *entities.User, message string) error
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+)
+
+type OAuthClientRepository interface {
+	CreateClient(ctx context.Context, client *entities.OAuthClient) error
+	FindClientByID(ctx context.Context, clientID string) (*entities.OAuthClient, error)
+}
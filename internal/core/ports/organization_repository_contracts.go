@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+)
+
+type OrganizationRepository interface {
+	// CreateOrganization creates organization together with owner, its first
+	// user, so a new organization always has someone to manage it
+	CreateOrganization(ctx context.Context, organization *entities.Organization, owner *entities.User) error
+	FindOrganizationBySlug(ctx context.Context, slug string) (*entities.Organization, error)
+	FindOrganizations(ctx context.Context) ([]entities.Organization, error)
+}
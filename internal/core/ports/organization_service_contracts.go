@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+)
+
+type OrganizationService interface {
+	FindOrganizationBySlug(ctx context.Context, slug string) (*entities.Organization, error)
+}
@@ -0,0 +1,18 @@
+package ports
+
+// PasswordHasher hashes passwords and checks them against stored hashes.
+// Besides the hashes it makes, it verifies hashes of the systems users are
+// migrated from, stored as PHC strings ($id$params$salt$hash).
+type PasswordHasher interface {
+	// Hash returns the hash of password with the preferred algorithm and cost
+	Hash(password string) (string, error)
+	// Verify reports whether password matches hash. It returns an error
+	// when hash is malformed or of an unsupported algorithm.
+	Verify(password, hash string) (bool, error)
+	// NeedsRehash reports whether hash is of another algorithm than the
+	// preferred one or of a lower cost, so it should be replaced after a
+	// successful login
+	NeedsRehash(hash string) bool
+	// Supports reports whether Verify understands hash
+	Supports(hash string) bool
+}
@@ -0,0 +1,7 @@
+package ports
+
+import "context"
+
+type PermissionRepository interface {
+	FindPermissionsByRole(ctx context.Context, role string) ([]string, error)
+}
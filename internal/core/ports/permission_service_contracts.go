@@ -0,0 +1,7 @@
+package ports
+
+import "context"
+
+type PermissionService interface {
+	HasPermission(ctx context.Context, role, permission string) (bool, error)
+}
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimiter counts requests per key over a sliding window. Limiters backed
+// by a shared store enforce one limit across every instance of the service.
+type RateLimiter interface {
+	// Allow counts a request for key if it is within limit requests per
+	// window. Denied requests are not counted.
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error)
+}
+
+// RateLimitResult describes the usage of a key after a request
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the current window ends
+	Reset time.Duration
+	// RetryAfter is how long a denied client has to wait before a request
+	// would be allowed
+	RetryAfter time.Duration
+}
@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+// SMSSender delivers text messages to a phone number
+type SMSSender interface {
+	// Send delivers message to the given phone number
+	Send(ctx context.Context, phoneNumber, message string) error
+}
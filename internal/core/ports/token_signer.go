@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenSigner signs and verifies the JWTs issued by the service
+type TokenSigner interface {
+	// Sign returns a signed token for claims with the signing key's kid in its header
+	Sign(claims jwt.MapClaims) (string, error)
+	// Verify checks the signature and expiry of token and returns its claims
+	Verify(token string) (jwt.MapClaims, error)
+	// PublicKeys returns the keys other services can use to verify tokens.
+	// It is empty when tokens are signed with a shared secret.
+	PublicKeys() []entities.JSONWebKey
+	// Rotate makes a newly generated key the signing key and returns its kid.
+	// The previous key keeps verifying tokens until they have expired.
+	Rotate() (string, error)
+}
@@ -0,0 +1,29 @@
+package ports
+
+// WebAuthnRelyingParty checks the responses of WebAuthn authenticators. It
+// returns the challenge the client signed, callers must check it is one they
+// issued and haven't accepted before.
+type WebAuthnRelyingParty interface {
+	ID() string
+	Name() string
+	// Algorithms are the COSE algorithms of the credentials it accepts
+	Algorithms() []int
+	// VerifyRegistration checks a new credential and returns its ID, its
+	// COSE encoded public key and its signature counter
+	VerifyRegistration(clientDataJSON, attestationObject []byte) (*WebAuthnAttestation, error)
+	// VerifyAssertion checks a signature made with a registered credential
+	// and returns the authenticator's new signature counter
+	VerifyAssertion(publicKey, clientDataJSON, authenticatorData, signature []byte) (*WebAuthnAssertion, error)
+}
+
+type WebAuthnAttestation struct {
+	Challenge    []byte
+	CredentialID []byte
+	PublicKey    []byte
+	SignCount    uint32
+}
+
+type WebAuthnAssertion struct {
+	Challenge []byte
+	SignCount uint32
+}
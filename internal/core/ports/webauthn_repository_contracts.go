@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+type WebAuthnRepository interface {
+	CreateCredential(ctx context.Context, credential *entities.WebAuthnCredential) error
+	FindCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]entities.WebAuthnCredential, error)
+	FindCredentialByCredentialID(ctx context.Context, credentialID []byte) (*entities.WebAuthnCredential, error)
+	UpdateCredentialSignCount(ctx context.Context, id uuid.UUID, signCount uint32) error
+}
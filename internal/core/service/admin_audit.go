@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const defaultAuditPageSize = 50
+
+// ListAuditEvents returns a page of the audit log matching req, newest
+// first. The next page is requested with the returned NextCursor.
+func (s *AdminService) ListAuditEvents(ctx context.Context, req *dto.AdminAuditEventsRequest) (*dto.AdminAuditEventListResponse, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while listing audit events",
+			ports.F("error", ctx.Err()),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	filter, err := auditFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// One more event than asked for tells whether there is another page
+	filter.Limit++
+	events, err := s.audit.FindEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	filter.Limit--
+
+	response := &dto.AdminAuditEventListResponse{
+		Events: make([]dto.AdminAuditEventResponse, 0, len(events)),
+	}
+	if len(events) > filter.Limit {
+		events = events[:filter.Limit]
+		last := events[len(events)-1]
+		response.NextCursor = encodeAuditCursor(entities.AuditCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	for _, event := range events {
+		response.Events = append(response.Events, auditEventResponse(&event))
+	}
+	return response, nil
+}
+
+// auditFilter turns a validated request into the filter for FindEvents
+func auditFilter(req *dto.AdminAuditEventsRequest) (*entities.AuditFilter, error) {
+	filter := &entities.AuditFilter{
+		Type:  req.Type,
+		Limit: req.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditPageSize
+	}
+
+	if req.ActorID != "" {
+		actorID, err := uuid.Parse(req.ActorID)
+		if err != nil {
+			return nil, errors.ErrInvalidAuditFilter
+		}
+		filter.ActorID = &actorID
+	}
+	if req.TargetID != "" {
+		targetID, err := uuid.Parse(req.TargetID)
+		if err != nil {
+			return nil, errors.ErrInvalidAuditFilter
+		}
+		filter.TargetID = &targetID
+	}
+	if req.From != "" {
+		from, err := time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return nil, errors.ErrInvalidAuditFilter
+		}
+		filter.From = &from
+	}
+	if req.To != "" {
+		to, err := time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return nil, errors.ErrInvalidAuditFilter
+		}
+		filter.To = &to
+	}
+	if req.Cursor != "" {
+		cursor, err := decodeAuditCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = cursor
+	}
+
+	return filter, nil
+}
+
+// encodeAuditCursor returns an opaque token for the position of an event
+func encodeAuditCursor(cursor entities.AuditCursor) string {
+	raw := strconv.FormatInt(cursor.CreatedAt.UnixNano(), 10) + ":" + cursor.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeAuditCursor(token string) (*entities.AuditCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.ErrInvalidAuditCursor
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, errors.ErrInvalidAuditCursor
+	}
+	createdAt, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, errors.ErrInvalidAuditCursor
+	}
+	eventID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.ErrInvalidAuditCursor
+	}
+
+	return &entities.AuditCursor{CreatedAt: time.Unix(0, createdAt).UTC(), ID: eventID}, nil
+}
+
+func auditEventResponse(event *entities.AuditEvent) dto.AdminAuditEventResponse {
+	response := dto.AdminAuditEventResponse{
+		ID:        event.ID.String(),
+		Type:      event.Type,
+		IP:        event.IP,
+		UserAgent: event.UserAgent,
+		Details:   event.Details,
+		CreatedAt: event.CreatedAt,
+	}
+	if event.ActorID != nil {
+		response.ActorID = event.ActorID.String()
+	}
+	if event.TargetID != nil {
+		response.TargetID = event.TargetID.String()
+	}
+	if len(event.Changes) > 0 {
+		response.Changes = make(map[string]dto.AdminAuditChange, len(event.Changes))
+		for field, change := range event.Changes {
+			response.Changes[field] = dto.AdminAuditChange{Before: change.Before, After: change.After}
+		}
+	}
+	return response
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// BulkUpdateUsers applies the action of req to every user in it and reports
+// the outcome user by user. Users the actor can't manage fail on their own.
+// Status, role and delete actions change the others in one transaction, so
+// they all fail if it does. Sessions are revoked user by user, also when a
+// role change, deactivation or deletion ends them.
+func (s *AdminService) BulkUpdateUsers(ctx context.Context, actor *entities.Principal, req *dto.AdminBulkUsersRequest) (*dto.AdminBulkUsersResponse, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while updating users",
+			ports.F("error", ctx.Err()),
+			ports.F("action", req.Action),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	// The request is validated for distinct IDs, but as strings, so the same
+	// user in another case or notation is caught here
+	seen := make(map[uuid.UUID]bool, len(req.UserIDs))
+	for _, id := range req.UserIDs {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		if seen[userID] {
+			return nil, errors.ErrInvalidBulkUsers
+		}
+		seen[userID] = true
+	}
+
+	update := &entities.UserBulkUpdate{DryRun: req.DryRun}
+	switch req.Action {
+	case entities.BulkActionChangeStatus:
+		status := entities.ParseStatusType(req.Status)
+		update.Status = &status
+	case entities.BulkActionChangeRole:
+		role := entities.ParseRoleType(req.Role)
+		update.Role = &role
+	case entities.BulkActionDelete:
+		update.Delete = true
+	case entities.BulkActionRevokeSessions:
+	default:
+		return nil, errors.ErrInvalidBulkAction
+	}
+
+	response := &dto.AdminBulkUsersResponse{
+		Action:  req.Action,
+		DryRun:  req.DryRun,
+		Results: make([]dto.AdminBulkUserResult, len(req.UserIDs)),
+	}
+
+	// The users that pass the checks, with the index of their result
+	var (
+		users   []*entities.User
+		results []int
+	)
+	for i, id := range req.UserIDs {
+		response.Results[i].UserID = id
+		user, err := s.bulkTarget(ctx, actor, id, update)
+		if err != nil {
+			response.Results[i].Error = err
+			continue
+		}
+		users = append(users, user)
+		results = append(results, i)
+	}
+
+	if req.Action == entities.BulkActionRevokeSessions {
+		for j, user := range users {
+			if !req.DryRun {
+				if err := s.revokeUserSessions(ctx, actor, user); err != nil {
+					response.Results[results[j]].Error = err
+					continue
+				}
+			}
+			response.Results[results[j]].Succeeded = true
+		}
+	} else if len(users) > 0 {
+		ids := make([]uuid.UUID, 0, len(users))
+		for _, user := range users {
+			ids = append(ids, user.ID)
+		}
+
+		err := s.db.AdminBulkUpdateUsers(ctx, ids, update)
+		for j, user := range users {
+			if err != nil {
+				response.Results[results[j]].Error = err
+				continue
+			}
+			if !req.DryRun {
+				recordAudit(ctx, s.audit, s.logger, bulkAuditEvent(actor, user, update))
+				if endsSessions(user, update) {
+					if err := s.endSessions(ctx, &user.ID); err != nil {
+						response.Results[results[j]].Error = err
+						continue
+					}
+				}
+			}
+			response.Results[results[j]].Succeeded = true
+		}
+	}
+
+	for _, result := range response.Results {
+		if result.Succeeded {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+
+	s.logger.Info("Bulk user action applied",
+		ports.F("actor_id", actor.UserID),
+		ports.F("action", req.Action),
+		ports.F("dry_run", req.DryRun),
+		ports.F("succeeded", response.Succeeded),
+		ports.F("failed", response.Failed),
+	)
+
+	return response, nil
+}
+
+// bulkTarget returns the user with id if actor may apply update to them,
+// following the same rules as the actions on a single user
+func (s *AdminService) bulkTarget(ctx context.Context, actor *entities.Principal, id string, update *entities.UserBulkUpdate) (*entities.User, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.ErrInvalidUserID
+	}
+	user, err := s.db.AdminGetUserByID(ctx, &userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCanModify(actor, user); err != nil {
+		return nil, err
+	}
+	if update.Role != nil && update.Role.Level() > actor.Role.Level() {
+		return nil, errors.ErrRoleAboveOwn
+	}
+	return user, nil
+}
+
+// revokeUserSessions ends every session of user, for a bulk action of actor
+func (s *AdminService) revokeUserSessions(ctx context.Context, actor *entities.Principal, user *entities.User) error {
+	revoked, err := revokeSessions(ctx, s.redis, user.ID.String())
+	if err != nil {
+		s.logger.Error("Error revoking sessions",
+			ports.F("error", err),
+			ports.F("user_id", user.ID),
+		)
+		return errors.ErrRevokeSessions
+	}
+
+	recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+		Type:     entities.AuditUserSessionsRevoked,
+		ActorID:  &actor.UserID,
+		TargetID: &user.ID,
+		Details:  map[string]string{"sessions": strconv.Itoa(revoked)},
+	})
+	return nil
+}
+
+// endsSessions reports whether update changes user in a way that revokes
+// their sessions, the same as for the change of a single user
+func endsSessions(user *entities.User, update *entities.UserBulkUpdate) bool {
+	switch {
+	case update.Delete:
+		return true
+	case update.Status != nil:
+		return *update.Status != entities.Active
+	case update.Role != nil:
+		return *update.Role != user.Role
+	}
+	return false
+}
+
+// bulkAuditEvent returns the event recorded for the change of update to
+// user, the same as for the change of a single user
+func bulkAuditEvent(actor *entities.Principal, user *entities.User, update *entities.UserBulkUpdate) *entities.AuditEvent {
+	event := &entities.AuditEvent{
+		ActorID:  &actor.UserID,
+		TargetID: &user.ID,
+		Details:  map[string]string{"bulk": "true"},
+	}
+	switch {
+	case update.Delete:
+		event.Type = entities.AuditUserDeleted
+		event.Changes = auditChanges([3]string{"status", user.Status.String(), entities.Deleted.String()})
+	case update.Status != nil:
+		event.Type = entities.AuditUserStatusChanged
+		event.Changes = auditChanges([3]string{"status", user.Status.String(), update.Status.String()})
+	case update.Role != nil:
+		event.Type = entities.AuditUserRoleChanged
+		event.Changes = auditChanges([3]string{"role", user.Role.String(), update.Role.String()})
+	}
+	return event
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBulkUpdateUsers tests that users the admin can't manage are reported on their own and the others changed together and logged out
+func TestBulkUpdateUsers(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockAuditRepo := mocks.NewMockAuditRepository(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		redis:  mockRedisRepo,
+		audit:  mockAuditRepo,
+		logger: newTestLogger(),
+	}
+
+	actor := &entities.Principal{UserID: uuid.New(), Role: entities.AdminRole}
+	user := &entities.User{ID: uuid.New(), Role: entities.UserRole, Status: entities.Active}
+	superAdmin := &entities.User{ID: uuid.New(), Role: entities.SuperAdminRole, Status: entities.Active}
+	missingID := uuid.New()
+
+	mockAdminRepo.On("AdminGetUserByID", mock.Anything, &user.ID).Return(user, nil).Once()
+	mockAdminRepo.On("AdminGetUserByID", mock.Anything, &superAdmin.ID).Return(superAdmin, nil).Once()
+	mockAdminRepo.On("AdminGetUserByID", mock.Anything, &missingID).Return(nil, errors.ErrUserNotFound).Once()
+	mockAdminRepo.On("AdminGetUserByID", mock.Anything, &actor.UserID).Return(&entities.User{ID: actor.UserID, Role: entities.AdminRole}, nil).Once()
+	mockAdminRepo.On("AdminBulkUpdateUsers", mock.Anything, []uuid.UUID{user.ID}, mock.MatchedBy(func(update *entities.UserBulkUpdate) bool {
+		return update.Status != nil && *update.Status == entities.Deactivated && !update.DryRun
+	})).Return(nil).Once()
+	mockAuditRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(event *entities.AuditEvent) bool {
+		return event.Type == entities.AuditUserStatusChanged && *event.TargetID == user.ID
+	})).Return(nil).Once()
+	expectSessionsRevoked(mockRedisRepo, user.ID.String(), uuid.New().String())
+
+	resp, err := service.BulkUpdateUsers(context.Background(), actor, &dto.AdminBulkUsersRequest{
+		UserIDs: []string{user.ID.String(), superAdmin.ID.String(), missingID.String(), actor.UserID.String()},
+		Action:  entities.BulkActionChangeStatus,
+		Status:  "deactivated",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Succeeded)
+	assert.Equal(t, 3, resp.Failed)
+	require.Len(t, resp.Results, 4)
+	assert.True(t, resp.Results[0].Succeeded)
+	assert.Equal(t, errors.ErrTargetRoleTooHigh, resp.Results[1].Error)
+	assert.Equal(t, errors.ErrUserNotFound, resp.Results[2].Error)
+	assert.Equal(t, errors.ErrCannotModifySelf, resp.Results[3].Error)
+}
+
+// TestBulkUpdateUsers_DuplicateID tests that the same user can't be listed twice in another notation
+func TestBulkUpdateUsers_DuplicateID(t *testing.T) {
+	service := &AdminService{
+		db:     mocks.NewMockAdminRepository(t),
+		audit:  mocks.NewMockAuditRepository(t),
+		logger: newTestLogger(),
+	}
+
+	actor := &entities.Principal{UserID: uuid.New(), Role: entities.AdminRole}
+	userID := uuid.New()
+
+	resp, err := service.BulkUpdateUsers(context.Background(), actor, &dto.AdminBulkUsersRequest{
+		UserIDs: []string{userID.String(), strings.ToUpper(userID.String())},
+		Action:  entities.BulkActionChangeStatus,
+		Status:  "deactivated",
+	})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, errors.ErrInvalidBulkUsers, err)
+}
+
+// TestBulkUpdateUsers_DryRun tests that a dry run checks the change without recording it
+func TestBulkUpdateUsers_DryRun(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		audit:  mocks.NewMockAuditRepository(t),
+		logger: newTestLogger(),
+	}
+
+	actor := &entities.Principal{UserID: uuid.New(), Role: entities.SuperAdminRole}
+	users := []*entities.User{
+		{ID: uuid.New(), Role: entities.AdminRole},
+		{ID: uuid.New(), Role: entities.UserRole},
+	}
+	for _, user := range users {
+		mockAdminRepo.On("AdminGetUserByID", mock.Anything, &user.ID).Return(user, nil).Once()
+	}
+	mockAdminRepo.On("AdminBulkUpdateUsers", mock.Anything, mock.MatchedBy(func(ids []uuid.UUID) bool {
+		return slices.Equal(ids, []uuid.UUID{users[0].ID, users[1].ID})
+	}), mock.MatchedBy(func(update *entities.UserBulkUpdate) bool {
+		return update.Delete && update.DryRun
+	})).Return(nil).Once()
+
+	resp, err := service.BulkUpdateUsers(context.Background(), actor, &dto.AdminBulkUsersRequest{
+		UserIDs: []string{users[0].ID.String(), users[1].ID.String()},
+		Action:  entities.BulkActionDelete,
+		DryRun:  true,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, 2, resp.Succeeded)
+	assert.Equal(t, 0, resp.Failed)
+}
+
+// TestBulkUpdateUsers_TransactionFails tests that every user fails when the transaction does
+func TestBulkUpdateUsers_TransactionFails(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		audit:  mocks.NewMockAuditRepository(t),
+		logger: newTestLogger(),
+	}
+
+	actor := &entities.Principal{UserID: uuid.New(), Role: entities.SuperAdminRole}
+	users := []*entities.User{
+		{ID: uuid.New(), Role: entities.AdminRole},
+		{ID: uuid.New(), Role: entities.AdminRole},
+	}
+	for _, user := range users {
+		mockAdminRepo.On("AdminGetUserByID", mock.Anything, &user.ID).Return(user, nil).Once()
+	}
+	mockAdminRepo.On("AdminBulkUpdateUsers", mock.Anything, mock.Anything, mock.Anything).Return(errors.ErrUpdateUser).Once()
+
+	resp, err := service.BulkUpdateUsers(context.Background(), actor, &dto.AdminBulkUsersRequest{
+		UserIDs: []string{users[0].ID.String(), users[1].ID.String()},
+		Action:  entities.BulkActionChangeRole,
+		Role:    "user",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, resp.Succeeded)
+	assert.Equal(t, 2, resp.Failed)
+	for _, result := range resp.Results {
+		assert.Equal(t, errors.ErrUpdateUser, result.Error)
+	}
+}
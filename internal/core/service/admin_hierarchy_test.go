@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestChangeUserRole_Hierarchy tests which role changes an admin may make depending on their own role and the target's
+func TestChangeUserRole_Hierarchy(t *testing.T) {
+	adminID := uuid.New()
+	tests := []struct {
+		name     string
+		actor    entities.Principal
+		targetID uuid.UUID
+		target   entities.RoleType
+		newRole  entities.RoleType
+		wantErr  error
+	}{
+		{
+			name:     "admin promotes user to admin",
+			actor:    entities.Principal{UserID: adminID, Role: entities.AdminRole},
+			targetID: uuid.New(),
+			target:   entities.UserRole,
+			newRole:  entities.AdminRole,
+		},
+		{
+			name:     "admin changes own role",
+			actor:    entities.Principal{UserID: adminID, Role: entities.AdminRole},
+			targetID: adminID,
+			target:   entities.AdminRole,
+			newRole:  entities.UserRole,
+			wantErr:  errors.ErrCannotModifySelf,
+		},
+		{
+			name:     "admin demotes another admin",
+			actor:    entities.Principal{UserID: adminID, Role: entities.AdminRole},
+			targetID: uuid.New(),
+			target:   entities.AdminRole,
+			newRole:  entities.UserRole,
+			wantErr:  errors.ErrTargetRoleTooHigh,
+		},
+		{
+			name:     "admin grants super admin",
+			actor:    entities.Principal{UserID: adminID, Role: entities.AdminRole},
+			targetID: uuid.New(),
+			target:   entities.UserRole,
+			newRole:  entities.SuperAdminRole,
+			wantErr:  errors.ErrRoleAboveOwn,
+		},
+		{
+			name:     "super admin demotes admin",
+			actor:    entities.Principal{UserID: adminID, Role: entities.SuperAdminRole},
+			targetID: uuid.New(),
+			target:   entities.AdminRole,
+			newRole:  entities.UserRole,
+		},
+		{
+			name:     "super admin demotes another super admin",
+			actor:    entities.Principal{UserID: adminID, Role: entities.SuperAdminRole},
+			targetID: uuid.New(),
+			target:   entities.SuperAdminRole,
+			newRole:  entities.AdminRole,
+			wantErr:  errors.ErrTargetRoleTooHigh,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAdminRepo := mocks.NewMockAdminRepository(t)
+			mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+			service := &AdminService{
+				db:     mockAdminRepo,
+				redis:  mockRedisRepo,
+				audit:  newTestAuditRepository(t),
+				logger: newTestLogger(),
+			}
+
+			mockAdminRepo.On("AdminGetUserByID", mock.Anything, &tt.targetID).Return(&entities.User{ID: tt.targetID, Role: tt.target}, nil).Once()
+			if tt.wantErr == nil {
+				mockAdminRepo.On("AdminChangeUserRole", mock.Anything, &tt.targetID, &tt.newRole).Return(nil).Once()
+				expectSessionsRevoked(mockRedisRepo, tt.targetID.String(), uuid.New().String())
+			}
+
+			err := service.ChangeUserRole(context.Background(), &tt.actor, &tt.targetID, &tt.newRole)
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+// TestAdminDeleteUser_SuperAdmin tests that a super admin can't delete another super admin
+func TestAdminDeleteUser_SuperAdmin(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		audit:  mocks.NewMockAuditRepository(t),
+		logger: newTestLogger(),
+	}
+
+	userID := uuid.New()
+	actor := &entities.Principal{UserID: uuid.New(), Role: entities.SuperAdminRole}
+	mockAdminRepo.On("AdminGetUserByID", mock.Anything, &userID).Return(&entities.User{ID: userID, Role: entities.SuperAdminRole}, nil).Once()
+
+	err := service.AdminDeleteUser(context.Background(), actor, &userID)
+
+	assert.Equal(t, errors.ErrTargetRoleTooHigh, err)
+}
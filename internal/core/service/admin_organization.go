@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// CreateOrganization creates an organization and its owner, a super admin
+// who logs in to the new organization with the phone number and password of
+// the request
+func (s *AdminService) CreateOrganization(ctx context.Context, req *dto.AdminCreateOrganizationRequest) (*dto.AdminOrganizationResponse, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while creating organization",
+			ports.F("error", ctx.Err()),
+			ports.F("slug", req.Slug),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	hashedPassword, err := s.hasher.Hash(req.OwnerPassword)
+	if err != nil {
+		s.logger.Error("Error hashing password",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrCreateOrganization
+	}
+
+	now := time.Now()
+	organization := &entities.Organization{
+		ID:        uuid.New(),
+		Slug:      req.Slug,
+		Name:      req.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	owner := &entities.User{
+		ID:             uuid.New(),
+		OrganizationID: organization.ID,
+		PhoneNumber:    req.OwnerPhoneNumber,
+		Password:       hashedPassword,
+		Status:         entities.Active,
+		Role:           entities.SuperAdminRole,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.organizations.CreateOrganization(ctx, organization, owner); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+		Type:     entities.AuditOrganizationCreated,
+		TargetID: &owner.ID,
+		Details: map[string]string{
+			"organization_id": organization.ID.String(),
+			"slug":            organization.Slug,
+		},
+	})
+
+	response := organizationResponse(organization)
+	response.OwnerID = owner.ID.String()
+	return &response, nil
+}
+
+// ListOrganizations returns every organization, oldest first
+func (s *AdminService) ListOrganizations(ctx context.Context) ([]dto.AdminOrganizationResponse, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while listing organizations",
+			ports.F("error", ctx.Err()),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	organizations, err := s.organizations.FindOrganizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.AdminOrganizationResponse, 0, len(organizations))
+	for _, organization := range organizations {
+		response = append(response, organizationResponse(&organization))
+	}
+	return response, nil
+}
+
+func organizationResponse(organization *entities.Organization) dto.AdminOrganizationResponse {
+	return dto.AdminOrganizationResponse{
+		ID:        organization.ID.String(),
+		Slug:      organization.Slug,
+		Name:      organization.Name,
+		CreatedAt: organization.CreatedAt,
+	}
+}
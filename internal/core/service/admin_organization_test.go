@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateOrganization tests that the owner is created as a super admin of the new organization
+func TestCreateOrganization(t *testing.T) {
+	mockOrganizationRepo := mocks.NewMockOrganizationRepository(t)
+	mockAuditRepo := mocks.NewMockAuditRepository(t)
+
+	service := &AdminService{
+		organizations: mockOrganizationRepo,
+		audit:         mockAuditRepo,
+		logger:        newTestLogger(),
+		hasher:        newTestHasher(),
+	}
+
+	req := &dto.AdminCreateOrganizationRequest{
+		Slug:             "acme",
+		Name:             "Acme",
+		OwnerPhoneNumber: "09123456789",
+		OwnerPassword:    "Password123",
+	}
+
+	var organization *entities.Organization
+	var owner *entities.User
+	mockOrganizationRepo.On("CreateOrganization", mock.Anything, mock.AnythingOfType("*entities.Organization"), mock.AnythingOfType("*entities.User")).
+		Run(func(args mock.Arguments) {
+			organization = args.Get(1).(*entities.Organization)
+			owner = args.Get(2).(*entities.User)
+		}).
+		Return(nil).Once()
+
+	var event *entities.AuditEvent
+	mockAuditRepo.On("CreateEvent", mock.Anything, mock.AnythingOfType("*entities.AuditEvent")).
+		Run(func(args mock.Arguments) { event = args.Get(1).(*entities.AuditEvent) }).
+		Return(nil).Once()
+
+	response, err := service.CreateOrganization(context.Background(), req)
+
+	require.NoError(t, err)
+	require.NotNil(t, organization)
+	require.NotNil(t, owner)
+	assert.Equal(t, "acme", organization.Slug)
+	assert.Equal(t, organization.ID, owner.OrganizationID)
+	assert.Equal(t, entities.SuperAdminRole, owner.Role)
+	assert.Equal(t, entities.Active, owner.Status)
+	matched, err := newTestHasher().Verify(req.OwnerPassword, owner.Password)
+	assert.NoError(t, err)
+	assert.True(t, matched)
+	assert.Equal(t, organization.ID.String(), response.ID)
+	assert.Equal(t, owner.ID.String(), response.OwnerID)
+
+	require.NotNil(t, event)
+	assert.Equal(t, entities.AuditOrganizationCreated, event.Type)
+	assert.Equal(t, &owner.ID, event.TargetID)
+	assert.Equal(t, entities.DefaultOrganizationID, event.OrganizationID)
+}
+
+// TestCreateOrganization_Duplicate tests that a taken slug reaches the caller without an audit event
+func TestCreateOrganization_Duplicate(t *testing.T) {
+	mockOrganizationRepo := mocks.NewMockOrganizationRepository(t)
+
+	service := &AdminService{
+		organizations: mockOrganizationRepo,
+		audit:         mocks.NewMockAuditRepository(t),
+		logger:        newTestLogger(),
+		hasher:        newTestHasher(),
+	}
+
+	req := &dto.AdminCreateOrganizationRequest{Slug: "acme", Name: "Acme", OwnerPhoneNumber: "09123456789", OwnerPassword: "Password123"}
+	mockOrganizationRepo.On("CreateOrganization", mock.Anything, mock.Anything, mock.Anything).Return(errors.ErrDuplicateOrganization).Once()
+
+	_, err := service.CreateOrganization(context.Background(), req)
+
+	assert.Equal(t, errors.ErrDuplicateOrganization, err)
+}
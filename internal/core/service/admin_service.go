@@ -1,8 +1,10 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/amirdashtii/go_auth/controller/dto"
@@ -12,11 +14,19 @@ import (
 	"github.com/amirdashtii/go_auth/internal/core/errors"
 	"github.com/amirdashtii/go_auth/internal/core/ports"
 	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type AdminService struct {
-	db     ports.AdminRepository
-	logger ports.Logger
+	db            ports.AdminRepository
+	redis         ports.InMemoryRespositoryContracts
+	clients       ports.OAuthClientRepository
+	mfa           ports.MFARepository
+	audit         ports.AuditRepository
+	organizations ports.OrganizationRepository
+	signer        ports.TokenSigner
+	hasher        ports.PasswordHasher
+	logger        ports.Logger
 }
 
 func NewAdminService() *AdminService {
@@ -36,45 +46,23 @@ func NewAdminService() *AdminService {
 	appLogger := logger.NewZerologLogger(loggerConfig)
 
 	adminRepo := repository.NewPGAdminRepository(db, appLogger)
-	return &AdminService{
-		db:     adminRepo,
-		logger: appLogger,
-	}
-}
-
-func (s *AdminService) GetUsers(ctx context.Context, status *entities.StatusType, role *entities.RoleType, sort, order *string) ([]dto.AdminUserResponse, error) {	if ctx.Err() != nil {
-		s.logger.Error("Context cancelled while getting users",
-			ports.F("error", ctx.Err()),
-			ports.F("status", status),
-			ports.F("role", role),
-		)
-		return nil, errors.ErrContextCancelled
-	}
-	users, err := s.db.FindUsers(ctx, status, role, sort, order)
+	redisRepo, err := repository.NewRedisRepository(appLogger)
 	if err != nil {
-		s.logger.Error("Error getting users",
-			ports.F("error", err),
-			ports.F("status", status),
-			ports.F("role", role),
-		)
-		return nil, errors.ErrGetUsers
-	}
-
-	var response []dto.AdminUserResponse
-	for _, user := range users {
-		response = append(response, dto.AdminUserResponse{
-			ID:          user.ID.String(),
-			PhoneNumber: user.PhoneNumber,
-			FirstName:   user.FirstName,
-			LastName:    user.LastName,
-			Email:       user.Email,
-			Status:      user.Status.String(),
-			Role:        user.Role.String(),
-			CreatedAt:   user.CreatedAt,
-			UpdatedAt:   user.UpdatedAt,
-		})
+		panic(errors.ErrRedisInit)
+	}
+	clientRepo := repository.NewPGOAuthClientRepository(db, appLogger)
+	mfaRepo := repository.NewPGMFARepository(db, appLogger)
+	return &AdminService{
+		db:            adminRepo,
+		redis:         redisRepo,
+		clients:       clientRepo,
+		mfa:           mfaRepo,
+		audit:         repository.NewPGAuditRepository(db, appLogger),
+		organizations: repository.NewPGOrganizationRepository(db, appLogger),
+		signer:        newTokenSigner(appLogger),
+		hasher:        newPasswordHasher(appLogger),
+		logger:        appLogger,
 	}
-	return response, nil
 }
 
 func (s *AdminService) AdminGetUserByID(ctx context.Context, userID *uuid.UUID) (*dto.AdminUserResponse, error) {
@@ -103,7 +91,7 @@ func (s *AdminService) AdminGetUserByID(ctx context.Context, userID *uuid.UUID)
 	}, nil
 }
 
-func (s *AdminService) AdminUpdateUser(ctx context.Context, userID *uuid.UUID, updateReq *dto.AdminUserUpdateRequest) error {
+func (s *AdminService) AdminUpdateUser(ctx context.Context, actor *entities.Principal, userID *uuid.UUID, updateReq *dto.AdminUserUpdateRequest) error {
 	if ctx.Err() != nil {
 		s.logger.Error("Context cancelled while updating user",
 			ports.F("error", ctx.Err()),
@@ -111,6 +99,15 @@ func (s *AdminService) AdminUpdateUser(ctx context.Context, userID *uuid.UUID, u
 		)
 		return errors.ErrContextCancelled
 	}
+
+	previous, err := s.db.AdminGetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkCanModify(actor, previous); err != nil {
+		return err
+	}
+
 	user := &entities.User{
 		ID:          *userID,
 		PhoneNumber: updateReq.PhoneNumber,
@@ -123,10 +120,27 @@ func (s *AdminService) AdminUpdateUser(ctx context.Context, userID *uuid.UUID, u
 		return err
 	}
 
+	// Empty fields weren't changed. Admins can change a phone number
+	// without a code sent to it, so the diff is the only record of it.
+	changes := auditChanges(
+		[3]string{"phone_number", previous.PhoneNumber, cmp.Or(user.PhoneNumber, previous.PhoneNumber)},
+		[3]string{"first_name", previous.FirstName, cmp.Or(user.FirstName, previous.FirstName)},
+		[3]string{"last_name", previous.LastName, cmp.Or(user.LastName, previous.LastName)},
+		[3]string{"email", previous.Email, cmp.Or(user.Email, previous.Email)},
+	)
+	if len(changes) > 0 {
+		recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+			Type:     entities.AuditUserUpdated,
+			ActorID:  &actor.UserID,
+			TargetID: userID,
+			Changes:  changes,
+		})
+	}
+
 	return nil
 }
 
-func (s *AdminService) ChangeUserRole(ctx context.Context, userID *uuid.UUID, updateRole *entities.RoleType) error {
+func (s *AdminService) ChangeUserRole(ctx context.Context, actor *entities.Principal, userID *uuid.UUID, updateRole *entities.RoleType) error {
 	if ctx.Err() != nil {
 		s.logger.Error("Context cancelled while changing user role",
 			ports.F("error", ctx.Err()),
@@ -135,14 +149,40 @@ func (s *AdminService) ChangeUserRole(ctx context.Context, userID *uuid.UUID, up
 		)
 		return errors.ErrContextCancelled
 	}
+	previous, err := s.db.AdminGetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkCanModify(actor, previous); err != nil {
+		return err
+	}
+	if updateRole.Level() > actor.Role.Level() {
+		s.logger.Warn("Role above own refused",
+			ports.F("actor_id", actor.UserID),
+			ports.F("user_id", userID),
+			ports.F("new_role", updateRole),
+		)
+		return errors.ErrRoleAboveOwn
+	}
 	if err := s.db.AdminChangeUserRole(ctx, userID, updateRole); err != nil {
 		return err
 	}
 
+	recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+		Type:     entities.AuditUserRoleChanged,
+		ActorID:  &actor.UserID,
+		TargetID: userID,
+		Changes:  auditChanges([3]string{"role", previous.Role.String(), updateRole.String()}),
+	})
+
+	// Tokens carry the role, the user logs in again to get the new one
+	if previous.Role != *updateRole {
+		return s.endSessions(ctx, userID)
+	}
 	return nil
 }
 
-func (s *AdminService) ChangeUserStatus(ctx context.Context, userID *uuid.UUID, updateStatus *entities.StatusType) error {
+func (s *AdminService) ChangeUserStatus(ctx context.Context, actor *entities.Principal, userID *uuid.UUID, updateStatus *entities.StatusType) error {
 	if ctx.Err() != nil {
 		s.logger.Error("Context cancelled while changing user status",
 			ports.F("error", ctx.Err()),
@@ -151,14 +191,31 @@ func (s *AdminService) ChangeUserStatus(ctx context.Context, userID *uuid.UUID,
 		)
 		return errors.ErrContextCancelled
 	}
+	previous, err := s.db.AdminGetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkCanModify(actor, previous); err != nil {
+		return err
+	}
 	if err := s.db.AdminChangeUserStatus(ctx, userID, updateStatus); err != nil {
 		return err
 	}
 
+	recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+		Type:     entities.AuditUserStatusChanged,
+		ActorID:  &actor.UserID,
+		TargetID: userID,
+		Changes:  auditChanges([3]string{"status", previous.Status.String(), updateStatus.String()}),
+	})
+
+	if *updateStatus != entities.Active {
+		return s.endSessions(ctx, userID)
+	}
 	return nil
 }
 
-func (s *AdminService) AdminDeleteUser(ctx context.Context, userID *uuid.UUID) error {
+func (s *AdminService) AdminDeleteUser(ctx context.Context, actor *entities.Principal, userID *uuid.UUID) error {
 	if ctx.Err() != nil {
 		s.logger.Error("Context cancelled while deleting user",
 			ports.F("error", ctx.Err()),
@@ -166,9 +223,223 @@ func (s *AdminService) AdminDeleteUser(ctx context.Context, userID *uuid.UUID) e
 		)
 		return errors.ErrContextCancelled
 	}
+	previous, err := s.db.AdminGetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkCanModify(actor, previous); err != nil {
+		return err
+	}
 	if err := s.db.AdminDeleteUser(ctx, userID); err != nil {
 		return err
 	}
 
+	recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+		Type:     entities.AuditUserDeleted,
+		ActorID:  &actor.UserID,
+		TargetID: userID,
+		Changes:  auditChanges([3]string{"status", previous.Status.String(), entities.Deleted.String()}),
+	})
+	return s.endSessions(ctx, userID)
+}
+
+// endSessions revokes every session of a user whose role or status changed,
+// so tokens issued before the change stop working right away
+func (s *AdminService) endSessions(ctx context.Context, userID *uuid.UUID) error {
+	revoked, err := revokeSessions(ctx, s.redis, userID.String())
+	if err != nil {
+		s.logger.Error("Error revoking sessions",
+			ports.F("error", err),
+			ports.F("user_id", userID),
+		)
+		return errors.ErrRevokeSessions
+	}
+
+	s.logger.Info("Sessions revoked",
+		ports.F("user_id", userID),
+		ports.F("sessions", revoked),
+	)
+	return nil
+}
+
+// ResetUserMFA turns off two-factor authentication for a user who lost
+// their authenticator and recovery codes, so they can log in with their
+// password and set it up again
+func (s *AdminService) ResetUserMFA(ctx context.Context, actor *entities.Principal, userID *uuid.UUID) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while resetting user MFA",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	// Turning off the second factor of a more powerful account would make
+	// it easier to take over
+	user, err := s.db.AdminGetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkCanModify(actor, user); err != nil {
+		return err
+	}
+
+	if err := s.mfa.DeleteMFA(ctx, *userID); err != nil {
+		return err
+	}
+
+	s.logger.Info("Two-factor authentication reset",
+		ports.F("user_id", userID),
+	)
+
+	recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+		Type:     entities.AuditUserMFAReset,
+		ActorID:  &actor.UserID,
+		TargetID: userID,
+	})
+	return nil
+}
+
+// UnlockUser clears the login failures of a user's phone number, so they can
+// try their password again right away
+func (s *AdminService) UnlockUser(ctx context.Context, actor *entities.Principal, userID *uuid.UUID) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while unlocking user",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	user, err := s.db.AdminGetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkCanModify(actor, user); err != nil {
+		return err
+	}
+
+	if err := clearThrottle(ctx, s.redis, phoneLoginThrottle, user.PhoneNumber); err != nil {
+		return err
+	}
+
+	s.logger.Info("Login lock cleared",
+		ports.F("user_id", userID),
+	)
+
+	recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+		Type:     entities.AuditUserUnlocked,
+		ActorID:  &actor.UserID,
+		TargetID: userID,
+	})
+	return nil
+}
+
+// RotateSigningKey replaces the token signing key and returns the kid of the
+// new key. Tokens signed with the old key stay valid until they expire.
+func (s *AdminService) RotateSigningKey(ctx context.Context) (string, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while rotating signing key",
+			ports.F("error", ctx.Err()),
+		)
+		return "", errors.ErrContextCancelled
+	}
+
+	return s.signer.Rotate()
+}
+
+// CreateOAuthClient registers an application that signs its users in through
+// go_auth, or a backend service that authenticates as itself. The secret of
+// a confidential client is only returned here, only its hash is stored.
+func (s *AdminService) CreateOAuthClient(ctx context.Context, req *dto.AdminCreateOAuthClientRequest) (*dto.AdminOAuthClientResponse, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while creating OAuth client",
+			ports.F("error", ctx.Err()),
+			ports.F("name", req.Name),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	grantTypes := req.GrantTypes
+	if len(grantTypes) == 0 {
+		grantTypes = []string{entities.GrantTypeAuthorizationCode, entities.GrantTypeRefreshToken}
+	}
+
+	scopes := req.Scopes
+	if len(scopes) == 0 && slices.Contains(grantTypes, entities.GrantTypeAuthorizationCode) {
+		scopes = entities.SupportedScopes
+	}
+	if !validClientScopes(grantTypes, scopes) {
+		s.logger.Error("Invalid scopes for OAuth client",
+			ports.F("name", req.Name),
+			ports.F("scopes", scopes),
+		)
+		return nil, errors.ErrInvalidScope
+	}
+
+	now := time.Now()
+	client := &entities.OAuthClient{
+		ID:             uuid.NewString(),
+		OrganizationID: entities.OrganizationIDFromContext(ctx),
+		Name:           req.Name,
+		RedirectURIs:   req.RedirectURIs,
+		Scopes:         scopes,
+		GrantTypes:     grantTypes,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	var secret string
+	if req.Confidential {
+		var err error
+		secret, err = randomToken()
+		if err != nil {
+			s.logger.Error("Error generating client secret",
+				ports.F("error", err),
+			)
+			return nil, errors.ErrCreateOAuthClient
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+		if err != nil {
+			s.logger.Error("Error hashing client secret",
+				ports.F("error", err),
+			)
+			return nil, errors.ErrCreateOAuthClient
+		}
+		client.SecretHash = string(hash)
+	}
+
+	if err := s.clients.CreateClient(ctx, client); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("OAuth client created",
+		ports.F("client_id", client.ID),
+		ports.F("name", client.Name),
+		ports.F("confidential", client.IsConfidential()),
+	)
+
+	return &dto.AdminOAuthClientResponse{
+		ClientID:       client.ID,
+		ClientSecret:   secret,
+		OrganizationID: client.OrganizationID.String(),
+		Name:           client.Name,
+		RedirectURIs:   client.RedirectURIs,
+		Scopes:         client.Scopes,
+		GrantTypes:     client.GrantTypes,
+		CreatedAt:      client.CreatedAt,
+	}, nil
+}
+
+// checkCanModify returns an error unless actor is above target in the role
+// hierarchy. No role is above super admins, so no one can change them here.
+func checkCanModify(actor *entities.Principal, target *entities.User) error {
+	if actor.UserID == target.ID {
+		return errors.ErrCannotModifySelf
+	}
+	if target.Role.Level() >= actor.Role.Level() {
+		return errors.ErrTargetRoleTooHigh
+	}
 	return nil
 }
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const defaultUserPageSize = 50
+
+// GetUsers returns a page of the users matching req and how many match in
+// total. The next page is requested with the returned NextCursor.
+func (s *AdminService) GetUsers(ctx context.Context, req *dto.AdminGetUsersRequest) (*dto.AdminUserListResponse, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while getting users",
+			ports.F("error", ctx.Err()),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	filter, err := userFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.db.CountUsers(ctx, filter)
+	if err != nil {
+		s.logger.Error("Error counting users",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrGetUsers
+	}
+
+	// One more user than asked for tells whether there is another page
+	filter.Limit++
+	users, err := s.db.FindUsers(ctx, filter)
+	if err != nil {
+		s.logger.Error("Error getting users",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrGetUsers
+	}
+	filter.Limit--
+
+	response := &dto.AdminUserListResponse{
+		Users: make([]dto.AdminUserResponse, 0, len(users)),
+		Total: total,
+	}
+	if len(users) > filter.Limit {
+		users = users[:filter.Limit]
+		last := users[len(users)-1]
+		response.NextCursor = encodeUserCursor(userCursor{
+			Sort:  filter.Sort,
+			Desc:  filter.Descending,
+			Value: userSortValue(&last, filter.Sort),
+			ID:    last.ID,
+		})
+	}
+	for _, user := range users {
+		response.Users = append(response.Users, adminUserResponse(&user))
+	}
+	return response, nil
+}
+
+func adminUserResponse(user *entities.User) dto.AdminUserResponse {
+	return dto.AdminUserResponse{
+		ID:          user.ID.String(),
+		PhoneNumber: user.PhoneNumber,
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		Email:       user.Email,
+		Status:      user.Status.String(),
+		Role:        user.Role.String(),
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+	}
+}
+
+// userFilter turns a validated request into the filter for FindUsers. Users
+// are listed newest first unless the request says otherwise.
+func userFilter(req *dto.AdminGetUsersRequest) (*entities.UserFilter, error) {
+	filter := &entities.UserFilter{
+		Search:     req.Search,
+		Sort:       req.Sort,
+		Descending: req.Order != "asc",
+		Limit:      req.Limit,
+	}
+	if filter.Sort == "" {
+		filter.Sort = "created_at"
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultUserPageSize
+	}
+
+	for _, status := range req.Status {
+		filter.Statuses = append(filter.Statuses, entities.ParseStatusType(status))
+	}
+	for _, role := range req.Role {
+		filter.Roles = append(filter.Roles, entities.ParseRoleType(role))
+	}
+
+	if req.CreatedFrom != "" {
+		from, err := time.Parse(time.RFC3339, req.CreatedFrom)
+		if err != nil {
+			return nil, errors.ErrInvalidUserFilter
+		}
+		filter.CreatedFrom = &from
+	}
+	if req.CreatedTo != "" {
+		to, err := time.Parse(time.RFC3339, req.CreatedTo)
+		if err != nil {
+			return nil, errors.ErrInvalidUserFilter
+		}
+		filter.CreatedTo = &to
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeUserCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		// The position of a user only means something in the order it was
+		// taken from
+		if cursor.Sort != filter.Sort || cursor.Desc != filter.Descending {
+			return nil, errors.ErrInvalidUserCursor
+		}
+		filter.After = &entities.UserCursor{Value: cursor.Value, ID: cursor.ID}
+	}
+
+	return filter, nil
+}
+
+// userSortValue returns the value of the sort column of user, as a cursor
+// stores it
+func userSortValue(user *entities.User, sort string) string {
+	switch sort {
+	case "updated_at":
+		return user.UpdatedAt.Format(time.RFC3339Nano)
+	case "email":
+		return user.Email
+	case "first_name":
+		return user.FirstName
+	case "last_name":
+		return user.LastName
+	case "role":
+		return strconv.Itoa(int(user.Role))
+	case "status":
+		return strconv.Itoa(int(user.Status))
+	default:
+		return user.CreatedAt.Format(time.RFC3339Nano)
+	}
+}
+
+// userCursor is the position of a user in a listing together with the order
+// of the listing
+type userCursor struct {
+	Sort  string    `json:"s"`
+	Desc  bool      `json:"d,omitempty"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// encodeUserCursor returns an opaque token for the position of a user
+func encodeUserCursor(cursor userCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeUserCursor(token string) (*userCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.ErrInvalidUserCursor
+	}
+
+	var cursor userCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return nil, errors.ErrInvalidUserCursor
+	}
+	return &cursor, nil
+}
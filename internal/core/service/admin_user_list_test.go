@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetUsers tests that pages are continued from the cursor of the previous one and carry the total
+func TestGetUsers(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		logger: newTestLogger(),
+	}
+
+	users := []entities.User{
+		{ID: uuid.New(), FirstName: "Ali"},
+		{ID: uuid.New(), FirstName: "Reza"},
+		{ID: uuid.New(), FirstName: "Sara"},
+	}
+	req := &dto.AdminGetUsersRequest{
+		Status: []string{"active", "deactivated"},
+		Role:   []string{"user"},
+		Search: "a",
+		Sort:   "first_name",
+		Order:  "asc",
+		Limit:  2,
+	}
+
+	mockAdminRepo.On("CountUsers", mock.Anything, mock.Anything).Return(3, nil).Twice()
+	// One more user than the page size is fetched to know there is a next page
+	mockAdminRepo.On("FindUsers", mock.Anything, mock.MatchedBy(func(filter *entities.UserFilter) bool {
+		return filter.After == nil && filter.Limit == 3 && filter.Sort == "first_name" && !filter.Descending &&
+			filter.Search == "a" && slices.Equal(filter.Statuses, []entities.StatusType{entities.Active, entities.Deactivated})
+	})).Return(users, nil).Once()
+
+	page, err := service.GetUsers(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, page.Users, 2)
+	assert.Equal(t, 3, page.Total)
+	require.NotEmpty(t, page.NextCursor)
+
+	mockAdminRepo.On("FindUsers", mock.Anything, mock.MatchedBy(func(filter *entities.UserFilter) bool {
+		return filter.After != nil && filter.After.ID == users[1].ID && filter.After.Value == "Reza"
+	})).Return(users[2:], nil).Once()
+
+	req.Cursor = page.NextCursor
+	page, err = service.GetUsers(context.Background(), req)
+	require.NoError(t, err)
+	assert.Len(t, page.Users, 1)
+	assert.Equal(t, 3, page.Total)
+	assert.Empty(t, page.NextCursor)
+}
+
+// TestGetUsers_InvalidCursor tests that cursors are refused when malformed or taken from a listing in another order
+func TestGetUsers_InvalidCursor(t *testing.T) {
+	service := &AdminService{
+		db:     mocks.NewMockAdminRepository(t),
+		logger: newTestLogger(),
+	}
+
+	_, err := service.GetUsers(context.Background(), &dto.AdminGetUsersRequest{Cursor: "not-a-cursor"})
+	assert.Equal(t, errors.ErrInvalidUserCursor, err)
+
+	cursor := encodeUserCursor(userCursor{Sort: "created_at", Desc: true, Value: "2024-01-01T00:00:00Z", ID: uuid.New()})
+	_, err = service.GetUsers(context.Background(), &dto.AdminGetUsersRequest{Sort: "email", Cursor: cursor})
+	assert.Equal(t, errors.ErrInvalidUserCursor, err)
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// exportPageSize is how many users an export reads from the database at a
+// time
+const exportPageSize = 500
+
+// ExportUsers calls write with every user matching the filters of req, in
+// the order of the user list. Users are read a page at a time, so exports of
+// any size are streamed.
+func (s *AdminService) ExportUsers(ctx context.Context, actor *entities.Principal, req *dto.AdminExportUsersRequest, write func(user *dto.AdminUserResponse) error) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while exporting users",
+			ports.F("error", ctx.Err()),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	listReq := req.AdminGetUsersRequest
+	listReq.Cursor = ""
+	filter, err := userFilter(&listReq)
+	if err != nil {
+		return err
+	}
+	filter.Limit = exportPageSize
+
+	exported := 0
+	for {
+		users, err := s.db.FindUsers(ctx, filter)
+		if err != nil {
+			s.logger.Error("Error exporting users",
+				ports.F("error", err),
+			)
+			return errors.ErrExportUsers
+		}
+
+		for _, user := range users {
+			response := adminUserResponse(&user)
+			if err := write(&response); err != nil {
+				s.logger.Error("Error writing exported user",
+					ports.F("error", err),
+					ports.F("user_id", user.ID),
+				)
+				return errors.ErrExportUsers
+			}
+		}
+		exported += len(users)
+
+		if len(users) < filter.Limit {
+			break
+		}
+		last := users[len(users)-1]
+		filter.After = &entities.UserCursor{Value: userSortValue(&last, filter.Sort), ID: last.ID}
+	}
+
+	recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+		Type:    entities.AuditUsersExported,
+		ActorID: &actor.UserID,
+		Details: map[string]string{
+			"format": req.Format,
+			"users":  strconv.Itoa(exported),
+		},
+	})
+	return nil
+}
+
+// ImportUsers creates a user for every row, which the handler validated, and
+// reports the rows that couldn't be imported. A row failing doesn't stop the
+// others. Rows repeating the phone number of an earlier row are skipped.
+func (s *AdminService) ImportUsers(ctx context.Context, actor *entities.Principal, rows []dto.AdminImportUserRow) (*dto.AdminImportUsersResponse, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while importing users",
+			ports.F("error", ctx.Err()),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	response := &dto.AdminImportUsersResponse{
+		Errors: []dto.AdminImportRowError{},
+	}
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		if ctx.Err() != nil {
+			return nil, errors.ErrContextCancelled
+		}
+
+		if seen[row.PhoneNumber] {
+			response.Errors = append(response.Errors, dto.AdminImportRowError{Row: row.Row, Error: errors.ErrDuplicateImportRow})
+			continue
+		}
+		seen[row.PhoneNumber] = true
+
+		user, err := s.importUser(ctx, actor, &row)
+		if err != nil {
+			response.Errors = append(response.Errors, dto.AdminImportRowError{Row: row.Row, Error: err})
+			continue
+		}
+		response.Imported++
+
+		recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+			Type:     entities.AuditUserImported,
+			ActorID:  &actor.UserID,
+			TargetID: &user.ID,
+		})
+	}
+	response.Failed = len(response.Errors)
+
+	s.logger.Info("Users imported",
+		ports.F("actor_id", actor.UserID),
+		ports.F("imported", response.Imported),
+		ports.F("failed", response.Failed),
+	)
+
+	return response, nil
+}
+
+// importUser creates the user of row. Nobody can be given a role above the
+// one of the admin importing them.
+func (s *AdminService) importUser(ctx context.Context, actor *entities.Principal, row *dto.AdminImportUserRow) (*entities.User, error) {
+	role := entities.UserRole
+	if row.Role != "" {
+		role = entities.ParseRoleType(row.Role)
+	}
+	if role.Level() > actor.Role.Level() {
+		return nil, errors.ErrRoleAboveOwn
+	}
+
+	status := entities.Active
+	if row.Status != "" {
+		status = entities.ParseStatusType(row.Status)
+	}
+
+	// Hashes from another system are stored as they are, so users keep
+	// their password. They are replaced by the preferred algorithm at the
+	// first login.
+	hashedPassword := row.PasswordHash
+	if hashedPassword != "" && !s.hasher.Supports(hashedPassword) {
+		return nil, errors.ErrInvalidPasswordHash
+	}
+	if hashedPassword == "" {
+		hash, err := s.hasher.Hash(row.Password)
+		if err != nil {
+			s.logger.Error("Error hashing password",
+				ports.F("error", err),
+			)
+			return nil, errors.ErrCreateUser
+		}
+		hashedPassword = hash
+	}
+
+	now := time.Now()
+	user := &entities.User{
+		ID:             uuid.New(),
+		OrganizationID: entities.OrganizationIDFromContext(ctx),
+		PhoneNumber:    row.PhoneNumber,
+		FirstName:      row.FirstName,
+		LastName:       row.LastName,
+		Email:          row.Email,
+		Password:       hashedPassword,
+		Status:         status,
+		Role:           role,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.db.AdminCreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
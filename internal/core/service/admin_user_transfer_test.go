@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExportUsers tests that exports read every page after the last user of the previous one
+func TestExportUsers(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+	mockAuditRepo := mocks.NewMockAuditRepository(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		audit:  mockAuditRepo,
+		logger: newTestLogger(),
+	}
+
+	firstPage := make([]entities.User, exportPageSize)
+	for i := range firstPage {
+		firstPage[i] = entities.User{ID: uuid.New(), Email: "user@example.com"}
+	}
+	last := firstPage[len(firstPage)-1]
+	secondPage := []entities.User{{ID: uuid.New()}}
+
+	mockAdminRepo.On("FindUsers", mock.Anything, mock.MatchedBy(func(filter *entities.UserFilter) bool {
+		return filter.After == nil && filter.Limit == exportPageSize && filter.Sort == "email"
+	})).Return(firstPage, nil).Once()
+	mockAdminRepo.On("FindUsers", mock.Anything, mock.MatchedBy(func(filter *entities.UserFilter) bool {
+		return filter.After != nil && filter.After.ID == last.ID && filter.After.Value == "user@example.com"
+	})).Return(secondPage, nil).Once()
+	mockAuditRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(event *entities.AuditEvent) bool {
+		return event.Type == entities.AuditUsersExported && event.Details["users"] == "501" && event.Details["format"] == "csv"
+	})).Return(nil).Once()
+
+	var exported []string
+	err := service.ExportUsers(context.Background(), &entities.Principal{UserID: uuid.New(), Role: entities.AdminRole},
+		&dto.AdminExportUsersRequest{
+			AdminGetUsersRequest: dto.AdminGetUsersRequest{Sort: "email", Cursor: "ignored", Limit: 10},
+			Format:               "csv",
+		},
+		func(user *dto.AdminUserResponse) error {
+			exported = append(exported, user.ID)
+			return nil
+		})
+
+	require.NoError(t, err)
+	require.Len(t, exported, exportPageSize+1)
+	assert.Equal(t, secondPage[0].ID.String(), exported[exportPageSize])
+}
+
+// TestImportUsers tests that rows failing are reported by number while the others are created
+func TestImportUsers(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+	mockAuditRepo := mocks.NewMockAuditRepository(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		audit:  mockAuditRepo,
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
+	}
+
+	legacyHash := "$pbkdf2-sha256$i=1000$c2FsdHNhbHRzYWx0c2FsdA$70cUhXcSYOUIjWzWnSYy3dcYSct7zJGo2jkT03TJR7g"
+	rows := []dto.AdminImportUserRow{
+		{Row: 1, PhoneNumber: "09120000001", Password: "Password1!"},
+		{Row: 2, PhoneNumber: "09120000002", PasswordHash: legacyHash, Role: "admin"},
+		{Row: 3, PhoneNumber: "09120000001", Password: "Password1!"},
+		{Row: 4, PhoneNumber: "09120000003", Password: "Password1!", Role: "superadmin"},
+		{Row: 5, PhoneNumber: "09120000004", Password: "Password1!"},
+		{Row: 6, PhoneNumber: "09120000005", PasswordHash: "$md5$c2FsdA$c2FsdHNhbHRzYWx0c2FsdA"},
+	}
+
+	mockAdminRepo.On("AdminCreateUser", mock.Anything, mock.MatchedBy(func(user *entities.User) bool {
+		return user.PhoneNumber == "09120000001" && user.Role == entities.UserRole && user.Status == entities.Active &&
+			!newTestHasher().NeedsRehash(user.Password)
+	})).Return(nil).Once()
+	// A hash from another system is stored as it is
+	mockAdminRepo.On("AdminCreateUser", mock.Anything, mock.MatchedBy(func(user *entities.User) bool {
+		return user.PhoneNumber == "09120000002" && user.Role == entities.AdminRole && user.Password == legacyHash
+	})).Return(nil).Once()
+	mockAdminRepo.On("AdminCreateUser", mock.Anything, mock.MatchedBy(func(user *entities.User) bool {
+		return user.PhoneNumber == "09120000004"
+	})).Return(errors.ErrDuplicatePhoneNumber).Once()
+	mockAuditRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(event *entities.AuditEvent) bool {
+		return event.Type == entities.AuditUserImported
+	})).Return(nil).Twice()
+
+	resp, err := service.ImportUsers(context.Background(), &entities.Principal{UserID: uuid.New(), Role: entities.AdminRole}, rows)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Imported)
+	assert.Equal(t, 4, resp.Failed)
+	assert.Equal(t, []dto.AdminImportRowError{
+		{Row: 3, Error: errors.ErrDuplicateImportRow},
+		{Row: 4, Error: errors.ErrRoleAboveOwn},
+		{Row: 5, Error: errors.ErrDuplicatePhoneNumber},
+		{Row: 6, Error: errors.ErrInvalidPasswordHash},
+	}, resp.Errors)
+}
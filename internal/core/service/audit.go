@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// recordAudit saves event in the organization of ctx with the client the
+// request came from. The actor is the authenticated user making the request
+// unless event already names one. Failing to save the event is logged but
+// doesn't fail the action it describes, which has already happened.
+func recordAudit(ctx context.Context, audit ports.AuditRepository, logger ports.Logger, event *entities.AuditEvent) {
+	client := entities.ClientInfoFromContext(ctx)
+	event.ID = uuid.New()
+	event.OrganizationID = entities.OrganizationIDFromContext(ctx)
+	event.IP = client.IP
+	event.UserAgent = client.UserAgent
+	event.CreatedAt = time.Now()
+	if event.ActorID == nil {
+		if actorID, err := uuid.Parse(client.UserID); err == nil {
+			event.ActorID = &actorID
+		}
+	}
+
+	// The event is recorded even if the request was cancelled right after
+	// the action was done
+	if err := audit.CreateEvent(context.WithoutCancel(ctx), event); err != nil {
+		logger.Error("Error recording audit event",
+			ports.F("error", err),
+			ports.F("event_type", event.Type),
+			ports.F("actor_id", event.ActorID),
+			ports.F("target_id", event.TargetID),
+		)
+	}
+}
+
+// auditChanges builds the changes of an audit event from field, before,
+// after triples, leaving out fields whose value stayed the same
+func auditChanges(fields ...[3]string) map[string]entities.AuditChange {
+	changes := make(map[string]entities.AuditChange)
+	for _, field := range fields {
+		if field[1] != field[2] {
+			changes[field[0]] = entities.AuditChange{Before: field[1], After: field[2]}
+		}
+	}
+	return changes
+}
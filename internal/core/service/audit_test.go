@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAuditRepository returns an audit repository that accepts any event
+func newTestAuditRepository(t *testing.T) *mocks.AuditRepository {
+	mockAuditRepo := mocks.NewMockAuditRepository(t)
+	mockAuditRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return mockAuditRepo
+}
+
+// TestLogin_AuditsUnknownUser tests that a failed login is recorded with the client it came from
+func TestLogin_AuditsUnknownUser(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockAuditRepo := mocks.NewMockAuditRepository(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		audit:  mockAuditRepo,
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
+	}
+
+	req := &dto.LoginRequest{PhoneNumber: "09123456789", Password: "Password123"}
+	expectLoginNotLocked(mockRedisRepo, req.PhoneNumber, "10.0.0.1")
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &req.PhoneNumber).Return(nil, errors.ErrUserNotFound).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, mock.Anything, loginFailureWindow).Return(int64(1), nil).Twice()
+
+	var event *entities.AuditEvent
+	mockAuditRepo.On("CreateEvent", mock.Anything, mock.AnythingOfType("*entities.AuditEvent")).
+		Run(func(args mock.Arguments) { event = args.Get(1).(*entities.AuditEvent) }).
+		Return(nil).Once()
+
+	ctx := entities.WithClientInfo(context.Background(), entities.ClientInfo{IP: "10.0.0.1", UserAgent: "curl/8.0"})
+	_, err := service.Login(ctx, req)
+
+	assert.Equal(t, errors.ErrUserNotFound, err)
+	require.NotNil(t, event)
+	assert.Equal(t, entities.AuditLoginFailed, event.Type)
+	assert.Nil(t, event.ActorID)
+	assert.Nil(t, event.TargetID)
+	assert.Equal(t, "10.0.0.1", event.IP)
+	assert.Equal(t, "curl/8.0", event.UserAgent)
+	assert.Equal(t, map[string]string{"identifier": req.PhoneNumber, "reason": "unknown_user"}, event.Details)
+}
+
+// TestChangeUserRole_AuditsChange tests that role changes are recorded with the admin who made them and the old and new role
+func TestChangeUserRole_AuditsChange(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockAuditRepo := mocks.NewMockAuditRepository(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		redis:  mockRedisRepo,
+		audit:  mockAuditRepo,
+		logger: newTestLogger(),
+	}
+
+	adminID := uuid.New()
+	userID := uuid.New()
+	role := entities.AdminRole
+	mockAdminRepo.On("AdminGetUserByID", mock.Anything, &userID).Return(&entities.User{ID: userID, Role: entities.UserRole}, nil).Once()
+	mockAdminRepo.On("AdminChangeUserRole", mock.Anything, &userID, &role).Return(nil).Once()
+	mockAuditRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(event *entities.AuditEvent) bool {
+		return event.Type == entities.AuditUserRoleChanged &&
+			*event.ActorID == adminID &&
+			*event.TargetID == userID &&
+			event.Changes["role"] == entities.AuditChange{Before: entities.UserRole.String(), After: entities.AdminRole.String()}
+	})).Return(nil).Once()
+	expectSessionsRevoked(mockRedisRepo, userID.String())
+
+	actor := &entities.Principal{UserID: adminID, Role: entities.SuperAdminRole}
+	err := service.ChangeUserRole(context.Background(), actor, &userID, &role)
+
+	assert.NoError(t, err)
+}
+
+// TestResetUserMFA_AuditsReset tests that turning off a user's second factor is recorded with the admin who did it
+func TestResetUserMFA_AuditsReset(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+	mockMFARepo := mocks.NewMockMFARepository(t)
+	mockAuditRepo := mocks.NewMockAuditRepository(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		mfa:    mockMFARepo,
+		audit:  mockAuditRepo,
+		logger: newTestLogger(),
+	}
+
+	adminID := uuid.New()
+	userID := uuid.New()
+	mockAdminRepo.On("AdminGetUserByID", mock.Anything, &userID).Return(&entities.User{ID: userID, Role: entities.UserRole}, nil).Once()
+	mockMFARepo.On("DeleteMFA", mock.Anything, userID).Return(nil).Once()
+	mockAuditRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(event *entities.AuditEvent) bool {
+		return event.Type == entities.AuditUserMFAReset &&
+			*event.ActorID == adminID &&
+			*event.TargetID == userID
+	})).Return(nil).Once()
+
+	actor := &entities.Principal{UserID: adminID, Role: entities.AdminRole}
+	err := service.ResetUserMFA(context.Background(), actor, &userID)
+
+	assert.NoError(t, err)
+}
+
+// TestAdminUpdateUser_AuditFailureIgnored tests that an update isn't undone when its audit event can't be saved
+func TestAdminUpdateUser_AuditFailureIgnored(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+	mockAuditRepo := mocks.NewMockAuditRepository(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		audit:  mockAuditRepo,
+		logger: newTestLogger(),
+	}
+
+	userID := uuid.New()
+	mockAdminRepo.On("AdminGetUserByID", mock.Anything, &userID).Return(&entities.User{ID: userID, FirstName: "Ali"}, nil).Once()
+	mockAdminRepo.On("AdminUpdateUser", mock.Anything, mock.Anything).Return(nil).Once()
+	mockAuditRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(event *entities.AuditEvent) bool {
+		return len(event.Changes) == 1 && event.Changes["first_name"] == entities.AuditChange{Before: "Ali", After: "Reza"}
+	})).Return(errors.ErrCreateAuditEvent).Once()
+
+	actor := &entities.Principal{UserID: uuid.New(), Role: entities.AdminRole}
+	err := service.AdminUpdateUser(context.Background(), actor, &userID, &dto.AdminUserUpdateRequest{FirstName: "Reza"})
+
+	assert.NoError(t, err)
+}
+
+// TestListAuditEvents tests that pages are continued from the cursor of the previous one
+func TestListAuditEvents(t *testing.T) {
+	mockAuditRepo := mocks.NewMockAuditRepository(t)
+
+	service := &AdminService{
+		audit:  mockAuditRepo,
+		logger: newTestLogger(),
+	}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	events := []entities.AuditEvent{
+		{ID: uuid.New(), Type: entities.AuditLogout, CreatedAt: now},
+		{ID: uuid.New(), Type: entities.AuditLogout, CreatedAt: now.Add(-time.Second)},
+		{ID: uuid.New(), Type: entities.AuditLogout, CreatedAt: now.Add(-2 * time.Second)},
+	}
+
+	// One more event than the page size is fetched to know there is a next page
+	mockAuditRepo.On("FindEvents", mock.Anything, mock.MatchedBy(func(filter *entities.AuditFilter) bool {
+		return filter.After == nil && filter.Limit == 3 && filter.Type == entities.AuditLogout
+	})).Return(events, nil).Once()
+
+	page, err := service.ListAuditEvents(context.Background(), &dto.AdminAuditEventsRequest{Type: entities.AuditLogout, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Events, 2)
+	require.NotEmpty(t, page.NextCursor)
+
+	mockAuditRepo.On("FindEvents", mock.Anything, mock.MatchedBy(func(filter *entities.AuditFilter) bool {
+		return filter.After != nil && filter.After.ID == events[1].ID && filter.After.CreatedAt.Equal(events[1].CreatedAt)
+	})).Return(events[2:], nil).Once()
+
+	page, err = service.ListAuditEvents(context.Background(), &dto.AdminAuditEventsRequest{Type: entities.AuditLogout, Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	assert.Len(t, page.Events, 1)
+	assert.Empty(t, page.NextCursor)
+
+	_, err = service.ListAuditEvents(context.Background(), &dto.AdminAuditEventsRequest{Cursor: "not-a-cursor"})
+	assert.Equal(t, errors.ErrInvalidAuditCursor, err)
+}
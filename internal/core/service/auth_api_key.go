@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// apiKeyPrefix marks the keys issued by this service, so they are easy to
+// spot in code and logs. A key looks like gak_<prefix>_<secret>.
+const apiKeyPrefix = "gak_"
+
+// CreateAPIKey creates an API key for the user. The key itself is only part
+// of this response, just its prefix and hash are stored.
+func (s *AuthService) CreateAPIKey(ctx context.Context, userID string, req *dto.CreateAPIKeyRequest) (*dto.APIKeyResponse, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while creating API key",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.ErrInvalidUserID
+	}
+
+	prefix, key, err := newAPIKey()
+	if err != nil {
+		s.logger.Error("Error generating API key",
+			ports.F("error", err),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrCreateAPIKey
+	}
+
+	scopes := req.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	apiKey := &entities.APIKey{
+		ID:        uuid.New(),
+		UserID:    userUUID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   hashAPIKey(key),
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.apiKeys.CreateAPIKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("API key created",
+		ports.F("user_id", userID),
+		ports.F("key_id", apiKey.ID),
+	)
+
+	response := apiKeyResponse(apiKey)
+	response.Key = key
+	return &response, nil
+}
+
+// ListAPIKeys returns the user's API keys, newest first
+func (s *AuthService) ListAPIKeys(ctx context.Context, userID string) ([]dto.APIKeyResponse, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while listing API keys",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.ErrInvalidUserID
+	}
+
+	apiKeys, err := s.apiKeys.FindAPIKeysByUserID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]dto.APIKeyResponse, 0, len(apiKeys))
+	for i := range apiKeys {
+		keys = append(keys, apiKeyResponse(&apiKeys[i]))
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey deletes one of the user's API keys
+func (s *AuthService) RevokeAPIKey(ctx context.Context, userID, keyID string) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while revoking API key",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+			ports.F("key_id", keyID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return errors.ErrInvalidUserID
+	}
+
+	keyUUID, err := uuid.Parse(keyID)
+	if err != nil {
+		return errors.ErrInvalidAPIKeyID
+	}
+
+	if err := s.apiKeys.DeleteAPIKey(ctx, userUUID, keyUUID); err != nil {
+		return err
+	}
+
+	s.logger.Info("API key revoked",
+		ports.F("user_id", userID),
+		ports.F("key_id", keyID),
+	)
+
+	return nil
+}
+
+// AuthenticateAPIKey returns the active user an API key belongs to, along
+// with the key
+func (s *AuthService) AuthenticateAPIKey(ctx context.Context, key string) (*entities.User, *entities.APIKey, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while authenticating API key",
+			ports.F("error", ctx.Err()),
+		)
+		return nil, nil, errors.ErrContextCancelled
+	}
+
+	prefix, ok := parseAPIKey(key)
+	if !ok {
+		return nil, nil, errors.ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.apiKeys.FindAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return nil, nil, errors.ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(key)), []byte(apiKey.KeyHash)) != 1 {
+		s.logger.Error("Invalid API key secret",
+			ports.F("key_id", apiKey.ID),
+		)
+		return nil, nil, errors.ErrInvalidAPIKey
+	}
+
+	if apiKey.IsExpired() {
+		s.logger.Error("Expired API key used",
+			ports.F("key_id", apiKey.ID),
+		)
+		return nil, nil, errors.ErrInvalidAPIKey
+	}
+
+	// Keys aren't tied to the organization a request names, they are used
+	// in the organization of their user
+	ctx = entities.WithOrganization(ctx, apiKey.OrganizationID)
+	user, err := s.db.FindUserByID(ctx, apiKey.UserID)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return nil, nil, errors.ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+
+	if user.Status != entities.Active {
+		s.logger.Error("API key used for inactive account",
+			ports.F("user_id", user.ID),
+			ports.F("key_id", apiKey.ID),
+		)
+		return nil, nil, errors.ErrAccountDeactivated
+	}
+
+	return user, apiKey, nil
+}
+
+// apiKeyPrefixLength is the length in bytes of the prefix that identifies a
+// key. Prefixes are unique, so it is long enough for keys not to collide.
+const apiKeyPrefixLength = 8
+
+// newAPIKey generates a key and the prefix that identifies it
+func newAPIKey() (string, string, error) {
+	b := make([]byte, apiKeyPrefixLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	prefix := hex.EncodeToString(b)
+
+	secret, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	return prefix, apiKeyPrefix + prefix + "_" + secret, nil
+}
+
+// parseAPIKey returns the prefix of a key in the format newAPIKey creates
+func parseAPIKey(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, apiKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 2*apiKeyPrefixLength || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+// hashAPIKey hashes a key for storage. Keys are random, so a fast hash is
+// enough.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func apiKeyResponse(key *entities.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:        key.ID.String(),
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		ExpiresAt: key.ExpiresAt,
+		CreatedAt: key.CreatedAt,
+	}
+}
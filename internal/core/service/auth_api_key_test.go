@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateAPIKey tests that only the prefix and hash of a new key are stored
+func TestCreateAPIKey(t *testing.T) {
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(t)
+
+	service := &AuthService{
+		apiKeys: mockAPIKeyRepo,
+		logger:  newTestLogger(),
+	}
+
+	userID := uuid.New()
+	var stored *entities.APIKey
+	mockAPIKeyRepo.On("CreateAPIKey", mock.Anything, mock.AnythingOfType("*entities.APIKey")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*entities.APIKey) }).Return(nil).Once()
+
+	resp, err := service.CreateAPIKey(context.Background(), userID.String(), &dto.CreateAPIKeyRequest{
+		Name:   "CI",
+		Scopes: []string{"users:read"},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(resp.Key, apiKeyPrefix+resp.Prefix+"_"))
+	assert.Equal(t, userID, stored.UserID)
+	assert.Equal(t, resp.Prefix, stored.Prefix)
+	assert.Equal(t, hashAPIKey(resp.Key), stored.KeyHash)
+	assert.Equal(t, []string{"users:read"}, stored.Scopes)
+}
+
+// TestAuthenticateAPIKey tests that a key is checked against its stored hash, expiry and owner
+func TestAuthenticateAPIKey(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(t)
+
+	service := &AuthService{
+		db:      mockAuthRepo,
+		apiKeys: mockAPIKeyRepo,
+		logger:  newTestLogger(),
+	}
+
+	prefix, key, err := newAPIKey()
+	require.NoError(t, err)
+
+	user := &entities.User{ID: uuid.New(), Status: entities.Active, Role: entities.UserRole}
+	apiKey := &entities.APIKey{ID: uuid.New(), UserID: user.ID, Prefix: prefix, KeyHash: hashAPIKey(key)}
+
+	mockAPIKeyRepo.On("FindAPIKeyByPrefix", mock.Anything, prefix).Return(apiKey, nil).Once()
+	mockAuthRepo.On("FindUserByID", mock.Anything, user.ID).Return(user, nil).Once()
+
+	gotUser, gotKey, err := service.AuthenticateAPIKey(context.Background(), key)
+	require.NoError(t, err)
+	assert.Equal(t, user, gotUser)
+	assert.Equal(t, apiKey, gotKey)
+}
+
+// TestAuthenticateAPIKey_Invalid tests that malformed, wrong and expired keys are rejected
+func TestAuthenticateAPIKey_Invalid(t *testing.T) {
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(t)
+
+	service := &AuthService{
+		apiKeys: mockAPIKeyRepo,
+		logger:  newTestLogger(),
+	}
+
+	prefix, key, err := newAPIKey()
+	require.NoError(t, err)
+
+	expired := time.Now().Add(-time.Minute)
+	mockAPIKeyRepo.On("FindAPIKeyByPrefix", mock.Anything, prefix).
+		Return(&entities.APIKey{ID: uuid.New(), Prefix: prefix, KeyHash: hashAPIKey(key)}, nil).Once()
+	mockAPIKeyRepo.On("FindAPIKeyByPrefix", mock.Anything, prefix).
+		Return(&entities.APIKey{ID: uuid.New(), Prefix: prefix, KeyHash: hashAPIKey(key), ExpiresAt: &expired}, nil).Once()
+
+	_, _, err = service.AuthenticateAPIKey(context.Background(), "not-a-key")
+	assert.Equal(t, errors.ErrInvalidAPIKey, err)
+
+	_, _, err = service.AuthenticateAPIKey(context.Background(), key+"x")
+	assert.Equal(t, errors.ErrInvalidAPIKey, err)
+
+	_, _, err = service.AuthenticateAPIKey(context.Background(), key)
+	assert.Equal(t, errors.ErrInvalidAPIKey, err)
+}
+
+// TestParseAPIKey tests that only keys in the format newAPIKey creates are recognised
+func TestParseAPIKey(t *testing.T) {
+	prefix, key, err := newAPIKey()
+	require.NoError(t, err)
+	assert.Len(t, prefix, 16)
+
+	parsed, ok := parseAPIKey(key)
+	assert.True(t, ok)
+	assert.Equal(t, prefix, parsed)
+
+	for _, invalid := range []string{"gak_0a1b2c3d_secret", "gak_0a1b2c_secret", "gak_" + prefix + "_", "key_" + prefix + "_secret"} {
+		_, ok = parseAPIKey(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
+
+// TestRevokeAPIKey tests that a user can only revoke their own keys
+func TestRevokeAPIKey(t *testing.T) {
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(t)
+
+	service := &AuthService{
+		apiKeys: mockAPIKeyRepo,
+		logger:  newTestLogger(),
+	}
+
+	userID := uuid.New()
+	keyID := uuid.New()
+	mockAPIKeyRepo.On("DeleteAPIKey", mock.Anything, userID, keyID).Return(nil).Once()
+	mockAPIKeyRepo.On("DeleteAPIKey", mock.Anything, userID, mock.Anything).Return(errors.ErrAPIKeyNotFound).Once()
+
+	assert.NoError(t, service.RevokeAPIKey(context.Background(), userID.String(), keyID.String()))
+	assert.Equal(t, errors.ErrAPIKeyNotFound, service.RevokeAPIKey(context.Background(), userID.String(), uuid.NewString()))
+	assert.Equal(t, errors.ErrInvalidAPIKeyID, service.RevokeAPIKey(context.Background(), userID.String(), "bad"))
+}
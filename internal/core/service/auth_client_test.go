@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestMachineClient(t *testing.T) *entities.OAuthClient {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	return &entities.OAuthClient{
+		ID:             "billing-job",
+		OrganizationID: entities.DefaultOrganizationID,
+		Name:           "Billing job",
+		SecretHash:     string(hash),
+		Scopes:         []string{"users:read", "users:write"},
+		GrantTypes:     []string{entities.GrantTypeClientCredentials},
+	}
+}
+
+// TestExchangeToken_ClientCredentials tests that a client gets an access token of its own
+func TestExchangeToken_ClientCredentials(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockClientRepo := mocks.NewMockOAuthClientRepository(t)
+
+	tokenSigner := newTestSigner(t)
+	service := &AuthService{
+		redis:   mockRedisRepo,
+		clients: mockClientRepo,
+		signer:  tokenSigner,
+		logger:  newTestLogger(),
+	}
+
+	var storedKey string
+	mockClientRepo.On("FindClientByID", mock.Anything, "billing-job").Return(newTestMachineClient(t), nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "client_token:") }), "billing-job", accessTokenExpiration).
+		Run(func(args mock.Arguments) { storedKey = args.String(1) }).Return(nil).Once()
+
+	resp, err := service.ExchangeToken(context.Background(), &dto.TokenRequest{
+		GrantType:    "client_credentials",
+		ClientID:     "billing-job",
+		ClientSecret: "secret",
+		Scope:        "users:read",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", resp.TokenType)
+	assert.Equal(t, "users:read", resp.Scope)
+	assert.Empty(t, resp.RefreshToken)
+	assert.Empty(t, resp.IDToken)
+
+	claims, err := tokenSigner.Verify(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "billing-job", claims["sub"])
+	assert.Equal(t, "billing-job", claims["client_id"])
+	assert.Equal(t, "access", claims["token_type"])
+	assert.Equal(t, entities.DefaultOrganizationID.String(), claims["org"])
+	assert.NotContains(t, claims, "user_id")
+	assert.Equal(t, clientTokenKey(claims["jti"].(string)), storedKey)
+}
+
+// TestExchangeToken_ClientCredentialsScopeNotAllowed tests that a client can't ask for more than it was granted
+func TestExchangeToken_ClientCredentialsScopeNotAllowed(t *testing.T) {
+	mockClientRepo := mocks.NewMockOAuthClientRepository(t)
+
+	service := &AuthService{
+		clients: mockClientRepo,
+		signer:  newTestSigner(t),
+		logger:  newTestLogger(),
+	}
+
+	mockClientRepo.On("FindClientByID", mock.Anything, "billing-job").Return(newTestMachineClient(t), nil).Once()
+
+	resp, err := service.ExchangeToken(context.Background(), &dto.TokenRequest{
+		GrantType:    "client_credentials",
+		ClientID:     "billing-job",
+		ClientSecret: "secret",
+		Scope:        "users:read users:delete",
+	})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, errors.ErrInvalidScope, err)
+}
+
+// TestExchangeToken_ClientCredentialsClientScopes tests that clients only get the client scopes of what they were granted
+func TestExchangeToken_ClientCredentialsClientScopes(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockClientRepo := mocks.NewMockOAuthClientRepository(t)
+
+	service := &AuthService{
+		redis:   mockRedisRepo,
+		clients: mockClientRepo,
+		signer:  newTestSigner(t),
+		logger:  newTestLogger(),
+	}
+
+	// users:write was granted before scopes were limited to the client scopes
+	mockClientRepo.On("FindClientByID", mock.Anything, "billing-job").Return(newTestMachineClient(t), nil).Twice()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.Anything, "billing-job", accessTokenExpiration).Return(nil).Once()
+
+	resp, err := service.ExchangeToken(context.Background(), &dto.TokenRequest{
+		GrantType:    "client_credentials",
+		ClientID:     "billing-job",
+		ClientSecret: "secret",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "users:read", resp.Scope)
+
+	resp, err = service.ExchangeToken(context.Background(), &dto.TokenRequest{
+		GrantType:    "client_credentials",
+		ClientID:     "billing-job",
+		ClientSecret: "secret",
+		Scope:        "users:write",
+	})
+	assert.Nil(t, resp)
+	assert.Equal(t, errors.ErrInvalidScope, err)
+}
+
+// TestValidClientScopes tests that clients are only granted the scopes of the grants they are registered for
+func TestValidClientScopes(t *testing.T) {
+	signIn := []string{entities.GrantTypeAuthorizationCode, entities.GrantTypeRefreshToken}
+	machine := []string{entities.GrantTypeClientCredentials}
+
+	assert.True(t, validClientScopes(signIn, entities.SupportedScopes))
+	assert.True(t, validClientScopes(machine, entities.ClientScopes))
+	assert.True(t, validClientScopes(append(signIn, machine...), []string{entities.ScopeOpenID, entities.PermissionAuditRead}))
+	assert.False(t, validClientScopes(signIn, []string{entities.PermissionUsersRead}))
+	assert.False(t, validClientScopes(machine, []string{entities.ScopeProfile}))
+	assert.False(t, validClientScopes(machine, []string{entities.PermissionUsersDelete}))
+	assert.False(t, validClientScopes(machine, []string{"reports:read"}))
+}
+
+// TestExchangeToken_ClientCredentialsNotAllowed tests that clients registered for user sign in can't act as themselves
+func TestExchangeToken_ClientCredentialsNotAllowed(t *testing.T) {
+	mockClientRepo := mocks.NewMockOAuthClientRepository(t)
+
+	service := &AuthService{
+		clients: mockClientRepo,
+		signer:  newTestSigner(t),
+		logger:  newTestLogger(),
+	}
+
+	mockClientRepo.On("FindClientByID", mock.Anything, "test-client").Return(newTestOAuthClient(), nil).Once()
+
+	resp, err := service.ExchangeToken(context.Background(), &dto.TokenRequest{
+		GrantType: "client_credentials",
+		ClientID:  "test-client",
+	})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, errors.ErrUnauthorizedClient, err)
+	assert.Equal(t, "unauthorized_client", OAuthErrorCode(err))
+}
+
+// TestValidateClientToken tests that client tokens are checked against the issued ones
+func TestValidateClientToken(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	mockRedisRepo.On("FindToken", mock.Anything, "client_token:token-1").Return("billing-job", nil).Twice()
+	mockRedisRepo.On("FindToken", mock.Anything, "client_token:token-2").Return("", errors.ErrTokenNotFound).Once()
+
+	assert.NoError(t, service.ValidateClientToken(context.Background(), "billing-job", "token-1"))
+	assert.Equal(t, errors.ErrInvalidToken, service.ValidateClientToken(context.Background(), "other-job", "token-1"))
+	assert.Equal(t, errors.ErrInvalidToken, service.ValidateClientToken(context.Background(), "billing-job", "token-2"))
+}
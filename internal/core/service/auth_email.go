@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	emailVerificationExpiration     = 24 * time.Hour
+	emailVerificationResendInterval = 1 * time.Minute
+)
+
+// RequestEmailVerification emails a link that confirms the user's pending
+// address, or their current one if it was never confirmed. A link for the
+// same address can't be requested again until
+// emailVerificationResendInterval has passed.
+func (s *AuthService) RequestEmailVerification(ctx context.Context, userID string) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while requesting email verification",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return errors.ErrInvalidUserID
+	}
+
+	user, err := s.db.FindUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	email := unverifiedEmail(user)
+	if email == "" {
+		return errors.ErrNoEmailToVerify
+	}
+
+	resendKey := emailVerificationResendKey(userID, email)
+	_, err = s.redis.FindToken(ctx, resendKey)
+	if err == nil {
+		s.logger.Warn("Email verification resend throttled",
+			ports.F("user_id", userID),
+		)
+		return errors.ErrVerificationEmailTooSoon
+	}
+	if !errors.IsNotFoundError(err) {
+		return err
+	}
+
+	link, err := s.emailVerificationLink(user, email)
+	if err != nil {
+		return err
+	}
+
+	if err := s.redis.AddToken(ctx, resendKey, "1", emailVerificationResendInterval); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Open this link to confirm your email address:\n\n%s\n\nThe link expires in %d hours. If you didn't ask for this, ignore this email.", link, int(emailVerificationExpiration.Hours()))
+	if err := s.email.Send(ctx, email, "Confirm your email address", body); err != nil {
+		return err
+	}
+
+	s.logger.Info("Email verification link sent",
+		ports.F("user_id", userID),
+	)
+	return nil
+}
+
+// VerifyEmail confirms the address a verification link was sent to. A
+// pending address replaces the user's current one.
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while verifying email",
+			ports.F("error", ctx.Err()),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	claims, err := s.ParseToken(token)
+	if err != nil {
+		return errors.ErrInvalidVerificationLink
+	}
+
+	tokenType, _ := claims["token_type"].(string)
+	email, _ := claims["email"].(string)
+	userID, err := uuidClaim(claims, "user_id")
+	if tokenType != "email_verification" || email == "" || err != nil {
+		s.logger.Error("Invalid email verification token",
+			ports.F("token_type", tokenType),
+		)
+		return errors.ErrInvalidVerificationLink
+	}
+
+	// Links are opened without naming an organization
+	if organizationID, err := uuidClaim(claims, "org"); err == nil {
+		ctx = entities.WithOrganization(ctx, organizationID)
+	}
+
+	// The address has to still be the user's current or pending one, so
+	// links to an address that was changed since stop working
+	if err := s.db.ConfirmEmail(ctx, userID, email); err != nil {
+		if errors.IsNotFoundError(err) {
+			return errors.ErrInvalidVerificationLink
+		}
+		return err
+	}
+
+	s.logger.Info("Email verified",
+		ports.F("user_id", userID),
+	)
+	return nil
+}
+
+// emailVerificationLink returns a link to /auth/email/verify carrying a
+// signed token for user and email
+func (s *AuthService) emailVerificationLink(user *entities.User, email string) (string, error) {
+	config, err := config.LoadConfig()
+	if err != nil {
+		return "", errors.ErrLoadConfig
+	}
+
+	now := time.Now()
+	token, err := s.signer.Sign(jwt.MapClaims{
+		"user_id":    user.ID.String(),
+		"org":        user.OrganizationID.String(),
+		"email":      email,
+		"token_type": "email_verification",
+		"jti":        uuid.NewString(),
+		"iat":        now.Unix(),
+		"exp":        now.Add(emailVerificationExpiration).Unix(),
+	})
+	if err != nil {
+		s.logger.Error("Error signing email verification token",
+			ports.F("error", err),
+			ports.F("user_id", user.ID),
+		)
+		return "", errors.ErrTokenCreation
+	}
+
+	return strings.TrimRight(config.OIDC.Issuer, "/") + "/auth/email/verify?token=" + url.QueryEscape(token), nil
+}
+
+// unverifiedEmail returns the address of user that is waiting to be
+// confirmed, or an empty string if there is none
+func unverifiedEmail(user *entities.User) string {
+	if user.PendingEmail != "" {
+		return user.PendingEmail
+	}
+	if user.Email != "" && user.EmailVerifiedAt == nil {
+		return user.Email
+	}
+	return ""
+}
+
+func emailVerificationResendKey(userID, email string) string {
+	return "email_verification:" + userID + ":" + email + ":resend"
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEmailVerification tests that the link sent to a pending address confirms it
+func TestEmailVerification(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockEmail := mocks.NewMockEmailSender(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		email:  mockEmail,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	user := &entities.User{ID: uuid.New(), PhoneNumber: "09123456789", Email: "old@example.com", PendingEmail: "new@example.com"}
+	verifiedAt := time.Now()
+	user.EmailVerifiedAt = &verifiedAt
+	resendKey := emailVerificationResendKey(user.ID.String(), user.PendingEmail)
+
+	mockAuthRepo.On("FindUserByID", mock.Anything, user.ID).Return(user, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, resendKey).Return("", errors.ErrTokenNotFound).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, resendKey, "1", emailVerificationResendInterval).Return(nil).Once()
+
+	var body string
+	mockEmail.On("Send", mock.Anything, "new@example.com", mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { body = args.String(3) }).
+		Return(nil).Once()
+
+	err := service.RequestEmailVerification(context.Background(), user.ID.String())
+	require.NoError(t, err)
+
+	link := regexp.MustCompile(`\S+/auth/email/verify\?token=\S+`).FindString(body)
+	require.NotEmpty(t, link)
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+
+	mockAuthRepo.On("ConfirmEmail", mock.Anything, user.ID, "new@example.com").Return(nil).Once()
+
+	err = service.VerifyEmail(context.Background(), parsed.Query().Get("token"))
+
+	assert.NoError(t, err)
+}
+
+// TestRequestEmailVerification_NothingToVerify tests that a confirmed address without a pending one is refused
+func TestRequestEmailVerification_NothingToVerify(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		email:  mocks.NewMockEmailSender(t),
+		logger: newTestLogger(),
+	}
+
+	verifiedAt := time.Now()
+	user := &entities.User{ID: uuid.New(), Email: "user@example.com", EmailVerifiedAt: &verifiedAt}
+	mockAuthRepo.On("FindUserByID", mock.Anything, user.ID).Return(user, nil).Once()
+
+	err := service.RequestEmailVerification(context.Background(), user.ID.String())
+
+	assert.Equal(t, errors.ErrNoEmailToVerify, err)
+}
+
+// TestVerifyEmail_InvalidToken tests that other tokens and links to an address that was changed since are refused
+func TestVerifyEmail_InvalidToken(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	tokenSigner := newTestSigner(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		signer: tokenSigner,
+		logger: newTestLogger(),
+	}
+
+	userID := uuid.New()
+	sign := func(tokenType string) string {
+		token, err := tokenSigner.Sign(jwt.MapClaims{
+			"user_id":    userID.String(),
+			"email":      "user@example.com",
+			"token_type": tokenType,
+			"exp":        time.Now().Add(time.Hour).Unix(),
+		})
+		require.NoError(t, err)
+		return token
+	}
+
+	err := service.VerifyEmail(context.Background(), sign("access"))
+	assert.Equal(t, errors.ErrInvalidVerificationLink, err)
+
+	err = service.VerifyEmail(context.Background(), "not-a-token")
+	assert.Equal(t, errors.ErrInvalidVerificationLink, err)
+
+	mockAuthRepo.On("ConfirmEmail", mock.Anything, userID, "user@example.com").Return(errors.ErrUserNotFound).Once()
+	err = service.VerifyEmail(context.Background(), sign("email_verification"))
+	assert.Equal(t, errors.ErrInvalidVerificationLink, err)
+}
+
+// TestLogin_WithEmail tests that a verified email can be used instead of the phone number
+func TestLogin_WithEmail(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		mfa:    newTestMFARepositoryWithoutMFA(t),
+		audit:  newTestAuditRepository(t),
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
+	}
+
+	hashedPassword, _ := newTestHasher().Hash("Password123")
+	verifiedAt := time.Now()
+	user := &entities.User{ID: uuid.New(), PhoneNumber: "09123456789", Email: "user@example.com", EmailVerifiedAt: &verifiedAt, Password: hashedPassword, Status: entities.Active}
+	req := &dto.LoginRequest{Email: "User@Example.com", Password: "Password123"}
+
+	// The email and the account's phone number are both checked for a lock
+	expectLoginNotLocked(mockRedisRepo, "user@example.com", "")
+	expectLoginNotLocked(mockRedisRepo, user.PhoneNumber, "")
+	mockAuthRepo.On("FindUserByVerifiedEmail", mock.Anything, "user@example.com").Return(user, nil).Once()
+	expectLoginFailuresReset(mockRedisRepo, user.PhoneNumber)
+	mockRedisRepo.On("AddToSet", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Times(3)
+	expectSessionNotRevoked(mockRedisRepo)
+
+	tokens, err := service.Login(context.Background(), req)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	mockAuthRepo.AssertNotCalled(t, "FindUserByPhoneNumber", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+const (
+	loginFailureWindow = 1 * time.Hour
+	loginBackoffBase   = 1 * time.Second
+	loginLockDuration  = 15 * time.Minute
+)
+
+// loginThrottle counts failed password logins for one kind of subject. Once
+// backoffAfter failures are seen, every failure makes the subject wait twice
+// as long as the last one, and from lockAfter failures on it is locked for
+// loginLockDuration.
+type loginThrottle struct {
+	scope        string
+	backoffAfter int64
+	lockAfter    int64
+	lockedErr    *errors.CustomError
+}
+
+var (
+	phoneLoginThrottle = loginThrottle{
+		scope:        "phone",
+		backoffAfter: 3,
+		lockAfter:    10,
+		lockedErr:    errors.ErrAccountLocked,
+	}
+	// One address can be shared by many users behind a NAT, so it gets more
+	// room before it is slowed down
+	ipLoginThrottle = loginThrottle{
+		scope:        "ip",
+		backoffAfter: 20,
+		lockAfter:    100,
+		lockedErr:    errors.ErrTooManyLoginAttempts,
+	}
+)
+
+// delay returns how long the subject has to wait after its nth failure
+func (t loginThrottle) delay(failures int64) time.Duration {
+	if failures < t.backoffAfter {
+		return 0
+	}
+	if failures >= t.lockAfter {
+		return loginLockDuration
+	}
+	delay := loginBackoffBase << (failures - t.backoffAfter)
+	if delay > loginLockDuration {
+		return loginLockDuration
+	}
+	return delay
+}
+
+// checkLoginLock returns a lockout error if the login identifier, a phone
+// number or email, or the client address has to wait before trying another
+// password. The address is the one ClientInfoMiddleware resolved, which only
+// comes from X-Forwarded-For behind a trusted proxy, so clients can't pick a
+// fresh one for every attempt.
+func (s *AuthService) checkLoginLock(ctx context.Context, identifier string) error {
+	if err := checkThrottle(ctx, s.redis, phoneLoginThrottle, identifier); err != nil {
+		return err
+	}
+	if ip := entities.ClientInfoFromContext(ctx).IP; ip != "" {
+		return checkThrottle(ctx, s.redis, ipLoginThrottle, ip)
+	}
+	return nil
+}
+
+// recordLoginFailure counts a wrong password or unknown identifier. It
+// returns a lockout error if this failure locked the identifier or address.
+func (s *AuthService) recordLoginFailure(ctx context.Context, identifier string) error {
+	lockErr := recordThrottleFailure(ctx, s.redis, phoneLoginThrottle, identifier)
+	if lockErr != nil && !errors.IsRateLimitError(lockErr) {
+		return lockErr
+	}
+
+	if ip := entities.ClientInfoFromContext(ctx).IP; ip != "" {
+		ipErr := recordThrottleFailure(ctx, s.redis, ipLoginThrottle, ip)
+		if ipErr != nil && !errors.IsRateLimitError(ipErr) {
+			return ipErr
+		}
+		if lockErr == nil {
+			lockErr = ipErr
+		}
+	}
+
+	if lockErr != nil {
+		s.logger.Warn("Login temporarily locked",
+			ports.F("identifier", identifier),
+			ports.F("ip", entities.ClientInfoFromContext(ctx).IP),
+		)
+	}
+	return lockErr
+}
+
+// resetLoginFailures forgets the failures of a phone number after a
+// successful login. The client address keeps its count, otherwise logging
+// in to an account of their own would let an attacker keep guessing.
+func (s *AuthService) resetLoginFailures(ctx context.Context, phoneNumber string) error {
+	return clearThrottle(ctx, s.redis, phoneLoginThrottle, phoneNumber)
+}
+
+func checkThrottle(ctx context.Context, redis ports.InMemoryRespositoryContracts, throttle loginThrottle, subject string) error {
+	lockedUntil, err := redis.FindToken(ctx, loginLockKey(ctx, throttle.scope, subject))
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	until, err := strconv.ParseInt(lockedUntil, 10, 64)
+	if err != nil {
+		return nil
+	}
+	if wait := time.Until(time.UnixMilli(until)); wait > 0 {
+		return throttle.lockedErr.WithRetryAfter(wait)
+	}
+	return nil
+}
+
+func recordThrottleFailure(ctx context.Context, redis ports.InMemoryRespositoryContracts, throttle loginThrottle, subject string) error {
+	failures, err := redis.IncrementCounter(ctx, loginFailuresKey(ctx, throttle.scope, subject), loginFailureWindow)
+	if err != nil {
+		return err
+	}
+
+	delay := throttle.delay(failures)
+	if delay == 0 {
+		return nil
+	}
+
+	// The lock stores when it ends, so clients can be told how long to wait
+	until := time.Now().Add(delay)
+	if err := redis.AddToken(ctx, loginLockKey(ctx, throttle.scope, subject), strconv.FormatInt(until.UnixMilli(), 10), delay); err != nil {
+		return err
+	}
+	return throttle.lockedErr.WithRetryAfter(delay)
+}
+
+func clearThrottle(ctx context.Context, redis ports.InMemoryRespositoryContracts, throttle loginThrottle, subject string) error {
+	if err := redis.RemoveToken(ctx, loginFailuresKey(ctx, throttle.scope, subject)); err != nil {
+		return err
+	}
+	return redis.RemoveToken(ctx, loginLockKey(ctx, throttle.scope, subject))
+}
+
+// Failures are counted per organization, since the same phone number or
+// email can belong to users of different organizations
+func loginFailuresKey(ctx context.Context, scope, subject string) string {
+	return "login_failures:" + entities.OrganizationIDFromContext(ctx).String() + ":" + scope + ":" + subject
+}
+
+func loginLockKey(ctx context.Context, scope, subject string) string {
+	return "login_lock:" + entities.OrganizationIDFromContext(ctx).String() + ":" + scope + ":" + subject
+}
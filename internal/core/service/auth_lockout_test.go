@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// expectLoginNotLocked sets up the lock checks of a login from a phone number
+// and, if ip isn't empty, a client address that aren't locked
+func expectLoginNotLocked(mockRedisRepo *mocks.InMemoryRespositoryContracts, phoneNumber, ip string) {
+	mockRedisRepo.On("FindToken", mock.Anything, loginLockKey(context.Background(), "phone", phoneNumber)).Return("", errors.ErrTokenNotFound)
+	if ip != "" {
+		mockRedisRepo.On("FindToken", mock.Anything, loginLockKey(context.Background(), "ip", ip)).Return("", errors.ErrTokenNotFound)
+	}
+}
+
+// expectLoginFailuresReset sets up the reset of a phone number's failures
+// after its password was accepted
+func expectLoginFailuresReset(mockRedisRepo *mocks.InMemoryRespositoryContracts, phoneNumber string) {
+	mockRedisRepo.On("RemoveToken", mock.Anything, loginFailuresKey(context.Background(), "phone", phoneNumber)).Return(nil)
+	mockRedisRepo.On("RemoveToken", mock.Anything, loginLockKey(context.Background(), "phone", phoneNumber)).Return(nil)
+}
+
+// TestLoginThrottleDelay tests that the wait doubles after each failure and ends in a lockout
+func TestLoginThrottleDelay(t *testing.T) {
+	assert.Equal(t, time.Duration(0), phoneLoginThrottle.delay(2))
+	assert.Equal(t, 1*time.Second, phoneLoginThrottle.delay(3))
+	assert.Equal(t, 2*time.Second, phoneLoginThrottle.delay(4))
+	assert.Equal(t, 64*time.Second, phoneLoginThrottle.delay(9))
+	assert.Equal(t, loginLockDuration, phoneLoginThrottle.delay(10))
+	assert.Equal(t, loginLockDuration, phoneLoginThrottle.delay(25))
+
+	assert.Equal(t, time.Duration(0), ipLoginThrottle.delay(19))
+	assert.Equal(t, loginLockDuration, ipLoginThrottle.delay(100))
+}
+
+// TestLogin_Locked tests that a locked phone number is refused without checking the password
+func TestLogin_Locked(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
+	}
+
+	req := &dto.LoginRequest{PhoneNumber: "09123456789", Password: "password123"}
+	lockedUntil := time.Now().Add(10 * time.Minute).UnixMilli()
+	mockRedisRepo.On("FindToken", mock.Anything, loginLockKey(context.Background(), "phone", req.PhoneNumber)).Return(strconv.FormatInt(lockedUntil, 10), nil).Once()
+
+	tokens, err := service.Login(context.Background(), req)
+
+	assert.Nil(t, tokens)
+	require.True(t, errors.IsRateLimitError(err))
+	assert.Equal(t, errors.ErrAccountLocked.Message, err.(*errors.CustomError).Message)
+	retryAfter, ok := errors.RetryAfter(err)
+	assert.True(t, ok)
+	assert.InDelta(t, (10 * time.Minute).Seconds(), retryAfter.Seconds(), 1)
+	mockAuthRepo.AssertNotCalled(t, "FindUserByPhoneNumber", mock.Anything, mock.Anything)
+}
+
+// TestLogin_LockAfterFailures tests that the failure reaching the limit locks the phone number
+func TestLogin_LockAfterFailures(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
+	}
+
+	hashedPassword, _ := newTestHasher().Hash("Password123")
+	user := &entities.User{ID: uuid.New(), PhoneNumber: "09123456789", Password: hashedPassword}
+	req := &dto.LoginRequest{PhoneNumber: user.PhoneNumber, Password: "WrongPassword1"}
+	ctx := entities.WithClientInfo(context.Background(), entities.ClientInfo{IP: "10.0.0.1"})
+
+	expectLoginNotLocked(mockRedisRepo, req.PhoneNumber, "10.0.0.1")
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &req.PhoneNumber).Return(user, nil).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, loginFailuresKey(context.Background(), "phone", req.PhoneNumber), loginFailureWindow).Return(phoneLoginThrottle.lockAfter, nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, loginLockKey(context.Background(), "phone", req.PhoneNumber), mock.AnythingOfType("string"), loginLockDuration).Return(nil).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, loginFailuresKey(context.Background(), "ip", "10.0.0.1"), loginFailureWindow).Return(int64(1), nil).Once()
+
+	tokens, err := service.Login(ctx, req)
+
+	assert.Nil(t, tokens)
+	require.True(t, errors.IsRateLimitError(err))
+	assert.Equal(t, errors.ErrAccountLocked.Message, err.(*errors.CustomError).Message)
+	retryAfter, _ := errors.RetryAfter(err)
+	assert.Equal(t, loginLockDuration, retryAfter)
+}
+
+// TestLogin_UnknownPhoneNumberCounted tests that guesses against unregistered numbers are counted like wrong passwords
+func TestLogin_UnknownPhoneNumberCounted(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
+	}
+
+	req := &dto.LoginRequest{PhoneNumber: "09120000000", Password: "Password123"}
+
+	expectLoginNotLocked(mockRedisRepo, req.PhoneNumber, "")
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &req.PhoneNumber).Return(nil, errors.ErrUserNotFound).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, loginFailuresKey(context.Background(), "phone", req.PhoneNumber), loginFailureWindow).Return(int64(1), nil).Once()
+
+	_, err := service.Login(context.Background(), req)
+
+	assert.Equal(t, errors.ErrUserNotFound, err)
+}
+
+// TestLogin_ResetsFailures tests that a successful login clears the phone number's failures
+func TestLogin_ResetsFailures(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		mfa:    newTestMFARepositoryWithoutMFA(t),
+		audit:  newTestAuditRepository(t),
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
+	}
+
+	hashedPassword, _ := newTestHasher().Hash("Password123")
+	user := &entities.User{ID: uuid.New(), PhoneNumber: "09123456789", Password: hashedPassword, Status: entities.Active}
+	req := &dto.LoginRequest{PhoneNumber: user.PhoneNumber, Password: "Password123"}
+
+	expectLoginNotLocked(mockRedisRepo, req.PhoneNumber, "")
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &req.PhoneNumber).Return(user, nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, loginFailuresKey(context.Background(), "phone", req.PhoneNumber)).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, loginLockKey(context.Background(), "phone", req.PhoneNumber)).Return(nil).Once()
+	mockRedisRepo.On("AddToSet", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Times(3)
+	expectSessionNotRevoked(mockRedisRepo)
+
+	_, err := service.Login(context.Background(), req)
+
+	require.NoError(t, err)
+	mockRedisRepo.AssertNotCalled(t, "IncrementCounter", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestUnlockUser tests that an admin can clear the lock of a user's phone number and the unlock is recorded
+func TestUnlockUser(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockAuditRepo := mocks.NewMockAuditRepository(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		redis:  mockRedisRepo,
+		audit:  mockAuditRepo,
+		logger: newTestLogger(),
+	}
+
+	actor := &entities.Principal{UserID: uuid.New(), Role: entities.AdminRole}
+	userID := uuid.New()
+	user := &entities.User{ID: userID, PhoneNumber: "09123456789", Role: entities.UserRole}
+
+	mockAdminRepo.On("AdminGetUserByID", mock.Anything, &userID).Return(user, nil).Once()
+	expectLoginFailuresReset(mockRedisRepo, user.PhoneNumber)
+	mockAuditRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(event *entities.AuditEvent) bool {
+		return event.Type == entities.AuditUserUnlocked && *event.ActorID == actor.UserID && *event.TargetID == userID
+	})).Return(nil).Once()
+
+	err := service.UnlockUser(context.Background(), actor, &userID)
+
+	assert.NoError(t, err)
+	mockRedisRepo.AssertExpectations(t)
+}
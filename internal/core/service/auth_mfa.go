@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// TOTP parameters from RFC 6238, the defaults every authenticator app
+	// supports
+	totpIssuer     = "go_auth"
+	totpPeriod     = 30
+	totpDigits     = 6
+	totpSkew       = 1
+	totpSecretSize = 20
+
+	mfaEnrollmentExpiration = 10 * time.Minute
+	mfaTokenExpiration      = 5 * time.Minute
+	mfaMaxAttempts          = 5
+	recoveryCodeCount       = 10
+)
+
+// EnrollTOTP starts setting up an authenticator for the user. The secret is
+// kept aside until the user proves they added it with ConfirmTOTP.
+func (s *AuthService) EnrollTOTP(ctx context.Context, userID string) (*dto.TOTPEnrollmentResponse, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while enrolling TOTP",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.ErrInvalidUserID
+	}
+
+	if _, err := s.mfa.FindMFAByUserID(ctx, userUUID); err != errors.ErrMFANotEnabled {
+		if err == nil {
+			return nil, errors.ErrMFAAlreadyEnabled
+		}
+		return nil, err
+	}
+
+	user, err := s.db.FindUserByID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	secretBytes := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secretBytes); err != nil {
+		s.logger.Error("Error generating TOTP secret",
+			ports.F("error", err),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrGenerateMFASecret
+	}
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secretBytes)
+
+	key := mfaEnrollmentKey(userID)
+	if err := s.redis.AddToken(ctx, key, secret, mfaEnrollmentExpiration); err != nil {
+		return nil, err
+	}
+	if err := s.redis.RemoveToken(ctx, key+":attempts"); err != nil {
+		return nil, err
+	}
+
+	return &dto.TOTPEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: totpURI(user.PhoneNumber, secret),
+	}, nil
+}
+
+// ConfirmTOTP turns on two-factor authentication once the user entered a
+// code from the authenticator being set up, and returns their recovery codes
+func (s *AuthService) ConfirmTOTP(ctx context.Context, userID, code string) (*dto.RecoveryCodesResponse, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while confirming TOTP",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.ErrInvalidUserID
+	}
+
+	key := mfaEnrollmentKey(userID)
+	secret, err := s.redis.FindToken(ctx, key)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return nil, errors.ErrMFAEnrollmentNotFound
+		}
+		return nil, err
+	}
+
+	attempts, err := s.redis.IncrementCounter(ctx, key+":attempts", mfaEnrollmentExpiration)
+	if err != nil {
+		return nil, err
+	}
+	if attempts > mfaMaxAttempts {
+		s.logger.Warn("TOTP confirmation attempts exceeded",
+			ports.F("user_id", userID),
+		)
+		if err := s.redis.RemoveToken(ctx, key); err != nil {
+			return nil, err
+		}
+		return nil, errors.ErrMFAAttemptsExceeded
+	}
+
+	if err := s.useTOTPCode(ctx, userID, secret, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		s.logger.Error("Error generating recovery codes",
+			ports.F("error", err),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrGenerateMFASecret
+	}
+
+	mfa := &entities.UserMFA{
+		UserID:     userUUID,
+		TOTPSecret: secret,
+		EnabledAt:  time.Now(),
+	}
+	if err := s.mfa.EnableMFA(ctx, mfa, hashes); err != nil {
+		return nil, err
+	}
+
+	if err := s.redis.RemoveToken(ctx, key); err != nil {
+		return nil, err
+	}
+	if err := s.redis.RemoveToken(ctx, key+":attempts"); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Two-factor authentication enabled",
+		ports.F("user_id", userID),
+	)
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// VerifyMFA finishes a login started with a password or login code. The code
+// is either from the user's authenticator or one of their recovery codes.
+func (s *AuthService) VerifyMFA(ctx context.Context, req *dto.MFAVerifyRequest) (*entities.TokenPair, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while verifying MFA",
+			ports.F("error", ctx.Err()),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	user, claims, err := s.parseAndValidateToken(ctx, req.MFAToken, "mfa_pending")
+	if err != nil {
+		return nil, err
+	}
+	ctx = entities.WithOrganization(ctx, user.OrganizationID)
+
+	tokenID, _ := claims["jti"].(string)
+	if tokenID == "" {
+		return nil, errors.ErrInvalidToken
+	}
+	key := mfaLoginKey(tokenID)
+
+	attempts, err := s.redis.IncrementCounter(ctx, key+":attempts", mfaTokenExpiration)
+	if err != nil {
+		return nil, err
+	}
+	if attempts > mfaMaxAttempts {
+		s.logger.Warn("MFA attempts exceeded",
+			ports.F("user_id", user.ID),
+		)
+		return nil, errors.ErrMFAAttemptsExceeded
+	}
+
+	mfa, err := s.mfa.FindMFAByUserID(ctx, user.ID)
+	if err != nil {
+		if err == errors.ErrMFANotEnabled {
+			// MFA was reset since the password was checked, start over
+			return nil, errors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if len(req.Code) == totpDigits {
+		err = s.useTOTPCode(ctx, user.ID.String(), mfa.TOTPSecret, req.Code)
+	} else {
+		err = s.useRecoveryCode(ctx, user.ID, req.Code)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The MFA token can only be exchanged once
+	used, err := s.redis.IncrementCounter(ctx, key+":used", mfaTokenExpiration)
+	if err != nil {
+		return nil, err
+	}
+	if used > 1 {
+		s.logger.Warn("MFA token reused",
+			ports.F("user_id", user.ID),
+		)
+		return nil, errors.ErrInvalidToken
+	}
+
+	deviceName, _ := claims["device_name"].(string)
+	return s.createTokenPair(ctx, user, deviceName)
+}
+
+// completeLogin is called once the user's password or login code was
+// checked. It starts a session, or returns an MFA token to exchange with
+// VerifyMFA if the user has two-factor authentication enabled.
+func (s *AuthService) completeLogin(ctx context.Context, user *entities.User, deviceName string) (*entities.TokenPair, error) {
+	if _, err := s.mfa.FindMFAByUserID(ctx, user.ID); err != nil {
+		if err != errors.ErrMFANotEnabled {
+			return nil, err
+		}
+		return s.createTokenPair(ctx, user, deviceName)
+	}
+
+	now := time.Now()
+	mfaToken, err := s.signer.Sign(jwt.MapClaims{
+		"user_id":     user.ID.String(),
+		"org":         user.OrganizationID.String(),
+		"device_name": deviceName,
+		"token_type":  "mfa_pending",
+		"jti":         uuid.NewString(),
+		"iat":         now.Unix(),
+		"exp":         now.Add(mfaTokenExpiration).Unix(),
+	})
+	if err != nil {
+		s.logger.Error("Error signing MFA token",
+			ports.F("error", err),
+			ports.F("user_id", user.ID),
+		)
+		return nil, errors.ErrTokenCreation
+	}
+
+	return &entities.TokenPair{MFAToken: mfaToken}, nil
+}
+
+// useTOTPCode checks code against the authenticator secret. A code is only
+// accepted once, so one seen over someone's shoulder can't be replayed.
+func (s *AuthService) useTOTPCode(ctx context.Context, userID, secret, code string) error {
+	step, ok := checkTOTP(secret, code, time.Now())
+	if !ok {
+		s.logger.Warn("Invalid TOTP code",
+			ports.F("user_id", userID),
+		)
+		return errors.ErrInvalidMFACode
+	}
+
+	key := mfaUsedStepKey(userID)
+	lastStep, err := s.redis.FindToken(ctx, key)
+	if err != nil && !errors.IsNotFoundError(err) {
+		return err
+	}
+	if err == nil {
+		if last, err := strconv.ParseInt(lastStep, 10, 64); err == nil && step <= last {
+			s.logger.Warn("TOTP code reused",
+				ports.F("user_id", userID),
+			)
+			return errors.ErrInvalidMFACode
+		}
+	}
+
+	// Codes are only valid for a few periods, there is no need to remember
+	// the step for longer
+	return s.redis.AddToken(ctx, key, strconv.FormatInt(step, 10), (2*totpSkew+1)*totpPeriod*time.Second)
+}
+
+func (s *AuthService) useRecoveryCode(ctx context.Context, userID uuid.UUID, code string) error {
+	if err := s.mfa.UseRecoveryCode(ctx, userID, hashRecoveryCode(code)); err != nil {
+		if err == errors.ErrRecoveryCodeNotFound {
+			s.logger.Warn("Invalid recovery code",
+				ports.F("user_id", userID),
+			)
+			return errors.ErrInvalidMFACode
+		}
+		return err
+	}
+
+	s.logger.Info("Recovery code used",
+		ports.F("user_id", userID),
+	)
+	return nil
+}
+
+// checkTOTP reports whether code is valid at t, allowing for totpSkew periods
+// of clock drift, and returns the time step it matched
+func checkTOTP(secret, code string, t time.Time) (int64, bool) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := t.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if hmac.Equal([]byte(totpCode(key, step)), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// totpCode computes the HOTP value of RFC 4226 for a time step
+func totpCode(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, value%mod)
+}
+
+// totpURI builds the Key URI Format authenticator apps read from QR codes
+func totpURI(account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", totpIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", strconv.Itoa(totpDigits))
+	query.Set("period", strconv.Itoa(totpPeriod))
+
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + totpIssuer + ":" + account,
+		RawQuery: query.Encode(),
+	}).String()
+}
+
+// generateRecoveryCodes returns new recovery codes and the hashes to store
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		code := hex.EncodeToString(b)
+		code = code[:5] + "-" + code[5:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes a recovery code the way it was typed, ignoring
+// case and the dash
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(code, "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func mfaEnrollmentKey(userID string) string {
+	return "mfa_enrollment:" + userID
+}
+
+func mfaLoginKey(tokenID string) string {
+	return "mfa_login:" + tokenID
+}
+
+func mfaUsedStepKey(userID string) string {
+	return "mfa_totp_step:" + userID
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"encoding/base32"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testTOTPSecret is the shared secret of the RFC 6238 test vectors
+var testTOTPSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+// newTestMFARepositoryWithoutMFA returns an MFA repository for users who never
+// enabled two-factor authentication
+func newTestMFARepositoryWithoutMFA(t *testing.T) *mocks.MFARepository {
+	mockMFARepo := mocks.NewMockMFARepository(t)
+	mockMFARepo.On("FindMFAByUserID", mock.Anything, mock.Anything).Return(nil, errors.ErrMFANotEnabled)
+	return mockMFARepo
+}
+
+func currentTOTPCode(t *testing.T, secret string) string {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	require.NoError(t, err)
+	return totpCode(key, time.Now().Unix()/totpPeriod)
+}
+
+// TestCheckTOTP tests codes against the RFC 6238 SHA1 test vectors
+func TestCheckTOTP(t *testing.T) {
+	step, ok := checkTOTP(testTOTPSecret, "287082", time.Unix(59, 0))
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), step)
+
+	_, ok = checkTOTP(testTOTPSecret, "081804", time.Unix(1111111109, 0))
+	assert.True(t, ok)
+
+	// One period of clock drift is allowed, more isn't
+	_, ok = checkTOTP(testTOTPSecret, "081804", time.Unix(1111111109+totpPeriod, 0))
+	assert.True(t, ok)
+	_, ok = checkTOTP(testTOTPSecret, "081804", time.Unix(1111111109+3*totpPeriod, 0))
+	assert.False(t, ok)
+}
+
+// TestLogin_MFARequired tests that a user with MFA gets an MFA token instead of a session
+func TestLogin_MFARequired(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockMFARepo := mocks.NewMockMFARepository(t)
+
+	tokenSigner := newTestSigner(t)
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		mfa:    mockMFARepo,
+		signer: tokenSigner,
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
+	}
+
+	hashedPassword, _ := newTestHasher().Hash("Password123")
+	user := &entities.User{ID: uuid.New(), PhoneNumber: "09123456789", Password: hashedPassword}
+	req := &dto.LoginRequest{PhoneNumber: user.PhoneNumber, Password: "Password123", DeviceName: "Pixel 8"}
+
+	expectLoginNotLocked(mockRedisRepo, req.PhoneNumber, "")
+	expectLoginFailuresReset(mockRedisRepo, req.PhoneNumber)
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &req.PhoneNumber).Return(user, nil).Once()
+	mockMFARepo.On("FindMFAByUserID", mock.Anything, user.ID).Return(&entities.UserMFA{UserID: user.ID, TOTPSecret: testTOTPSecret}, nil).Once()
+
+	tokens, err := service.Login(context.Background(), req)
+
+	require.NoError(t, err)
+	assert.Empty(t, tokens.AccessToken)
+	assert.Empty(t, tokens.RefreshToken)
+
+	claims, err := tokenSigner.Verify(tokens.MFAToken)
+	require.NoError(t, err)
+	assert.Equal(t, "mfa_pending", claims["token_type"])
+	assert.Equal(t, user.ID.String(), claims["user_id"])
+	assert.Equal(t, "Pixel 8", claims["device_name"])
+}
+
+// TestVerifyMFA tests finishing a login with an authenticator code
+func TestVerifyMFA(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockMFARepo := mocks.NewMockMFARepository(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		mfa:    mockMFARepo,
+		audit:  newTestAuditRepository(t),
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	user := &entities.User{ID: uuid.New(), Status: entities.Active}
+	mfa := &entities.UserMFA{UserID: user.ID, TOTPSecret: testTOTPSecret}
+
+	mockMFARepo.On("FindMFAByUserID", mock.Anything, user.ID).Return(mfa, nil).Twice()
+	pending, err := service.completeLogin(context.Background(), user, "Pixel 8")
+	require.NoError(t, err)
+
+	mockAuthRepo.On("FindUserByID", mock.Anything, user.ID).Return(user, nil).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "mfa_login:") }), mfaTokenExpiration).Return(int64(1), nil).Twice()
+	mockRedisRepo.On("FindToken", mock.Anything, mfaUsedStepKey(user.ID.String())).Return("", errors.ErrTokenNotFound).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mfaUsedStepKey(user.ID.String()), mock.AnythingOfType("string"), mock.Anything).Return(nil).Once()
+	mockRedisRepo.On("AddToSet", mock.Anything, user.ID.String()+":sessions", mock.Anything, refreshTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "session:") }), mock.Anything, mock.Anything).Return(nil).Times(3)
+	expectSessionNotRevoked(mockRedisRepo)
+
+	tokens, err := service.VerifyMFA(context.Background(), &dto.MFAVerifyRequest{
+		MFAToken: pending.MFAToken,
+		Code:     currentTOTPCode(t, testTOTPSecret),
+	})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.Empty(t, tokens.MFAToken)
+}
+
+// TestVerifyMFA_RecoveryCode tests that codes in the recovery code format are checked against the stored recovery codes
+func TestVerifyMFA_RecoveryCode(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockMFARepo := mocks.NewMockMFARepository(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		mfa:    mockMFARepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	user := &entities.User{ID: uuid.New(), Status: entities.Active}
+	mfa := &entities.UserMFA{UserID: user.ID, TOTPSecret: testTOTPSecret}
+
+	mockMFARepo.On("FindMFAByUserID", mock.Anything, user.ID).Return(mfa, nil).Twice()
+	pending, err := service.completeLogin(context.Background(), user, "")
+	require.NoError(t, err)
+
+	mockAuthRepo.On("FindUserByID", mock.Anything, user.ID).Return(user, nil).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, mock.Anything, mfaTokenExpiration).Return(int64(1), nil).Once()
+	mockMFARepo.On("UseRecoveryCode", mock.Anything, user.ID, hashRecoveryCode("abcde12345")).Return(errors.ErrRecoveryCodeNotFound).Once()
+
+	tokens, err := service.VerifyMFA(context.Background(), &dto.MFAVerifyRequest{
+		MFAToken: pending.MFAToken,
+		Code:     "ABCDE-12345",
+	})
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, errors.ErrInvalidMFACode, err)
+}
+
+// TestVerifyMFA_CodeReused tests that an authenticator code can't be used twice
+func TestVerifyMFA_CodeReused(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		redis:  mockRedisRepo,
+		logger: newTestLogger(),
+	}
+
+	userID := uuid.NewString()
+	step := strconv.FormatInt(time.Now().Unix()/totpPeriod+totpSkew, 10)
+	mockRedisRepo.On("FindToken", mock.Anything, mfaUsedStepKey(userID)).Return(step, nil).Once()
+
+	err := service.useTOTPCode(context.Background(), userID, testTOTPSecret, currentTOTPCode(t, testTOTPSecret))
+	assert.Equal(t, errors.ErrInvalidMFACode, err)
+}
+
+// TestConfirmTOTP tests that confirming an authenticator enables MFA with hashed recovery codes
+func TestConfirmTOTP(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockMFARepo := mocks.NewMockMFARepository(t)
+
+	service := &AuthService{
+		redis:  mockRedisRepo,
+		mfa:    mockMFARepo,
+		logger: newTestLogger(),
+	}
+
+	userID := uuid.New()
+	key := mfaEnrollmentKey(userID.String())
+
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return(testTOTPSecret, nil).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, key+":attempts", mfaEnrollmentExpiration).Return(int64(1), nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, mfaUsedStepKey(userID.String())).Return("", errors.ErrTokenNotFound).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mfaUsedStepKey(userID.String()), mock.AnythingOfType("string"), mock.Anything).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":attempts").Return(nil).Once()
+
+	var storedHashes []string
+	mockMFARepo.On("EnableMFA", mock.Anything, mock.MatchedBy(func(mfa *entities.UserMFA) bool {
+		return mfa.UserID == userID && mfa.TOTPSecret == testTOTPSecret
+	}), mock.Anything).Run(func(args mock.Arguments) { storedHashes = args.Get(2).([]string) }).Return(nil).Once()
+
+	resp, err := service.ConfirmTOTP(context.Background(), userID.String(), currentTOTPCode(t, testTOTPSecret))
+
+	require.NoError(t, err)
+	require.Len(t, resp.RecoveryCodes, recoveryCodeCount)
+	require.Len(t, storedHashes, recoveryCodeCount)
+	for i, code := range resp.RecoveryCodes {
+		assert.Equal(t, hashRecoveryCode(code), storedHashes[i])
+	}
+}
+
+// TestEnrollTOTP_AlreadyEnabled tests that a second authenticator can't replace the first
+func TestEnrollTOTP_AlreadyEnabled(t *testing.T) {
+	mockMFARepo := mocks.NewMockMFARepository(t)
+
+	service := &AuthService{
+		mfa:    mockMFARepo,
+		logger: newTestLogger(),
+	}
+
+	userID := uuid.New()
+	mockMFARepo.On("FindMFAByUserID", mock.Anything, userID).Return(&entities.UserMFA{UserID: userID}, nil).Once()
+
+	resp, err := service.EnrollTOTP(context.Background(), userID.String())
+
+	assert.Nil(t, resp)
+	assert.Equal(t, errors.ErrMFAAlreadyEnabled, err)
+}
@@ -0,0 +1,630 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	authorizationCodeExpiration = 1 * time.Minute
+	idTokenExpiration           = 1 * time.Hour
+)
+
+// authorizationGrant is what the user approved at the authorization
+// endpoint. It is stored under the hash of its authorization code until the
+// client redeems the code at the token endpoint.
+type authorizationGrant struct {
+	ClientID       string    `json:"client_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	RedirectURI    string    `json:"redirect_uri"`
+	Scope          string    `json:"scope"`
+	Nonce          string    `json:"nonce,omitempty"`
+	CodeChallenge  string    `json:"code_challenge"`
+	AuthTime       time.Time `json:"auth_time"`
+}
+
+// OpenIDConfiguration returns the OpenID Connect discovery document
+func (s *AuthService) OpenIDConfiguration(ctx context.Context) (*dto.OpenIDConfiguration, error) {
+	if ctx.Err() != nil {
+		return nil, errors.ErrContextCancelled
+	}
+
+	config, err := config.LoadConfig()
+	if err != nil {
+		return nil, errors.ErrLoadConfig
+	}
+
+	issuer := strings.TrimSuffix(config.OIDC.Issuer, "/")
+	algorithm := config.JWT.Algorithm
+	if algorithm == "" {
+		algorithm = jwt.SigningMethodHS256.Alg()
+	}
+
+	return &dto.OpenIDConfiguration{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/authorize",
+		TokenEndpoint:                     issuer + "/token",
+		UserinfoEndpoint:                  issuer + "/userinfo",
+		JWKSURI:                           issuer + "/.well-known/jwks.json",
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{entities.GrantTypeAuthorizationCode, entities.GrantTypeRefreshToken, entities.GrantTypeClientCredentials},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{algorithm},
+		ScopesSupported:                   entities.SupportedScopes,
+		ClaimsSupported:                   []string{"sub", "name", "given_name", "family_name", "email", "email_verified", "phone_number"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+	}, nil
+}
+
+// Authorize issues an authorization code for the signed in user and returns
+// the client redirect URI that delivers it. Once the client and its redirect
+// URI are known to be valid, a rejected request also returns a redirect URI,
+// one that reports the error to the client.
+func (s *AuthService) Authorize(ctx context.Context, userID string, req *dto.AuthorizeRequest) (string, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while authorizing client",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+			ports.F("client_id", req.ClientID),
+		)
+		return "", errors.ErrContextCancelled
+	}
+
+	client, err := s.clients.FindClientByID(ctx, req.ClientID)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return "", errors.ErrInvalidClient
+		}
+		return "", err
+	}
+
+	if !client.HasRedirectURI(req.RedirectURI) {
+		s.logger.Error("Redirect URI is not registered",
+			ports.F("client_id", client.ID),
+			ports.F("redirect_uri", req.RedirectURI),
+		)
+		return "", errors.ErrInvalidRedirectURI
+	}
+
+	if req.ResponseType != "code" {
+		return authorizationErrorURI(req, errors.ErrUnsupportedResponseType), errors.ErrUnsupportedResponseType
+	}
+
+	if !client.AllowsGrantType(entities.GrantTypeAuthorizationCode) {
+		return authorizationErrorURI(req, errors.ErrUnauthorizedClient), errors.ErrUnauthorizedClient
+	}
+
+	scope, err := checkScope(client, req.Scope)
+	if err != nil {
+		return authorizationErrorURI(req, err), err
+	}
+
+	if req.CodeChallengeMethod != "S256" || !validCodeChallenge(req.CodeChallenge) {
+		return authorizationErrorURI(req, errors.ErrInvalidCodeChallenge), errors.ErrInvalidCodeChallenge
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return "", errors.ErrInvalidUserID
+	}
+
+	user, err := s.db.FindUserByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if user.Status != entities.Active {
+		s.logger.Error("Inactive user tried to authorize client",
+			ports.F("user_id", userID),
+			ports.F("client_id", client.ID),
+		)
+		return "", errors.ErrAccountDeactivated
+	}
+
+	code, err := randomToken()
+	if err != nil {
+		s.logger.Error("Error generating authorization code",
+			ports.F("error", err),
+		)
+		return "", errors.ErrTokenCreation
+	}
+
+	grant, err := json.Marshal(&authorizationGrant{
+		ClientID:       client.ID,
+		UserID:         user.ID,
+		OrganizationID: user.OrganizationID,
+		RedirectURI:    req.RedirectURI,
+		Scope:          scope,
+		Nonce:          req.Nonce,
+		CodeChallenge:  req.CodeChallenge,
+		AuthTime:       time.Now(),
+	})
+	if err != nil {
+		return "", errors.ErrAddToken
+	}
+
+	if err := s.redis.AddToken(ctx, authorizationCodeKey(code), string(grant), authorizationCodeExpiration); err != nil {
+		return "", err
+	}
+
+	s.logger.Info("Authorization code issued",
+		ports.F("user_id", userID),
+		ports.F("client_id", client.ID),
+		ports.F("scope", scope),
+	)
+
+	params := url.Values{"code": {code}}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+	return withQuery(req.RedirectURI, params), nil
+}
+
+// ExchangeToken implements the OAuth token endpoint. It redeems an
+// authorization code for an access, refresh and ID token, refreshes the
+// tokens of a client's session, or issues a token to a client acting on its
+// own behalf.
+func (s *AuthService) ExchangeToken(ctx context.Context, req *dto.TokenRequest) (*dto.TokenResponse, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while exchanging token",
+			ports.F("error", ctx.Err()),
+			ports.F("client_id", req.ClientID),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GrantType {
+	case entities.GrantTypeAuthorizationCode, entities.GrantTypeRefreshToken, entities.GrantTypeClientCredentials:
+	default:
+		return nil, errors.ErrUnsupportedGrantType
+	}
+
+	if !client.AllowsGrantType(req.GrantType) {
+		s.logger.Error("Client is not allowed to use grant type",
+			ports.F("client_id", client.ID),
+			ports.F("grant_type", req.GrantType),
+		)
+		return nil, errors.ErrUnauthorizedClient
+	}
+
+	switch req.GrantType {
+	case entities.GrantTypeAuthorizationCode:
+		return s.exchangeAuthorizationCode(ctx, client, req)
+	case entities.GrantTypeRefreshToken:
+		return s.exchangeRefreshToken(ctx, client, req)
+	default:
+		return s.exchangeClientCredentials(ctx, client, req)
+	}
+}
+
+// UserInfo returns the claims about the user that the session's scope
+// allows. Sessions started directly with go_auth see every claim.
+func (s *AuthService) UserInfo(ctx context.Context, userID, sessionID string) (map[string]interface{}, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while getting user info",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	session, err := s.findSession(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.db.FindUserByID(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return userClaims(user, session.Scope), nil
+}
+
+// OAuthErrorCode returns the OAuth 2.0 error code that describes err
+func OAuthErrorCode(err error) string {
+	switch err {
+	case errors.ErrInvalidClient:
+		return "invalid_client"
+	case errors.ErrInvalidGrant, errors.ErrInvalidToken, errors.ErrRefreshTokenReused,
+		errors.ErrSessionNotFound, errors.ErrTokenNotFound, errors.ErrUserNotFound,
+		errors.ErrInvalidCredentials, errors.ErrAccountDeactivated:
+		return "invalid_grant"
+	case errors.ErrUnsupportedGrantType:
+		return "unsupported_grant_type"
+	case errors.ErrUnauthorizedClient:
+		return "unauthorized_client"
+	case errors.ErrUnsupportedResponseType:
+		return "unsupported_response_type"
+	case errors.ErrInvalidScope:
+		return "invalid_scope"
+	}
+
+	if errors.IsValidationError(err) {
+		return "invalid_request"
+	}
+	return "server_error"
+}
+
+func (s *AuthService) exchangeAuthorizationCode(ctx context.Context, client *entities.OAuthClient, req *dto.TokenRequest) (*dto.TokenResponse, error) {
+	grant, err := s.redeemAuthorizationCode(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	if grant.ClientID != client.ID || grant.RedirectURI != req.RedirectURI {
+		s.logger.Error("Authorization code was issued for another client or redirect URI",
+			ports.F("client_id", client.ID),
+			ports.F("redirect_uri", req.RedirectURI),
+		)
+		return nil, errors.ErrInvalidGrant
+	}
+
+	if !verifyCodeChallenge(req.CodeVerifier, grant.CodeChallenge) {
+		s.logger.Error("PKCE verification failed",
+			ports.F("client_id", client.ID),
+			ports.F("user_id", grant.UserID),
+		)
+		return nil, errors.ErrInvalidGrant
+	}
+
+	// Clients redeem codes without naming an organization
+	ctx = entities.WithOrganization(ctx, grant.OrganizationID)
+	user, err := s.db.FindUserByID(ctx, grant.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Status != entities.Active {
+		s.logger.Error("Authorization code redeemed for inactive user",
+			ports.F("user_id", user.ID),
+			ports.F("client_id", client.ID),
+		)
+		return nil, errors.ErrInvalidGrant
+	}
+
+	session, err := s.createSession(ctx, user, client.Name)
+	if err != nil {
+		return nil, err
+	}
+	session.ClientID = client.ID
+	session.Scope = grant.Scope
+
+	// Recorded before the tokens are issued, so a replay that comes in
+	// meanwhile revokes the session before it is saved
+	if err := s.recordCodeRedemption(ctx, req.Code, session); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.issueTokenPair(ctx, user, session)
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, err := s.createIDToken(user, client, grant)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Authorization code redeemed",
+		ports.F("user_id", user.ID),
+		ports.F("client_id", client.ID),
+		ports.F("session_id", session.ID),
+	)
+
+	return &dto.TokenResponse{
+		AccessToken:  tokens.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(accessTokenExpiration.Seconds()),
+		RefreshToken: tokens.RefreshToken,
+		IDToken:      idToken,
+		Scope:        grant.Scope,
+	}, nil
+}
+
+func (s *AuthService) exchangeRefreshToken(ctx context.Context, client *entities.OAuthClient, req *dto.TokenRequest) (*dto.TokenResponse, error) {
+	claims, err := s.ParseToken(req.RefreshToken)
+	if err != nil {
+		return nil, errors.ErrInvalidGrant
+	}
+
+	// A client may only refresh the sessions that were issued to it
+	if clientID, _ := claims["client_id"].(string); clientID != client.ID {
+		s.logger.Error("Refresh token was issued to another client",
+			ports.F("client_id", client.ID),
+		)
+		return nil, errors.ErrInvalidGrant
+	}
+
+	tokens, err := s.RefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, _ := claims["scope"].(string)
+
+	return &dto.TokenResponse{
+		AccessToken:  tokens.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(accessTokenExpiration.Seconds()),
+		RefreshToken: tokens.RefreshToken,
+		Scope:        scope,
+	}, nil
+}
+
+// authenticateClient identifies the client calling the token endpoint.
+// Confidential clients must prove their identity with their secret, public
+// clients are bound to their grants by PKCE instead.
+func (s *AuthService) authenticateClient(ctx context.Context, clientID, clientSecret string) (*entities.OAuthClient, error) {
+	if clientID == "" {
+		return nil, errors.ErrInvalidClient
+	}
+
+	client, err := s.clients.FindClientByID(ctx, clientID)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return nil, errors.ErrInvalidClient
+		}
+		return nil, err
+	}
+
+	if client.IsConfidential() {
+		if err := bcrypt.CompareHashAndPassword([]byte(client.SecretHash), []byte(clientSecret)); err != nil {
+			s.logger.Error("Invalid client secret",
+				ports.F("client_id", clientID),
+			)
+			return nil, errors.ErrInvalidClient
+		}
+	}
+
+	return client, nil
+}
+
+// checkSessionClient checks that the client a session was issued to is still
+// registered and still allowed to refresh it. The client is loaded again, as
+// it may have been deleted or lost the grant since the session started.
+func (s *AuthService) checkSessionClient(ctx context.Context, clientID string) error {
+	client, err := s.clients.FindClientByID(ctx, clientID)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return errors.ErrInvalidClient
+		}
+		return err
+	}
+
+	if !client.AllowsGrantType(entities.GrantTypeRefreshToken) {
+		s.logger.Error("Client is no longer allowed to refresh its sessions",
+			ports.F("client_id", clientID),
+		)
+		return errors.ErrUnauthorizedClient
+	}
+	return nil
+}
+
+// redeemAuthorizationCode returns the grant of code and makes sure the code
+// can't be redeemed a second time. Taking the code is what redeems it, so
+// only one of concurrent redemptions gets the grant.
+func (s *AuthService) redeemAuthorizationCode(ctx context.Context, code string) (*authorizationGrant, error) {
+	if code == "" {
+		return nil, errors.ErrInvalidGrant
+	}
+
+	data, err := s.redis.TakeToken(ctx, authorizationCodeKey(code))
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return nil, s.revokeCodeSession(ctx, code)
+		}
+		return nil, err
+	}
+
+	var grant authorizationGrant
+	if err := json.Unmarshal([]byte(data), &grant); err != nil {
+		s.logger.Error("Error decoding authorization grant",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrGetToken
+	}
+
+	return &grant, nil
+}
+
+// recordCodeRedemption remembers the session started with code, for as long
+// as the tokens issued for it can live, so a replay of the code can revoke it
+func (s *AuthService) recordCodeRedemption(ctx context.Context, code string, session *entities.Session) error {
+	return s.redis.AddToken(ctx, redeemedCodeKey(code), session.UserID.String()+":"+session.ID.String(), refreshTokenExpiration)
+}
+
+// revokeCodeSession is called when code is redeemed but unknown. If it was
+// redeemed before it has most likely been intercepted, so the session
+// started with it is revoked as RFC 6749 section 4.1.2 recommends.
+func (s *AuthService) revokeCodeSession(ctx context.Context, code string) error {
+	redemption, err := s.redis.FindToken(ctx, redeemedCodeKey(code))
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return errors.ErrInvalidGrant
+		}
+		return err
+	}
+
+	userID, sessionID, _ := strings.Cut(redemption, ":")
+	s.logger.Warn("Authorization code redeemed more than once, revoking its session",
+		ports.F("security_event", "authorization_code_reuse"),
+		ports.F("user_id", userID),
+		ports.F("session_id", sessionID),
+		ports.F("ip", entities.ClientInfoFromContext(ctx).IP),
+	)
+	if err := removeSession(ctx, s.redis, userID, sessionID); err != nil {
+		return err
+	}
+	return errors.ErrInvalidGrant
+}
+
+// createIDToken signs the OpenID Connect ID token that tells client who the
+// user is
+func (s *AuthService) createIDToken(user *entities.User, client *entities.OAuthClient, grant *authorizationGrant) (string, error) {
+	config, err := config.LoadConfig()
+	if err != nil {
+		return "", errors.ErrLoadConfig
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":        strings.TrimSuffix(config.OIDC.Issuer, "/"),
+		"aud":        client.ID,
+		"iat":        now.Unix(),
+		"exp":        now.Add(idTokenExpiration).Unix(),
+		"auth_time":  grant.AuthTime.Unix(),
+		"token_type": "id",
+		"org":        user.OrganizationID.String(),
+	}
+	if grant.Nonce != "" {
+		claims["nonce"] = grant.Nonce
+	}
+	for name, value := range userClaims(user, grant.Scope) {
+		claims[name] = value
+	}
+
+	return s.signer.Sign(claims)
+}
+
+// checkScope validates a space separated scope request and returns it
+// without duplicates
+func checkScope(client *entities.OAuthClient, scope string) (string, error) {
+	var scopes []string
+	for _, requested := range strings.Fields(scope) {
+		if !slices.Contains(entities.SupportedScopes, requested) || !client.AllowsScope(requested) {
+			return "", errors.ErrInvalidScope
+		}
+		if !slices.Contains(scopes, requested) {
+			scopes = append(scopes, requested)
+		}
+	}
+
+	if !slices.Contains(scopes, entities.ScopeOpenID) {
+		return "", errors.ErrInvalidScope
+	}
+
+	return strings.Join(scopes, " "), nil
+}
+
+// userClaims returns the standard claims about user covered by scope. An
+// empty scope covers every claim.
+func userClaims(user *entities.User, scope string) map[string]interface{} {
+	scopes := strings.Fields(scope)
+	allows := func(s string) bool {
+		return scope == "" || slices.Contains(scopes, s)
+	}
+
+	claims := map[string]interface{}{
+		"sub": user.ID.String(),
+	}
+
+	if allows(entities.ScopeProfile) {
+		if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+			claims["name"] = name
+		}
+		if user.FirstName != "" {
+			claims["given_name"] = user.FirstName
+		}
+		if user.LastName != "" {
+			claims["family_name"] = user.LastName
+		}
+	}
+	if allows(entities.ScopeEmail) && user.Email != "" {
+		claims["email"] = user.Email
+		claims["email_verified"] = user.EmailVerifiedAt != nil
+	}
+	if allows(entities.ScopePhone) && user.PhoneNumber != "" {
+		claims["phone_number"] = user.PhoneNumber
+	}
+
+	return claims
+}
+
+func authorizationCodeKey(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return "oauth_code:" + hex.EncodeToString(sum[:])
+}
+
+func redeemedCodeKey(code string) string {
+	return authorizationCodeKey(code) + ":redeemed"
+}
+
+// authorizationErrorURI returns the redirect URI that reports err to the client
+func authorizationErrorURI(req *dto.AuthorizeRequest, err error) string {
+	params := url.Values{"error": {OAuthErrorCode(err)}}
+	if e, ok := err.(*errors.CustomError); ok {
+		params.Set("error_description", e.Message.English)
+	}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+	return withQuery(req.RedirectURI, params)
+}
+
+// withQuery adds params to the query of a registered redirect URI
+func withQuery(uri string, params url.Values) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// validCodeChallenge reports whether challenge is a base64url encoded SHA-256 hash
+func validCodeChallenge(challenge string) bool {
+	decoded, err := base64.RawURLEncoding.DecodeString(challenge)
+	return err == nil && len(decoded) == sha256.Size
+}
+
+// verifyCodeChallenge checks a PKCE code verifier against the S256 challenge
+// sent to the authorization endpoint
+func verifyCodeChallenge(verifier, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
+
+// randomToken returns 256 random bits encoded for use in URLs
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,434 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testCodeVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+func testCodeChallenge() string {
+	sum := sha256.Sum256([]byte(testCodeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func newTestOAuthClient() *entities.OAuthClient {
+	return &entities.OAuthClient{
+		ID:           "test-client",
+		Name:         "Test App",
+		RedirectURIs: []string{"https://app.example.com/callback"},
+		Scopes:       entities.SupportedScopes,
+		GrantTypes:   []string{entities.GrantTypeAuthorizationCode, entities.GrantTypeRefreshToken},
+	}
+}
+
+func newTestAuthorizeRequest() *dto.AuthorizeRequest {
+	return &dto.AuthorizeRequest{
+		ResponseType:        "code",
+		ClientID:            "test-client",
+		RedirectURI:         "https://app.example.com/callback",
+		Scope:               "openid profile",
+		State:               "xyz",
+		Nonce:               "n-0S6_WzA2Mj",
+		CodeChallenge:       testCodeChallenge(),
+		CodeChallengeMethod: "S256",
+	}
+}
+
+// TestAuthorize tests that an authorization code is stored and sent back to the client
+func TestAuthorize(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockClientRepo := mocks.NewMockOAuthClientRepository(t)
+
+	service := &AuthService{
+		db:      mockAuthRepo,
+		redis:   mockRedisRepo,
+		clients: mockClientRepo,
+		signer:  newTestSigner(t),
+		logger:  newTestLogger(),
+	}
+
+	user := &entities.User{ID: uuid.New(), Status: entities.Active}
+	req := newTestAuthorizeRequest()
+
+	var storedKey, storedGrant string
+	mockClientRepo.On("FindClientByID", mock.Anything, "test-client").Return(newTestOAuthClient(), nil).Once()
+	mockAuthRepo.On("FindUserByID", mock.Anything, user.ID).Return(user, nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.Anything, mock.Anything, authorizationCodeExpiration).
+		Run(func(args mock.Arguments) {
+			storedKey = args.String(1)
+			storedGrant = args.String(2)
+		}).Return(nil).Once()
+
+	redirectURI, err := service.Authorize(context.Background(), user.ID.String(), req)
+
+	require.NoError(t, err)
+	location, err := url.Parse(redirectURI)
+	require.NoError(t, err)
+	assert.Equal(t, "app.example.com", location.Host)
+	assert.Equal(t, "xyz", location.Query().Get("state"))
+
+	code := location.Query().Get("code")
+	require.NotEmpty(t, code)
+	// Only the hash of the code is used as the key
+	assert.Equal(t, authorizationCodeKey(code), storedKey)
+	assert.NotContains(t, storedKey, code)
+
+	var grant authorizationGrant
+	require.NoError(t, json.Unmarshal([]byte(storedGrant), &grant))
+	assert.Equal(t, user.ID, grant.UserID)
+	assert.Equal(t, "openid profile", grant.Scope)
+	assert.Equal(t, req.Nonce, grant.Nonce)
+}
+
+// TestAuthorize_UnregisteredRedirectURI tests that unknown redirect URIs are never redirected to
+func TestAuthorize_UnregisteredRedirectURI(t *testing.T) {
+	mockClientRepo := mocks.NewMockOAuthClientRepository(t)
+
+	service := &AuthService{
+		clients: mockClientRepo,
+		signer:  newTestSigner(t),
+		logger:  newTestLogger(),
+	}
+
+	req := newTestAuthorizeRequest()
+	req.RedirectURI = "https://evil.example.com/callback"
+
+	mockClientRepo.On("FindClientByID", mock.Anything, "test-client").Return(newTestOAuthClient(), nil).Once()
+
+	redirectURI, err := service.Authorize(context.Background(), uuid.NewString(), req)
+
+	assert.Empty(t, redirectURI)
+	assert.Equal(t, errors.ErrInvalidRedirectURI, err)
+}
+
+// TestAuthorize_MissingPKCE tests that a request without a PKCE challenge is reported to the client
+func TestAuthorize_MissingPKCE(t *testing.T) {
+	mockClientRepo := mocks.NewMockOAuthClientRepository(t)
+
+	service := &AuthService{
+		clients: mockClientRepo,
+		signer:  newTestSigner(t),
+		logger:  newTestLogger(),
+	}
+
+	req := newTestAuthorizeRequest()
+	req.CodeChallenge = ""
+	req.CodeChallengeMethod = ""
+
+	mockClientRepo.On("FindClientByID", mock.Anything, "test-client").Return(newTestOAuthClient(), nil).Once()
+
+	redirectURI, err := service.Authorize(context.Background(), uuid.NewString(), req)
+
+	assert.Equal(t, errors.ErrInvalidCodeChallenge, err)
+	location, parseErr := url.Parse(redirectURI)
+	require.NoError(t, parseErr)
+	assert.Equal(t, "invalid_request", location.Query().Get("error"))
+	assert.Equal(t, "xyz", location.Query().Get("state"))
+	assert.Empty(t, location.Query().Get("code"))
+}
+
+// TestExchangeToken_AuthorizationCode tests redeeming a code for tokens with PKCE
+func TestExchangeToken_AuthorizationCode(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockClientRepo := mocks.NewMockOAuthClientRepository(t)
+
+	tokenSigner := newTestSigner(t)
+	service := &AuthService{
+		db:      mockAuthRepo,
+		redis:   mockRedisRepo,
+		clients: mockClientRepo,
+		signer:  tokenSigner,
+		logger:  newTestLogger(),
+	}
+
+	user := &entities.User{
+		ID:          uuid.New(),
+		PhoneNumber: "09123456789",
+		FirstName:   "Sara",
+		LastName:    "Ahmadi",
+		Email:       "sara@example.com",
+		Status:      entities.Active,
+	}
+	grant := encodeTestGrant(t, &authorizationGrant{
+		ClientID:      "test-client",
+		UserID:        user.ID,
+		RedirectURI:   "https://app.example.com/callback",
+		Scope:         "openid profile",
+		Nonce:         "n-0S6_WzA2Mj",
+		CodeChallenge: testCodeChallenge(),
+		AuthTime:      time.Now(),
+	})
+	key := authorizationCodeKey("the-code")
+
+	mockClientRepo.On("FindClientByID", mock.Anything, "test-client").Return(newTestOAuthClient(), nil).Once()
+	mockRedisRepo.On("TakeToken", mock.Anything, key).Return(grant, nil).Once()
+	mockAuthRepo.On("FindUserByID", mock.Anything, user.ID).Return(user, nil).Once()
+	mockRedisRepo.On("AddToSet", mock.Anything, user.ID.String()+":sessions", mock.Anything, refreshTokenExpiration).Return(nil).Once()
+	var redemption string
+	mockRedisRepo.On("AddToken", mock.Anything, key+":redeemed", mock.Anything, refreshTokenExpiration).
+		Run(func(args mock.Arguments) { redemption = args.String(2) }).Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasSuffix(key, ":access") }), mock.Anything, accessTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasSuffix(key, ":refresh") }), mock.Anything, refreshTokenExpiration).Return(nil).Once()
+	var storedSession string
+	mockRedisRepo.On("AddToken", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.Count(key, ":") == 1 }), mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { storedSession = args.String(2) }).Return(nil).Once()
+	expectSessionNotRevoked(mockRedisRepo)
+
+	resp, err := service.ExchangeToken(context.Background(), &dto.TokenRequest{
+		GrantType:    "authorization_code",
+		Code:         "the-code",
+		RedirectURI:  "https://app.example.com/callback",
+		CodeVerifier: testCodeVerifier,
+		ClientID:     "test-client",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", resp.TokenType)
+	assert.Equal(t, "openid profile", resp.Scope)
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.NotEmpty(t, resp.RefreshToken)
+
+	// The session remembers which client it was issued to
+	var session entities.Session
+	require.NoError(t, json.Unmarshal([]byte(storedSession), &session))
+	assert.Equal(t, "test-client", session.ClientID)
+	assert.Equal(t, "Test App", session.DeviceName)
+	// and the code remembers the session it started
+	assert.Equal(t, user.ID.String()+":"+session.ID.String(), redemption)
+
+	accessClaims, err := tokenSigner.Verify(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "test-client", accessClaims["client_id"])
+
+	idClaims, err := tokenSigner.Verify(resp.IDToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID.String(), idClaims["sub"])
+	assert.Equal(t, "test-client", idClaims["aud"])
+	assert.Equal(t, "n-0S6_WzA2Mj", idClaims["nonce"])
+	assert.Equal(t, "Sara Ahmadi", idClaims["name"])
+	// The email scope was not granted
+	assert.NotContains(t, idClaims, "email")
+}
+
+// TestExchangeToken_WrongCodeVerifier tests that a code can't be redeemed without its PKCE verifier
+func TestExchangeToken_WrongCodeVerifier(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockClientRepo := mocks.NewMockOAuthClientRepository(t)
+
+	service := &AuthService{
+		redis:   mockRedisRepo,
+		clients: mockClientRepo,
+		signer:  newTestSigner(t),
+		logger:  newTestLogger(),
+	}
+
+	grant := encodeTestGrant(t, &authorizationGrant{
+		ClientID:      "test-client",
+		UserID:        uuid.New(),
+		RedirectURI:   "https://app.example.com/callback",
+		Scope:         "openid",
+		CodeChallenge: testCodeChallenge(),
+		AuthTime:      time.Now(),
+	})
+	key := authorizationCodeKey("the-code")
+
+	mockClientRepo.On("FindClientByID", mock.Anything, "test-client").Return(newTestOAuthClient(), nil).Once()
+	mockRedisRepo.On("TakeToken", mock.Anything, key).Return(grant, nil).Once()
+
+	resp, err := service.ExchangeToken(context.Background(), &dto.TokenRequest{
+		GrantType:    "authorization_code",
+		Code:         "the-code",
+		RedirectURI:  "https://app.example.com/callback",
+		CodeVerifier: strings.Repeat("a", 43),
+		ClientID:     "test-client",
+	})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, errors.ErrInvalidGrant, err)
+}
+
+// TestExchangeToken_CodeReuse tests that an authorization code can only be
+// redeemed once and that redeeming it again revokes the session it started
+func TestExchangeToken_CodeReuse(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockClientRepo := mocks.NewMockOAuthClientRepository(t)
+
+	service := &AuthService{
+		redis:   mockRedisRepo,
+		clients: mockClientRepo,
+		signer:  newTestSigner(t),
+		logger:  newTestLogger(),
+	}
+
+	userID := uuid.New().String()
+	sessionID := uuid.New().String()
+	key := authorizationCodeKey("the-code")
+
+	mockClientRepo.On("FindClientByID", mock.Anything, "test-client").Return(newTestOAuthClient(), nil).Once()
+	mockRedisRepo.On("TakeToken", mock.Anything, key).Return("", errors.ErrTokenNotFound).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key+":redeemed").Return(userID+":"+sessionID, nil).Once()
+	sessionKey := "session:" + sessionID
+	mockRedisRepo.On("AddToken", mock.Anything, sessionKey+":revoked", "1", refreshTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, sessionKey+":access").Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, sessionKey+":refresh").Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, sessionKey).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, sessionKey+":rotated").Return(nil).Once()
+	mockRedisRepo.On("RemoveFromSet", mock.Anything, userID+":sessions", sessionID).Return(nil).Once()
+
+	resp, err := service.ExchangeToken(context.Background(), &dto.TokenRequest{
+		GrantType:    "authorization_code",
+		Code:         "the-code",
+		RedirectURI:  "https://app.example.com/callback",
+		CodeVerifier: testCodeVerifier,
+		ClientID:     "test-client",
+	})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, errors.ErrInvalidGrant, err)
+}
+
+// TestExchangeToken_UnknownCode tests that a code that was never issued is rejected
+func TestExchangeToken_UnknownCode(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockClientRepo := mocks.NewMockOAuthClientRepository(t)
+
+	service := &AuthService{
+		redis:   mockRedisRepo,
+		clients: mockClientRepo,
+		signer:  newTestSigner(t),
+		logger:  newTestLogger(),
+	}
+
+	key := authorizationCodeKey("the-code")
+
+	mockClientRepo.On("FindClientByID", mock.Anything, "test-client").Return(newTestOAuthClient(), nil).Once()
+	mockRedisRepo.On("TakeToken", mock.Anything, key).Return("", errors.ErrTokenNotFound).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key+":redeemed").Return("", errors.ErrTokenNotFound).Once()
+
+	resp, err := service.ExchangeToken(context.Background(), &dto.TokenRequest{
+		GrantType:    "authorization_code",
+		Code:         "the-code",
+		RedirectURI:  "https://app.example.com/callback",
+		CodeVerifier: testCodeVerifier,
+		ClientID:     "test-client",
+	})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, errors.ErrInvalidGrant, err)
+}
+
+// TestRefreshToken_ClientRevoked tests that a session issued to a client
+// can't be refreshed once the client is deleted or loses the refresh grant
+func TestRefreshToken_ClientRevoked(t *testing.T) {
+	withoutRefresh := newTestOAuthClient()
+	withoutRefresh.GrantTypes = []string{entities.GrantTypeAuthorizationCode}
+
+	tests := []struct {
+		name      string
+		client    *entities.OAuthClient
+		clientErr error
+		wantErr   error
+	}{
+		{"client deleted", nil, errors.ErrOAuthClientNotFound, errors.ErrInvalidClient},
+		{"refresh grant removed", withoutRefresh, nil, errors.ErrUnauthorizedClient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthRepo := mocks.NewMockAuthRepository(t)
+			mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+			mockClientRepo := mocks.NewMockOAuthClientRepository(t)
+
+			service := &AuthService{
+				db:      mockAuthRepo,
+				redis:   mockRedisRepo,
+				clients: mockClientRepo,
+				signer:  newTestSigner(t),
+				logger:  newTestLogger(),
+			}
+
+			user := &entities.User{ID: uuid.New(), PhoneNumber: "09123456789", Status: entities.Active}
+			session := &entities.Session{
+				ID:        uuid.New(),
+				UserID:    user.ID,
+				ClientID:  "test-client",
+				Scope:     "openid",
+				ExpiresAt: time.Now().Add(time.Hour),
+			}
+			key := "session:" + session.ID.String()
+			refreshToken := signTestToken(t, jwt.MapClaims{
+				"user_id":    user.ID.String(),
+				"session_id": session.ID.String(),
+				"client_id":  "test-client",
+				"token_type": "refresh",
+				"jti":        uuid.NewString(),
+				"exp":        time.Now().Add(time.Hour).Unix(),
+			})
+
+			mockAuthRepo.On("FindUserByID", mock.Anything, user.ID).Return(user, nil).Once()
+			mockRedisRepo.On("FindToken", mock.Anything, key+":refresh").Return(refreshToken, nil).Once()
+			mockRedisRepo.On("FindToken", mock.Anything, key).Return(encodeTestSession(t, session), nil).Once()
+			mockClientRepo.On("FindClientByID", mock.Anything, "test-client").Return(tt.client, tt.clientErr).Once()
+
+			tokens, err := service.RefreshToken(context.Background(), refreshToken)
+
+			assert.Nil(t, tokens)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+// TestExchangeToken_InvalidClientSecret tests that confidential clients must authenticate
+func TestExchangeToken_InvalidClientSecret(t *testing.T) {
+	mockClientRepo := mocks.NewMockOAuthClientRepository(t)
+
+	service := &AuthService{
+		clients: mockClientRepo,
+		signer:  newTestSigner(t),
+		logger:  newTestLogger(),
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	client := newTestOAuthClient()
+	client.SecretHash = string(hash)
+
+	mockClientRepo.On("FindClientByID", mock.Anything, "test-client").Return(client, nil).Once()
+
+	resp, err := service.ExchangeToken(context.Background(), &dto.TokenRequest{
+		GrantType:    "authorization_code",
+		Code:         "the-code",
+		ClientID:     "test-client",
+		ClientSecret: "wrong",
+	})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, errors.ErrInvalidClient, err)
+	assert.Equal(t, "invalid_client", OAuthErrorCode(err))
+}
+
+func encodeTestGrant(t *testing.T, grant *authorizationGrant) string {
+	data, err := json.Marshal(grant)
+	require.NoError(t, err)
+	return string(data)
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+const (
+	otpLength         = 6
+	otpExpiration     = 2 * time.Minute
+	otpResendInterval = 1 * time.Minute
+	otpMaxAttempts    = 5
+
+	otpPurposeLogin = "login"
+)
+
+// RequestOTP sends a one-time login code to the given phone number. Unknown
+// phone numbers are accepted silently so the endpoint can't be used to
+// discover registered accounts.
+func (s *AuthService) RequestOTP(ctx context.Context, req *dto.OTPRequest) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while requesting OTP",
+			ports.F("error", ctx.Err()),
+			ports.F("phone_number", req.PhoneNumber),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	user, err := s.db.FindUserByPhoneNumber(ctx, &req.PhoneNumber)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			s.logger.Warn("OTP requested for unknown phone number",
+				ports.F("phone_number", req.PhoneNumber),
+			)
+			return nil
+		}
+		return err
+	}
+
+	if user.Status != entities.Active {
+		s.logger.Warn("OTP requested for inactive user",
+			ports.F("user_id", user.ID),
+		)
+		return nil
+	}
+
+	return s.sendOTP(ctx, otpPurposeLogin, req.PhoneNumber)
+}
+
+// VerifyOTP checks a one-time login code and issues a new token pair on
+// success, or an MFA token if the user has two-factor authentication enabled
+func (s *AuthService) VerifyOTP(ctx context.Context, req *dto.OTPVerifyRequest) (*entities.TokenPair, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while verifying OTP",
+			ports.F("error", ctx.Err()),
+			ports.F("phone_number", req.PhoneNumber),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	if err := s.checkOTP(ctx, otpPurposeLogin, req.PhoneNumber, req.Code); err != nil {
+		return nil, err
+	}
+
+	user, err := s.db.FindUserByPhoneNumber(ctx, &req.PhoneNumber)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return nil, errors.ErrInvalidOTP
+		}
+		return nil, err
+	}
+
+	if user.Status == entities.Deleted {
+		s.logger.Error("User is deleted",
+			ports.F("user_id", user.ID),
+		)
+		return nil, errors.ErrInvalidCredentials
+	}
+	if user.Status == entities.Deactivated {
+		s.logger.Error("User is deactivated",
+			ports.F("user_id", user.ID),
+		)
+		return nil, errors.ErrAccountDeactivated
+	}
+
+	return s.completeLogin(ctx, user, req.DeviceName)
+}
+
+// sendOTP generates a new code for purpose and phoneNumber and delivers it
+// by SMS
+func (s *AuthService) sendOTP(ctx context.Context, purpose, phoneNumber string) error {
+	code, err := s.issueOTP(ctx, purpose, phoneNumber)
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Your verification code is %s. It expires in %d minutes.", code, int(otpExpiration.Minutes()))
+	if err := s.sms.Send(ctx, phoneNumber, message); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// issueOTP generates a new code for purpose and phoneNumber and stores its
+// hash. A new code can't be requested until otpResendInterval has passed
+// since the previous one.
+func (s *AuthService) issueOTP(ctx context.Context, purpose, phoneNumber string) (string, error) {
+	key := otpKey(ctx, purpose, phoneNumber)
+
+	_, err := s.redis.FindToken(ctx, key+":resend")
+	if err == nil {
+		s.logger.Warn("OTP resend throttled",
+			ports.F("purpose", purpose),
+			ports.F("phone_number", phoneNumber),
+		)
+		return "", errors.ErrOTPResendTooSoon
+	}
+	if !errors.IsNotFoundError(err) {
+		return "", err
+	}
+
+	code, err := generateOTP()
+	if err != nil {
+		s.logger.Error("Error generating OTP",
+			ports.F("error", err),
+		)
+		return "", errors.ErrGenerateOTP
+	}
+
+	hash := s.hashOTP(purpose, phoneNumber, code)
+
+	if err := s.redis.AddToken(ctx, key, hash, otpExpiration); err != nil {
+		return "", err
+	}
+	if err := s.redis.RemoveToken(ctx, key+":attempts"); err != nil {
+		return "", err
+	}
+	if err := s.redis.AddToken(ctx, key+":resend", "1", otpResendInterval); err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// checkOTP verifies code against the stored hash for purpose and phoneNumber.
+// The code is consumed on success and discarded after otpMaxAttempts failures.
+func (s *AuthService) checkOTP(ctx context.Context, purpose, phoneNumber, code string) error {
+	key := otpKey(ctx, purpose, phoneNumber)
+
+	// The attempt is counted before the code is read, so concurrent guesses
+	// can't get past the limit
+	attempts, err := s.redis.IncrementCounter(ctx, key+":attempts", otpExpiration)
+	if err != nil {
+		return err
+	}
+	if attempts > otpMaxAttempts {
+		s.logger.Warn("OTP attempts exceeded",
+			ports.F("purpose", purpose),
+			ports.F("phone_number", phoneNumber),
+		)
+		if err := s.redis.RemoveToken(ctx, key); err != nil {
+			return err
+		}
+		return errors.ErrOTPAttemptsExceeded
+	}
+
+	storedHash, err := s.redis.FindToken(ctx, key)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return errors.ErrInvalidOTP
+		}
+		return err
+	}
+
+	hash := s.hashOTP(purpose, phoneNumber, code)
+	if !hmac.Equal([]byte(hash), []byte(storedHash)) {
+		s.logger.Warn("Invalid OTP",
+			ports.F("purpose", purpose),
+			ports.F("phone_number", phoneNumber),
+			ports.F("attempts", attempts),
+		)
+		return errors.ErrInvalidOTP
+	}
+
+	// Taking the code is what consumes it. Of concurrent requests with the
+	// right code only one gets it, and it must still be the code checked.
+	takenHash, err := s.redis.TakeToken(ctx, key)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return errors.ErrInvalidOTP
+		}
+		return err
+	}
+	if !hmac.Equal([]byte(hash), []byte(takenHash)) {
+		return errors.ErrInvalidOTP
+	}
+
+	if err := s.redis.RemoveToken(ctx, key+":attempts"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// otpKey is the key of the code for purpose sent to phoneNumber. The same
+// number can belong to users of different organizations, so the key is
+// scoped to the organization of ctx.
+func otpKey(ctx context.Context, purpose, phoneNumber string) string {
+	return "otp:" + entities.OrganizationIDFromContext(ctx).String() + ":" + purpose + ":" + phoneNumber
+}
+
+func generateOTP() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < otpLength; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", otpLength, n), nil
+}
+
+// hashOTP is the HMAC of code for purpose and phoneNumber under the OTP
+// secret, which is what gets stored instead of the code
+func (s *AuthService) hashOTP(purpose, phoneNumber, code string) string {
+	mac := hmac.New(sha256.New, s.otpSecret)
+	mac.Write([]byte(purpose + ":" + phoneNumber + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
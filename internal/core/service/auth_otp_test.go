@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestRequestOTP tests that a code is stored and sent to a registered user
+func TestRequestOTP(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockSMS := mocks.NewMockSMSSender(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		sms:    mockSMS,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	phone := "09123456789"
+	user := &entities.User{ID: uuid.New(), PhoneNumber: phone, Status: entities.Active}
+	key := otpKey(context.Background(), otpPurposeLogin, phone)
+
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &phone).Return(user, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key+":resend").Return("", errors.ErrTokenNotFound).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, key, mock.AnythingOfType("string"), otpExpiration).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":attempts").Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, key+":resend", "1", otpResendInterval).Return(nil).Once()
+
+	var sentMessage string
+	mockSMS.On("Send", mock.Anything, phone, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { sentMessage = args.String(2) }).
+		Return(nil).Once()
+
+	err := service.RequestOTP(context.Background(), &dto.OTPRequest{PhoneNumber: phone})
+
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`\b[0-9]{6}\b`), sentMessage)
+}
+
+// TestRequestOTP_Throttled tests that a second request inside the resend interval is rejected
+func TestRequestOTP_Throttled(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		sms:    mocks.NewMockSMSSender(t),
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	phone := "09123456789"
+	user := &entities.User{ID: uuid.New(), PhoneNumber: phone, Status: entities.Active}
+
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &phone).Return(user, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, otpKey(context.Background(), otpPurposeLogin, phone)+":resend").Return("1", nil).Once()
+
+	err := service.RequestOTP(context.Background(), &dto.OTPRequest{PhoneNumber: phone})
+
+	assert.Equal(t, errors.ErrOTPResendTooSoon, err)
+}
+
+// TestRequestOTP_UnknownPhone tests that unknown phone numbers don't leak through the response
+func TestRequestOTP_UnknownPhone(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mocks.NewMockInMemoryRespositoryContracts(t),
+		sms:    mocks.NewMockSMSSender(t),
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	phone := "09123456789"
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &phone).Return(nil, errors.ErrUserNotFound).Once()
+
+	err := service.RequestOTP(context.Background(), &dto.OTPRequest{PhoneNumber: phone})
+
+	assert.NoError(t, err)
+}
+
+// TestVerifyOTP_InvalidCode tests that a wrong code is rejected and counted
+func TestVerifyOTP_InvalidCode(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		db:     mocks.NewMockAuthRepository(t),
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	phone := "09123456789"
+	key := otpKey(context.Background(), otpPurposeLogin, phone)
+	storedHash := service.hashOTP(otpPurposeLogin, phone, "123456")
+
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return(storedHash, nil).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, key+":attempts", otpExpiration).Return(int64(1), nil).Once()
+
+	_, err := service.VerifyOTP(context.Background(), &dto.OTPVerifyRequest{PhoneNumber: phone, Code: "654321"})
+
+	assert.Equal(t, errors.ErrInvalidOTP, err)
+}
+
+// TestVerifyOTP_AttemptsExceeded tests that the code is discarded after too many failures
+func TestVerifyOTP_AttemptsExceeded(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		db:     mocks.NewMockAuthRepository(t),
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	phone := "09123456789"
+	key := otpKey(context.Background(), otpPurposeLogin, phone)
+
+	mockRedisRepo.On("IncrementCounter", mock.Anything, key+":attempts", otpExpiration).Return(int64(otpMaxAttempts+1), nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key).Return(nil).Once()
+
+	_, err := service.VerifyOTP(context.Background(), &dto.OTPVerifyRequest{PhoneNumber: phone, Code: "123456"})
+
+	assert.Equal(t, errors.ErrOTPAttemptsExceeded, err)
+}
+
+// TestVerifyOTP_AlreadyConsumed tests that a valid code taken by a concurrent request logs in only once
+func TestVerifyOTP_AlreadyConsumed(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		db:     mocks.NewMockAuthRepository(t),
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	phone := "09123456789"
+	key := otpKey(context.Background(), otpPurposeLogin, phone)
+	storedHash := service.hashOTP(otpPurposeLogin, phone, "123456")
+
+	mockRedisRepo.On("IncrementCounter", mock.Anything, key+":attempts", otpExpiration).Return(int64(2), nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return(storedHash, nil).Once()
+	mockRedisRepo.On("TakeToken", mock.Anything, key).Return("", errors.ErrTokenNotFound).Once()
+
+	_, err := service.VerifyOTP(context.Background(), &dto.OTPVerifyRequest{PhoneNumber: phone, Code: "123456"})
+
+	assert.Equal(t, errors.ErrInvalidOTP, err)
+}
+
+// TestVerifyOTP tests that a valid code is consumed and tokens are issued
+func TestVerifyOTP(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		signer: newTestSigner(t),
+		mfa:    newTestMFARepositoryWithoutMFA(t),
+		logger: newTestLogger(),
+	}
+
+	phone := "09123456789"
+	key := otpKey(context.Background(), otpPurposeLogin, phone)
+	user := &entities.User{ID: uuid.New(), PhoneNumber: phone, Status: entities.Active}
+	storedHash := service.hashOTP(otpPurposeLogin, phone, "123456")
+
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return(storedHash, nil).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, key+":attempts", otpExpiration).Return(int64(1), nil).Once()
+	mockRedisRepo.On("TakeToken", mock.Anything, key).Return(storedHash, nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":attempts").Return(nil).Once()
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &phone).Return(user, nil).Once()
+	mockRedisRepo.On("AddToSet", mock.Anything, user.ID.String()+":sessions", mock.Anything, refreshTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Times(3)
+	expectSessionNotRevoked(mockRedisRepo)
+
+	tokens, err := service.VerifyOTP(context.Background(), &dto.OTPVerifyRequest{PhoneNumber: phone, Code: "123456"})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+}
+
+// TestOTPKey_ScopedToOrganization tests that the same phone number gets separate codes in separate organizations
+func TestOTPKey_ScopedToOrganization(t *testing.T) {
+	ctx := entities.WithOrganization(context.Background(), uuid.New())
+
+	assert.NotEqual(t, otpKey(context.Background(), otpPurposeLogin, "09123456789"), otpKey(ctx, otpPurposeLogin, "09123456789"))
+}
+
+// TestHashOTP_KeyedBySecret tests that stored codes depend on the OTP secret
+func TestHashOTP_KeyedBySecret(t *testing.T) {
+	service := &AuthService{otpSecret: []byte("first-otp-secret")}
+	other := &AuthService{otpSecret: []byte("second-otp-secret")}
+
+	assert.Equal(t, service.hashOTP(otpPurposeLogin, "09123456789", "123456"), service.hashOTP(otpPurposeLogin, "09123456789", "123456"))
+	assert.NotEqual(t, service.hashOTP(otpPurposeLogin, "09123456789", "123456"), other.hashOTP(otpPurposeLogin, "09123456789", "123456"))
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+const otpPurposePasswordReset = "password_reset"
+
+// ForgotPassword sends a password reset code to the user with the given
+// phone number. It succeeds the same way whether or not the account exists,
+// and when a code was sent recently, so it can't be used to find accounts.
+func (s *AuthService) ForgotPassword(ctx context.Context, req *dto.ForgotPasswordRequest) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while requesting password reset",
+			ports.F("error", ctx.Err()),
+			ports.F("phone_number", req.PhoneNumber),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	user, err := s.db.FindUserByPhoneNumber(ctx, &req.PhoneNumber)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			s.logger.Warn("Password reset requested for unknown phone number",
+				ports.F("phone_number", req.PhoneNumber),
+			)
+			return nil
+		}
+		return err
+	}
+
+	if user.Status != entities.Active {
+		s.logger.Warn("Password reset requested for inactive user",
+			ports.F("user_id", user.ID),
+		)
+		return nil
+	}
+
+	code, err := s.issueOTP(ctx, otpPurposePasswordReset, user.PhoneNumber)
+	if err != nil {
+		if err == errors.ErrOTPResendTooSoon {
+			return nil
+		}
+		return err
+	}
+
+	message := fmt.Sprintf("Your password reset code is %s. It expires in %d minutes. If you didn't ask to reset your password, ignore this message.", code, int(otpExpiration.Minutes()))
+	if err := s.notifier.Notify(ctx, user, message); err != nil {
+		return err
+	}
+
+	s.logger.Info("Password reset code sent",
+		ports.F("user_id", user.ID),
+	)
+	return nil
+}
+
+// ResetPassword sets a new password once the reset code is checked. Every
+// session of the user is ended, so whoever knew the old password is signed
+// out everywhere.
+func (s *AuthService) ResetPassword(ctx context.Context, req *dto.ResetPasswordRequest) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while resetting password",
+			ports.F("error", ctx.Err()),
+			ports.F("phone_number", req.PhoneNumber),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	if err := s.checkOTP(ctx, otpPurposePasswordReset, req.PhoneNumber, req.Code); err != nil {
+		return err
+	}
+
+	user, err := s.db.FindUserByPhoneNumber(ctx, &req.PhoneNumber)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return errors.ErrInvalidOTP
+		}
+		return err
+	}
+
+	if user.Status == entities.Deleted {
+		s.logger.Error("User is deleted",
+			ports.F("user_id", user.ID),
+		)
+		return errors.ErrInvalidOTP
+	}
+	if user.Status == entities.Deactivated {
+		s.logger.Error("User is deactivated",
+			ports.F("user_id", user.ID),
+		)
+		return errors.ErrAccountDeactivated
+	}
+
+	hashedPassword, err := s.hasher.Hash(req.NewPassword)
+	if err != nil {
+		s.logger.Error("Error hashing password",
+			ports.F("error", err),
+			ports.F("user_id", user.ID),
+		)
+		return errors.ErrChangePassword
+	}
+
+	if err := s.db.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+		Type:     entities.AuditPasswordReset,
+		ActorID:  &user.ID,
+		TargetID: &user.ID,
+	})
+
+	// The reset event covers ending the sessions, so this doesn't go through
+	// LogoutAll and its own event
+	if _, err := revokeSessions(ctx, s.redis, user.ID.String()); err != nil {
+		return err
+	}
+
+	// The user proved they own the phone number, any lockout from guessing
+	// the old password no longer applies
+	if err := s.resetLoginFailures(ctx, user.PhoneNumber); err != nil {
+		return err
+	}
+
+	s.logger.Info("Password reset",
+		ports.F("user_id", user.ID),
+	)
+	return nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestForgotPassword tests that a reset code is stored and sent to a registered user
+func TestForgotPassword(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockNotifier := mocks.NewMockNotifier(t)
+
+	service := &AuthService{
+		db:       mockAuthRepo,
+		redis:    mockRedisRepo,
+		notifier: mockNotifier,
+		logger:   newTestLogger(),
+	}
+
+	phone := "09123456789"
+	user := &entities.User{ID: uuid.New(), PhoneNumber: phone, Status: entities.Active}
+	key := otpKey(context.Background(), otpPurposePasswordReset, phone)
+
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &phone).Return(user, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key+":resend").Return("", errors.ErrTokenNotFound).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, key, mock.AnythingOfType("string"), otpExpiration).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":attempts").Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, key+":resend", "1", otpResendInterval).Return(nil).Once()
+
+	var sentMessage string
+	mockNotifier.On("Notify", mock.Anything, user, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { sentMessage = args.String(2) }).
+		Return(nil).Once()
+
+	err := service.ForgotPassword(context.Background(), &dto.ForgotPasswordRequest{PhoneNumber: phone})
+
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`\b[0-9]{6}\b`), sentMessage)
+}
+
+// TestForgotPassword_UnknownPhone tests that unknown phone numbers succeed without sending anything
+func TestForgotPassword_UnknownPhone(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockNotifier := mocks.NewMockNotifier(t)
+
+	service := &AuthService{
+		db:       mockAuthRepo,
+		notifier: mockNotifier,
+		logger:   newTestLogger(),
+	}
+
+	phone := "09120000000"
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &phone).Return(nil, errors.ErrUserNotFound).Once()
+
+	err := service.ForgotPassword(context.Background(), &dto.ForgotPasswordRequest{PhoneNumber: phone})
+
+	assert.NoError(t, err)
+	mockNotifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestForgotPassword_Throttled tests that a repeated request succeeds without sending another code
+func TestForgotPassword_Throttled(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockNotifier := mocks.NewMockNotifier(t)
+
+	service := &AuthService{
+		db:       mockAuthRepo,
+		redis:    mockRedisRepo,
+		notifier: mockNotifier,
+		logger:   newTestLogger(),
+	}
+
+	phone := "09123456789"
+	user := &entities.User{ID: uuid.New(), PhoneNumber: phone, Status: entities.Active}
+
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &phone).Return(user, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, otpKey(context.Background(), otpPurposePasswordReset, phone)+":resend").Return("1", nil).Once()
+
+	err := service.ForgotPassword(context.Background(), &dto.ForgotPasswordRequest{PhoneNumber: phone})
+
+	assert.NoError(t, err)
+	mockNotifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestResetPassword tests that a valid code sets the new password and ends every session
+func TestResetPassword(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
+	}
+
+	phone := "09123456789"
+	key := otpKey(context.Background(), otpPurposePasswordReset, phone)
+	user := &entities.User{ID: uuid.New(), PhoneNumber: phone, Status: entities.Active}
+	storedHash := service.hashOTP(otpPurposePasswordReset, phone, "123456")
+
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return(storedHash, nil).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, key+":attempts", otpExpiration).Return(int64(1), nil).Once()
+	mockRedisRepo.On("TakeToken", mock.Anything, key).Return(storedHash, nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":attempts").Return(nil).Once()
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &phone).Return(user, nil).Once()
+
+	var newHash string
+	mockAuthRepo.On("UpdatePassword", mock.Anything, user.ID, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { newHash = args.String(2) }).
+		Return(nil).Once()
+
+	sessionID := uuid.New().String()
+	mockRedisRepo.On("FindSetMembers", mock.Anything, userSessionsKey(user.ID.String())).Return([]string{sessionID}, nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, revokedSessionKey(sessionID), "1", refreshTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, sessionKey(sessionID)+":access").Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, sessionKey(sessionID)+":refresh").Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, sessionKey(sessionID)).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, rotatedTokensKey(sessionID)).Return(nil).Once()
+	mockRedisRepo.On("RemoveFromSet", mock.Anything, userSessionsKey(user.ID.String()), sessionID).Return(nil).Once()
+	expectLoginFailuresReset(mockRedisRepo, phone)
+
+	err := service.ResetPassword(context.Background(), &dto.ResetPasswordRequest{
+		PhoneNumber: phone,
+		Code:        "123456",
+		NewPassword: "NewPassword123",
+	})
+
+	require.NoError(t, err)
+	matched, err := newTestHasher().Verify("NewPassword123", newHash)
+	assert.NoError(t, err)
+	assert.True(t, matched)
+}
+
+// TestResetPassword_InvalidCode tests that a wrong code leaves the password unchanged
+func TestResetPassword_InvalidCode(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
+	}
+
+	phone := "09123456789"
+	key := otpKey(context.Background(), otpPurposePasswordReset, phone)
+	storedHash := service.hashOTP(otpPurposePasswordReset, phone, "123456")
+
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return(storedHash, nil).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, key+":attempts", otpExpiration).Return(int64(1), nil).Once()
+
+	err := service.ResetPassword(context.Background(), &dto.ResetPasswordRequest{
+		PhoneNumber: phone,
+		Code:        "654321",
+		NewPassword: "NewPassword123",
+	})
+
+	assert.Equal(t, errors.ErrInvalidOTP, err)
+	mockAuthRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const otpPurposePhoneChange = "phone_change"
+
+// RequestPhoneNumberChange sends a code to the new phone number. The user
+// keeps logging in with the current number until the code is confirmed with
+// ConfirmPhoneNumberChange.
+func (s *AuthService) RequestPhoneNumberChange(ctx context.Context, userID string, req *dto.ChangePhoneNumberRequest) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while requesting phone number change",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return errors.ErrInvalidUserID
+	}
+
+	if _, err := s.db.FindUserByID(ctx, id); err != nil {
+		return err
+	}
+
+	_, err = s.db.FindUserByPhoneNumber(ctx, &req.PhoneNumber)
+	if err == nil {
+		return errors.ErrDuplicatePhoneNumber
+	}
+	if !errors.IsNotFoundError(err) {
+		return err
+	}
+
+	code, err := s.issueOTP(ctx, phoneChangePurpose(userID), req.PhoneNumber)
+	if err != nil {
+		return err
+	}
+
+	if err := s.redis.AddToken(ctx, phoneChangeKey(userID), req.PhoneNumber, otpExpiration); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Your verification code for changing your phone number is %s. It expires in %d minutes.", code, int(otpExpiration.Minutes()))
+	if err := s.sms.Send(ctx, req.PhoneNumber, message); err != nil {
+		return err
+	}
+
+	s.logger.Info("Phone number change requested",
+		ports.F("user_id", userID),
+	)
+	return nil
+}
+
+// ConfirmPhoneNumberChange replaces the user's phone number with the one a
+// code was sent to by RequestPhoneNumberChange, and lets the old number know
+// it is no longer used.
+func (s *AuthService) ConfirmPhoneNumberChange(ctx context.Context, userID string, req *dto.ConfirmPhoneNumberRequest) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while confirming phone number change",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return errors.ErrInvalidUserID
+	}
+
+	newPhoneNumber, err := s.redis.FindToken(ctx, phoneChangeKey(userID))
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return errors.ErrPhoneChangeNotFound
+		}
+		return err
+	}
+
+	if err := s.checkOTP(ctx, phoneChangePurpose(userID), newPhoneNumber, req.Code); err != nil {
+		return err
+	}
+
+	user, err := s.db.FindUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	oldPhoneNumber := user.PhoneNumber
+
+	if err := s.db.UpdatePhoneNumber(ctx, id, newPhoneNumber); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+		Type:     entities.AuditPhoneNumberChanged,
+		ActorID:  &id,
+		TargetID: &id,
+		Changes:  auditChanges([3]string{"phone_number", oldPhoneNumber, newPhoneNumber}),
+	})
+
+	if err := s.redis.RemoveToken(ctx, phoneChangeKey(userID)); err != nil {
+		return err
+	}
+
+	// The change is already saved, failing to warn the old number shouldn't
+	// undo it
+	message := fmt.Sprintf("The phone number of your account was changed to %s. If you didn't do this, contact support right away.", maskPhoneNumber(newPhoneNumber))
+	if err := s.sms.Send(ctx, oldPhoneNumber, message); err != nil {
+		s.logger.Error("Error notifying old phone number",
+			ports.F("error", err),
+			ports.F("user_id", userID),
+		)
+	}
+
+	s.logger.Info("Phone number changed",
+		ports.F("user_id", userID),
+	)
+	return nil
+}
+
+// phoneChangePurpose scopes phone change codes to the user, so a code can
+// only confirm the change it was sent for
+func phoneChangePurpose(userID string) string {
+	return otpPurposePhoneChange + ":" + userID
+}
+
+func phoneChangeKey(userID string) string {
+	return "phone_change:" + userID
+}
+
+// maskPhoneNumber hides the digits of phoneNumber between its first two and
+// last two
+func maskPhoneNumber(phoneNumber string) string {
+	if len(phoneNumber) <= 4 {
+		return phoneNumber
+	}
+	masked := []byte(phoneNumber)
+	for i := 2; i < len(masked)-2; i++ {
+		masked[i] = '*'
+	}
+	return string(masked)
+}
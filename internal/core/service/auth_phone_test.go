@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPhoneNumberChange tests that the number only changes once the code sent to it is confirmed, and the old number is told
+func TestPhoneNumberChange(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockSMS := mocks.NewMockSMSSender(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		sms:    mockSMS,
+		audit:  newTestAuditRepository(t),
+		logger: newTestLogger(),
+	}
+
+	user := &entities.User{ID: uuid.New(), PhoneNumber: "09123456789", Status: entities.Active}
+	userID := user.ID.String()
+	newPhone := "09350000000"
+	key := otpKey(context.Background(), phoneChangePurpose(userID), newPhone)
+
+	mockAuthRepo.On("FindUserByID", mock.Anything, user.ID).Return(user, nil).Twice()
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &newPhone).Return(nil, errors.ErrUserNotFound).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key+":resend").Return("", errors.ErrTokenNotFound).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, key, mock.AnythingOfType("string"), otpExpiration).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":attempts").Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, key+":resend", "1", otpResendInterval).Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, phoneChangeKey(userID), newPhone, otpExpiration).Return(nil).Once()
+
+	var sentMessage string
+	mockSMS.On("Send", mock.Anything, newPhone, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { sentMessage = args.String(2) }).
+		Return(nil).Once()
+
+	err := service.RequestPhoneNumberChange(context.Background(), userID, &dto.ChangePhoneNumberRequest{PhoneNumber: newPhone})
+	require.NoError(t, err)
+	code := regexp.MustCompile(`\b[0-9]{6}\b`).FindString(sentMessage)
+	require.NotEmpty(t, code)
+
+	storedHash := service.hashOTP(phoneChangePurpose(userID), newPhone, code)
+	mockRedisRepo.On("FindToken", mock.Anything, phoneChangeKey(userID)).Return(newPhone, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return(storedHash, nil).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, key+":attempts", otpExpiration).Return(int64(1), nil).Once()
+	mockRedisRepo.On("TakeToken", mock.Anything, key).Return(storedHash, nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":attempts").Return(nil).Once()
+	mockAuthRepo.On("UpdatePhoneNumber", mock.Anything, user.ID, newPhone).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, phoneChangeKey(userID)).Return(nil).Once()
+	mockSMS.On("Send", mock.Anything, user.PhoneNumber, mock.AnythingOfType("string")).Return(nil).Once()
+
+	err = service.ConfirmPhoneNumberChange(context.Background(), userID, &dto.ConfirmPhoneNumberRequest{Code: code})
+
+	assert.NoError(t, err)
+}
+
+// TestRequestPhoneNumberChange_NumberTaken tests that a number used by another account is refused
+func TestRequestPhoneNumberChange_NumberTaken(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		sms:    mocks.NewMockSMSSender(t),
+		logger: newTestLogger(),
+	}
+
+	user := &entities.User{ID: uuid.New(), PhoneNumber: "09123456789"}
+	other := &entities.User{ID: uuid.New(), PhoneNumber: "09350000000"}
+
+	mockAuthRepo.On("FindUserByID", mock.Anything, user.ID).Return(user, nil).Once()
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &other.PhoneNumber).Return(other, nil).Once()
+
+	err := service.RequestPhoneNumberChange(context.Background(), user.ID.String(), &dto.ChangePhoneNumberRequest{PhoneNumber: other.PhoneNumber})
+
+	assert.Equal(t, errors.ErrDuplicatePhoneNumber, err)
+}
+
+// TestConfirmPhoneNumberChange_NotStarted tests that a code can't be confirmed without a pending change
+func TestConfirmPhoneNumberChange_NotStarted(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		db:     mocks.NewMockAuthRepository(t),
+		redis:  mockRedisRepo,
+		logger: newTestLogger(),
+	}
+
+	userID := uuid.NewString()
+	mockRedisRepo.On("FindToken", mock.Anything, phoneChangeKey(userID)).Return("", errors.ErrTokenNotFound).Once()
+
+	err := service.ConfirmPhoneNumberChange(context.Background(), userID, &dto.ConfirmPhoneNumberRequest{Code: "123456"})
+
+	assert.Equal(t, errors.ErrPhoneChangeNotFound, err)
+}
+
+// TestAdminUpdateUser_PhoneOverride tests that admins can still change a phone number directly
+func TestAdminUpdateUser_PhoneOverride(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		audit:  newTestAuditRepository(t),
+		logger: newTestLogger(),
+	}
+
+	userID := uuid.New()
+	mockAdminRepo.On("AdminGetUserByID", mock.Anything, &userID).Return(&entities.User{ID: userID, PhoneNumber: "09123456789"}, nil).Once()
+	mockAdminRepo.On("AdminUpdateUser", mock.Anything, mock.MatchedBy(func(u *entities.User) bool {
+		return u.ID == userID && u.PhoneNumber == "09350000000"
+	})).Return(nil).Once()
+
+	actor := &entities.Principal{UserID: uuid.New(), Role: entities.AdminRole}
+	ctx := entities.WithClientInfo(context.Background(), entities.ClientInfo{IP: "10.0.0.1", UserID: actor.UserID.String()})
+	err := service.AdminUpdateUser(ctx, actor, &userID, &dto.AdminUserUpdateRequest{PhoneNumber: "09350000000"})
+
+	assert.NoError(t, err)
+}
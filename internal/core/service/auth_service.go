@@ -2,19 +2,25 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/amirdashtii/go_auth/config"
 	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/infrastructure/email"
 	"github.com/amirdashtii/go_auth/infrastructure/logger"
+	"github.com/amirdashtii/go_auth/infrastructure/notifier"
+	"github.com/amirdashtii/go_auth/infrastructure/password"
 	"github.com/amirdashtii/go_auth/infrastructure/repository"
+	"github.com/amirdashtii/go_auth/infrastructure/signer"
+	"github.com/amirdashtii/go_auth/infrastructure/sms"
 	"github.com/amirdashtii/go_auth/internal/core/entities"
 	"github.com/amirdashtii/go_auth/internal/core/errors"
 	"github.com/amirdashtii/go_auth/internal/core/ports"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
 const (
@@ -23,9 +29,21 @@ const (
 )
 
 type AuthService struct {
-	db     ports.AuthRepository
-	redis  ports.InMemoryRespositoryContracts
-	logger ports.Logger
+	db           ports.AuthRepository
+	redis        ports.InMemoryRespositoryContracts
+	clients      ports.OAuthClientRepository
+	apiKeys      ports.APIKeyRepository
+	mfa          ports.MFARepository
+	webauthn     ports.WebAuthnRepository
+	relyingParty ports.WebAuthnRelyingParty
+	sms          ports.SMSSender
+	notifier     ports.Notifier
+	email        ports.EmailSender
+	audit        ports.AuditRepository
+	signer       ports.TokenSigner
+	hasher       ports.PasswordHasher
+	otpSecret    []byte
+	logger       ports.Logger
 }
 
 func NewAuthService() *AuthService {
@@ -50,11 +68,111 @@ func NewAuthService() *AuthService {
 		panic(errors.ErrRedisInit)
 	}
 
+	clientRepo := repository.NewPGOAuthClientRepository(db, appLogger)
+	apiKeyRepo := repository.NewPGAPIKeyRepository(db, appLogger)
+	mfaRepo := repository.NewPGMFARepository(db, appLogger)
+	webauthnRepo := repository.NewPGWebAuthnRepository(db, appLogger)
+	smsSender := newSMSSender(appLogger)
+
 	return &AuthService{
-		db:     authRepo,
-		redis:  redisRepo,
-		logger: appLogger,
+		db:           authRepo,
+		redis:        redisRepo,
+		clients:      clientRepo,
+		apiKeys:      apiKeyRepo,
+		mfa:          mfaRepo,
+		webauthn:     webauthnRepo,
+		relyingParty: newRelyingParty(appLogger),
+		sms:          smsSender,
+		notifier:     notifier.NewSMSNotifier(smsSender, appLogger),
+		email:        newEmailSender(appLogger),
+		audit:        repository.NewPGAuditRepository(db, appLogger),
+		signer:       newTokenSigner(appLogger),
+		hasher:       newPasswordHasher(appLogger),
+		otpSecret:    newOTPSecret(),
+		logger:       appLogger,
+	}
+}
+
+// newPasswordHasher creates the password hasher with the hashing cost of the
+// configuration
+func newPasswordHasher(logger ports.Logger) ports.PasswordHasher {
+	config, err := config.LoadConfig()
+	if err != nil {
+		panic(errors.ErrLoadConfig)
+	}
+
+	hasher, err := password.NewHasher(config.Password)
+	if err != nil {
+		logger.Error("Error creating password hasher",
+			ports.F("error", err),
+		)
+		panic(err)
 	}
+	return hasher
+}
+
+// newOTPSecret returns the key one-time codes are hashed with. It is required,
+// so codes can't be forged by anyone who knows another secret of the service.
+func newOTPSecret() []byte {
+	config, err := config.LoadConfig()
+	if err != nil {
+		panic(errors.ErrLoadConfig)
+	}
+
+	if config.OTP.Secret == "" {
+		panic(errors.ErrMissingOTPSecret)
+	}
+	return []byte(config.OTP.Secret)
+}
+
+// newSMSSender creates the SMS sender selected by the configuration
+func newSMSSender(logger ports.Logger) ports.SMSSender {
+	config, err := config.LoadConfig()
+	if err != nil {
+		panic(errors.ErrLoadConfig)
+	}
+
+	sender, err := sms.NewSender(config.SMS, config.Environment, logger)
+	if err != nil {
+		logger.Error("Error creating SMS sender",
+			ports.F("error", err),
+			ports.F("sender", config.SMS.Sender),
+		)
+		panic(err)
+	}
+	return sender
+}
+
+// newEmailSender creates the email sender selected by the configuration
+func newEmailSender(logger ports.Logger) ports.EmailSender {
+	config, err := config.LoadConfig()
+	if err != nil {
+		panic(errors.ErrLoadConfig)
+	}
+
+	sender, err := email.NewSender(config.Email, config.Environment, logger)
+	if err != nil {
+		logger.Error("Error creating email sender",
+			ports.F("error", err),
+			ports.F("sender", config.Email.Sender),
+		)
+		panic(err)
+	}
+	return sender
+}
+
+// newTokenSigner creates the token signer described by the JWT configuration
+func newTokenSigner(logger ports.Logger) ports.TokenSigner {
+	config, err := config.LoadConfig()
+	if err != nil {
+		panic(errors.ErrLoadConfig)
+	}
+
+	tokenSigner, err := signer.NewJWTSigner(config.JWT, logger)
+	if err != nil {
+		panic(errors.ErrLoadSigningKey)
+	}
+	return tokenSigner
 }
 
 func (s *AuthService) Register(ctx context.Context, req *dto.RegisterRequest) error {
@@ -66,7 +184,7 @@ func (s *AuthService) Register(ctx context.Context, req *dto.RegisterRequest) er
 		return errors.ErrContextCancelled
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	hashedPassword, err := s.hasher.Hash(req.Password)
 	if err != nil {
 		s.logger.Error("Error hashing password",
 			ports.F("error", err),
@@ -79,13 +197,14 @@ func (s *AuthService) Register(ctx context.Context, req *dto.RegisterRequest) er
 	}
 	
 	user := &entities.User{
-		ID:          uuid.New(),
-		PhoneNumber: req.PhoneNumber,
-		Password:    string(hashedPassword),
-		Status:      entities.Active,
-		Role:        entities.UserRole,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:             uuid.New(),
+		OrganizationID: entities.OrganizationIDFromContext(ctx),
+		PhoneNumber:    req.PhoneNumber,
+		Password:       hashedPassword,
+		Status:         entities.Active,
+		Role:           entities.UserRole,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
 	}
 
 	if err := s.db.Create(ctx, user); err != nil {
@@ -104,36 +223,82 @@ func (s *AuthService) Login(ctx context.Context, loginReq *dto.LoginRequest) (*e
 		return nil, errors.ErrContextCancelled
 	}
 	
-	user, err := s.db.FindUserByPhoneNumber(ctx, &loginReq.PhoneNumber)
+	identifier := loginReq.PhoneNumber
+	if loginReq.Email != "" {
+		identifier = strings.ToLower(loginReq.Email)
+	}
+
+	if err := s.checkLoginLock(ctx, identifier); err != nil {
+		return nil, err
+	}
+
+	var user *entities.User
+	var err error
+	if loginReq.Email != "" {
+		user, err = s.db.FindUserByVerifiedEmail(ctx, identifier)
+	} else {
+		user, err = s.db.FindUserByPhoneNumber(ctx, &loginReq.PhoneNumber)
+	}
 	if err != nil {
+		if errors.IsNotFoundError(err) {
+			// Guesses against unknown identifiers count too, so they can't
+			// be told apart from registered ones by the lockout
+			s.auditLoginFailure(ctx, identifier, nil, "unknown_user")
+			if lockErr := s.recordLoginFailure(ctx, identifier); lockErr != nil {
+				return nil, lockErr
+			}
+		}
 		return nil, err
 	}
 
+	// Failures are counted against the account's phone number whichever
+	// identifier was used, so logging in by email doesn't get more guesses
+	if identifier != user.PhoneNumber {
+		if err := checkThrottle(ctx, s.redis, phoneLoginThrottle, user.PhoneNumber); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(loginReq.Password)); err != nil {
+	matched, err := s.hasher.Verify(loginReq.Password, user.Password)
+	if err != nil || !matched {
 		s.logger.Error("Invalid password",
 			ports.F("error", err),
 			ports.F("user_id", user.ID),
 		)
+		s.auditLoginFailure(ctx, identifier, user, "wrong_password")
+		if lockErr := s.recordLoginFailure(ctx, user.PhoneNumber); lockErr != nil {
+			return nil, lockErr
+		}
 		return nil, errors.ErrInvalidCredentials
 	}
 
+	if err := s.resetLoginFailures(ctx, user.PhoneNumber); err != nil {
+		return nil, err
+	}
+
 	// Check user status
 	if user.Status == entities.Deleted {
 		s.logger.Error("User is deleted",
 			ports.F("user_id", user.ID),
 		)
+		s.auditLoginFailure(ctx, identifier, user, "account_deleted")
 		return nil, errors.ErrInvalidCredentials
 	}
 	if user.Status == entities.Deactivated {
 		s.logger.Error("User is deactivated",
 			ports.F("user_id", user.ID),
 		)
+		s.auditLoginFailure(ctx, identifier, user, "account_deactivated")
 		return nil, errors.ErrAccountDeactivated
 	}
 
-	// Generate tokens
-	tokens, err := s.createTokenPair(ctx, user)
+	if s.hasher.NeedsRehash(user.Password) {
+		s.rehashPassword(ctx, user, loginReq.Password)
+	}
+
+	// Generate tokens, or ask for the second factor first
+	tokens, err := s.completeLogin(ctx, user, loginReq.DeviceName)
 	if err != nil {
 		return nil, err
 	}
@@ -141,29 +306,70 @@ func (s *AuthService) Login(ctx context.Context, loginReq *dto.LoginRequest) (*e
 	return tokens, nil
 }
 
-func (s *AuthService) Logout(ctx context.Context, userID string) error {
+// rehashPassword replaces the stored hash of user, made with an older
+// algorithm or cost, by one made with the preferred ones. The login goes on
+// if it fails, the hash is replaced at a later login.
+func (s *AuthService) rehashPassword(ctx context.Context, user *entities.User, plainPassword string) {
+	hashedPassword, err := s.hasher.Hash(plainPassword)
+	if err != nil {
+		s.logger.Error("Error rehashing password",
+			ports.F("error", err),
+			ports.F("user_id", user.ID),
+		)
+		return
+	}
+	if err := s.db.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		s.logger.Error("Error storing rehashed password",
+			ports.F("error", err),
+			ports.F("user_id", user.ID),
+		)
+		return
+	}
+	user.Password = hashedPassword
+
+	s.logger.Info("Password rehashed",
+		ports.F("user_id", user.ID),
+	)
+}
+
+// auditLoginFailure records a failed password login. user is nil when no
+// account has the identifier.
+func (s *AuthService) auditLoginFailure(ctx context.Context, identifier string, user *entities.User, reason string) {
+	event := &entities.AuditEvent{
+		Type: entities.AuditLoginFailed,
+		Details: map[string]string{
+			"identifier": identifier,
+			"reason":     reason,
+		},
+	}
+	if user != nil {
+		event.TargetID = &user.ID
+	}
+	recordAudit(ctx, s.audit, s.logger, event)
+}
+
+func (s *AuthService) Logout(ctx context.Context, userID, sessionID string) error {
 	if ctx.Err() != nil {
 		s.logger.Error("Context cancelled while logging out user",
 			ports.F("error", ctx.Err()),
 			ports.F("user_id", userID),
+			ports.F("session_id", sessionID),
 		)
 		return errors.ErrContextCancelled
 	}
 
-	err := s.redis.RemoveToken(ctx, userID+":access")
-	if err != nil {
+	if err := removeSession(ctx, s.redis, userID, sessionID); err != nil {
 		return err
 	}
 
-	if ctx.Err() != nil {
-		return ctx.Err()
+	if id, err := uuid.Parse(userID); err == nil {
+		recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+			Type:     entities.AuditLogout,
+			ActorID:  &id,
+			TargetID: &id,
+			Details:  map[string]string{"session_id": sessionID},
+		})
 	}
-
-	err = s.redis.RemoveToken(ctx, userID+":refresh")
-	if err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -176,16 +382,31 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*e
 		return nil, errors.ErrContextCancelled
 	}
 
-	user, err := s.parseAndValidateToken(ctx, refreshToken, "refresh")
+	// The user is loaded again, so the new tokens carry their current role
+	// and only active users get them
+	user, claims, err := s.parseAndValidateToken(ctx, refreshToken, "refresh")
 	if err != nil {
 		return nil, err
 	}
+	if user.Status != entities.Active {
+		return nil, errors.ErrAccountDeactivated
+	}
+	ctx = entities.WithOrganization(ctx, user.OrganizationID)
+
+	sessionID, err := uuidClaim(claims, "session_id")
+	if err != nil {
+		s.logger.Error("Invalid session ID",
+			ports.F("error", err),
+			ports.F("user_id", user.ID),
+		)
+		return nil, errors.ErrInvalidToken
+	}
 
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
-	storedToken, err := s.redis.FindToken(ctx, user.ID.String()+":refresh")
+	storedToken, err := s.redis.FindToken(ctx, sessionKey(sessionID.String())+":refresh")
 	if err != nil {
 		return nil, err
 	}
@@ -194,36 +415,91 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*e
 		return nil, ctx.Err()
 	}
 
+	tokenID, _ := claims["jti"].(string)
+
 	if storedToken != refreshToken {
-		s.logger.Error("Invalid refresh token",
-			ports.F("user_id", user.ID),
-		)
-		return nil, errors.ErrInvalidToken
+		return nil, s.checkRefreshTokenReuse(ctx, user.ID.String(), sessionID.String(), tokenID)
+	}
+
+	session, err := s.findSession(ctx, user.ID.String(), sessionID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// A session issued to a client lives only as long as the client may
+	// refresh it, however its refresh token reaches us
+	if session.ClientID != "" {
+		if err := s.checkSessionClient(ctx, session.ClientID); err != nil {
+			return nil, err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if err := s.markRefreshTokenRotated(ctx, user.ID.String(), sessionID.String(), tokenID); err != nil {
+		return nil, err
 	}
 
-	err = s.Logout(ctx, user.ID.String())
+	now := time.Now()
+	session.LastSeenAt = now
+	session.ExpiresAt = now.Add(refreshTokenExpiration)
+
+	// The session lives on with the new refresh token, so the index of the
+	// user's sessions must too, or it could no longer be revoked
+	if err := s.redis.AddToSet(ctx, userSessionsKey(user.ID.String()), session.ID.String(), refreshTokenExpiration); err != nil {
+		return nil, err
+	}
+
+	tokenPair, err := s.issueTokenPair(ctx, user, session)
 	if err != nil {
 		return nil, err
 	}
 
+	recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+		Type:     entities.AuditTokenRefreshed,
+		ActorID:  &user.ID,
+		TargetID: &user.ID,
+		Details:  map[string]string{"session_id": session.ID.String()},
+	})
+	return tokenPair, nil
+}
+
+// createTokenPair starts a new session for user and issues its first token pair
+func (s *AuthService) createTokenPair(ctx context.Context, user *entities.User, deviceName string) (*entities.TokenPair, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
-	tokenPair, err := s.createTokenPair(ctx, user)
+	session, err := s.createSession(ctx, user, deviceName)
 	if err != nil {
 		return nil, err
 	}
 
+	tokenPair, err := s.issueTokenPair(ctx, user, session)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every way of logging in ends here once all factors were checked
+	recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+		Type:     entities.AuditLoginSucceeded,
+		ActorID:  &user.ID,
+		TargetID: &user.ID,
+		Details:  map[string]string{"session_id": session.ID.String()},
+	})
 	return tokenPair, nil
 }
 
-func (s *AuthService) createTokenPair(ctx context.Context, user *entities.User) (*entities.TokenPair, error) {
+// issueTokenPair signs a new access and refresh token for session, replacing
+// any tokens the session had before
+func (s *AuthService) issueTokenPair(ctx context.Context, user *entities.User, session *entities.Session) (*entities.TokenPair, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
-	accessToken, err := s.createToken(ctx, user, accessTokenExpiration, "access")
+	accessToken, err := s.createToken(ctx, user, session, accessTokenExpiration, "access")
 	if err != nil {
 		return nil, err
 	}
@@ -232,7 +508,7 @@ func (s *AuthService) createTokenPair(ctx context.Context, user *entities.User)
 		return nil, ctx.Err()
 	}
 
-	refreshToken, err := s.createToken(ctx, user, refreshTokenExpiration, "refresh")
+	refreshToken, err := s.createToken(ctx, user, session, refreshTokenExpiration, "refresh")
 	if err != nil {
 		return nil, err
 	}
@@ -241,7 +517,7 @@ func (s *AuthService) createTokenPair(ctx context.Context, user *entities.User)
 		return nil, ctx.Err()
 	}
 
-	err = s.redis.AddToken(ctx, user.ID.String()+":access", accessToken, accessTokenExpiration)
+	err = s.redis.AddToken(ctx, sessionKey(session.ID.String())+":access", accessToken, accessTokenExpiration)
 	if err != nil {
 		return nil, err
 	}
@@ -250,90 +526,55 @@ func (s *AuthService) createTokenPair(ctx context.Context, user *entities.User)
 		return nil, ctx.Err()
 	}
 
-	err = s.redis.AddToken(ctx, user.ID.String()+":refresh", refreshToken, refreshTokenExpiration)
+	err = s.redis.AddToken(ctx, sessionKey(session.ID.String())+":refresh", refreshToken, refreshTokenExpiration)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.saveSession(ctx, session); err != nil {
+		return nil, err
+	}
+
 	return &entities.TokenPair{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 	}, nil
 }
 
-func (s *AuthService) createToken(ctx context.Context, user *entities.User, expiration time.Duration, tokenType string) (string, error) {
+func (s *AuthService) createToken(ctx context.Context, user *entities.User, session *entities.Session, expiration time.Duration, tokenType string) (string, error) {
 	if ctx.Err() != nil {
 		return "", ctx.Err()
 	}
 
 	claims := jwt.MapClaims{
 		"user_id":    user.ID,
+		"org":        user.OrganizationID,
+		"session_id": session.ID,
 		"role":       user.Role,
 		"token_type": tokenType,
+		"jti":        uuid.NewString(),
 		"exp":        time.Now().Add(expiration).Unix(),
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	config, err := config.LoadConfig()
-	if err != nil {
-		return "", err
+	if session.ClientID != "" {
+		claims["client_id"] = session.ClientID
+		claims["scope"] = session.Scope
 	}
 
-	if ctx.Err() != nil {
-		return "", ctx.Err()
-	}
-
-	jwtSecret := config.JWT.Secret
-	tokenString, err := token.SignedString([]byte(jwtSecret))
-	if err != nil {
-		s.logger.Error("Error creating token",
-			ports.F("error", err),
-			ports.F("user_id", user.ID),
-		)
-		return "", errors.ErrTokenCreation
-	}
-
-	return tokenString, nil
+	return s.signer.Sign(claims)
 }
 
-func (s *AuthService) parseAndValidateToken(ctx context.Context, token string, expectedType string) (*entities.User, error) {
-	if ctx.Err() != nil {
-		return nil, ctx.Err()
-	}
-
-	config, err := config.LoadConfig()
-	if err != nil {
-		return nil, err
-	}
-
+func (s *AuthService) parseAndValidateToken(ctx context.Context, token string, expectedType string) (*entities.User, jwt.MapClaims, error) {
 	if ctx.Err() != nil {
-		return nil, ctx.Err()
+		return nil, nil, ctx.Err()
 	}
 
-	jwtSecret := config.JWT.Secret
-
-	parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-		return []byte(jwtSecret), nil
-	})
+	claims, err := s.ParseToken(token)
 	if err != nil {
-		s.logger.Error("Error parsing token",
-			ports.F("error", err),
-			ports.F("token", token),
-		)
-		return nil, errors.ErrInvalidToken
+		return nil, nil, errors.ErrInvalidToken
 	}
 
 	if ctx.Err() != nil {
-		return nil, ctx.Err()
-	}
-
-	claims, ok := parsedToken.Claims.(jwt.MapClaims)
-	if !ok {
-		s.logger.Error("Invalid token claims",
-			ports.F("token", token),
-		)
-		return nil, errors.ErrInvalidToken
+		return nil, nil, ctx.Err()
 	}
 
 	tokenType, ok := claims["token_type"].(string)
@@ -341,91 +582,101 @@ func (s *AuthService) parseAndValidateToken(ctx context.Context, token string, e
 		s.logger.Error("Invalid token type",
 			ports.F("token", token),
 		)
-		return nil, errors.ErrInvalidToken
-	}
-
-	userIDValue, ok := claims["user_id"]
-	if !ok {
-		s.logger.Error("Invalid token claims",
-			ports.F("token", token),
-		)
-		return nil, errors.ErrInvalidToken
+		return nil, nil, errors.ErrInvalidToken
 	}
 
-	var userID uuid.UUID
-	switch v := userIDValue.(type) {
-	case string:
-		userID, err = uuid.Parse(v)
-		if err != nil {
-			s.logger.Error("Invalid user ID",
-				ports.F("error", err),
-				ports.F("token", token),
-			)
-			return nil, errors.ErrInvalidToken
-		}
-	case map[string]interface{}:
-		if uuidStr, ok := v["String"].(string); ok {
-			userID, err = uuid.Parse(uuidStr)
-			if err != nil {
-				s.logger.Error("Invalid user ID",
-					ports.F("error", err),
-					ports.F("token", token),
-				)
-				return nil, errors.ErrInvalidToken
-			}
-		} else {
-			s.logger.Error("Invalid user ID",
-				ports.F("token", token),
-			)
-			return nil, errors.ErrInvalidToken
-		}
-	default:
+	userID, err := uuidClaim(claims, "user_id")
+	if err != nil {
 		s.logger.Error("Invalid user ID",
+			ports.F("error", err),
 			ports.F("token", token),
 		)
-		return nil, errors.ErrInvalidToken
+		return nil, nil, errors.ErrInvalidToken
 	}
 
 	if ctx.Err() != nil {
-		return nil, ctx.Err()
+		return nil, nil, ctx.Err()
+	}
+
+	// The user is looked up in the organization the token was issued in,
+	// whichever organization the request names
+	if organizationID, err := uuidClaim(claims, "org"); err == nil {
+		ctx = entities.WithOrganization(ctx, organizationID)
 	}
 
 	user, err := s.db.FindUserByID(ctx, userID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if ctx.Err() != nil {
-		return nil, ctx.Err()
+		return nil, nil, ctx.Err()
 	}
 
 	if user.Status == entities.Deleted {
 		s.logger.Error("User is deleted",
 			ports.F("user_id", userID),
 		)
-		return nil, errors.ErrInvalidCredentials
+		return nil, nil, errors.ErrInvalidCredentials
 	}
 	if user.Status == entities.Deactivated {
 		s.logger.Error("User is deactivated",
 			ports.F("user_id", userID),
 		)
-		return nil, errors.ErrAccountDeactivated
+		return nil, nil, errors.ErrAccountDeactivated
+	}
+
+	return user, claims, nil
+}
+
+// ParseToken verifies the signature and expiry of token and returns its claims
+func (s *AuthService) ParseToken(token string) (jwt.MapClaims, error) {
+	claims, err := s.signer.Verify(token)
+	if err != nil {
+		s.logger.Error("Error parsing token",
+			ports.F("error", err),
+			ports.F("token", token),
+		)
+		return nil, err
 	}
+	return claims, nil
+}
 
-	return user, nil
+// JWKS returns the public keys that verify the tokens issued by the service
+func (s *AuthService) JWKS(ctx context.Context) (*entities.JSONWebKeySet, error) {
+	if ctx.Err() != nil {
+		return nil, errors.ErrContextCancelled
+	}
+
+	return &entities.JSONWebKeySet{Keys: s.signer.PublicKeys()}, nil
 }
 
-func (s *AuthService) ValidateToken(ctx context.Context, userID, token string) error {
+// uuidClaim reads a UUID claim, which is encoded either as a plain string or
+// as an object with a String field
+func uuidClaim(claims jwt.MapClaims, key string) (uuid.UUID, error) {
+	switch v := claims[key].(type) {
+	case string:
+		return uuid.Parse(v)
+	case map[string]interface{}:
+		if uuidStr, ok := v["String"].(string); ok {
+			return uuid.Parse(uuidStr)
+		}
+	}
+	return uuid.Nil, fmt.Errorf("claim %q is not a valid UUID", key)
+}
+
+func (s *AuthService) ValidateToken(ctx context.Context, userID, sessionID, token string) error {
 	if ctx.Err() != nil {
 		s.logger.Error("Context cancelled while validating token",
 			ports.F("error", ctx.Err()),
 			ports.F("user_id", userID),
+			ports.F("session_id", sessionID),
 			ports.F("token", token),
 		)
 		return errors.ErrContextCancelled
 	}
 
-	storedToken, err := s.redis.FindToken(ctx, userID+":access")
+	storedToken, err := s.redis.FindToken(ctx, sessionKey(sessionID)+":access")
 	if err != nil {
 		return err
 	}
@@ -437,9 +688,10 @@ func (s *AuthService) ValidateToken(ctx context.Context, userID, token string) e
 	if storedToken != token {
 		s.logger.Error("Invalid access token",
 			ports.F("user_id", userID),
+			ports.F("session_id", sessionID),
 		)
 		return errors.ErrInvalidToken
 	}
 
-	return nil
+	return s.touchSession(ctx, userID, sessionID)
 }
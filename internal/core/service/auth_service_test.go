@@ -3,21 +3,72 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/amirdashtii/go_auth/config"
 	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/infrastructure/logger"
+	"github.com/amirdashtii/go_auth/infrastructure/password"
+	"github.com/amirdashtii/go_auth/infrastructure/signer"
 	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
 	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"golang.org/x/crypto/bcrypt"
 )
 
+// newTestLogger returns a logger that discards everything
+func newTestLogger() ports.Logger {
+	return logger.NewZerologLogger(ports.LoggerConfig{
+		Level:       "fatal",
+		Environment: "test",
+		ServiceName: "go_auth",
+		Output:      io.Discard,
+	})
+}
+
+// newTestHasher returns a password hasher of the lowest argon2id cost, so
+// the tests hash quickly
+func newTestHasher() ports.PasswordHasher {
+	hasher, _ := password.NewHasher(config.PasswordConfig{Argon2Memory: 8, Argon2Time: 1, Argon2Parallelism: 1})
+	return hasher
+}
+
+// newTestSigner returns the signer configured for the service
+func newTestSigner(t *testing.T) ports.TokenSigner {
+	cfg, err := config.LoadConfig()
+	assert.NoError(t, err)
+
+	tokenSigner, err := signer.NewJWTSigner(cfg.JWT, newTestLogger())
+	assert.NoError(t, err)
+	return tokenSigner
+}
+
+// signTestToken signs claims with the configured JWT secret
+func signTestToken(t *testing.T, claims jwt.MapClaims) string {
+	cfg, err := config.LoadConfig()
+	assert.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.JWT.Secret))
+	assert.NoError(t, err)
+	return token
+}
+
+// encodeTestSession returns the stored form of a session
+func encodeTestSession(t *testing.T, session *entities.Session) string {
+	data, err := json.Marshal(session)
+	assert.NoError(t, err)
+	return string(data)
+}
+
 // TestRegister tests the user registration functionality
 func TestRegister(t *testing.T) {
 	// Initialize mock repositories
@@ -26,8 +77,11 @@ func TestRegister(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
 	}
 
 	// Create registration request
@@ -37,7 +91,7 @@ func TestRegister(t *testing.T) {
 	}
 
 	// Set up mock expectations
-	mockAuthRepo.On("Create", mock.Anything).Return(nil).Once()
+	mockAuthRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
 
 	// Execute registration
 	err := service.Register(context.Background(), req)
@@ -55,8 +109,11 @@ func TestRegister_DuplicateUser(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
 	}
 
 	// Create registration request
@@ -67,14 +124,14 @@ func TestRegister_DuplicateUser(t *testing.T) {
 
 	// Set up mock expectations
 	// Expect Create to be called once and return a duplicate user error
-	mockAuthRepo.On("Create", mock.Anything).Return(fmt.Errorf("user with this phone number already exists")).Once()
+	mockAuthRepo.On("Create", mock.Anything, mock.Anything).Return(fmt.Errorf("user with this phone number already exists")).Once()
 
 	// Execute registration
 	err := service.Register(context.Background(), req)
 
 	// Verify results
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "already exists")
+	assert.Contains(t, err.Error(), "Failed to create user")
 	mockAuthRepo.AssertExpectations(t)
 }
 
@@ -86,17 +143,22 @@ func TestLogin(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		signer: newTestSigner(t),
+		mfa:    newTestMFARepositoryWithoutMFA(t),
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
 	}
 
 	// Create test user
 	userID := uuid.New()
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	hashedPassword, _ := newTestHasher().Hash("password123")
 	user := &entities.User{
 		ID:          userID,
 		PhoneNumber: "09123456789",
-		Password:    string(hashedPassword),
+		Password:    hashedPassword,
 		Role:        entities.UserRole,
 	}
 
@@ -104,14 +166,25 @@ func TestLogin(t *testing.T) {
 	req := &dto.LoginRequest{
 		PhoneNumber: "09123456789",
 		Password:    "password123",
+		DeviceName:  "Pixel 8",
 	}
 
+	// Request comes from a known client
+	ctx := entities.WithClientInfo(context.Background(), entities.ClientInfo{
+		IP:        "10.0.0.1",
+		UserAgent: "go_auth-test",
+	})
+
 	// Set up mock expectations
-	mockAuthRepo.On("FindUserByPhoneNumber", &req.PhoneNumber).Return(user, nil).Once()
-	mockRedisRepo.On("AddToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	expectLoginNotLocked(mockRedisRepo, req.PhoneNumber, "10.0.0.1")
+	expectLoginFailuresReset(mockRedisRepo, req.PhoneNumber)
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &req.PhoneNumber).Return(user, nil).Once()
+	mockRedisRepo.On("AddToSet", mock.Anything, userID.String()+":sessions", mock.Anything, refreshTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.MatchedBy(func(key string) bool { return len(key) > len("session:") }), mock.Anything, mock.Anything).Return(nil).Times(3)
+	expectSessionNotRevoked(mockRedisRepo)
 
 	// Execute login
-	tokens, err := service.Login(context.Background(), req)
+	tokens, err := service.Login(ctx, req)
 
 	// Verify results
 	assert.NoError(t, err)
@@ -119,6 +192,126 @@ func TestLogin(t *testing.T) {
 	assert.NotEmpty(t, tokens.RefreshToken)
 	mockAuthRepo.AssertExpectations(t)
 	mockRedisRepo.AssertExpectations(t)
+
+	// The session must carry the device details and be referenced by the tokens
+	var stored entities.Session
+	for _, call := range mockRedisRepo.Calls {
+		if call.Method == "AddToken" && strings.Count(call.Arguments.String(1), ":") == 1 {
+			assert.NoError(t, json.Unmarshal([]byte(call.Arguments.String(2)), &stored))
+		}
+	}
+	assert.Equal(t, userID, stored.UserID)
+	assert.Equal(t, "Pixel 8", stored.DeviceName)
+	assert.Equal(t, "10.0.0.1", stored.IP)
+	assert.Equal(t, "go_auth-test", stored.UserAgent)
+
+	claims := jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(tokens.AccessToken, claims)
+	assert.NoError(t, err)
+	assert.Equal(t, stored.ID.String(), claims["session_id"])
+}
+
+// TestLogin_RehashesLegacyPassword tests that a hash imported from another system is verified and replaced by the preferred one
+func TestLogin_RehashesLegacyPassword(t *testing.T) {
+	mockAuthRepo := new(mocks.AuthRepository)
+	mockRedisRepo := new(mocks.InMemoryRespositoryContracts)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		signer: newTestSigner(t),
+		mfa:    newTestMFARepositoryWithoutMFA(t),
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
+	}
+
+	user := &entities.User{
+		ID:          uuid.New(),
+		PhoneNumber: "09123456789",
+		Password:    "$pbkdf2-sha256$i=1000$c2FsdHNhbHRzYWx0c2FsdA$70cUhXcSYOUIjWzWnSYy3dcYSct7zJGo2jkT03TJR7g",
+		Role:        entities.UserRole,
+	}
+	req := &dto.LoginRequest{
+		PhoneNumber: "09123456789",
+		Password:    "Legacy1!",
+	}
+
+	expectLoginNotLocked(mockRedisRepo, req.PhoneNumber, "")
+	expectLoginFailuresReset(mockRedisRepo, req.PhoneNumber)
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &req.PhoneNumber).Return(user, nil).Once()
+	mockAuthRepo.On("UpdatePassword", mock.Anything, user.ID, mock.MatchedBy(func(hash string) bool {
+		matched, _ := newTestHasher().Verify("Legacy1!", hash)
+		return matched && strings.HasPrefix(hash, "$argon2id$")
+	})).Return(nil).Once()
+	mockRedisRepo.On("AddToSet", mock.Anything, user.ID.String()+":sessions", mock.Anything, refreshTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Times(3)
+	expectSessionNotRevoked(mockRedisRepo)
+
+	tokens, err := service.Login(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	mockAuthRepo.AssertExpectations(t)
+	mockRedisRepo.AssertExpectations(t)
+}
+
+// TestLogin_MultipleSessions tests that logging in twice keeps both sessions
+func TestLogin_MultipleSessions(t *testing.T) {
+	// Initialize mock repositories
+	mockAuthRepo := new(mocks.AuthRepository)
+	mockRedisRepo := new(mocks.InMemoryRespositoryContracts)
+
+	// Create service instance with mock repositories
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		signer: newTestSigner(t),
+		mfa:    newTestMFARepositoryWithoutMFA(t),
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
+	}
+
+	// Create test user
+	hashedPassword, _ := newTestHasher().Hash("password123")
+	user := &entities.User{
+		ID:          uuid.New(),
+		PhoneNumber: "09123456789",
+		Password:    hashedPassword,
+		Role:        entities.UserRole,
+	}
+
+	req := &dto.LoginRequest{
+		PhoneNumber: "09123456789",
+		Password:    "password123",
+	}
+
+	// Set up mock expectations
+	expectLoginNotLocked(mockRedisRepo, req.PhoneNumber, "")
+	expectLoginFailuresReset(mockRedisRepo, req.PhoneNumber)
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &req.PhoneNumber).Return(user, nil).Twice()
+	mockRedisRepo.On("AddToSet", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	expectSessionNotRevoked(mockRedisRepo)
+	expectSessionNotRevoked(mockRedisRepo)
+
+	// Execute login twice
+	first, err := service.Login(context.Background(), req)
+	assert.NoError(t, err)
+	second, err := service.Login(context.Background(), req)
+	assert.NoError(t, err)
+
+	// Verify that no session was removed and each login got its own session
+	for _, call := range mockRedisRepo.Calls {
+		if call.Method == "RemoveToken" {
+			assert.False(t, strings.HasPrefix(call.Arguments.String(1), "session:"))
+		}
+	}
+	firstClaims, secondClaims := jwt.MapClaims{}, jwt.MapClaims{}
+	_, _, _ = jwt.NewParser().ParseUnverified(first.AccessToken, firstClaims)
+	_, _, _ = jwt.NewParser().ParseUnverified(second.AccessToken, secondClaims)
+	assert.NotEqual(t, firstClaims["session_id"], secondClaims["session_id"])
 }
 
 // TestLogin_InvalidPassword tests login with an incorrect password
@@ -129,17 +322,21 @@ func TestLogin_InvalidPassword(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
 	}
 
 	// Create test user with correct password
 	userID := uuid.New()
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correct_password"), bcrypt.DefaultCost)
+	hashedPassword, _ := newTestHasher().Hash("correct_password")
 	user := &entities.User{
 		ID:          userID,
 		PhoneNumber: "09123456789",
-		Password:    string(hashedPassword),
+		Password:    hashedPassword,
 		Status:      entities.Active,
 		Role:        entities.UserRole,
 	}
@@ -152,14 +349,17 @@ func TestLogin_InvalidPassword(t *testing.T) {
 
 	// Set up mock expectations
 	// Expect FindUserByPhoneNumber to be called once and return the test user
-	mockAuthRepo.On("FindUserByPhoneNumber", &loginReq.PhoneNumber).Return(user, nil).Once()
+	expectLoginNotLocked(mockRedisRepo, loginReq.PhoneNumber, "")
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &loginReq.PhoneNumber).Return(user, nil).Once()
+	// Expect the failure to be counted
+	mockRedisRepo.On("IncrementCounter", mock.Anything, loginFailuresKey(context.Background(), "phone", loginReq.PhoneNumber), loginFailureWindow).Return(int64(1), nil).Once()
 
 	// Execute login
 	_, err := service.Login(context.Background(), loginReq)
 
 	// Verify results
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid password")
+	assert.Contains(t, err.Error(), "Invalid credentials")
 	mockAuthRepo.AssertExpectations(t)
 }
 
@@ -171,17 +371,21 @@ func TestLogin_DeactivatedUser(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
 	}
 
 	// Create test user with deactivated status
 	userID := uuid.New()
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	hashedPassword, _ := newTestHasher().Hash("password123")
 	user := &entities.User{
 		ID:          userID,
 		PhoneNumber: "09123456789",
-		Password:    string(hashedPassword),
+		Password:    hashedPassword,
 		Status:      entities.Deactivated,
 		Role:        entities.UserRole,
 	}
@@ -194,14 +398,16 @@ func TestLogin_DeactivatedUser(t *testing.T) {
 
 	// Set up mock expectations
 	// Expect FindUserByPhoneNumber to be called once and return the deactivated user
-	mockAuthRepo.On("FindUserByPhoneNumber", &loginReq.PhoneNumber).Return(user, nil).Once()
+	expectLoginNotLocked(mockRedisRepo, loginReq.PhoneNumber, "")
+	expectLoginFailuresReset(mockRedisRepo, loginReq.PhoneNumber)
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &loginReq.PhoneNumber).Return(user, nil).Once()
 
 	// Execute login
 	_, err := service.Login(context.Background(), loginReq)
 
 	// Verify results
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "user is deactivated")
+	assert.Contains(t, err.Error(), "Account is deactivated")
 	mockAuthRepo.AssertExpectations(t)
 }
 
@@ -213,17 +419,21 @@ func TestLogin_DeletedUser(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
 	}
 
 	// Create test user with deleted status
 	userID := uuid.New()
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	hashedPassword, _ := newTestHasher().Hash("password123")
 	user := &entities.User{
 		ID:          userID,
 		PhoneNumber: "09123456789",
-		Password:    string(hashedPassword),
+		Password:    hashedPassword,
 		Status:      entities.Deleted,
 		Role:        entities.UserRole,
 	}
@@ -236,14 +446,16 @@ func TestLogin_DeletedUser(t *testing.T) {
 
 	// Set up mock expectations
 	// Expect FindUserByPhoneNumber to be called once and return the deleted user
-	mockAuthRepo.On("FindUserByPhoneNumber", &loginReq.PhoneNumber).Return(user, nil).Once()
+	expectLoginNotLocked(mockRedisRepo, loginReq.PhoneNumber, "")
+	expectLoginFailuresReset(mockRedisRepo, loginReq.PhoneNumber)
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &loginReq.PhoneNumber).Return(user, nil).Once()
 
 	// Execute login
 	_, err := service.Login(context.Background(), loginReq)
 
 	// Verify results
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "user not found")
+	assert.Contains(t, err.Error(), "Invalid credentials")
 	mockAuthRepo.AssertExpectations(t)
 }
 
@@ -255,17 +467,21 @@ func TestLogin_RedisError(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		mfa:    newTestMFARepositoryWithoutMFA(t),
+		logger: newTestLogger(),
+		hasher: newTestHasher(),
 	}
 
 	// Create test user
 	userID := uuid.New()
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	hashedPassword, _ := newTestHasher().Hash("password123")
 	user := &entities.User{
 		ID:          userID,
 		PhoneNumber: "09123456789",
-		Password:    string(hashedPassword),
+		Password:    hashedPassword,
 		Status:      entities.Active,
 		Role:        entities.UserRole,
 	}
@@ -278,16 +494,18 @@ func TestLogin_RedisError(t *testing.T) {
 
 	// Set up mock expectations
 	// Expect FindUserByPhoneNumber to be called once and return the test user
-	mockAuthRepo.On("FindUserByPhoneNumber", &loginReq.PhoneNumber).Return(user, nil).Once()
+	expectLoginNotLocked(mockRedisRepo, loginReq.PhoneNumber, "")
+	expectLoginFailuresReset(mockRedisRepo, loginReq.PhoneNumber)
+	mockAuthRepo.On("FindUserByPhoneNumber", mock.Anything, &loginReq.PhoneNumber).Return(user, nil).Once()
+	mockRedisRepo.On("AddToSet", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 	// Expect AddToken to be called once and return a Redis error
-	mockRedisRepo.On("AddToken", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("redis error")).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.ErrAddToken).Once()
 
 	// Execute login
 	_, err := service.Login(context.Background(), loginReq)
 
 	// Verify results
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to store access token in redis")
+	assert.Equal(t, errors.ErrAddToken, err)
 	mockAuthRepo.AssertExpectations(t)
 	mockRedisRepo.AssertExpectations(t)
 }
@@ -300,20 +518,28 @@ func TestLogout(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
 	}
 
-	// Create test user ID
+	// Create test user and session IDs
 	userID := uuid.New()
+	sessionID := uuid.New()
 
 	// Set up mock expectations
-	// Expect RemoveToken to be called twice - once for access token and once for refresh token
-	mockRedisRepo.On("RemoveToken", userID.String()+":access").Return(nil).Once()
-	mockRedisRepo.On("RemoveToken", userID.String()+":refresh").Return(nil).Once()
+	// Expect the session tokens, the session record and its membership to be removed
+	mockRedisRepo.On("AddToken", mock.Anything, "session:"+sessionID.String()+":revoked", "1", refreshTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, "session:"+sessionID.String()+":access").Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, "session:"+sessionID.String()+":refresh").Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, "session:"+sessionID.String()).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, "session:"+sessionID.String()+":rotated").Return(nil).Once()
+	mockRedisRepo.On("RemoveFromSet", mock.Anything, userID.String()+":sessions", sessionID.String()).Return(nil).Once()
 
 	// Execute logout
-	err := service.Logout(context.Background(), userID.String())
+	err := service.Logout(context.Background(), userID.String(), sessionID.String())
 
 	// Verify results
 	assert.NoError(t, err)
@@ -328,19 +554,23 @@ func TestLogout_RedisError(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
 	}
 
-	// Create test user ID
+	// Create test user and session IDs
 	userID := uuid.New()
+	sessionID := uuid.New()
 
 	// Set up mock expectations
+	mockRedisRepo.On("AddToken", mock.Anything, "session:"+sessionID.String()+":revoked", "1", refreshTokenExpiration).Return(nil).Once()
 	// Expect RemoveToken to be called once for access token and return a Redis error
-	mockRedisRepo.On("RemoveToken", userID.String()+":access").Return(fmt.Errorf("redis error")).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, "session:"+sessionID.String()+":access").Return(fmt.Errorf("redis error")).Once()
 
 	// Execute logout
-	err := service.Logout(context.Background(), userID.String())
+	err := service.Logout(context.Background(), userID.String(), sessionID.String())
 
 	// Verify results
 	assert.Error(t, err)
@@ -356,34 +586,54 @@ func TestRefreshToken(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
 	}
 
-	// Create test user
+	// Create test user and session
 	userID := uuid.New()
 	user := &entities.User{
 		ID:          userID,
 		PhoneNumber: "09123456789",
-		Role:        entities.UserRole,
+		Role:        entities.AdminRole,
 	}
+	session := &entities.Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		DeviceName: "Pixel 8",
+		CreatedAt:  time.Now().Add(-time.Hour),
+		LastSeenAt: time.Now().Add(-time.Hour),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	key := "session:" + session.ID.String()
 
 	// Create refresh token
-	cfg, _ := config.LoadConfig()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	tokenID := uuid.NewString()
+	refreshToken := signTestToken(t, jwt.MapClaims{
 		"user_id":    userID.String(),
-		"role":       user.Role,
+		"session_id": session.ID.String(),
+		// The user was promoted since the token was issued
+		"role":       entities.UserRole,
 		"token_type": "refresh",
+		"jti":        tokenID,
 		"exp":        time.Now().Add(time.Hour * 24).Unix(),
 	})
-	refreshToken, _ := token.SignedString([]byte(cfg.JWT.Secret))
 
 	// Set up mock expectations
-	mockRedisRepo.On("FindToken", userID.String()+":refresh").Return(refreshToken, nil).Once()
-	mockAuthRepo.On("FindUserByID", userID).Return(user, nil).Once()
-	mockRedisRepo.On("RemoveToken", userID.String()+":access").Return(nil).Once()
-	mockRedisRepo.On("RemoveToken", userID.String()+":refresh").Return(nil).Once()
-	mockRedisRepo.On("AddToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("FindUserByID", mock.Anything, userID).Return(user, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key+":refresh").Return(refreshToken, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return(encodeTestSession(t, session), nil).Once()
+	// Expect the presented token to be remembered as rotated
+	mockRedisRepo.On("AddToSetIfAbsent", mock.Anything, key+":rotated", tokenID, refreshTokenExpiration).Return(true, nil).Once()
+	// and the session to stay in the user's index as long as the new token
+	mockRedisRepo.On("AddToSet", mock.Anything, userID.String()+":sessions", session.ID.String(), refreshTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, key+":access", mock.Anything, accessTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, key+":refresh", mock.Anything, refreshTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, key, mock.Anything, mock.Anything).Return(nil).Once()
+	expectSessionNotRevoked(mockRedisRepo)
 
 	// Execute refresh token
 	tokens, err := service.RefreshToken(context.Background(), refreshToken)
@@ -394,6 +644,133 @@ func TestRefreshToken(t *testing.T) {
 	assert.NotEmpty(t, tokens.RefreshToken)
 	mockAuthRepo.AssertExpectations(t)
 	mockRedisRepo.AssertExpectations(t)
+
+	// The new tokens stay in the same session
+	claims := jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(tokens.RefreshToken, claims)
+	assert.NoError(t, err)
+	assert.Equal(t, session.ID.String(), claims["session_id"])
+
+	// and carry the user's current role
+	claims = jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(tokens.AccessToken, claims)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(entities.AdminRole), claims["role"])
+}
+
+// TestRefreshToken_ReuseDetected tests that replaying a rotated refresh token revokes the whole token family
+func TestRefreshToken_ReuseDetected(t *testing.T) {
+	// Initialize mock repositories
+	mockAuthRepo := new(mocks.AuthRepository)
+	mockRedisRepo := new(mocks.InMemoryRespositoryContracts)
+
+	// Create service instance with mock repositories
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	// Create test user
+	userID := uuid.New()
+	sessionID := uuid.New()
+	key := "session:" + sessionID.String()
+	user := &entities.User{
+		ID:          userID,
+		PhoneNumber: "09123456789",
+		Status:      entities.Active,
+		Role:        entities.UserRole,
+	}
+
+	// Create a refresh token that has already been rotated
+	tokenID := uuid.NewString()
+	oldRefreshToken := signTestToken(t, jwt.MapClaims{
+		"user_id":    userID,
+		"session_id": sessionID,
+		"role":       user.Role,
+		"token_type": "refresh",
+		"jti":        tokenID,
+		"exp":        time.Now().Add(7 * 24 * time.Hour).Unix(),
+	})
+
+	// Set up mock expectations
+	mockAuthRepo.On("FindUserByID", mock.Anything, userID).Return(user, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key+":refresh").Return("current_refresh_token", nil).Once()
+	mockRedisRepo.On("FindSetMembers", mock.Anything, key+":rotated").Return([]string{uuid.NewString(), tokenID}, nil).Once()
+	// Expect the whole session to be revoked
+	mockRedisRepo.On("AddToken", mock.Anything, key+":revoked", "1", refreshTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":access").Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":refresh").Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":rotated").Return(nil).Once()
+	mockRedisRepo.On("RemoveFromSet", mock.Anything, userID.String()+":sessions", sessionID.String()).Return(nil).Once()
+
+	// Execute refresh token
+	_, err := service.RefreshToken(context.Background(), oldRefreshToken)
+
+	// Verify results
+	assert.Equal(t, errors.ErrRefreshTokenReused, err)
+	mockAuthRepo.AssertExpectations(t)
+	mockRedisRepo.AssertExpectations(t)
+}
+
+// TestRefreshToken_ConcurrentRotation tests that when the same refresh token is rotated by two requests at once the one that loses revokes the token family
+func TestRefreshToken_ConcurrentRotation(t *testing.T) {
+	mockAuthRepo := new(mocks.AuthRepository)
+	mockRedisRepo := new(mocks.InMemoryRespositoryContracts)
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	userID := uuid.New()
+	user := &entities.User{
+		ID:          userID,
+		PhoneNumber: "09123456789",
+		Status:      entities.Active,
+		Role:        entities.UserRole,
+	}
+	session := &entities.Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		LastSeenAt: time.Now(),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	key := "session:" + session.ID.String()
+
+	tokenID := uuid.NewString()
+	refreshToken := signTestToken(t, jwt.MapClaims{
+		"user_id":    userID,
+		"session_id": session.ID,
+		"role":       user.Role,
+		"token_type": "refresh",
+		"jti":        tokenID,
+		"exp":        time.Now().Add(7 * 24 * time.Hour).Unix(),
+	})
+
+	// The token is still the current one, but another request claimed it first
+	mockAuthRepo.On("FindUserByID", mock.Anything, userID).Return(user, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key+":refresh").Return(refreshToken, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return(encodeTestSession(t, session), nil).Once()
+	mockRedisRepo.On("AddToSetIfAbsent", mock.Anything, key+":rotated", tokenID, refreshTokenExpiration).Return(false, nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, key+":revoked", "1", refreshTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":access").Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":refresh").Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":rotated").Return(nil).Once()
+	mockRedisRepo.On("RemoveFromSet", mock.Anything, userID.String()+":sessions", session.ID.String()).Return(nil).Once()
+
+	_, err := service.RefreshToken(context.Background(), refreshToken)
+
+	assert.Equal(t, errors.ErrRefreshTokenReused, err)
+	// No new token pair is issued, only the session is marked revoked
+	mockRedisRepo.AssertNotCalled(t, "AddToken", mock.Anything, key+":refresh", mock.Anything, mock.Anything)
+	mockAuthRepo.AssertExpectations(t)
+	mockRedisRepo.AssertExpectations(t)
 }
 
 // TestRefreshToken_ExpiredToken tests refresh with an expired token
@@ -402,8 +779,10 @@ func TestRefreshToken_ExpiredToken(t *testing.T) {
 	mockRedisRepo := new(mocks.InMemoryRespositoryContracts)
 
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
 	}
 
 	userID := uuid.New()
@@ -411,19 +790,16 @@ func TestRefreshToken_ExpiredToken(t *testing.T) {
 		Role: entities.UserRole,
 	}
 
-	config, _ := config.LoadConfig()
-	claims := jwt.MapClaims{
+	refreshToken := signTestToken(t, jwt.MapClaims{
 		"user_id":    userID,
+		"session_id": uuid.New(),
 		"role":       user.Role,
 		"token_type": "refresh",
 		"exp":        time.Now().Add(-1 * time.Hour).Unix(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	refreshToken, _ := token.SignedString([]byte(config.JWT.Secret))
+	})
 
 	_, err := service.RefreshToken(context.Background(), refreshToken)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid refresh token")
+	assert.Equal(t, errors.ErrInvalidToken, err)
 }
 
 // TestRefreshToken_RedisError tests refresh when Redis operations fail
@@ -434,11 +810,13 @@ func TestRefreshToken_RedisError(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
 	}
 
-	// Create test user
+	// Create test user and session
 	userID := uuid.New()
 	user := &entities.User{
 		ID:          userID,
@@ -446,35 +824,40 @@ func TestRefreshToken_RedisError(t *testing.T) {
 		Status:      entities.Active,
 		Role:        entities.UserRole,
 	}
+	session := &entities.Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		LastSeenAt: time.Now(),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	key := "session:" + session.ID.String()
 
 	// Create valid refresh token
-	config, _ := config.LoadConfig()
-	claims := jwt.MapClaims{
+	refreshToken := signTestToken(t, jwt.MapClaims{
 		"user_id":    userID,
+		"session_id": session.ID,
 		"role":       user.Role,
 		"token_type": "refresh",
+		"jti":        uuid.NewString(),
 		"exp":        time.Now().Add(7 * 24 * time.Hour).Unix(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	refreshToken, _ := token.SignedString([]byte(config.JWT.Secret))
+	})
 
 	// Set up mock expectations
 	// Expect FindUserByID to be called once and return the test user
-	mockAuthRepo.On("FindUserByID", userID).Return(user, nil).Once()
-	// Expect FindToken to be called once and return the refresh token
-	mockRedisRepo.On("FindToken", userID.String()+":refresh").Return(refreshToken, nil).Once()
-	// Expect RemoveToken to be called twice - once for access token and once for refresh token
-	mockRedisRepo.On("RemoveToken", userID.String()+":access").Return(nil).Once()
-	mockRedisRepo.On("RemoveToken", userID.String()+":refresh").Return(nil).Once()
+	mockAuthRepo.On("FindUserByID", mock.Anything, userID).Return(user, nil).Once()
+	// Expect FindToken to be called for the refresh token and the session
+	mockRedisRepo.On("FindToken", mock.Anything, key+":refresh").Return(refreshToken, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return(encodeTestSession(t, session), nil).Once()
+	mockRedisRepo.On("AddToSetIfAbsent", mock.Anything, key+":rotated", mock.Anything, refreshTokenExpiration).Return(true, nil).Once()
+	mockRedisRepo.On("AddToSet", mock.Anything, userID.String()+":sessions", session.ID.String(), refreshTokenExpiration).Return(nil).Once()
 	// Expect AddToken to be called once and return a Redis error
-	mockRedisRepo.On("AddToken", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("redis error")).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.ErrAddToken).Once()
 
 	// Execute refresh token
 	_, err := service.RefreshToken(context.Background(), refreshToken)
 
 	// Verify results
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to store access token in redis")
+	assert.Equal(t, errors.ErrAddToken, err)
 	mockAuthRepo.AssertExpectations(t)
 	mockRedisRepo.AssertExpectations(t)
 }
@@ -487,8 +870,10 @@ func TestRefreshToken_InvalidToken(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
 	}
 
 	// Create invalid refresh token
@@ -498,8 +883,7 @@ func TestRefreshToken_InvalidToken(t *testing.T) {
 	_, err := service.RefreshToken(context.Background(), refreshToken)
 
 	// Verify results
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid refresh token")
+	assert.Equal(t, errors.ErrInvalidToken, err)
 }
 
 // TestRefreshToken_UserNotFound tests refresh for a non-existent user
@@ -510,34 +894,69 @@ func TestRefreshToken_UserNotFound(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
 	}
 
 	// Create test user ID
 	userID := uuid.New()
 
 	// Create valid refresh token
-	config, _ := config.LoadConfig()
-	claims := jwt.MapClaims{
+	refreshToken := signTestToken(t, jwt.MapClaims{
 		"user_id":    userID,
+		"session_id": uuid.New(),
 		"role":       entities.UserRole,
 		"token_type": "refresh",
 		"exp":        time.Now().Add(7 * 24 * time.Hour).Unix(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	refreshToken, _ := token.SignedString([]byte(config.JWT.Secret))
+	})
 
 	// Set up mock expectations
 	// Expect FindUserByID to be called and return user not found error
-	mockAuthRepo.On("FindUserByID", userID).Return(nil, fmt.Errorf("user not found")).Once()
+	mockAuthRepo.On("FindUserByID", mock.Anything, userID).Return(nil, errors.ErrUserNotFound).Once()
 
 	// Execute refresh token
 	_, err := service.RefreshToken(context.Background(), refreshToken)
 
 	// Verify results
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "user not found")
+	assert.Equal(t, errors.ErrUserNotFound, err)
+	mockAuthRepo.AssertExpectations(t)
+	mockRedisRepo.AssertExpectations(t)
+}
+
+// TestRefreshToken_DeactivatedUser tests that a user deactivated since logging in can't get new tokens
+func TestRefreshToken_DeactivatedUser(t *testing.T) {
+	// Initialize mock repositories
+	mockAuthRepo := new(mocks.AuthRepository)
+	mockRedisRepo := new(mocks.InMemoryRespositoryContracts)
+
+	// Create service instance with mock repositories
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	userID := uuid.New()
+	refreshToken := signTestToken(t, jwt.MapClaims{
+		"user_id":    userID,
+		"session_id": uuid.New(),
+		"role":       entities.UserRole,
+		"token_type": "refresh",
+		"exp":        time.Now().Add(7 * 24 * time.Hour).Unix(),
+	})
+
+	// Set up mock expectations
+	// The user's current status is read back, the refresh token isn't looked up
+	mockAuthRepo.On("FindUserByID", mock.Anything, userID).Return(&entities.User{ID: userID, Status: entities.Deactivated}, nil).Once()
+
+	// Execute refresh token
+	_, err := service.RefreshToken(context.Background(), refreshToken)
+
+	// Verify results
+	assert.Equal(t, errors.ErrAccountDeactivated, err)
 	mockAuthRepo.AssertExpectations(t)
 	mockRedisRepo.AssertExpectations(t)
 }
@@ -550,12 +969,15 @@ func TestRefreshToken_TokenMismatch(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
 	}
 
 	// Create test user
 	userID := uuid.New()
+	sessionID := uuid.New()
 	user := &entities.User{
 		ID:          userID,
 		PhoneNumber: "09123456789",
@@ -564,31 +986,28 @@ func TestRefreshToken_TokenMismatch(t *testing.T) {
 	}
 
 	// Create valid refresh token
-	config, _ := config.LoadConfig()
-	claims := jwt.MapClaims{
+	refreshToken := signTestToken(t, jwt.MapClaims{
 		"user_id":    userID,
+		"session_id": sessionID,
 		"role":       user.Role,
 		"token_type": "refresh",
 		"exp":        time.Now().Add(7 * 24 * time.Hour).Unix(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	refreshToken, _ := token.SignedString([]byte(config.JWT.Secret))
+	})
 
 	// Create a different token to be returned by FindToken
 	storedToken := "different_refresh_token"
 
 	// Set up mock expectations
 	// Expect FindUserByID to be called once and return the test user
-	mockAuthRepo.On("FindUserByID", userID).Return(user, nil).Once()
+	mockAuthRepo.On("FindUserByID", mock.Anything, userID).Return(user, nil).Once()
 	// Expect FindToken to be called once and return a different token
-	mockRedisRepo.On("FindToken", userID.String()+":refresh").Return(storedToken, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, "session:"+sessionID.String()+":refresh").Return(storedToken, nil).Once()
 
 	// Execute refresh token
 	_, err := service.RefreshToken(context.Background(), refreshToken)
 
 	// Verify results
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "refresh token does not match stored token")
+	assert.Equal(t, errors.ErrInvalidToken, err)
 	mockAuthRepo.AssertExpectations(t)
 	mockRedisRepo.AssertExpectations(t)
 }
@@ -601,27 +1020,39 @@ func TestValidateToken(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
 	}
 
-	// Create test user
+	// Create test user and a session that has been idle for a while
 	userID := uuid.New()
+	session := &entities.Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		LastSeenAt: time.Now().Add(-time.Hour),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	key := "session:" + session.ID.String()
 
 	// Create access token
-	cfg, _ := config.LoadConfig()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userID.String(),
-		"type":    "access",
-		"exp":     time.Now().Add(time.Hour).Unix(),
+	accessToken := signTestToken(t, jwt.MapClaims{
+		"user_id":    userID.String(),
+		"session_id": session.ID.String(),
+		"token_type": "access",
+		"exp":        time.Now().Add(time.Hour).Unix(),
 	})
-	accessToken, _ := token.SignedString([]byte(cfg.JWT.Secret))
 
 	// Set up mock expectations
-	mockRedisRepo.On("FindToken", userID.String()+":access").Return(accessToken, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key+":access").Return(accessToken, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return(encodeTestSession(t, session), nil).Once()
+	// Expect the last seen time to be written back
+	mockRedisRepo.On("AddToken", mock.Anything, key, mock.Anything, mock.Anything).Return(nil).Once()
+	expectSessionNotRevoked(mockRedisRepo)
 
 	// Execute validate token
-	err := service.ValidateToken(context.Background(), userID.String(), accessToken)
+	err := service.ValidateToken(context.Background(), userID.String(), session.ID.String(), accessToken)
 
 	// Verify results
 	assert.NoError(t, err)
@@ -637,22 +1068,24 @@ func TestValidateToken_InvalidToken(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
 	}
 
 	// Create test user
 	userID := uuid.New()
+	sessionID := uuid.New()
 
 	// Set up mock expectations
-	mockRedisRepo.On("FindToken", userID.String()+":access").Return("", fmt.Errorf("token not found")).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, "session:"+sessionID.String()+":access").Return("another_token", nil).Once()
 
 	// Execute validate token with invalid token
-	err := service.ValidateToken(context.Background(), userID.String(), "invalid_token")
+	err := service.ValidateToken(context.Background(), userID.String(), sessionID.String(), "invalid_token")
 
 	// Verify results
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to find token")
+	assert.Equal(t, errors.ErrInvalidToken, err)
 	mockRedisRepo.AssertExpectations(t)
 }
 
@@ -664,27 +1097,29 @@ func TestValidateToken_TokenNotFound(t *testing.T) {
 
 	// Create service instance with mock repositories
 	service := &AuthService{
-		db:    mockAuthRepo,
-		redis: mockRedisRepo,
+		db:     mockAuthRepo,
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
 	}
 
 	// Create test user
 	userID := uuid.New()
+	sessionID := uuid.New()
 
 	// Create access token
-	cfg, _ := config.LoadConfig()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userID.String(),
-		"type":    "access",
-		"exp":     time.Now().Add(time.Hour).Unix(),
+	accessToken := signTestToken(t, jwt.MapClaims{
+		"user_id":    userID.String(),
+		"session_id": sessionID.String(),
+		"token_type": "access",
+		"exp":        time.Now().Add(time.Hour).Unix(),
 	})
-	accessToken, _ := token.SignedString([]byte(cfg.JWT.Secret))
 
 	// Set up mock expectations
-	mockRedisRepo.On("FindToken", userID.String()+":access").Return("", fmt.Errorf("token not found")).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, "session:"+sessionID.String()+":access").Return("", errors.ErrTokenNotFound).Once()
 
 	// Execute validate token
-	err := service.ValidateToken(context.Background(), userID.String(), accessToken)
+	err := service.ValidateToken(context.Background(), userID.String(), sessionID.String(), accessToken)
 
 	// Verify results
 	assert.Error(t, err)
@@ -713,22 +1148,20 @@ func TestNewAuthService(t *testing.T) {
 // TestParseAndValidateToken_ExpiredToken tests token parsing with expired token
 func TestParseAndValidateToken_ExpiredToken(t *testing.T) {
 	// Create service instance with mock repositories
-	service := &AuthService{}
+	service := &AuthService{signer: newTestSigner(t), logger: newTestLogger()}
 
 	// Create test user
 	userID := uuid.New()
 
 	// Create expired token
-	cfg, _ := config.LoadConfig()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	expiredToken := signTestToken(t, jwt.MapClaims{
 		"user_id": userID.String(),
 		"type":    "access",
 		"exp":     time.Now().Add(-time.Hour).Unix(),
 	})
-	expiredToken, _ := token.SignedString([]byte(cfg.JWT.Secret))
 
 	// Execute validate token
-	_, err := service.parseAndValidateToken(context.Background(), expiredToken, "access")
+	_, _, err := service.parseAndValidateToken(context.Background(), expiredToken, "access")
 
 	// Verify results
 	assert.Error(t, err)
@@ -738,7 +1171,7 @@ func TestParseAndValidateToken_ExpiredToken(t *testing.T) {
 // TestParseAndValidateToken_InvalidSignature tests token parsing with invalid signature
 func TestParseAndValidateToken_InvalidSignature(t *testing.T) {
 	// Create service instance with mock repositories
-	service := &AuthService{}
+	service := &AuthService{signer: newTestSigner(t), logger: newTestLogger()}
 
 	// Create test user
 	userID := uuid.New()
@@ -754,7 +1187,7 @@ func TestParseAndValidateToken_InvalidSignature(t *testing.T) {
 	invalidToken, _ := token.SignedString([]byte("wrong_secret"))
 
 	// Execute validate token
-	_, err := service.parseAndValidateToken(context.Background(), invalidToken, "access")
+	_, _, err := service.parseAndValidateToken(context.Background(), invalidToken, "access")
 
 	// Verify results
 	assert.Error(t, err)
@@ -765,18 +1198,16 @@ func TestParseAndValidateToken_InvalidSignature(t *testing.T) {
 func TestParseAndValidateToken_MissingClaims(t *testing.T) {
 
 	// Create service instance with mock repositories
-	service := &AuthService{}
+	service := &AuthService{signer: newTestSigner(t), logger: newTestLogger()}
 
 	// Create token with missing claims
-	cfg, _ := config.LoadConfig()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	invalidToken := signTestToken(t, jwt.MapClaims{
 		"user_id": uuid.New().String(),
 		"exp":     time.Now().Add(time.Hour).Unix(),
 	})
-	invalidToken, _ := token.SignedString([]byte(cfg.JWT.Secret))
 
 	// Execute validate token
-	_, err := service.parseAndValidateToken(context.Background(), invalidToken, "access")
+	_, _, err := service.parseAndValidateToken(context.Background(), invalidToken, "access")
 
 	// Verify results
 	assert.Error(t, err)
@@ -785,18 +1216,16 @@ func TestParseAndValidateToken_MissingClaims(t *testing.T) {
 // TestParseAndValidateToken_MissingUserID tests token parsing when user ID is missing
 func TestParseAndValidateToken_MissingUserID(t *testing.T) {
 	// Create service instance with mock repositories
-	service := &AuthService{}
+	service := &AuthService{signer: newTestSigner(t), logger: newTestLogger()}
 
 	// Create token without user ID
-	cfg, _ := config.LoadConfig()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"type": "access",
-		"exp":  time.Now().Add(time.Hour).Unix(),
+	invalidToken := signTestToken(t, jwt.MapClaims{
+		"token_type": "access",
+		"exp":        time.Now().Add(time.Hour).Unix(),
 	})
-	invalidToken, _ := token.SignedString([]byte(cfg.JWT.Secret))
 
 	// Execute validate token
-	_, err := service.parseAndValidateToken(context.Background(), invalidToken, "access")
+	_, _, err := service.parseAndValidateToken(context.Background(), invalidToken, "access")
 
 	// Verify results
 	assert.Error(t, err)
@@ -805,19 +1234,17 @@ func TestParseAndValidateToken_MissingUserID(t *testing.T) {
 // TestParseAndValidateToken_InvalidUserIDFormat tests token parsing with invalid user ID format
 func TestParseAndValidateToken_InvalidUserIDFormat(t *testing.T) {
 	// Create service instance with mock repositories
-	service := &AuthService{}
+	service := &AuthService{signer: newTestSigner(t), logger: newTestLogger()}
 
 	// Create token with invalid user ID format
-	cfg, _ := config.LoadConfig()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": 123,
-		"type":    "access",
-		"exp":     time.Now().Add(time.Hour).Unix(),
+	invalidToken := signTestToken(t, jwt.MapClaims{
+		"user_id":    123,
+		"token_type": "access",
+		"exp":        time.Now().Add(time.Hour).Unix(),
 	})
-	invalidToken, _ := token.SignedString([]byte(cfg.JWT.Secret))
 
 	// Execute validate token
-	_, err := service.parseAndValidateToken(context.Background(), invalidToken, "access")
+	_, _, err := service.parseAndValidateToken(context.Background(), invalidToken, "access")
 
 	// Verify results
 	assert.Error(t, err)
@@ -826,19 +1253,17 @@ func TestParseAndValidateToken_InvalidUserIDFormat(t *testing.T) {
 // TestParseAndValidateToken_InvalidUserIDString tests token parsing with invalid user ID string
 func TestParseAndValidateToken_InvalidUserIDString(t *testing.T) {
 	// Create service instance with mock repositories
-	service := &AuthService{}
+	service := &AuthService{signer: newTestSigner(t), logger: newTestLogger()}
 
 	// Create token with invalid user ID string
-	cfg, _ := config.LoadConfig()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": "not-a-uuid",
-		"type":    "access",
-		"exp":     time.Now().Add(time.Hour).Unix(),
+	invalidToken := signTestToken(t, jwt.MapClaims{
+		"user_id":    "not-a-uuid",
+		"token_type": "access",
+		"exp":        time.Now().Add(time.Hour).Unix(),
 	})
-	invalidToken, _ := token.SignedString([]byte(cfg.JWT.Secret))
 
 	// Execute validate token
-	_, err := service.parseAndValidateToken(context.Background(), invalidToken, "access")
+	_, _, err := service.parseAndValidateToken(context.Background(), invalidToken, "access")
 
 	// Verify results
 	assert.Error(t, err)
@@ -0,0 +1,386 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// sessionTouchInterval limits how often LastSeenAt is written back while a
+// session is being used
+const sessionTouchInterval = 5 * time.Minute
+
+// ListSessions returns the active sessions of a user, most recently used
+// first. currentSessionID marks the session making the request.
+func (s *AuthService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]dto.SessionResponse, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while listing sessions",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	sessionIDs, err := s.redis.FindSetMembers(ctx, userSessionsKey(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]dto.SessionResponse, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		session, err := s.findSession(ctx, userID, sessionID)
+		if err != nil {
+			if err == errors.ErrSessionNotFound {
+				// The session expired on its own, drop the stale reference
+				if err := s.redis.RemoveFromSet(ctx, userSessionsKey(userID), sessionID); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return nil, err
+		}
+
+		sessions = append(sessions, dto.SessionResponse{
+			ID:         session.ID.String(),
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    sessionID == currentSessionID,
+		})
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+// RevokeSession ends one of the user's sessions, revoking its access and
+// refresh tokens
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while revoking session",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+			ports.F("session_id", sessionID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	if _, err := s.findSession(ctx, userID, sessionID); err != nil {
+		return err
+	}
+
+	if err := removeSession(ctx, s.redis, userID, sessionID); err != nil {
+		return err
+	}
+
+	s.logger.Info("Session revoked",
+		ports.F("user_id", userID),
+		ports.F("session_id", sessionID),
+	)
+
+	if id, err := uuid.Parse(userID); err == nil {
+		recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+			Type:     entities.AuditSessionRevoked,
+			ActorID:  &id,
+			TargetID: &id,
+			Details:  map[string]string{"session_id": sessionID},
+		})
+	}
+	return nil
+}
+
+// LogoutAll ends every session of the user
+func (s *AuthService) LogoutAll(ctx context.Context, userID string) error {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while logging out all sessions",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return errors.ErrContextCancelled
+	}
+
+	revoked, err := revokeSessions(ctx, s.redis, userID)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("All sessions revoked",
+		ports.F("user_id", userID),
+		ports.F("sessions", revoked),
+	)
+
+	if id, err := uuid.Parse(userID); err == nil {
+		recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+			Type:     entities.AuditLogoutAll,
+			ActorID:  &id,
+			TargetID: &id,
+			Details:  map[string]string{"sessions": strconv.Itoa(revoked)},
+		})
+	}
+	return nil
+}
+
+// revokeSessions ends every session of the user and returns how many there
+// were
+func revokeSessions(ctx context.Context, redis ports.InMemoryRespositoryContracts, userID string) (int, error) {
+	sessionIDs, err := redis.FindSetMembers(ctx, userSessionsKey(userID))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, sessionID := range sessionIDs {
+		if err := removeSession(ctx, redis, userID, sessionID); err != nil {
+			return 0, err
+		}
+	}
+	return len(sessionIDs), nil
+}
+
+func sessionKey(sessionID string) string {
+	return "session:" + sessionID
+}
+
+func userSessionsKey(userID string) string {
+	return userID + ":sessions"
+}
+
+func rotatedTokensKey(sessionID string) string {
+	return sessionKey(sessionID) + ":rotated"
+}
+
+// revokedSessionKey marks a session as revoked for as long as any of its
+// refresh tokens could still be valid
+func revokedSessionKey(sessionID string) string {
+	return sessionKey(sessionID) + ":revoked"
+}
+
+// createSession starts a new session for user on the device that sent the
+// current request. The session record itself is stored once its first token
+// pair is issued.
+func (s *AuthService) createSession(ctx context.Context, user *entities.User, deviceName string) (*entities.Session, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	client := entities.ClientInfoFromContext(ctx)
+	now := time.Now()
+
+	session := &entities.Session{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		DeviceName: deviceName,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(refreshTokenExpiration),
+	}
+
+	if err := s.redis.AddToSet(ctx, userSessionsKey(user.ID.String()), session.ID.String(), refreshTokenExpiration); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// saveSession stores session until it expires
+func (s *AuthService) saveSession(ctx context.Context, session *entities.Session) error {
+	expiration := time.Until(session.ExpiresAt)
+	if expiration <= 0 {
+		return errors.ErrInvalidToken
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		s.logger.Error("Error encoding session",
+			ports.F("error", err),
+			ports.F("session_id", session.ID),
+		)
+		return errors.ErrAddToken
+	}
+
+	if err := s.redis.AddToken(ctx, sessionKey(session.ID.String()), string(data), expiration); err != nil {
+		return err
+	}
+
+	// A session revoked while it was being saved, for example by a concurrent
+	// refresh that detected reuse, must stay revoked. removeSession marks the
+	// session before removing anything, so checking after everything has been
+	// written catches the revocation whichever request ran first.
+	_, err = s.redis.FindToken(ctx, revokedSessionKey(session.ID.String()))
+	if err == nil {
+		s.logger.Warn("Session was revoked while it was being saved",
+			ports.F("user_id", session.UserID),
+			ports.F("session_id", session.ID),
+		)
+		if err := removeSession(ctx, s.redis, session.UserID.String(), session.ID.String()); err != nil {
+			return err
+		}
+		return errors.ErrSessionRevoked
+	}
+	if !errors.IsNotFoundError(err) {
+		return err
+	}
+	return nil
+}
+
+// findSession loads a session that belongs to userID
+func (s *AuthService) findSession(ctx context.Context, userID, sessionID string) (*entities.Session, error) {
+	data, err := s.redis.FindToken(ctx, sessionKey(sessionID))
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return nil, errors.ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	var session entities.Session
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		s.logger.Error("Error decoding session",
+			ports.F("error", err),
+			ports.F("session_id", sessionID),
+		)
+		return nil, errors.ErrGetToken
+	}
+
+	if session.UserID.String() != userID {
+		s.logger.Error("Session belongs to another user",
+			ports.F("session_id", sessionID),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrSessionNotFound
+	}
+
+	return &session, nil
+}
+
+// touchSession records that the session has just been used
+func (s *AuthService) touchSession(ctx context.Context, userID, sessionID string) error {
+	session, err := s.findSession(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if time.Since(session.LastSeenAt) < sessionTouchInterval {
+		return nil
+	}
+
+	session.LastSeenAt = time.Now()
+	return s.saveSession(ctx, session)
+}
+
+// removeSession revokes the access and refresh tokens of a session and
+// forgets the session itself
+func removeSession(ctx context.Context, redis ports.InMemoryRespositoryContracts, userID, sessionID string) error {
+	// The session is marked first, so a save of it that is running at the
+	// same time fails instead of bringing it back
+	if err := redis.AddToken(ctx, revokedSessionKey(sessionID), "1", refreshTokenExpiration); err != nil {
+		return err
+	}
+
+	if err := redis.RemoveToken(ctx, sessionKey(sessionID)+":access"); err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if err := redis.RemoveToken(ctx, sessionKey(sessionID)+":refresh"); err != nil {
+		return err
+	}
+
+	if err := redis.RemoveToken(ctx, sessionKey(sessionID)); err != nil {
+		return err
+	}
+
+	if err := redis.RemoveToken(ctx, rotatedTokensKey(sessionID)); err != nil {
+		return err
+	}
+
+	return redis.RemoveFromSet(ctx, userSessionsKey(userID), sessionID)
+}
+
+// markRefreshTokenRotated remembers the ID of a refresh token that is being
+// replaced so a later replay of it can be recognised. Every session is a
+// refresh token family and its ID is the lineage shared by all the tokens
+// issued for it. The ID is claimed atomically, so when the same token is
+// refreshed concurrently only one request rotates it and the others are
+// treated as a replay.
+func (s *AuthService) markRefreshTokenRotated(ctx context.Context, userID, sessionID, tokenID string) error {
+	if tokenID == "" {
+		s.logger.Error("Invalid refresh token",
+			ports.F("user_id", userID),
+			ports.F("session_id", sessionID),
+		)
+		return errors.ErrInvalidToken
+	}
+
+	claimed, err := s.redis.AddToSetIfAbsent(ctx, rotatedTokensKey(sessionID), tokenID, refreshTokenExpiration)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return s.revokeTokenFamily(ctx, userID, sessionID, tokenID)
+	}
+	return nil
+}
+
+// revokeTokenFamily ends the session of a refresh token that was used after
+// it had been rotated
+func (s *AuthService) revokeTokenFamily(ctx context.Context, userID, sessionID, tokenID string) error {
+	s.logger.Warn("Refresh token reuse detected, revoking token family",
+		ports.F("security_event", "refresh_token_reuse"),
+		ports.F("user_id", userID),
+		ports.F("session_id", sessionID),
+		ports.F("token_id", tokenID),
+		ports.F("ip", entities.ClientInfoFromContext(ctx).IP),
+	)
+	if err := removeSession(ctx, s.redis, userID, sessionID); err != nil {
+		return err
+	}
+	return errors.ErrRefreshTokenReused
+}
+
+// checkRefreshTokenReuse is called when a correctly signed refresh token is
+// not the current one of its session. If the token was already rotated it
+// has most likely been stolen, so the whole family is revoked.
+func (s *AuthService) checkRefreshTokenReuse(ctx context.Context, userID, sessionID, tokenID string) error {
+	if tokenID == "" {
+		s.logger.Error("Invalid refresh token",
+			ports.F("user_id", userID),
+			ports.F("session_id", sessionID),
+		)
+		return errors.ErrInvalidToken
+	}
+
+	rotated, err := s.redis.FindSetMembers(ctx, rotatedTokensKey(sessionID))
+	if err != nil {
+		return err
+	}
+
+	for _, id := range rotated {
+		if id == tokenID {
+			return s.revokeTokenFamily(ctx, userID, sessionID, tokenID)
+		}
+	}
+
+	s.logger.Error("Invalid refresh token",
+		ports.F("user_id", userID),
+		ports.F("session_id", sessionID),
+	)
+	return errors.ErrInvalidToken
+}
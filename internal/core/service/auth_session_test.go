@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestListSessions tests that sessions are listed newest first and expired ones are dropped
+func TestListSessions(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	userID := uuid.New()
+	older := &entities.Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		DeviceName: "Laptop",
+		IP:         "10.0.0.1",
+		LastSeenAt: time.Now().Add(-time.Hour),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	newer := &entities.Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		DeviceName: "Phone",
+		IP:         "10.0.0.2",
+		LastSeenAt: time.Now(),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	expiredID := uuid.New().String()
+
+	mockRedisRepo.On("FindSetMembers", mock.Anything, userID.String()+":sessions").
+		Return([]string{older.ID.String(), expiredID, newer.ID.String()}, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, "session:"+older.ID.String()).Return(encodeTestSession(t, older), nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, "session:"+expiredID).Return("", errors.ErrTokenNotFound).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, "session:"+newer.ID.String()).Return(encodeTestSession(t, newer), nil).Once()
+	mockRedisRepo.On("RemoveFromSet", mock.Anything, userID.String()+":sessions", expiredID).Return(nil).Once()
+
+	sessions, err := service.ListSessions(context.Background(), userID.String(), older.ID.String())
+
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.Equal(t, newer.ID.String(), sessions[0].ID)
+	assert.Equal(t, "Phone", sessions[0].DeviceName)
+	assert.False(t, sessions[0].Current)
+	assert.Equal(t, older.ID.String(), sessions[1].ID)
+	assert.Equal(t, "10.0.0.1", sessions[1].IP)
+	assert.True(t, sessions[1].Current)
+}
+
+// expectSessionsRevoked expects every one of sessionIDs of the user to be removed
+func expectSessionsRevoked(mockRedisRepo *mocks.InMemoryRespositoryContracts, userID string, sessionIDs ...string) {
+	mockRedisRepo.On("FindSetMembers", mock.Anything, userID+":sessions").Return(sessionIDs, nil).Once()
+	for _, sessionID := range sessionIDs {
+		key := "session:" + sessionID
+		mockRedisRepo.On("AddToken", mock.Anything, key+":revoked", "1", refreshTokenExpiration).Return(nil).Once()
+		mockRedisRepo.On("RemoveToken", mock.Anything, key+":access").Return(nil).Once()
+		mockRedisRepo.On("RemoveToken", mock.Anything, key+":refresh").Return(nil).Once()
+		mockRedisRepo.On("RemoveToken", mock.Anything, key).Return(nil).Once()
+		mockRedisRepo.On("RemoveToken", mock.Anything, key+":rotated").Return(nil).Once()
+		mockRedisRepo.On("RemoveFromSet", mock.Anything, userID+":sessions", sessionID).Return(nil).Once()
+	}
+}
+
+// expectSessionNotRevoked expects a saved session to be checked for a
+// revocation that isn't there
+func expectSessionNotRevoked(mockRedisRepo *mocks.InMemoryRespositoryContracts) {
+	mockRedisRepo.On("FindToken", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasSuffix(key, ":revoked") })).
+		Return("", errors.ErrTokenNotFound).Once()
+}
+
+// memoryStore keeps keys like Redis does, expiring them by a clock the test
+// moves forward
+type memoryStore struct {
+	mu      sync.Mutex
+	now     time.Time
+	values  map[string]string
+	sets    map[string]map[string]bool
+	expires map[string]time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		now:     time.Now(),
+		values:  map[string]string{},
+		sets:    map[string]map[string]bool{},
+		expires: map[string]time.Time{},
+	}
+}
+
+// advance moves the clock of the store forward by d
+func (m *memoryStore) advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = m.now.Add(d)
+}
+
+// expire drops key if its time to live has passed
+func (m *memoryStore) expire(key string) {
+	if at, ok := m.expires[key]; ok && !m.now.Before(at) {
+		delete(m.values, key)
+		delete(m.sets, key)
+		delete(m.expires, key)
+	}
+}
+
+func (m *memoryStore) AddToken(ctx context.Context, key, value string, expiration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value
+	m.expires[key] = m.now.Add(expiration)
+	return nil
+}
+
+func (m *memoryStore) RemoveToken(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.values, key)
+	delete(m.sets, key)
+	delete(m.expires, key)
+	return nil
+}
+
+func (m *memoryStore) FindToken(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expire(key)
+	value, ok := m.values[key]
+	if !ok {
+		return "", errors.ErrTokenNotFound
+	}
+	return value, nil
+}
+
+func (m *memoryStore) TakeToken(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expire(key)
+	value, ok := m.values[key]
+	if !ok {
+		return "", errors.ErrTokenNotFound
+	}
+	delete(m.values, key)
+	delete(m.expires, key)
+	return value, nil
+}
+
+func (m *memoryStore) IncrementCounter(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	panic("not used by the session tests")
+}
+
+func (m *memoryStore) AddToSet(ctx context.Context, key, member string, expiration time.Duration) error {
+	_, err := m.AddToSetIfAbsent(ctx, key, member, expiration)
+	return err
+}
+
+func (m *memoryStore) AddToSetIfAbsent(ctx context.Context, key, member string, expiration time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expire(key)
+	if m.sets[key] == nil {
+		m.sets[key] = map[string]bool{}
+	}
+	added := !m.sets[key][member]
+	m.sets[key][member] = true
+	m.expires[key] = m.now.Add(expiration)
+	return added, nil
+}
+
+func (m *memoryStore) RemoveFromSet(ctx context.Context, key, member string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sets[key], member)
+	return nil
+}
+
+func (m *memoryStore) FindSetMembers(ctx context.Context, key string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expire(key)
+	members := []string{}
+	for member := range m.sets[key] {
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// racingStore runs race once, just before a session is added to the index of
+// the user's sessions
+type racingStore struct {
+	*memoryStore
+	race func()
+}
+
+func (r *racingStore) AddToSet(ctx context.Context, key, member string, expiration time.Duration) error {
+	if race := r.race; race != nil {
+		r.race = nil
+		race()
+	}
+	return r.memoryStore.AddToSet(ctx, key, member, expiration)
+}
+
+// TestRefreshToken_RevokedDuringRotation tests that a session revoked by a
+// concurrent refresh with the same token isn't brought back by the request
+// that rotated it
+func TestRefreshToken_RevokedDuringRotation(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	store := &racingStore{memoryStore: newMemoryStore()}
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  store,
+		audit:  newTestAuditRepository(t),
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	user := &entities.User{ID: uuid.New(), PhoneNumber: "09123456789", Role: entities.UserRole, Status: entities.Active}
+	mockAuthRepo.On("FindUserByID", mock.Anything, user.ID).Return(user, nil)
+
+	tokens, err := service.createTokenPair(context.Background(), user, "Phone")
+	assert.NoError(t, err)
+	sessionIDs, err := store.FindSetMembers(context.Background(), userSessionsKey(user.ID.String()))
+	assert.NoError(t, err)
+	assert.Len(t, sessionIDs, 1)
+
+	// The second request arrives after the first one claimed the token but
+	// before it stored the new pair
+	store.race = func() {
+		_, err := service.RefreshToken(context.Background(), tokens.RefreshToken)
+		assert.Equal(t, errors.ErrRefreshTokenReused, err)
+	}
+	_, err = service.RefreshToken(context.Background(), tokens.RefreshToken)
+	assert.Equal(t, errors.ErrSessionRevoked, err)
+
+	_, err = store.FindToken(context.Background(), sessionKey(sessionIDs[0])+":refresh")
+	assert.Equal(t, errors.ErrTokenNotFound, err)
+	_, err = store.FindToken(context.Background(), sessionKey(sessionIDs[0]))
+	assert.Equal(t, errors.ErrTokenNotFound, err)
+}
+
+// TestLogoutAll_AfterRefresh tests that a session kept alive by refreshing
+// can still be revoked once the index entry of its first token would have expired
+func TestLogoutAll_AfterRefresh(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	store := newMemoryStore()
+
+	service := &AuthService{
+		db:     mockAuthRepo,
+		redis:  store,
+		audit:  newTestAuditRepository(t),
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	user := &entities.User{ID: uuid.New(), PhoneNumber: "09123456789", Role: entities.UserRole, Status: entities.Active}
+	mockAuthRepo.On("FindUserByID", mock.Anything, user.ID).Return(user, nil)
+
+	tokens, err := service.createTokenPair(context.Background(), user, "Phone")
+	assert.NoError(t, err)
+
+	store.advance(6 * 24 * time.Hour)
+	tokens, err = service.RefreshToken(context.Background(), tokens.RefreshToken)
+	assert.NoError(t, err)
+
+	// The refresh token issued at login, and its index entry, have expired by now
+	store.advance(2 * 24 * time.Hour)
+	err = service.LogoutAll(context.Background(), user.ID.String())
+	assert.NoError(t, err)
+
+	_, err = service.RefreshToken(context.Background(), tokens.RefreshToken)
+	assert.Equal(t, errors.ErrTokenNotFound, err)
+}
+
+// TestRevokeSession tests that a session's tokens are removed together and the revocation is recorded
+func TestRevokeSession(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockAuditRepo := mocks.NewMockAuditRepository(t)
+
+	service := &AuthService{
+		redis:  mockRedisRepo,
+		audit:  mockAuditRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	userID := uuid.New()
+	session := &entities.Session{ID: uuid.New(), UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+	key := "session:" + session.ID.String()
+
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return(encodeTestSession(t, session), nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, key+":revoked", "1", refreshTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":access").Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":refresh").Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key).Return(nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key+":rotated").Return(nil).Once()
+	mockRedisRepo.On("RemoveFromSet", mock.Anything, userID.String()+":sessions", session.ID.String()).Return(nil).Once()
+	mockAuditRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(event *entities.AuditEvent) bool {
+		return event.Type == entities.AuditSessionRevoked && *event.ActorID == userID && *event.TargetID == userID &&
+			event.Details["session_id"] == session.ID.String()
+	})).Return(nil).Once()
+
+	err := service.RevokeSession(context.Background(), userID.String(), session.ID.String())
+
+	assert.NoError(t, err)
+}
+
+// TestRevokeSession_OtherUser tests that a user can't revoke someone else's session
+func TestRevokeSession_OtherUser(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AuthService{
+		redis:  mockRedisRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	session := &entities.Session{ID: uuid.New(), UserID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockRedisRepo.On("FindToken", mock.Anything, "session:"+session.ID.String()).Return(encodeTestSession(t, session), nil).Once()
+
+	err := service.RevokeSession(context.Background(), uuid.New().String(), session.ID.String())
+
+	assert.Equal(t, errors.ErrSessionNotFound, err)
+}
+
+// TestLogoutAll tests that every session of the user is removed and the logout is recorded
+func TestLogoutAll(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockAuditRepo := mocks.NewMockAuditRepository(t)
+
+	service := &AuthService{
+		redis:  mockRedisRepo,
+		audit:  mockAuditRepo,
+		signer: newTestSigner(t),
+		logger: newTestLogger(),
+	}
+
+	userID := uuid.New()
+	sessionIDs := []string{uuid.New().String(), uuid.New().String()}
+
+	expectSessionsRevoked(mockRedisRepo, userID.String(), sessionIDs...)
+	mockAuditRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(event *entities.AuditEvent) bool {
+		return event.Type == entities.AuditLogoutAll && *event.ActorID == userID && *event.TargetID == userID &&
+			event.Details["sessions"] == "2"
+	})).Return(nil).Once()
+
+	err := service.LogoutAll(context.Background(), userID.String())
+
+	assert.NoError(t, err)
+}
+
+// TestChangeUserStatus_RevokesSessions tests that deactivating a user ends their sessions right away
+func TestChangeUserStatus_RevokesSessions(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		logger: newTestLogger(),
+	}
+
+	actor := &entities.Principal{UserID: uuid.New(), Role: entities.AdminRole}
+	userID := uuid.New()
+	status := entities.Deactivated
+
+	mockAdminRepo.On("AdminGetUserByID", mock.Anything, &userID).Return(&entities.User{ID: userID, Role: entities.UserRole, Status: entities.Active}, nil).Once()
+	mockAdminRepo.On("AdminChangeUserStatus", mock.Anything, &userID, &status).Return(nil).Once()
+	expectSessionsRevoked(mockRedisRepo, userID.String(), uuid.New().String(), uuid.New().String())
+
+	err := service.ChangeUserStatus(context.Background(), actor, &userID, &status)
+
+	assert.NoError(t, err)
+}
+
+// TestChangeUserStatus_ActivateKeepsSessions tests that activating a user doesn't touch their sessions
+func TestChangeUserStatus_ActivateKeepsSessions(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		logger: newTestLogger(),
+	}
+
+	actor := &entities.Principal{UserID: uuid.New(), Role: entities.AdminRole}
+	userID := uuid.New()
+	status := entities.Active
+
+	mockAdminRepo.On("AdminGetUserByID", mock.Anything, &userID).Return(&entities.User{ID: userID, Role: entities.UserRole, Status: entities.Deactivated}, nil).Once()
+	mockAdminRepo.On("AdminChangeUserStatus", mock.Anything, &userID, &status).Return(nil).Once()
+
+	err := service.ChangeUserStatus(context.Background(), actor, &userID, &status)
+
+	assert.NoError(t, err)
+	mockRedisRepo.AssertNotCalled(t, "FindSetMembers", mock.Anything, mock.Anything)
+}
+
+// TestAdminDeleteUser_RevokesSessions tests that deleting a user ends their sessions right away
+func TestAdminDeleteUser_RevokesSessions(t *testing.T) {
+	mockAdminRepo := mocks.NewMockAdminRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+
+	service := &AdminService{
+		db:     mockAdminRepo,
+		redis:  mockRedisRepo,
+		audit:  newTestAuditRepository(t),
+		logger: newTestLogger(),
+	}
+
+	actor := &entities.Principal{UserID: uuid.New(), Role: entities.AdminRole}
+	userID := uuid.New()
+
+	mockAdminRepo.On("AdminGetUserByID", mock.Anything, &userID).Return(&entities.User{ID: userID, Role: entities.UserRole}, nil).Once()
+	mockAdminRepo.On("AdminDeleteUser", mock.Anything, &userID).Return(nil).Once()
+	expectSessionsRevoked(mockRedisRepo, userID.String(), uuid.New().String())
+
+	err := service.AdminDeleteUser(context.Background(), actor, &userID)
+
+	assert.NoError(t, err)
+}
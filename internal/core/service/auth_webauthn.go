@@ -0,0 +1,383 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/infrastructure/webauthn"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const (
+	webauthnChallengeSize       = 32
+	webauthnChallengeExpiration = 5 * time.Minute
+	webauthnCredentialType      = "public-key"
+)
+
+// newRelyingParty creates the WebAuthn relying party described by the
+// configuration
+func newRelyingParty(logger ports.Logger) ports.WebAuthnRelyingParty {
+	config, err := config.LoadConfig()
+	if err != nil {
+		panic(errors.ErrLoadConfig)
+	}
+
+	return webauthn.NewRelyingParty(config.WebAuthn, logger)
+}
+
+// BeginWebAuthnRegistration returns the options the user's browser needs to
+// create a passkey for this site
+func (s *AuthService) BeginWebAuthnRegistration(ctx context.Context, userID string) (*dto.WebAuthnRegistrationOptions, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while starting WebAuthn registration",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.ErrInvalidUserID
+	}
+
+	user, err := s.db.FindUserByID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := s.webauthn.FindCredentialsByUserID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := newWebAuthnChallenge()
+	if err != nil {
+		s.logger.Error("Error generating WebAuthn challenge",
+			ports.F("error", err),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrInternalServer
+	}
+
+	if err := s.redis.AddToken(ctx, webauthnRegistrationKey(userID), challenge, webauthnChallengeExpiration); err != nil {
+		return nil, err
+	}
+
+	displayName := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if displayName == "" {
+		displayName = user.PhoneNumber
+	}
+
+	params := make([]dto.WebAuthnCredentialParameters, 0, len(s.relyingParty.Algorithms()))
+	for _, alg := range s.relyingParty.Algorithms() {
+		params = append(params, dto.WebAuthnCredentialParameters{Type: webauthnCredentialType, Alg: alg})
+	}
+
+	return &dto.WebAuthnRegistrationOptions{
+		Challenge: challenge,
+		RP: dto.WebAuthnRelyingPartyEntity{
+			ID:   s.relyingParty.ID(),
+			Name: s.relyingParty.Name(),
+		},
+		User: dto.WebAuthnUserEntity{
+			ID:          base64.RawURLEncoding.EncodeToString(userUUID[:]),
+			Name:        user.PhoneNumber,
+			DisplayName: displayName,
+		},
+		PubKeyCredParams:   params,
+		Timeout:            webauthnChallengeExpiration.Milliseconds(),
+		ExcludeCredentials: webauthnDescriptors(credentials),
+		// Passkeys must be discoverable so the user can sign in without
+		// typing their phone number first
+		AuthenticatorSelection: dto.WebAuthnAuthenticatorSelection{
+			ResidentKey:        "required",
+			RequireResidentKey: true,
+			UserVerification:   "required",
+		},
+		Attestation: "none",
+	}, nil
+}
+
+// FinishWebAuthnRegistration checks the credential the browser created and
+// saves it as one of the user's passkeys
+func (s *AuthService) FinishWebAuthnRegistration(ctx context.Context, userID string, req *dto.WebAuthnRegistrationRequest) (*dto.WebAuthnCredentialResponse, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while finishing WebAuthn registration",
+			ports.F("error", ctx.Err()),
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.ErrInvalidUserID
+	}
+
+	if req.Type != webauthnCredentialType {
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+	clientDataJSON, err := decodeWebAuthnBytes(req.Response.ClientDataJSON)
+	if err != nil {
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+	attestationObject, err := decodeWebAuthnBytes(req.Response.AttestationObject)
+	if err != nil {
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+
+	attestation, err := s.relyingParty.VerifyRegistration(clientDataJSON, attestationObject)
+	if err != nil {
+		s.logger.Warn("Invalid WebAuthn registration",
+			ports.F("error", err),
+			ports.F("user_id", userID),
+		)
+		return nil, err
+	}
+
+	if id, err := decodeWebAuthnBytes(req.ID); err != nil || !bytes.Equal(id, attestation.CredentialID) {
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+
+	// The challenge is removed before the credential is saved so it can only
+	// be used once
+	key := webauthnRegistrationKey(userID)
+	challenge, err := s.redis.FindToken(ctx, key)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return nil, errors.ErrWebAuthnChallengeNotFound
+		}
+		return nil, err
+	}
+	if err := s.redis.RemoveToken(ctx, key); err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(challenge), []byte(base64.RawURLEncoding.EncodeToString(attestation.Challenge))) != 1 {
+		s.logger.Warn("WebAuthn registration challenge mismatch",
+			ports.F("user_id", userID),
+		)
+		return nil, errors.ErrWebAuthnChallengeNotFound
+	}
+
+	credential := &entities.WebAuthnCredential{
+		ID:           uuid.New(),
+		UserID:       userUUID,
+		CredentialID: attestation.CredentialID,
+		PublicKey:    attestation.PublicKey,
+		SignCount:    attestation.SignCount,
+		Transports:   req.Response.Transports,
+		CreatedAt:    time.Now(),
+	}
+	if credential.Transports == nil {
+		credential.Transports = []string{}
+	}
+
+	if err := s.webauthn.CreateCredential(ctx, credential); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("WebAuthn credential registered",
+		ports.F("user_id", userID),
+		ports.F("credential_id", credential.ID),
+	)
+
+	return &dto.WebAuthnCredentialResponse{
+		ID:         base64.RawURLEncoding.EncodeToString(credential.CredentialID),
+		Transports: credential.Transports,
+		CreatedAt:  credential.CreatedAt,
+	}, nil
+}
+
+// BeginWebAuthnLogin returns the options for signing in with a passkey. The
+// user isn't known yet, the authenticator picks one of its credentials.
+func (s *AuthService) BeginWebAuthnLogin(ctx context.Context) (*dto.WebAuthnAssertionOptions, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while starting WebAuthn login",
+			ports.F("error", ctx.Err()),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	challenge, err := newWebAuthnChallenge()
+	if err != nil {
+		s.logger.Error("Error generating WebAuthn challenge",
+			ports.F("error", err),
+		)
+		return nil, errors.ErrInternalServer
+	}
+
+	if err := s.redis.AddToken(ctx, webauthnLoginKey(challenge), "pending", webauthnChallengeExpiration); err != nil {
+		return nil, err
+	}
+
+	return &dto.WebAuthnAssertionOptions{
+		Challenge:        challenge,
+		Timeout:          webauthnChallengeExpiration.Milliseconds(),
+		RPID:             s.relyingParty.ID(),
+		AllowCredentials: []dto.WebAuthnCredentialDescriptor{},
+		UserVerification: "required",
+	}, nil
+}
+
+// FinishWebAuthnLogin checks the passkey signature and starts a session the
+// same way a password login does. User verification on the authenticator
+// already is a second factor, so no TOTP code is asked for.
+func (s *AuthService) FinishWebAuthnLogin(ctx context.Context, req *dto.WebAuthnLoginRequest) (*entities.TokenPair, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while finishing WebAuthn login",
+			ports.F("error", ctx.Err()),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	if req.Type != webauthnCredentialType {
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+	credentialID, err := decodeWebAuthnBytes(req.ID)
+	if err != nil {
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+	clientDataJSON, err := decodeWebAuthnBytes(req.Response.ClientDataJSON)
+	if err != nil {
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+	authenticatorData, err := decodeWebAuthnBytes(req.Response.AuthenticatorData)
+	if err != nil {
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+	signature, err := decodeWebAuthnBytes(req.Response.Signature)
+	if err != nil {
+		return nil, errors.ErrInvalidWebAuthnResponse
+	}
+
+	credential, err := s.webauthn.FindCredentialByCredentialID(ctx, credentialID)
+	if err != nil {
+		if err == errors.ErrWebAuthnCredentialNotFound {
+			s.logger.Warn("Unknown WebAuthn credential")
+			return nil, errors.ErrInvalidWebAuthnResponse
+		}
+		return nil, err
+	}
+
+	if req.Response.UserHandle != "" {
+		userHandle, err := decodeWebAuthnBytes(req.Response.UserHandle)
+		if err != nil || !bytes.Equal(userHandle, credential.UserID[:]) {
+			s.logger.Warn("WebAuthn user handle mismatch",
+				ports.F("user_id", credential.UserID),
+			)
+			return nil, errors.ErrInvalidWebAuthnResponse
+		}
+	}
+
+	assertion, err := s.relyingParty.VerifyAssertion(credential.PublicKey, clientDataJSON, authenticatorData, signature)
+	if err != nil {
+		s.logger.Warn("Invalid WebAuthn assertion",
+			ports.F("error", err),
+			ports.F("user_id", credential.UserID),
+		)
+		return nil, err
+	}
+
+	// The challenge must be one we issued, and can only be used once
+	key := webauthnLoginKey(base64.RawURLEncoding.EncodeToString(assertion.Challenge))
+	if _, err := s.redis.FindToken(ctx, key); err != nil {
+		if errors.IsNotFoundError(err) {
+			return nil, errors.ErrWebAuthnChallengeNotFound
+		}
+		return nil, err
+	}
+	used, err := s.redis.IncrementCounter(ctx, key+":used", webauthnChallengeExpiration)
+	if err != nil {
+		return nil, err
+	}
+	if used > 1 {
+		s.logger.Warn("WebAuthn challenge reused",
+			ports.F("user_id", credential.UserID),
+		)
+		return nil, errors.ErrWebAuthnChallengeNotFound
+	}
+	if err := s.redis.RemoveToken(ctx, key); err != nil {
+		return nil, err
+	}
+
+	// Authenticators that keep a counter must report a larger one every
+	// time, otherwise the key may have been cloned
+	if (assertion.SignCount != 0 || credential.SignCount != 0) && assertion.SignCount <= credential.SignCount {
+		s.logger.Warn("WebAuthn signature counter did not increase",
+			ports.F("user_id", credential.UserID),
+			ports.F("credential_id", credential.ID),
+		)
+		return nil, errors.ErrWebAuthnSignCount
+	}
+
+	if err := s.webauthn.UpdateCredentialSignCount(ctx, credential.ID, assertion.SignCount); err != nil {
+		return nil, err
+	}
+
+	user, err := s.db.FindUserByID(ctx, credential.UserID)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return nil, errors.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if user.Status == entities.Deleted {
+		s.logger.Error("User is deleted",
+			ports.F("user_id", user.ID),
+		)
+		return nil, errors.ErrInvalidCredentials
+	}
+	if user.Status == entities.Deactivated {
+		s.logger.Error("User is deactivated",
+			ports.F("user_id", user.ID),
+		)
+		return nil, errors.ErrAccountDeactivated
+	}
+
+	return s.createTokenPair(ctx, user, req.DeviceName)
+}
+
+func newWebAuthnChallenge() (string, error) {
+	b := make([]byte, webauthnChallengeSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeWebAuthnBytes decodes a base64url value, with or without padding
+func decodeWebAuthnBytes(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+func webauthnDescriptors(credentials []entities.WebAuthnCredential) []dto.WebAuthnCredentialDescriptor {
+	descriptors := make([]dto.WebAuthnCredentialDescriptor, 0, len(credentials))
+	for _, credential := range credentials {
+		descriptors = append(descriptors, dto.WebAuthnCredentialDescriptor{
+			Type:       webauthnCredentialType,
+			ID:         base64.RawURLEncoding.EncodeToString(credential.CredentialID),
+			Transports: credential.Transports,
+		})
+	}
+	return descriptors
+}
+
+func webauthnRegistrationKey(userID string) string {
+	return "webauthn_registration:" + userID
+}
+
+func webauthnLoginKey(challenge string) string {
+	return "webauthn_login:" + challenge
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/config"
+	"github.com/amirdashtii/go_auth/controller/dto"
+	"github.com/amirdashtii/go_auth/infrastructure/webauthn"
+	"github.com/amirdashtii/go_auth/infrastructure/webauthn/webauthntest"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testWebAuthnRPID   = "example.com"
+	testWebAuthnOrigin = "https://app.example.com"
+)
+
+func newTestRelyingParty() ports.WebAuthnRelyingParty {
+	return webauthn.NewRelyingParty(config.WebAuthnConfig{
+		RPID:    testWebAuthnRPID,
+		RPName:  "go_auth",
+		Origins: []string{testWebAuthnOrigin},
+	}, newTestLogger())
+}
+
+// newTestWebAuthnLogin returns the response of authenticator signing in with
+// the challenge of a login started with BeginWebAuthnLogin
+func newTestWebAuthnLogin(t *testing.T, authenticator *webauthntest.Authenticator, challenge string) *dto.WebAuthnLoginRequest {
+	rawChallenge, err := base64.RawURLEncoding.DecodeString(challenge)
+	require.NoError(t, err)
+
+	clientDataJSON, authData, signature := authenticator.Assert(rawChallenge)
+	return &dto.WebAuthnLoginRequest{
+		ID:   base64.RawURLEncoding.EncodeToString(authenticator.CredentialID),
+		Type: "public-key",
+		Response: dto.WebAuthnAssertionResponse{
+			ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientDataJSON),
+			AuthenticatorData: base64.RawURLEncoding.EncodeToString(authData),
+			Signature:         base64.RawURLEncoding.EncodeToString(signature),
+			UserHandle:        base64.RawURLEncoding.EncodeToString(authenticator.UserHandle),
+		},
+		DeviceName: "Pixel 8",
+	}
+}
+
+// TestWebAuthnRegistration tests registering a passkey with a software authenticator
+func TestWebAuthnRegistration(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockWebAuthnRepo := mocks.NewMockWebAuthnRepository(t)
+
+	service := &AuthService{
+		db:           mockAuthRepo,
+		redis:        mockRedisRepo,
+		webauthn:     mockWebAuthnRepo,
+		relyingParty: newTestRelyingParty(),
+		logger:       newTestLogger(),
+	}
+
+	user := &entities.User{ID: uuid.New(), PhoneNumber: "09123456789", FirstName: "Sara"}
+	key := webauthnRegistrationKey(user.ID.String())
+
+	var challenge string
+	mockAuthRepo.On("FindUserByID", mock.Anything, user.ID).Return(user, nil).Once()
+	mockWebAuthnRepo.On("FindCredentialsByUserID", mock.Anything, user.ID).Return([]entities.WebAuthnCredential{}, nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, key, mock.AnythingOfType("string"), webauthnChallengeExpiration).
+		Run(func(args mock.Arguments) { challenge = args.String(2) }).Return(nil).Once()
+
+	options, err := service.BeginWebAuthnRegistration(context.Background(), user.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, challenge, options.Challenge)
+	assert.Equal(t, testWebAuthnRPID, options.RP.ID)
+	assert.Equal(t, "Sara", options.User.DisplayName)
+	assert.Equal(t, "required", options.AuthenticatorSelection.UserVerification)
+
+	rawChallenge, err := base64.RawURLEncoding.DecodeString(options.Challenge)
+	require.NoError(t, err)
+	userHandle, err := base64.RawURLEncoding.DecodeString(options.User.ID)
+	require.NoError(t, err)
+
+	authenticator := webauthntest.New(testWebAuthnRPID, testWebAuthnOrigin)
+	clientDataJSON, attestationObject := authenticator.Register(rawChallenge, userHandle)
+
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return(challenge, nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key).Return(nil).Once()
+	mockWebAuthnRepo.On("CreateCredential", mock.Anything, mock.MatchedBy(func(c *entities.WebAuthnCredential) bool {
+		return c.UserID == user.ID &&
+			string(c.CredentialID) == string(authenticator.CredentialID) &&
+			string(c.PublicKey) == string(authenticator.PublicKey())
+	})).Return(nil).Once()
+
+	credential, err := service.FinishWebAuthnRegistration(context.Background(), user.ID.String(), &dto.WebAuthnRegistrationRequest{
+		ID:   base64.RawURLEncoding.EncodeToString(authenticator.CredentialID),
+		Type: "public-key",
+		Response: dto.WebAuthnAttestationResponse{
+			ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientDataJSON),
+			AttestationObject: base64.RawURLEncoding.EncodeToString(attestationObject),
+			Transports:        []string{"internal"},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(authenticator.CredentialID), credential.ID)
+	assert.Equal(t, []string{"internal"}, credential.Transports)
+}
+
+// TestWebAuthnLogin tests that signing in with a passkey starts a session
+func TestWebAuthnLogin(t *testing.T) {
+	mockAuthRepo := mocks.NewMockAuthRepository(t)
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockWebAuthnRepo := mocks.NewMockWebAuthnRepository(t)
+
+	service := &AuthService{
+		db:           mockAuthRepo,
+		redis:        mockRedisRepo,
+		webauthn:     mockWebAuthnRepo,
+		relyingParty: newTestRelyingParty(),
+		audit:        newTestAuditRepository(t),
+		signer:       newTestSigner(t),
+		logger:       newTestLogger(),
+	}
+
+	user := &entities.User{ID: uuid.New(), Status: entities.Active, Role: entities.UserRole}
+	authenticator := webauthntest.New(testWebAuthnRPID, testWebAuthnOrigin)
+	authenticator.UserHandle = user.ID[:]
+	authenticator.SignCount = 4
+	credential := &entities.WebAuthnCredential{
+		ID:           uuid.New(),
+		UserID:       user.ID,
+		CredentialID: authenticator.CredentialID,
+		PublicKey:    authenticator.PublicKey(),
+		SignCount:    4,
+	}
+
+	mockRedisRepo.On("AddToken", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "webauthn_login:") }), "pending", webauthnChallengeExpiration).Return(nil).Once()
+
+	options, err := service.BeginWebAuthnLogin(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testWebAuthnRPID, options.RPID)
+
+	key := webauthnLoginKey(options.Challenge)
+	mockWebAuthnRepo.On("FindCredentialByCredentialID", mock.Anything, authenticator.CredentialID).Return(credential, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return("pending", nil).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, key+":used", webauthnChallengeExpiration).Return(int64(1), nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key).Return(nil).Once()
+	mockWebAuthnRepo.On("UpdateCredentialSignCount", mock.Anything, credential.ID, uint32(5)).Return(nil).Once()
+	mockAuthRepo.On("FindUserByID", mock.Anything, user.ID).Return(user, nil).Once()
+	mockRedisRepo.On("AddToSet", mock.Anything, user.ID.String()+":sessions", mock.Anything, refreshTokenExpiration).Return(nil).Once()
+	mockRedisRepo.On("AddToken", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "session:") }), mock.Anything, mock.Anything).Return(nil).Times(3)
+	expectSessionNotRevoked(mockRedisRepo)
+
+	tokens, err := service.FinishWebAuthnLogin(context.Background(), newTestWebAuthnLogin(t, authenticator, options.Challenge))
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+}
+
+// TestFinishWebAuthnLogin_ChallengeReused tests that a signed challenge is only accepted once
+func TestFinishWebAuthnLogin_ChallengeReused(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockWebAuthnRepo := mocks.NewMockWebAuthnRepository(t)
+
+	service := &AuthService{
+		redis:        mockRedisRepo,
+		webauthn:     mockWebAuthnRepo,
+		relyingParty: newTestRelyingParty(),
+		logger:       newTestLogger(),
+	}
+
+	userID := uuid.New()
+	authenticator := webauthntest.New(testWebAuthnRPID, testWebAuthnOrigin)
+	authenticator.UserHandle = userID[:]
+	credential := &entities.WebAuthnCredential{
+		ID:           uuid.New(),
+		UserID:       userID,
+		CredentialID: authenticator.CredentialID,
+		PublicKey:    authenticator.PublicKey(),
+	}
+
+	challenge := base64.RawURLEncoding.EncodeToString([]byte("login-challenge"))
+	key := webauthnLoginKey(challenge)
+	mockWebAuthnRepo.On("FindCredentialByCredentialID", mock.Anything, authenticator.CredentialID).Return(credential, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return("pending", nil).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, key+":used", webauthnChallengeExpiration).Return(int64(2), nil).Once()
+
+	tokens, err := service.FinishWebAuthnLogin(context.Background(), newTestWebAuthnLogin(t, authenticator, challenge))
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, errors.ErrWebAuthnChallengeNotFound, err)
+}
+
+// TestFinishWebAuthnLogin_SignCount tests that a signature counter going backwards is rejected as a possible clone
+func TestFinishWebAuthnLogin_SignCount(t *testing.T) {
+	mockRedisRepo := mocks.NewMockInMemoryRespositoryContracts(t)
+	mockWebAuthnRepo := mocks.NewMockWebAuthnRepository(t)
+
+	service := &AuthService{
+		redis:        mockRedisRepo,
+		webauthn:     mockWebAuthnRepo,
+		relyingParty: newTestRelyingParty(),
+		logger:       newTestLogger(),
+	}
+
+	userID := uuid.New()
+	authenticator := webauthntest.New(testWebAuthnRPID, testWebAuthnOrigin)
+	authenticator.UserHandle = userID[:]
+	authenticator.SignCount = 2
+	credential := &entities.WebAuthnCredential{
+		ID:           uuid.New(),
+		UserID:       userID,
+		CredentialID: authenticator.CredentialID,
+		PublicKey:    authenticator.PublicKey(),
+		SignCount:    10,
+	}
+
+	challenge := base64.RawURLEncoding.EncodeToString([]byte("login-challenge"))
+	key := webauthnLoginKey(challenge)
+	mockWebAuthnRepo.On("FindCredentialByCredentialID", mock.Anything, authenticator.CredentialID).Return(credential, nil).Once()
+	mockRedisRepo.On("FindToken", mock.Anything, key).Return("pending", nil).Once()
+	mockRedisRepo.On("IncrementCounter", mock.Anything, key+":used", webauthnChallengeExpiration).Return(int64(1), nil).Once()
+	mockRedisRepo.On("RemoveToken", mock.Anything, key).Return(nil).Once()
+
+	tokens, err := service.FinishWebAuthnLogin(context.Background(), newTestWebAuthnLogin(t, authenticator, challenge))
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, errors.ErrWebAuthnSignCount, err)
+}
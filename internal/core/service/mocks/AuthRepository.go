@@ -39,6 +39,53 @@ func (_m *AuthRepository) EXPECT() *MockAuthRepository_Expecter {
 	return &MockAuthRepository_Expecter{mock: &_m.Mock}
 }
 
+// ConfirmEmail provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) ConfirmEmail(ctx context.Context, id uuid.UUID, email string) error {
+	ret := _mock.Called(ctx, id, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmEmail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = returnFunc(ctx, id, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAuthRepository_ConfirmEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmEmail'
+type MockAuthRepository_ConfirmEmail_Call struct {
+	*mock.Call
+}
+
+// ConfirmEmail is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - email
+func (_e *MockAuthRepository_Expecter) ConfirmEmail(ctx interface{}, id interface{}, email interface{}) *MockAuthRepository_ConfirmEmail_Call {
+	return &MockAuthRepository_ConfirmEmail_Call{Call: _e.mock.On("ConfirmEmail", ctx, id, email)}
+}
+
+func (_c *MockAuthRepository_ConfirmEmail_Call) Run(run func(ctx context.Context, id uuid.UUID, email string)) *MockAuthRepository_ConfirmEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAuthRepository_ConfirmEmail_Call) Return(err error) *MockAuthRepository_ConfirmEmail_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAuthRepository_ConfirmEmail_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, email string) error) *MockAuthRepository_ConfirmEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function for the type AuthRepository
 func (_mock *AuthRepository) Create(ctx context.Context, user *entities.User) error {
 	ret := _mock.Called(ctx, user)
@@ -198,3 +245,154 @@ func (_c *MockAuthRepository_FindUserByPhoneNumber_Call) RunAndReturn(run func(c
 	_c.Call.Return(run)
 	return _c
 }
+
+// FindUserByVerifiedEmail provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) FindUserByVerifiedEmail(ctx context.Context, email string) (*entities.User, error) {
+	ret := _mock.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserByVerifiedEmail")
+	}
+
+	var r0 *entities.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*entities.User, error)); ok {
+		return returnFunc(ctx, email)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *entities.User); ok {
+		r0 = returnFunc(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAuthRepository_FindUserByVerifiedEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUserByVerifiedEmail'
+type MockAuthRepository_FindUserByVerifiedEmail_Call struct {
+	*mock.Call
+}
+
+// FindUserByVerifiedEmail is a helper method to define mock.On call
+//   - ctx
+//   - email
+func (_e *MockAuthRepository_Expecter) FindUserByVerifiedEmail(ctx interface{}, email interface{}) *MockAuthRepository_FindUserByVerifiedEmail_Call {
+	return &MockAuthRepository_FindUserByVerifiedEmail_Call{Call: _e.mock.On("FindUserByVerifiedEmail", ctx, email)}
+}
+
+func (_c *MockAuthRepository_FindUserByVerifiedEmail_Call) Run(run func(ctx context.Context, email string)) *MockAuthRepository_FindUserByVerifiedEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockAuthRepository_FindUserByVerifiedEmail_Call) Return(user *entities.User, err error) *MockAuthRepository_FindUserByVerifiedEmail_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockAuthRepository_FindUserByVerifiedEmail_Call) RunAndReturn(run func(ctx context.Context, email string) (*entities.User, error)) *MockAuthRepository_FindUserByVerifiedEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePassword provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error {
+	ret := _mock.Called(ctx, id, hashedPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = returnFunc(ctx, id, hashedPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAuthRepository_UpdatePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePassword'
+type MockAuthRepository_UpdatePassword_Call struct {
+	*mock.Call
+}
+
+// UpdatePassword is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - hashedPassword
+func (_e *MockAuthRepository_Expecter) UpdatePassword(ctx interface{}, id interface{}, hashedPassword interface{}) *MockAuthRepository_UpdatePassword_Call {
+	return &MockAuthRepository_UpdatePassword_Call{Call: _e.mock.On("UpdatePassword", ctx, id, hashedPassword)}
+}
+
+func (_c *MockAuthRepository_UpdatePassword_Call) Run(run func(ctx context.Context, id uuid.UUID, hashedPassword string)) *MockAuthRepository_UpdatePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAuthRepository_UpdatePassword_Call) Return(err error) *MockAuthRepository_UpdatePassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAuthRepository_UpdatePassword_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, hashedPassword string) error) *MockAuthRepository_UpdatePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePhoneNumber provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) UpdatePhoneNumber(ctx context.Context, id uuid.UUID, phoneNumber string) error {
+	ret := _mock.Called(ctx, id, phoneNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePhoneNumber")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = returnFunc(ctx, id, phoneNumber)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAuthRepository_UpdatePhoneNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePhoneNumber'
+type MockAuthRepository_UpdatePhoneNumber_Call struct {
+	*mock.Call
+}
+
+// UpdatePhoneNumber is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - phoneNumber
+func (_e *MockAuthRepository_Expecter) UpdatePhoneNumber(ctx interface{}, id interface{}, phoneNumber interface{}) *MockAuthRepository_UpdatePhoneNumber_Call {
+	return &MockAuthRepository_UpdatePhoneNumber_Call{Call: _e.mock.On("UpdatePhoneNumber", ctx, id, phoneNumber)}
+}
+
+func (_c *MockAuthRepository_UpdatePhoneNumber_Call) Run(run func(ctx context.Context, id uuid.UUID, phoneNumber string)) *MockAuthRepository_UpdatePhoneNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAuthRepository_UpdatePhoneNumber_Call) Return(err error) *MockAuthRepository_UpdatePhoneNumber_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAuthRepository_UpdatePhoneNumber_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, phoneNumber string) error) *MockAuthRepository_UpdatePhoneNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
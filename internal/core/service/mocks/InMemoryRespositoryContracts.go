@@ -38,6 +38,111 @@ func (_m *InMemoryRespositoryContracts) EXPECT() *MockInMemoryRespositoryContrac
 	return &MockInMemoryRespositoryContracts_Expecter{mock: &_m.Mock}
 }
 
+// AddToSet provides a mock function for the type InMemoryRespositoryContracts
+func (_mock *InMemoryRespositoryContracts) AddToSet(ctx context.Context, key string, member string, expiration time.Duration) error {
+	ret := _mock.Called(ctx, key, member, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddToSet")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) error); ok {
+		r0 = returnFunc(ctx, key, member, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInMemoryRespositoryContracts_AddToSet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddToSet'
+type MockInMemoryRespositoryContracts_AddToSet_Call struct {
+	*mock.Call
+}
+
+// AddToSet is a helper method to define mock.On call
+//   - ctx
+//   - key
+//   - member
+//   - expiration
+func (_e *MockInMemoryRespositoryContracts_Expecter) AddToSet(ctx interface{}, key interface{}, member interface{}, expiration interface{}) *MockInMemoryRespositoryContracts_AddToSet_Call {
+	return &MockInMemoryRespositoryContracts_AddToSet_Call{Call: _e.mock.On("AddToSet", ctx, key, member, expiration)}
+}
+
+func (_c *MockInMemoryRespositoryContracts_AddToSet_Call) Run(run func(ctx context.Context, key string, member string, expiration time.Duration)) *MockInMemoryRespositoryContracts_AddToSet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockInMemoryRespositoryContracts_AddToSet_Call) Return(err error) *MockInMemoryRespositoryContracts_AddToSet_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInMemoryRespositoryContracts_AddToSet_Call) RunAndReturn(run func(ctx context.Context, key string, member string, expiration time.Duration) error) *MockInMemoryRespositoryContracts_AddToSet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddToSetIfAbsent provides a mock function for the type InMemoryRespositoryContracts
+func (_mock *InMemoryRespositoryContracts) AddToSetIfAbsent(ctx context.Context, key string, member string, expiration time.Duration) (bool, error) {
+	ret := _mock.Called(ctx, key, member, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddToSetIfAbsent")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) (bool, error)); ok {
+		return returnFunc(ctx, key, member, expiration)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) bool); ok {
+		r0 = returnFunc(ctx, key, member, expiration)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
+		r1 = returnFunc(ctx, key, member, expiration)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInMemoryRespositoryContracts_AddToSetIfAbsent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddToSetIfAbsent'
+type MockInMemoryRespositoryContracts_AddToSetIfAbsent_Call struct {
+	*mock.Call
+}
+
+// AddToSetIfAbsent is a helper method to define mock.On call
+//   - ctx
+//   - key
+//   - member
+//   - expiration
+func (_e *MockInMemoryRespositoryContracts_Expecter) AddToSetIfAbsent(ctx interface{}, key interface{}, member interface{}, expiration interface{}) *MockInMemoryRespositoryContracts_AddToSetIfAbsent_Call {
+	return &MockInMemoryRespositoryContracts_AddToSetIfAbsent_Call{Call: _e.mock.On("AddToSetIfAbsent", ctx, key, member, expiration)}
+}
+
+func (_c *MockInMemoryRespositoryContracts_AddToSetIfAbsent_Call) Run(run func(ctx context.Context, key string, member string, expiration time.Duration)) *MockInMemoryRespositoryContracts_AddToSetIfAbsent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockInMemoryRespositoryContracts_AddToSetIfAbsent_Call) Return(b bool, err error) *MockInMemoryRespositoryContracts_AddToSetIfAbsent_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockInMemoryRespositoryContracts_AddToSetIfAbsent_Call) RunAndReturn(run func(ctx context.Context, key string, member string, expiration time.Duration) (bool, error)) *MockInMemoryRespositoryContracts_AddToSetIfAbsent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // AddToken provides a mock function for the type InMemoryRespositoryContracts
 func (_mock *InMemoryRespositoryContracts) AddToken(ctx context.Context, userID string, token string, expiration time.Duration) error {
 	ret := _mock.Called(ctx, userID, token, expiration)
@@ -86,6 +191,63 @@ func (_c *MockInMemoryRespositoryContracts_AddToken_Call) RunAndReturn(run func(
 	return _c
 }
 
+// FindSetMembers provides a mock function for the type InMemoryRespositoryContracts
+func (_mock *InMemoryRespositoryContracts) FindSetMembers(ctx context.Context, key string) ([]string, error) {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindSetMembers")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]string, error)); ok {
+		return returnFunc(ctx, key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInMemoryRespositoryContracts_FindSetMembers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindSetMembers'
+type MockInMemoryRespositoryContracts_FindSetMembers_Call struct {
+	*mock.Call
+}
+
+// FindSetMembers is a helper method to define mock.On call
+//   - ctx
+//   - key
+func (_e *MockInMemoryRespositoryContracts_Expecter) FindSetMembers(ctx interface{}, key interface{}) *MockInMemoryRespositoryContracts_FindSetMembers_Call {
+	return &MockInMemoryRespositoryContracts_FindSetMembers_Call{Call: _e.mock.On("FindSetMembers", ctx, key)}
+}
+
+func (_c *MockInMemoryRespositoryContracts_FindSetMembers_Call) Run(run func(ctx context.Context, key string)) *MockInMemoryRespositoryContracts_FindSetMembers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockInMemoryRespositoryContracts_FindSetMembers_Call) Return(ss []string, err error) *MockInMemoryRespositoryContracts_FindSetMembers_Call {
+	_c.Call.Return(ss, err)
+	return _c
+}
+
+func (_c *MockInMemoryRespositoryContracts_FindSetMembers_Call) RunAndReturn(run func(ctx context.Context, key string) ([]string, error)) *MockInMemoryRespositoryContracts_FindSetMembers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindToken provides a mock function for the type InMemoryRespositoryContracts
 func (_mock *InMemoryRespositoryContracts) FindToken(ctx context.Context, userID string) (string, error) {
 	ret := _mock.Called(ctx, userID)
@@ -141,6 +303,109 @@ func (_c *MockInMemoryRespositoryContracts_FindToken_Call) RunAndReturn(run func
 	return _c
 }
 
+// IncrementCounter provides a mock function for the type InMemoryRespositoryContracts
+func (_mock *InMemoryRespositoryContracts) IncrementCounter(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	ret := _mock.Called(ctx, key, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementCounter")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Duration) (int64, error)); ok {
+		return returnFunc(ctx, key, expiration)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Duration) int64); ok {
+		r0 = returnFunc(ctx, key, expiration)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = returnFunc(ctx, key, expiration)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInMemoryRespositoryContracts_IncrementCounter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncrementCounter'
+type MockInMemoryRespositoryContracts_IncrementCounter_Call struct {
+	*mock.Call
+}
+
+// IncrementCounter is a helper method to define mock.On call
+//   - ctx
+//   - key
+//   - expiration
+func (_e *MockInMemoryRespositoryContracts_Expecter) IncrementCounter(ctx interface{}, key interface{}, expiration interface{}) *MockInMemoryRespositoryContracts_IncrementCounter_Call {
+	return &MockInMemoryRespositoryContracts_IncrementCounter_Call{Call: _e.mock.On("IncrementCounter", ctx, key, expiration)}
+}
+
+func (_c *MockInMemoryRespositoryContracts_IncrementCounter_Call) Run(run func(ctx context.Context, key string, expiration time.Duration)) *MockInMemoryRespositoryContracts_IncrementCounter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockInMemoryRespositoryContracts_IncrementCounter_Call) Return(n int64, err error) *MockInMemoryRespositoryContracts_IncrementCounter_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockInMemoryRespositoryContracts_IncrementCounter_Call) RunAndReturn(run func(ctx context.Context, key string, expiration time.Duration) (int64, error)) *MockInMemoryRespositoryContracts_IncrementCounter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveFromSet provides a mock function for the type InMemoryRespositoryContracts
+func (_mock *InMemoryRespositoryContracts) RemoveFromSet(ctx context.Context, key string, member string) error {
+	ret := _mock.Called(ctx, key, member)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveFromSet")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, key, member)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInMemoryRespositoryContracts_RemoveFromSet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveFromSet'
+type MockInMemoryRespositoryContracts_RemoveFromSet_Call struct {
+	*mock.Call
+}
+
+// RemoveFromSet is a helper method to define mock.On call
+//   - ctx
+//   - key
+//   - member
+func (_e *MockInMemoryRespositoryContracts_Expecter) RemoveFromSet(ctx interface{}, key interface{}, member interface{}) *MockInMemoryRespositoryContracts_RemoveFromSet_Call {
+	return &MockInMemoryRespositoryContracts_RemoveFromSet_Call{Call: _e.mock.On("RemoveFromSet", ctx, key, member)}
+}
+
+func (_c *MockInMemoryRespositoryContracts_RemoveFromSet_Call) Run(run func(ctx context.Context, key string, member string)) *MockInMemoryRespositoryContracts_RemoveFromSet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockInMemoryRespositoryContracts_RemoveFromSet_Call) Return(err error) *MockInMemoryRespositoryContracts_RemoveFromSet_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInMemoryRespositoryContracts_RemoveFromSet_Call) RunAndReturn(run func(ctx context.Context, key string, member string) error) *MockInMemoryRespositoryContracts_RemoveFromSet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RemoveToken provides a mock function for the type InMemoryRespositoryContracts
 func (_mock *InMemoryRespositoryContracts) RemoveToken(ctx context.Context, userID string) error {
 	ret := _mock.Called(ctx, userID)
@@ -186,3 +451,58 @@ func (_c *MockInMemoryRespositoryContracts_RemoveToken_Call) RunAndReturn(run fu
 	_c.Call.Return(run)
 	return _c
 }
+
+// TakeToken provides a mock function for the type InMemoryRespositoryContracts
+func (_mock *InMemoryRespositoryContracts) TakeToken(ctx context.Context, key string) (string, error) {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TakeToken")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInMemoryRespositoryContracts_TakeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TakeToken'
+type MockInMemoryRespositoryContracts_TakeToken_Call struct {
+	*mock.Call
+}
+
+// TakeToken is a helper method to define mock.On call
+//   - ctx
+//   - key
+func (_e *MockInMemoryRespositoryContracts_Expecter) TakeToken(ctx interface{}, key interface{}) *MockInMemoryRespositoryContracts_TakeToken_Call {
+	return &MockInMemoryRespositoryContracts_TakeToken_Call{Call: _e.mock.On("TakeToken", ctx, key)}
+}
+
+func (_c *MockInMemoryRespositoryContracts_TakeToken_Call) Run(run func(ctx context.Context, key string)) *MockInMemoryRespositoryContracts_TakeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockInMemoryRespositoryContracts_TakeToken_Call) Return(s string, err error) *MockInMemoryRespositoryContracts_TakeToken_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockInMemoryRespositoryContracts_TakeToken_Call) RunAndReturn(run func(ctx context.Context, key string) (string, error)) *MockInMemoryRespositoryContracts_TakeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/amirdashtii/go_auth/infrastructure/logger"
+	"github.com/amirdashtii/go_auth/infrastructure/repository"
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+// organizationCacheExpiration is how long an organization found by its slug
+// is kept in memory. Every request naming an organization looks it up.
+const organizationCacheExpiration = 1 * time.Minute
+
+type cachedOrganization struct {
+	organization *entities.Organization
+	expiresAt    time.Time
+}
+
+type OrganizationService struct {
+	db     ports.OrganizationRepository
+	logger ports.Logger
+
+	mu    sync.Mutex
+	cache map[string]cachedOrganization
+}
+
+func NewOrganizationService() *OrganizationService {
+	dbRepo, err := repository.NewPGRepository()
+	if err != nil {
+		panic(errors.ErrDatabaseInit)
+	}
+	db := dbRepo.DB()
+
+	// Initialize logger with both file and console output
+	loggerConfig := ports.LoggerConfig{
+		Level:       "info",
+		Environment: "development",
+		ServiceName: "go_auth",
+		Output:      os.Stdout,
+	}
+	appLogger := logger.NewZerologLogger(loggerConfig)
+
+	return &OrganizationService{
+		db:     repository.NewPGOrganizationRepository(db, appLogger),
+		logger: appLogger,
+		cache:  make(map[string]cachedOrganization),
+	}
+}
+
+// FindOrganizationBySlug returns the organization with slug, from the cache
+// while it hasn't expired
+func (s *OrganizationService) FindOrganizationBySlug(ctx context.Context, slug string) (*entities.Organization, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while finding organization",
+			ports.F("error", ctx.Err()),
+			ports.F("slug", slug),
+		)
+		return nil, errors.ErrContextCancelled
+	}
+
+	s.mu.Lock()
+	cached, ok := s.cache[slug]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.organization, nil
+	}
+
+	organization, err := s.db.FindOrganizationBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[slug] = cachedOrganization{
+		organization: organization,
+		expiresAt:    time.Now().Add(organizationCacheExpiration),
+	}
+	s.mu.Unlock()
+	return organization, nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/amirdashtii/go_auth/infrastructure/logger"
+	"github.com/amirdashtii/go_auth/infrastructure/repository"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/ports"
+)
+
+// permissionCacheExpiration is how long the permissions of a role are kept
+// in memory, so changes to role_permissions take effect within it
+const permissionCacheExpiration = 1 * time.Minute
+
+type cachedPermissions struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
+type PermissionService struct {
+	db     ports.PermissionRepository
+	logger ports.Logger
+
+	mu    sync.Mutex
+	cache map[string]cachedPermissions
+}
+
+func NewPermissionService() *PermissionService {
+	dbRepo, err := repository.NewPGRepository()
+	if err != nil {
+		panic(errors.ErrDatabaseInit)
+	}
+	db := dbRepo.DB()
+
+	// Initialize logger with both file and console output
+	loggerConfig := ports.LoggerConfig{
+		Level:       "info",
+		Environment: "development",
+		ServiceName: "go_auth",
+		Output:      os.Stdout,
+	}
+	appLogger := logger.NewZerologLogger(loggerConfig)
+
+	return &PermissionService{
+		db:     repository.NewPGPermissionRepository(db, appLogger),
+		logger: appLogger,
+		cache:  make(map[string]cachedPermissions),
+	}
+}
+
+// HasPermission reports whether users with role were granted permission
+func (s *PermissionService) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	if ctx.Err() != nil {
+		s.logger.Error("Context cancelled while checking permission",
+			ports.F("error", ctx.Err()),
+			ports.F("role", role),
+			ports.F("permission", permission),
+		)
+		return false, errors.ErrContextCancelled
+	}
+
+	permissions, err := s.rolePermissions(ctx, role)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(permissions, permission), nil
+}
+
+// rolePermissions returns the permissions of role, from the cache while it
+// hasn't expired
+func (s *PermissionService) rolePermissions(ctx context.Context, role string) ([]string, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[role]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.permissions, nil
+	}
+
+	permissions, err := s.db.FindPermissionsByRole(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[role] = cachedPermissions{
+		permissions: permissions,
+		expiresAt:   time.Now().Add(permissionCacheExpiration),
+	}
+	s.mu.Unlock()
+	return permissions, nil
+}
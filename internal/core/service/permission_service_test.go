@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amirdashtii/go_auth/internal/core/entities"
+	"github.com/amirdashtii/go_auth/internal/core/errors"
+	"github.com/amirdashtii/go_auth/internal/core/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestHasPermission tests that a role's permissions are looked up once and then served from the cache
+func TestHasPermission(t *testing.T) {
+	mockPermissionRepo := mocks.NewMockPermissionRepository(t)
+
+	service := &PermissionService{
+		db:     mockPermissionRepo,
+		logger: newTestLogger(),
+		cache:  make(map[string]cachedPermissions),
+	}
+
+	mockPermissionRepo.On("FindPermissionsByRole", mock.Anything, entities.AdminRole.String()).
+		Return([]string{entities.PermissionUsersRead, entities.PermissionUsersWrite}, nil).Once()
+
+	allowed, err := service.HasPermission(context.Background(), entities.AdminRole.String(), entities.PermissionUsersWrite)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = service.HasPermission(context.Background(), entities.AdminRole.String(), entities.PermissionKeysRotate)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+// TestHasPermission_RepositoryError tests that a failed lookup isn't cached
+func TestHasPermission_RepositoryError(t *testing.T) {
+	mockPermissionRepo := mocks.NewMockPermissionRepository(t)
+
+	service := &PermissionService{
+		db:     mockPermissionRepo,
+		logger: newTestLogger(),
+		cache:  make(map[string]cachedPermissions),
+	}
+
+	mockPermissionRepo.On("FindPermissionsByRole", mock.Anything, entities.UserRole.String()).Return(nil, errors.ErrGetPermissions).Once()
+	mockPermissionRepo.On("FindPermissionsByRole", mock.Anything, entities.UserRole.String()).Return([]string{}, nil).Once()
+
+	_, err := service.HasPermission(context.Background(), entities.UserRole.String(), entities.PermissionUsersRead)
+	assert.Equal(t, errors.ErrGetPermissions, err)
+
+	allowed, err := service.HasPermission(context.Background(), entities.UserRole.String(), entities.PermissionUsersRead)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+}
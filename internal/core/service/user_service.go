@@ -1,8 +1,10 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"os"
+	"strings"
 
 	"github.com/amirdashtii/go_auth/controller/dto"
 	"github.com/amirdashtii/go_auth/infrastructure/logger"
@@ -11,11 +13,12 @@ import (
 	"github.com/amirdashtii/go_auth/internal/core/errors"
 	"github.com/amirdashtii/go_auth/internal/core/ports"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type UserService struct {
 	db     ports.UserRepository
+	audit  ports.AuditRepository
+	hasher ports.PasswordHasher
 	logger ports.Logger
 }
 
@@ -38,6 +41,8 @@ func NewUserService() *UserService {
 	userRepo := repository.NewPGUserRepository(db, appLogger)
 	return &UserService{
 		db:     userRepo,
+		audit:  repository.NewPGAuditRepository(db, appLogger),
+		hasher: newPasswordHasher(appLogger),
 		logger: appLogger,
 	}
 }
@@ -68,10 +73,12 @@ func (s *UserService) GetProfile(ctx context.Context, userID *uuid.UUID) (*dto.U
 	}
 
 	resp := dto.UserProfileResponse{
-		ID:        user.ID.String(),
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Email:     user.Email,
+		ID:            user.ID.String(),
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		PendingEmail:  user.PendingEmail,
 	}
 	return &resp, nil
 }
@@ -84,17 +91,49 @@ func (s *UserService) UpdateProfile(ctx context.Context, userID *uuid.UUID, req
 		)
 		return errors.ErrContextCancelled
 	}
+	// The phone number is the login identifier, it is changed through
+	// AuthService.RequestPhoneNumberChange with a code sent to the new number
+	if req.PhoneNumber != "" {
+		return errors.ErrPhoneChangeNeedsVerification
+	}
+
+	currentUser, err := s.db.FindUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
 	user := &entities.User{
-		ID:          *userID,
-		PhoneNumber: req.PhoneNumber,
-		FirstName:   req.FirstName,
-		LastName:    req.LastName,
-		Email:       req.Email,
+		ID:        *userID,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+	}
+
+	// A new email stays pending until the link sent to it is opened, the
+	// current one keeps working until then
+	if req.Email != "" {
+		if email := strings.ToLower(req.Email); email != strings.ToLower(currentUser.Email) {
+			user.PendingEmail = email
+		}
 	}
 
 	if err := s.db.Update(ctx, user); err != nil {
 		return err
 	}
+
+	// Empty fields weren't changed
+	changes := auditChanges(
+		[3]string{"first_name", currentUser.FirstName, cmp.Or(user.FirstName, currentUser.FirstName)},
+		[3]string{"last_name", currentUser.LastName, cmp.Or(user.LastName, currentUser.LastName)},
+		[3]string{"pending_email", currentUser.PendingEmail, cmp.Or(user.PendingEmail, currentUser.PendingEmail)},
+	)
+	if len(changes) > 0 {
+		recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+			Type:     entities.AuditProfileUpdated,
+			ActorID:  userID,
+			TargetID: userID,
+			Changes:  changes,
+		})
+	}
 	return nil
 }
 
@@ -124,14 +163,15 @@ func (s *UserService) ChangePassword(ctx context.Context, userID *uuid.UUID, cha
 		return errors.ErrAccountDeactivated
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(currentUser.Password), []byte(changePasswordReq.OldPassword)); err != nil {
+	if matched, err := s.hasher.Verify(changePasswordReq.OldPassword, currentUser.Password); err != nil || !matched {
 		s.logger.Error("Old password is incorrect",
+			ports.F("error", err),
 			ports.F("user_id", userID),
 		)
 		return errors.ErrInvalidCredentials
 	}
 
-	hashedNewPassword, err := bcrypt.GenerateFromPassword([]byte(changePasswordReq.NewPassword), bcrypt.DefaultCost)
+	hashedNewPassword, err := s.hasher.Hash(changePasswordReq.NewPassword)
 	if err != nil {
 		s.logger.Error("Error generating new password hash",
 			ports.F("error", err),
@@ -142,7 +182,7 @@ func (s *UserService) ChangePassword(ctx context.Context, userID *uuid.UUID, cha
 
 	user := &entities.User{}
 	user.ID = *userID
-	user.Password = string(hashedNewPassword)
+	user.Password = hashedNewPassword
 	if err := s.db.Update(ctx, user); err != nil {
 		s.logger.Error("Error updating user password",
 			ports.F("error", err),
@@ -150,6 +190,12 @@ func (s *UserService) ChangePassword(ctx context.Context, userID *uuid.UUID, cha
 		)
 		return errors.ErrChangePassword
 	}
+
+	recordAudit(ctx, s.audit, s.logger, &entities.AuditEvent{
+		Type:     entities.AuditPasswordChanged,
+		ActorID:  userID,
+		TargetID: userID,
+	})
 	return nil
 }
 
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMP,
+    ADD COLUMN pending_email VARCHAR(100);
\ No newline at end of file
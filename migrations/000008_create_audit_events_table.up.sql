@@ -0,0 +1,17 @@
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY,
+    event_type VARCHAR(100) NOT NULL,
+    actor_id UUID,
+    target_id UUID,
+    ip VARCHAR(64),
+    user_agent TEXT,
+    changes JSONB,
+    details JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Events outlive the users they mention, so there are no foreign keys
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at DESC, id DESC);
+CREATE INDEX idx_audit_events_actor_id ON audit_events(actor_id);
+CREATE INDEX idx_audit_events_target_id ON audit_events(target_id);
+CREATE INDEX idx_audit_events_event_type ON audit_events(event_type);
\ No newline at end of file
@@ -0,0 +1,41 @@
+CREATE TABLE roles (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(50) UNIQUE NOT NULL
+);
+
+CREATE TABLE permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions (
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission)
+);
+
+-- The ids are the values of users.role
+INSERT INTO roles (id, name) VALUES
+    (0, 'User'),
+    (1, 'SuperAdmin'),
+    (2, 'Admin');
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:read', 'List users and view their details'),
+    ('users:write', 'Update the profile of any user'),
+    ('users:role:change', 'Change the role of a user'),
+    ('users:status:change', 'Activate or deactivate a user'),
+    ('users:delete', 'Delete a user'),
+    ('users:mfa:reset', 'Turn off two-factor authentication of a user'),
+    ('users:unlock', 'Clear the login lockout of a user'),
+    ('audit:read', 'List audit events'),
+    ('keys:rotate', 'Rotate the token signing key'),
+    ('oauth_clients:write', 'Register OAuth clients');
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT 1, name FROM permissions;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT 2, name FROM permissions WHERE name NOT IN ('keys:rotate', 'oauth_clients:write');
+
+ALTER TABLE users ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles(id);
\ No newline at end of file
@@ -0,0 +1,14 @@
+DELETE FROM permissions WHERE name IN ('organizations:read', 'organizations:write');
+
+DROP INDEX idx_audit_events_organization_id;
+ALTER TABLE audit_events DROP COLUMN organization_id;
+
+-- Fails if two organizations have users with the same phone number or email
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+ALTER TABLE users DROP CONSTRAINT users_phone_number_key;
+ALTER TABLE users ADD CONSTRAINT users_phone_number_key UNIQUE (phone_number);
+
+ALTER TABLE users DROP COLUMN organization_id;
+
+DROP TABLE organizations;
\ No newline at end of file
@@ -0,0 +1,34 @@
+CREATE TABLE organizations (
+    id UUID PRIMARY KEY,
+    slug VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Users and audit events from before organizations existed belong to the
+-- default organization
+INSERT INTO organizations (id, slug, name) VALUES
+    ('00000000-0000-0000-0000-000000000001', 'default', 'Default');
+
+ALTER TABLE users ADD COLUMN organization_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES organizations(id);
+ALTER TABLE users ALTER COLUMN organization_id DROP DEFAULT;
+
+-- Phone numbers and emails only have to be unique within an organization.
+-- The constraints keep their names, which duplicate key errors are matched on.
+ALTER TABLE users DROP CONSTRAINT users_phone_number_key;
+ALTER TABLE users ADD CONSTRAINT users_phone_number_key UNIQUE (organization_id, phone_number);
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (organization_id, email);
+
+ALTER TABLE audit_events ADD COLUMN organization_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+ALTER TABLE audit_events ALTER COLUMN organization_id DROP DEFAULT;
+CREATE INDEX idx_audit_events_organization_id ON audit_events(organization_id, created_at DESC, id DESC);
+
+INSERT INTO permissions (name, description) VALUES
+    ('organizations:read', 'List organizations'),
+    ('organizations:write', 'Create organizations');
+
+INSERT INTO role_permissions (role_id, permission) VALUES
+    (1, 'organizations:read'),
+    (1, 'organizations:write');
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP INDEX idx_users_organization_updated_at;
+DROP INDEX idx_users_organization_created_at;
\ No newline at end of file
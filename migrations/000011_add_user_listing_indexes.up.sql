@@ -0,0 +1,3 @@
+-- Listing users pages through an organization by the sort column and ID
+CREATE INDEX idx_users_organization_created_at ON users(organization_id, created_at, id);
+CREATE INDEX idx_users_organization_updated_at ON users(organization_id, updated_at, id);
\ No newline at end of file
@@ -0,0 +1 @@
+DELETE FROM permissions WHERE name = 'users:sessions:revoke';
\ No newline at end of file
@@ -0,0 +1,6 @@
+INSERT INTO permissions (name, description) VALUES
+    ('users:sessions:revoke', 'End every session of a user');
+
+INSERT INTO role_permissions (role_id, permission) VALUES
+    (1, 'users:sessions:revoke'),
+    (2, 'users:sessions:revoke');
\ No newline at end of file
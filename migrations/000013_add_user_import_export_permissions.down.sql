@@ -0,0 +1 @@
+DELETE FROM permissions WHERE name IN ('users:export', 'users:import');
\ No newline at end of file
@@ -0,0 +1,9 @@
+INSERT INTO permissions (name, description) VALUES
+    ('users:export', 'Export users as CSV or NDJSON'),
+    ('users:import', 'Create users from a CSV or NDJSON file');
+
+INSERT INTO role_permissions (role_id, permission) VALUES
+    (1, 'users:export'),
+    (1, 'users:import'),
+    (2, 'users:export'),
+    (2, 'users:import');
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- The constraint doesn't allow several empty emails in an organization, but
+-- any number of NULL ones, which is how users without an email were stored.
+DROP INDEX users_email_key;
+UPDATE users SET email = NULL WHERE email = '';
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (organization_id, email);
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Users without an email have an empty one, and any number of them can be in
+-- an organization. The index keeps the name duplicate key errors are matched on.
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+CREATE UNIQUE INDEX users_email_key ON users(organization_id, email) WHERE email <> '';
\ No newline at end of file
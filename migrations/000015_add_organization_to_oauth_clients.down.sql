@@ -0,0 +1 @@
+ALTER TABLE oauth_clients DROP COLUMN organization_id;
\ No newline at end of file
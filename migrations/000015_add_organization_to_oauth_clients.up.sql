@@ -0,0 +1,4 @@
+-- Clients registered before organizations were bound to one belong to the
+-- default organization
+ALTER TABLE oauth_clients ADD COLUMN organization_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES organizations(id);
+ALTER TABLE oauth_clients ALTER COLUMN organization_id DROP DEFAULT;